@@ -23,14 +23,19 @@ var (
 	// key         *string
 	secret  *string
 	caching *bool
+	staging *bool
 )
 
 var exchanges map[string]dia.Exchange
 
+// assetCacheBatchSize is the number of assets which are written to the cache in one round trip.
+const assetCacheBatchSize = 500
+
 func init() {
 	assetSource = flag.String("source", "Uniswap", "Data source for asset collection")
 	secret = flag.String("secret", "", "secret for asset source")
 	caching = flag.Bool("caching", true, "caching assets in redis")
+	staging = flag.Bool("staging", true, "stage assets for review before saving them in the asset table")
 	flag.Parse()
 
 	// source, err := datasource.InitSource()
@@ -144,33 +149,57 @@ func main() {
 		log.Errorln("Error connecting to asset DB: ", err)
 		return
 	}
-	runAssetSource(relDB, *assetSource, *caching, *secret)
+	runAssetSource(relDB, *assetSource, *caching, *staging, *secret)
 	log.Infof("Successfully ran asset collector for %s", *assetSource)
 }
 
-func runAssetSource(relDB *models.RelDB, source string, caching bool, secret string) {
+func runAssetSource(relDB *models.RelDB, source string, caching bool, staging bool, secret string) {
 	log.Println("Fetching asset from ", source)
 	asset := NewAssetScraper(source, secret)
+	approvalRules := models.DefaultAutoApprovalRules()
+	var cacheBatch []dia.Asset
+	flushCache := func() {
+		if err := relDB.SetAssetCacheBatch(cacheBatch); err != nil {
+			log.Error("Error caching assets: ", err)
+		}
+		cacheBatch = nil
+	}
 
 	for {
 		select {
 		case receivedAsset := <-asset.Asset():
-			// Set to persistent DB
-			err := relDB.SetAsset(receivedAsset)
-			if err != nil {
-				log.Errorf("Error saving asset %v: %v", receivedAsset, err)
+			if staging {
+				// Stage asset for review. Only approved assets are cached.
+				status, err := relDB.SubmitPendingAsset(receivedAsset, source, approvalRules)
+				if err != nil {
+					log.Errorf("Error staging asset %v: %v", receivedAsset, err)
+					continue
+				}
+				log.Infof("staged asset %v with status %s", receivedAsset, status)
+				if status != models.PendingAssetApproved {
+					continue
+				}
 			} else {
-				log.Info("successfully set asset ", receivedAsset)
+				// Set to persistent DB
+				err := relDB.SetAsset(receivedAsset)
+				if err != nil {
+					log.Errorf("Error saving asset %v: %v", receivedAsset, err)
+				} else {
+					log.Info("successfully set asset ", receivedAsset)
+				}
 			}
 
 			// Set to cache
 			if caching {
-				err := relDB.SetAssetCache(receivedAsset)
-				if err != nil {
-					log.Error("Error caching asset: ", err)
+				cacheBatch = append(cacheBatch, receivedAsset)
+				if len(cacheBatch) >= assetCacheBatchSize {
+					flushCache()
 				}
 			}
 		case <-asset.Done():
+			if caching {
+				flushCache()
+			}
 			return
 		}
 	}
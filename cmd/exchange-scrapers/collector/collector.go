@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/tradeContract"
 	models "github.com/diadata-org/diadata/pkg/model"
 	"github.com/segmentio/kafka-go"
 	"github.com/sirupsen/logrus"
@@ -50,6 +53,13 @@ var (
 	mode              = flag.String("mode", "current", "either storeTrades, current, historical or estimation.")
 	pairsfile         = flag.Bool("pairsfile", false, "read pairs from json file in config folder.")
 	replicaKafkaTopic string
+	// contract==report:	default. Violations of the scraper's data contract are counted and reported periodically.
+	//						All trades are forwarded as usual.
+	// contract==enforce:	trades violating the scraper's data contract are dropped.
+	// contract==shadow:	violations are logged and trades are only written to the test topic. Use for new integrations.
+	// contract==off:		trades are not checked.
+	contract     = flag.String("contract", "report", "either report, enforce, shadow or off.")
+	contractMode tradeContract.Mode
 )
 
 func init() {
@@ -68,6 +78,11 @@ func init() {
 		log.Fatal("Invalid exchange string: ", *exchange)
 	}
 	replicaKafkaTopic = utils.Getenv("REPLICA_KAFKA_TOPIC", "false")
+	var err error
+	contractMode, err = tradeContract.ParseMode(*contract)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
 // main manages all PairScrapers and handles incoming trade information
@@ -132,6 +147,18 @@ func main() {
 		}
 	}()
 
+	if contractMode == tradeContract.ModeShadow && wTest == nil {
+		wTest = kafkaHelper.NewWriter(kafkaHelper.TopicTradesTest)
+	}
+
+	// Trades of historical runs are old by design, so their time is not checked.
+	maxLag := getenvSeconds("CONTRACT_MAX_LAG_SECONDS", 300)
+	if *mode == "historical" {
+		maxLag = time.Duration(math.MaxInt64)
+	}
+	maxLead := getenvSeconds("CONTRACT_MAX_LEAD_SECONDS", 10)
+	tc := tradeContract.New(*exchange, pairsExchange, maxLag, maxLead)
+
 	wg := sync.WaitGroup{}
 
 	if scrapers.Exchanges[*exchange].Centralized || scrapers.ExchangeDuplicates[*exchange].Centralized {
@@ -157,10 +184,12 @@ func main() {
 		defer wg.Wait()
 
 	}
-	go handleTrades(es.Channel(), &wg, w, wTest, wReplica, ds, *exchange, *mode)
+	// Raw trades are written in batches and replays of a restarted scraper are dropped.
+	tw := models.NewTradeWriter(ds)
+	go handleTrades(es.Channel(), &wg, w, wTest, wReplica, tw, *exchange, *mode, tc)
 }
 
-func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, wTest *kafka.Writer, wReplica *kafka.Writer, ds *models.DB, exchange string, mode string) {
+func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, wTest *kafka.Writer, wReplica *kafka.Writer, tw *models.TradeWriter, exchange string, mode string, tc *tradeContract.Contract) {
 	lastTradeTime := time.Now()
 	watchdogDelay := scrapers.Exchanges[exchange].WatchdogDelay
 	if watchdogDelay == 0 {
@@ -170,18 +199,49 @@ func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, wTest
 	for {
 		select {
 		case <-t.C:
+			if mode == "storeTrades" {
+				if err := tw.Flush(); err != nil {
+					log.Error("flush trades: ", err)
+				}
+				written, duplicates := tw.Stats()
+				log.Infof("stored %d trades, dropped %d duplicates", written, duplicates)
+			}
 			duration := time.Since(lastTradeTime)
 			if duration > time.Duration(watchdogDelay)*time.Second {
 				log.Error(duration)
 				panic("frozen? ")
 			}
+			if contractMode != tradeContract.ModeOff {
+				report := tc.Report()
+				log.Infof("contract %s: checked %d trades, violations: %v", contractMode, report.Checked, report.Violations)
+			}
 		case t, ok := <-c:
 			if !ok {
+				if mode == "storeTrades" {
+					if err := tw.Flush(); err != nil {
+						log.Error("flush trades: ", err)
+					}
+				}
 				wg.Done()
 				log.Error("handleTrades")
 				return
 			}
 			lastTradeTime = time.Now()
+			if contractMode != tradeContract.ModeOff {
+				// Violations are counted by the contract and reported on each watchdog tick.
+				if violations := tc.Check(t, time.Now()); len(violations) > 0 && contractMode == tradeContract.ModeEnforce {
+					log.Warnf("drop trade %s on %s at %v violating contract: %v", t.Pair, t.Source, t.Time, violations)
+					continue
+				}
+				// Trades of scrapers in shadow mode never reach production storage.
+				if contractMode == tradeContract.ModeShadow {
+					err := writeTradeToKafka(wTest, t)
+					if err != nil {
+						log.Error(err)
+					}
+					continue
+				}
+			}
 			// Trades are sent to the tradesblockservice through a kafka channel - either
 			// through trades topic or historical trades topic.
 			if mode == "current" || mode == "historical" || mode == "estimation" {
@@ -212,7 +272,7 @@ func handleTrades(c chan *dia.Trade, wg *sync.WaitGroup, w *kafka.Writer, wTest
 			}
 			// Trades are just saved in influx - not sent to the tradesblockservice through a kafka channel.
 			if mode == "storeTrades" {
-				err := ds.SaveTradeInflux(t)
+				err := tw.Add(*t)
 				if err != nil {
 					log.Error(err)
 				}
@@ -249,6 +309,16 @@ func writeTradeToKafka(w *kafka.Writer, t *dia.Trade) error {
 	return nil
 }
 
+// getenvSeconds returns the number of seconds in env var @key as duration, or @fallback seconds if unset or malformed.
+func getenvSeconds(key string, fallback int) time.Duration {
+	seconds, err := strconv.Atoi(utils.Getenv(key, strconv.Itoa(fallback)))
+	if err != nil {
+		log.Warnf("malformed %s, using %d seconds", key, fallback)
+		seconds = fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 func isValidExchange(estring string) bool {
 	for e := range scrapers.Exchanges {
 		if e == estring {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"time"
 
 	scrapers "github.com/diadata-org/diadata/pkg/dia/scraper/historical-scrapers"
 
@@ -11,11 +12,13 @@ import (
 )
 
 var (
-	source *string
+	source        *string
+	archiveMonths *int
 )
 
 func init() {
 	source = flag.String("source", "", "which source for historical quotations")
+	archiveMonths = flag.Int("archiveMonths", 0, "if positive, archive historical quotations older than the given number of months and exit")
 	flag.Parse()
 }
 
@@ -26,6 +29,15 @@ func main() {
 		log.Fatal("datastore error: ", err)
 	}
 
+	if *archiveMonths > 0 {
+		archived, err := rdb.ArchiveHistoricalQuotations(time.Now().AddDate(0, -*archiveMonths, 0))
+		if err != nil {
+			log.Fatal("archive historical quotations: ", err)
+		}
+		log.Infof("archived %d historical quotations", archived)
+		return
+	}
+
 	datastore, err := models.NewDataStoreWithoutRedis()
 	if err != nil {
 		log.Fatalf("Failed to create datastore: %v", err)
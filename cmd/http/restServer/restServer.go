@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"os"
 	"strings"
 	"time"
@@ -189,6 +190,24 @@ func main() {
 	if err != nil {
 		log.Errorln("NewRelDataStore", err)
 	}
+	// Volume ranges are read from the downsampled series installed by the volume service where they cover the range.
+	go func() {
+		for {
+			if err := store.SetDownsampledSeries(models.DefaultDownsampleSeries); err != nil {
+				log.Warn("set downsampled series: ", err)
+			}
+			time.Sleep(time.Hour)
+		}
+	}()
+	if utils.Getenv("WARM_ASSET_CACHE", "false") == "true" {
+		go func() {
+			warmup, err := relStore.WarmAssetCache(context.Background(), "", models.WarmInMemoryCache())
+			if err != nil {
+				log.Error("warm asset cache: ", err)
+			}
+			log.Infof("warmed asset cache with %d of %d assets", warmup.Cached, warmup.Assets)
+		}()
+	}
 
 	signerKey := os.Getenv("SIGNER_KEY")
 	aqs := utils.NewAssetQuotationSigner(signerKey)
@@ -204,6 +223,20 @@ func main() {
 	{
 		diaAuth.POST("/supply", diaApiEnv.PostSupply)
 		diaAuth.POST("/quotation", diaApiEnv.SetQuotation)
+		diaAuth.GET("/pendingAssets", diaApiEnv.GetPendingAssets)
+		diaAuth.POST("/pendingAssets/:id", diaApiEnv.PostPendingAssetReview)
+		diaAuth.GET("/exchangePairs/:exchange", diaApiEnv.GetAllExchangePairs)
+		diaAuth.GET("/exchangeSymbols", diaApiEnv.GetExchangeSymbols)
+		diaAuth.POST("/summaryCounts/refresh", diaApiEnv.PostSummaryCountsRefresh)
+		diaAuth.POST("/symbolLabel", diaApiEnv.PostSymbolLabel)
+		diaAuth.POST("/symbolCasing", diaApiEnv.PostSymbolCasing)
+		diaAuth.DELETE("/symbolVerification/:exchange/:symbol", diaApiEnv.DeleteSymbolVerification)
+		diaAuth.GET("/symbolVerificationHistory/:exchange/:symbol", diaApiEnv.GetVerificationHistory)
+		diaAuth.POST("/excludedPairs", diaApiEnv.PostExcludedPair)
+		diaAuth.POST("/benchmarkAssets", diaApiEnv.PostBenchmarkAsset)
+		diaAuth.DELETE("/benchmarkAssets/:blockchain/:address", diaApiEnv.DeleteBenchmarkAsset)
+		diaAuth.GET("/sync/snapshot/:entity", diaApiEnv.GetSyncSnapshot)
+		diaAuth.GET("/sync/changes/:version", diaApiEnv.GetSyncChanges)
 	}
 
 	diaGroup := r.Group(urlFolderPrefix + "/v1")
@@ -211,8 +244,13 @@ func main() {
 		// Trades and prices endpoints.
 		diaGroup.GET("/quotation/:symbol", cache.CachePageAtomic(memoryStore, cacheTime.CachingTime20Secs, diaApiEnv.GetQuotation))
 		diaGroup.GET("/assetQuotation/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTime20Secs, diaApiEnv.GetAssetQuotation))
+		diaGroup.GET("/quotationEnvelope/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTime20Secs, diaApiEnv.GetQuotationEnvelope))
+		diaGroup.GET("/assetVolumeHistory/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetVolumeHistory))
+		diaGroup.GET("/assetVolumeBreakdown/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetVolumeBreakdown))
 		diaGroup.GET("/lastTradeTime/:exchange/:blockchain/:address", diaApiEnv.GetLastTradeTime)
 		diaGroup.GET("/lastTradesAsset/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetLastTradesAsset))
+		diaGroup.GET("/lastTradesPair/:exchange/:pair", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetLastTradesPair))
+		diaGroup.GET("/tradeGaps/:exchange/:pair", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetTradeGaps))
 
 		// Filters endpoints.
 		diaGroup.GET("/chartPoints/:filter/:exchange/:symbol", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetChartPoints))
@@ -236,10 +274,20 @@ func main() {
 		diaGroup.GET("/poolPriceImpact/:blockchain/:addressPool/:addressAsset/:poolType/:priceDeviation", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetPoolPriceImpact))
 		diaGroup.GET("/priceImpactSimulation/:poolType/:liquidityA/:liquidityB/:priceDeviation", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetPriceImpactSimulation))
 		diaGroup.GET("/poolsByAsset/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetPoolsByAsset))
+		diaGroup.GET("/TVL/:blockchain/:protocol", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetTVL))
+		diaGroup.GET("/TVLByChain", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetTVLByChain))
+		diaGroup.GET("/defiProtocols", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetDefiProtocols))
+		diaGroup.GET("/defiRates/:protocol/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetDefiRates))
+		diaGroup.GET("/pegDeviation/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetPegDeviation))
+		diaGroup.GET("/marketCap/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetMarketCap))
+		diaGroup.GET("/topAssetsByMarketCap", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetTopAssetsByMarketCap))
+		diaGroup.GET("/assetRankHistory/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetRankHistory))
+		diaGroup.GET("/fiatQuotation/:base/:quote", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetFiatQuotation))
 
 		// Pairs endpoints
 		diaGroup.GET("/pairsCex/:exchange", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetExchangePairs))
 		diaGroup.GET("/pairsAssetCex/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetPairs))
+		diaGroup.GET("/pairsAssetCexAt/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetPairsAt))
 
 		// Volume endpoints.
 		diaGroup.GET("/volume24/:exchange", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.Get24hVolume))
@@ -255,9 +303,20 @@ func main() {
 		diaGroup.GET("/availableAssets/:assetClass", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAvailableAssets))
 
 		diaGroup.GET("/missingToken/:exchange", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetMissingExchangeSymbol))
+		diaGroup.GET("/symbolSuggestions/:exchange/:symbol", diaApiEnv.GetSymbolSuggestions)
 		diaGroup.GET("/tokenexchanges/:symbol", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetExchanges))
 
 		diaGroup.GET("/exchanges", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetExchanges))
+
+		diaGroup.GET("/heatmap", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetSectorHeatmap))
+
+		// Edge node synchronization endpoints.
+		diaGroup.POST("/sync/quotations", diaApiEnv.PostSyncQuotations)
+
+		// Token lists in Uniswap token list format.
+		diaGroup.GET("/tokenlist/:blockchain", diaApiEnv.GetTokenList)
+		diaGroup.POST("/tokenlist", diaApiEnv.PostTokenList)
+
 		diaGroup.GET("/NFT/exchanges", cache.CachePageAtomic(memoryStore, cacheTime.CachingTime1Sec, diaApiEnv.GetNFTExchanges))
 
 		diaGroup.GET("/blockchains", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAllBlockchains))
@@ -282,9 +341,11 @@ func main() {
 		// dia.GET("/stockQuotation/:source/:symbol/:time", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetStockQuotation))
 
 		// Endpoints for foreign sources
-		diaGroup.GET("/foreignQuotation/:source/:symbol", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetForeignQuotation))
-		diaGroup.GET("/foreignQuotation/:source/:symbol/:time", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetForeignQuotation))
-		diaGroup.GET("/foreignSymbols/:source", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetForeignSymbols))
+		// Upstream licenses of foreign sources may prohibit distribution in some regions.
+		diaGroup.GET("/foreignQuotation/:source/:symbol", diaApiEnv.RestrictRegions("foreignQuotation", "source"), cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetForeignQuotation))
+		diaGroup.GET("/foreignQuotation/:source/:symbol/:time", diaApiEnv.RestrictRegions("foreignQuotation", "source"), cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetForeignQuotation))
+		diaGroup.GET("/foreignSymbols/:source", diaApiEnv.RestrictRegions("foreignQuotation", "source"), cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetForeignSymbols))
+		diaGroup.GET("/foreignQuotationRange/:source/:symbol", diaApiEnv.RestrictRegions("foreignQuotation", "source"), cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetForeignQuotationRange))
 
 		// Endpoints for customized products
 		diaGroup.GET("/custom/vwapFirefly/:ticker", cache.CachePageAtomic(memoryStore, cacheTime.CachingTime20Secs, diaApiEnv.GetVwapFirefly))
@@ -301,6 +362,7 @@ func main() {
 		diaGroup.GET("/NFTTrades/:blockchain/:address/:id", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTTrades))
 		diaGroup.GET("/NFTTradesCollection/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTTradesCollection))
 		diaGroup.GET("/NFTFloor/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTFloor))
+		diaGroup.GET("/NFTRobustFloor/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTRobustFloor))
 		diaGroup.GET("/NFTFloorMA/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTFloorMA))
 		diaGroup.GET("/NFTDownday/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTDownday))
 		diaGroup.GET("/NFTVolatility/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTFloorVola))
@@ -310,6 +372,13 @@ func main() {
 		diaGroup.GET("/NFTMarketCap/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetNFTMarketCap))
 
 		diaGroup.GET("/assetmap/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetMap))
+		diaGroup.GET("/assetgroup/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetGroup))
+		diaGroup.GET("/assetgroupquotation/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetAssetGroupQuotation))
+		diaGroup.GET("/assetTickers/:blockchain/:address", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetTickers))
+		diaGroup.GET("/tickerAssets/:symbol", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetAssetsByTicker))
+		diaGroup.GET("/benchmarkAssets", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeLong, diaApiEnv.GetBenchmarkAssets))
+		diaGroup.GET("/summaryCounts", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetSummaryCounts))
+		diaGroup.GET("/summaryCounts/:exchange", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetExchangeSummaryCounts))
 		diaGroup.GET("/assetUpdates/:blockchain/:address/:deviation/:frequencySeconds", cache.CachePageAtomic(memoryStore, cacheTime.CachingTimeShort, diaApiEnv.GetAssetUpdates))
 
 		// Endpoints for Synthassets
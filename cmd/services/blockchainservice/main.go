@@ -2,13 +2,18 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 
 	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/dia/helpers/chainlist"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	log "github.com/sirupsen/logrus"
 )
 
+var syncChainlist = flag.Bool("chainlist", false, "add EVM chains from the public chainlist which are not in the blockchain table yet.")
+
 const (
 	configFileBlockchains  = "blockchains/blockchains"
 	configFileExchanges    = "exchanges/exchanges"
@@ -33,6 +38,7 @@ type nftExchangesConfig struct {
 }
 
 func main() {
+	flag.Parse()
 
 	rdb, err := models.NewRelDataStore()
 	if err != nil {
@@ -89,6 +95,68 @@ func main() {
 		}
 	}
 
+	if *syncChainlist {
+		err = addChainlistChains(rdb, utils.Getenv("CHAINLIST_URL", chainlist.CHAINLIST_URL))
+		if err != nil {
+			log.Error("sync chainlist: ", err)
+		}
+	}
+
+}
+
+// addChainlistChains adds all mainnets from the chainlist at @url whose chain ID is not in the
+// blockchain table yet, together with their native token and a public RPC.
+// Existing blockchains are left untouched, as the config files are more complete than the chainlist.
+func addChainlistChains(rdb *models.RelDB, url string) error {
+	chains, err := chainlist.FetchChains(url)
+	if err != nil {
+		return err
+	}
+	blockchains, err := rdb.GetAllBlockchains(false)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]struct{})
+	for _, blockchain := range blockchains {
+		existing[blockchain.Name] = struct{}{}
+		if blockchain.ChainID != "" {
+			existing[blockchain.ChainID] = struct{}{}
+		}
+	}
+
+	for _, chain := range chains {
+		if !chain.IsMainnet() || chain.BlockchainName() == "" {
+			continue
+		}
+		if _, ok := existing[chain.DIAChainID()]; ok {
+			continue
+		}
+		if _, ok := existing[chain.BlockchainName()]; ok {
+			log.Warnf("skip chain %d: blockchain %s exists with another chain ID", chain.ChainID, chain.BlockchainName())
+			continue
+		}
+
+		err = rdb.SetAsset(chain.NativeToken())
+		if err != nil {
+			log.Errorf("set native token of %s: %v", chain.BlockchainName(), err)
+			continue
+		}
+		err = rdb.SetBlockchain(chain.BlockChain())
+		if err != nil {
+			log.Errorf("set blockchain %s: %v", chain.BlockchainName(), err)
+			continue
+		}
+		if chainconfig, ok := chain.ChainConfig(); ok {
+			err = rdb.SetChainConfig(chainconfig)
+			if err != nil {
+				log.Errorf("set chainconfig of %s: %v", chain.BlockchainName(), err)
+			}
+		}
+		existing[chain.DIAChainID()] = struct{}{}
+		existing[chain.BlockchainName()] = struct{}{}
+		log.Infof("added blockchain %s with chain ID %d", chain.BlockchainName(), chain.ChainID)
+	}
+	return nil
 }
 
 func fetchBlockchainsFromConfig() (blockchains []dia.BlockChain, err error) {
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/ethclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// The chain head monitor compares the latest block processed by each on-chain scraper, as reported
+// to the scraperblock table, to the head of the scraped blockchain. The lag is stored in influx and
+// an alert is raised when it exceeds the threshold of the blockchain, given in seconds by the
+// environment variable MAX_LAG_SECONDS_<BLOCKCHAIN> and by MAX_LAG_SECONDS otherwise.
+// Currently only the UniswapV2 scraper and its forks report their processed block, so the lag of
+// all other on-chain scrapers is not monitored.
+// It also checks the health of the RPC endpoints in the blockchain_rpc table, so that clients
+// of the table fail over to the next healthy endpoint.
+
+var (
+	relDB     *models.RelDB
+	datastore *models.DB
+	// clients holds the RPC clients per blockchain.
+	clients = make(map[string]rpcClient)
+)
+
+type rpcClient struct {
+	url    string
+	client *ethclient.Client
+}
+
+func main() {
+	var err error
+	relDB, err = models.NewRelDataStore()
+	if err != nil {
+		log.Fatal("new relational datastore: ", err)
+	}
+	datastore, err = models.NewDataStore()
+	if err != nil {
+		log.Fatal("new datastore: ", err)
+	}
+
+	interval := getenvSeconds("MONITOR_INTERVAL_SECONDS", "60")
+	ticker := time.NewTicker(interval)
+	for ; true; <-ticker.C {
+		monitor()
+	}
+}
+
+// monitor measures and stores the lag of all on-chain scrapers.
+func monitor() {
+	blocks, err := relDB.GetScraperBlocks()
+	if err != nil {
+		log.Error("get processed blocks: ", err)
+		return
+	}
+	checkRPCHealth(blocks)
+	rpcURLs, err := getRPCURLs()
+	if err != nil {
+		log.Error("get rpc urls: ", err)
+		return
+	}
+
+	for _, block := range blocks {
+		client, err := getClient(block.Blockchain, rpcURLs)
+		if err != nil {
+			log.Errorf("rpc client for %s: %v", block.Blockchain, err)
+			continue
+		}
+		lag, err := measureLag(client, block)
+		if err != nil {
+			log.Errorf("measure lag of %s on %s: %v", block.Scraper, block.Blockchain, err)
+			// Redial on the next run, possibly to another endpoint.
+			client.Close()
+			delete(clients, block.Blockchain)
+			continue
+		}
+		maxLag := getenvSeconds("MAX_LAG_SECONDS_"+strings.ToUpper(block.Blockchain), utils.Getenv("MAX_LAG_SECONDS", "300"))
+		if lag.LagSeconds > maxLag.Seconds() {
+			lag.Alert = true
+			log.Errorf("ALERT: %s on %s is %d blocks (%v) behind chain head %d", block.Scraper, block.Blockchain, lag.LagBlocks, time.Duration(lag.LagSeconds)*time.Second, lag.HeadBlock)
+		} else {
+			log.Infof("%s on %s is %d blocks (%v) behind chain head %d", block.Scraper, block.Blockchain, lag.LagBlocks, time.Duration(lag.LagSeconds)*time.Second, lag.HeadBlock)
+		}
+		err = datastore.SetScraperLag(lag)
+		if err != nil {
+			log.Error("set scraper lag: ", err)
+		}
+	}
+
+	err = datastore.Flush()
+	if err != nil {
+		log.Error("flush influx batch: ", err)
+	}
+}
+
+// measureLag returns the lag of the processed @block to the chain head.
+func measureLag(client *ethclient.Client, block models.ScraperBlock) (lag models.ScraperLag, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return
+	}
+	processed, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.BlockNumber))
+	if err != nil {
+		return
+	}
+
+	lag = models.ScraperLag{
+		Scraper:        block.Scraper,
+		Blockchain:     block.Blockchain,
+		ProcessedBlock: block.BlockNumber,
+		HeadBlock:      head.Number.Uint64(),
+		LagBlocks:      head.Number.Int64() - int64(block.BlockNumber),
+		LagSeconds:     float64(head.Time) - float64(processed.Time),
+		Time:           time.Now(),
+	}
+	if lag.LagSeconds < 0 {
+		lag.LagSeconds = 0
+	}
+	return
+}
+
+// checkRPCHealth checks all registered RPC endpoints of the blockchains of @blocks and stores their health.
+func checkRPCHealth(blocks []models.ScraperBlock) {
+	checked := make(map[string]struct{})
+	for _, block := range blocks {
+		if _, ok := checked[block.Blockchain]; ok {
+			continue
+		}
+		checked[block.Blockchain] = struct{}{}
+
+		rpcs, err := relDB.GetBlockchainRPCs(block.Blockchain)
+		if err != nil {
+			log.Errorf("get rpcs of %s: %v", block.Blockchain, err)
+			continue
+		}
+		for _, rpc := range rpcs {
+			err = pingRPC(rpc.RestURL)
+			if err != nil {
+				log.Warnf("rpc %s of %s is unhealthy: %v", rpc.RestURL, rpc.Blockchain, err)
+			}
+			err = relDB.SetBlockchainRPCHealth(rpc.Blockchain, rpc.RestURL, err == nil)
+			if err != nil {
+				log.Error("set rpc health: ", err)
+			}
+		}
+	}
+}
+
+// pingRPC returns an error if the head block cannot be fetched from @url.
+func pingRPC(url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	_, err = client.BlockNumber(ctx)
+	return err
+}
+
+// getRPCURLs returns the RPC url of each EVM blockchain. The url can be set by the environment
+// variable <BLOCKCHAIN>_URI_REST. Otherwise, the healthy endpoint with the highest priority in the
+// blockchain_rpc table is used, falling back to the chain config of the blockchain registry.
+// The chain configs are keyed by numeric chain id, while the ChainID of a blockchain is prefixed by Ethereum.
+func getRPCURLs() (map[string]string, error) {
+	blockchains, err := relDB.GetAllBlockchains(false)
+	if err != nil {
+		return nil, err
+	}
+	chainConfigs, err := relDB.GetAllChainConfig()
+	if err != nil {
+		return nil, err
+	}
+	restURLs := make(map[string]string)
+	for _, chainConfig := range chainConfigs {
+		restURLs[chainConfig.ChainID] = chainConfig.RestURL
+	}
+
+	rpcURLs := make(map[string]string)
+	for _, blockchain := range blockchains {
+		rpcURL := restURLs[strings.TrimPrefix(blockchain.ChainID, "Ethereum")]
+		if rpc, err := relDB.GetHealthyRPC(blockchain.Name); err == nil {
+			rpcURL = rpc.RestURL
+		}
+		rpcURLs[blockchain.Name] = utils.Getenv(strings.ToUpper(blockchain.Name)+"_URI_REST", rpcURL)
+	}
+	return rpcURLs, nil
+}
+
+// getClient returns a client of @blockchain. The client is replaced when the url of the blockchain changes.
+func getClient(blockchain string, rpcURLs map[string]string) (*ethclient.Client, error) {
+	url := rpcURLs[blockchain]
+	if cached, ok := clients[blockchain]; ok && cached.url == url {
+		return cached.client, nil
+	}
+	if url == "" {
+		return nil, errors.New("no rpc url in blockchain registry")
+	}
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := clients[blockchain]; ok {
+		cached.client.Close()
+	}
+	clients[blockchain] = rpcClient{url: url, client: client}
+	return client, nil
+}
+
+// getenvSeconds returns the duration given in seconds by the environment variable @key.
+func getenvSeconds(key string, defaultSeconds string) time.Duration {
+	seconds, err := strconv.ParseInt(utils.Getenv(key, defaultSeconds), 10, 64)
+	if err != nil {
+		log.Errorf("parse %s: %v", key, err)
+		seconds, _ = strconv.ParseInt(defaultSeconds, 10, 64)
+	}
+	return time.Duration(seconds) * time.Second
+}
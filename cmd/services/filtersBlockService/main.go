@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/segmentio/kafka-go"
 	log "github.com/sirupsen/logrus"
 )
@@ -25,6 +27,8 @@ var (
 	fbsDoneWriter         *kafka.Writer
 )
 
+const benchmarksRefreshInterval = 10 * time.Minute
+
 func init() {
 	flag.Parse()
 	log.Println("replayInflux=", *replayInflux)
@@ -62,6 +66,8 @@ func main() {
 		channel := make(chan *dia.FiltersBlock)
 
 		f := filters.NewFiltersBlockService(loadFilterPointsFromPreviousBlock(), s, channel)
+		go updateBenchmarks(f)
+		reloadConfig(f)
 
 		w := kafkaHelper.NewSyncWriterWithCompression(filtersBlockTopic)
 
@@ -124,6 +130,51 @@ func main() {
 	}
 }
 
+// reloadConfig applies the configuration of filtersBlockService stored in postgres on startup, on SIGHUP
+// and every CONFIG_RELOAD_SECONDS. Invalid configurations are logged and not applied.
+func reloadConfig(f *filters.FiltersBlockService) {
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Error("config reload disabled. NewRelDataStore: ", err)
+		return
+	}
+	seconds, err := strconv.Atoi(utils.Getenv("CONFIG_RELOAD_SECONDS", "300"))
+	if err != nil {
+		log.Error("parse CONFIG_RELOAD_SECONDS: ", err)
+	}
+	reload := func() {
+		values, err := relDB.GetServiceConfig("filtersBlockService")
+		if err != nil {
+			log.Error("get service config: ", err)
+			return
+		}
+		err = f.ReloadConfig(values)
+		if err != nil {
+			log.Error("reject service config: ", err)
+		}
+	}
+	reload()
+	utils.OnReload(time.Duration(seconds)*time.Second, reload)
+}
+
+// updateBenchmarks periodically loads the benchmark asset definitions into @f.
+func updateBenchmarks(f *filters.FiltersBlockService) {
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Error("NewRelDataStore: ", err)
+		return
+	}
+	for {
+		benchmarks, err := relDB.GetBenchmarkAssets()
+		if err != nil {
+			log.Error("get benchmark assets: ", err)
+		} else {
+			f.SetBenchmarks(benchmarks)
+		}
+		time.Sleep(benchmarksRefreshInterval)
+	}
+}
+
 func handler(channel chan *dia.FiltersBlock, wg *sync.WaitGroup, w *kafka.Writer) {
 	var block int
 	for {
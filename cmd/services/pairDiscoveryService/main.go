@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	scrapers "github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers"
+	listingscrapers "github.com/diadata-org/diadata/pkg/dia/scraper/listing-scrapers"
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/configCollectors"
@@ -20,7 +22,7 @@ var (
 	log      *logrus.Logger
 	exchange string
 	exch     = flag.String("exchange", "", "which exchange")
-	mode     = flag.String("mode", "verification", "verification or remoteFetch: fetching pairs from exchange's API.")
+	mode     = flag.String("mode", "verification", "verification, remoteFetch: fetching pairs from exchange's API, reconcile: syncing listed pairs with exchange's API, snapshot: recording the verified pairs or listings: storing listing announcements.")
 )
 
 func init() {
@@ -56,6 +58,24 @@ func main() {
 		if err != nil {
 			log.Fatalf("update exchange pairs for %s: %v", exchange, err)
 		}
+	case "reconcile":
+		err = reconcileExchangePairs(relDB)
+		if err != nil {
+			log.Fatalf("reconcile exchange pairs for %s: %v", exchange, err)
+		}
+	case "snapshot":
+		opened, closed, err := relDB.SnapshotExchangePairs(time.Now())
+		if err != nil {
+			log.Fatal("snapshot exchange pairs: ", err)
+		}
+		log.Infof("snapshot of exchange pairs: %d pairs added, %d pairs removed.", opened, closed)
+	case "listings":
+		// Runs until the listing scraper is closed.
+		scraper := listingscrapers.NewListingScraper(exchange, relDB)
+		if scraper == nil {
+			log.Fatalf("no listing scraper for %s", exchange)
+		}
+		listingscrapers.HandleListings(scraper, relDB)
 	default:
 		log.Fatal("unknown mode.")
 	}
@@ -79,6 +99,13 @@ func updateExchangePairs(relDB *models.RelDB) (err error) {
 		log.Errorf("adding pairs from config file for exchange %s: %v", exchange, err)
 	}
 
+	// Add announced markets, such that they are verified before trades appear.
+	listings, err := relDB.GetUpcomingListings(exchange)
+	if err != nil {
+		log.Errorf("get upcoming listings for exchange %s: %v", exchange, err)
+	}
+	pairs = dia.AddListedPairs(pairs, listings)
+
 	// --------- Verify all pairs collected above ---------
 
 	// Extract list of symbols from pairs.
@@ -103,6 +130,7 @@ func updateExchangePairs(relDB *models.RelDB) (err error) {
 	setGitcoinSymbols(gitcoinSymbols, relDB)
 
 	// Verify/falsify exchange pairs using the exchangesymbol table in postgres.
+	var updatedPairs []dia.ExchangePair
 	for _, pair := range pairs {
 		var exchangepair dia.ExchangePair
 		var pairSymbols []string
@@ -156,11 +184,19 @@ func updateExchangePairs(relDB *models.RelDB) (err error) {
 			pair.Verified = true
 		}
 
-		// Set pair to postgres and redis cache.
-		err = relDB.SetExchangePair(exchange, pair, true)
+		updatedPairs = append(updatedPairs, pair)
+	}
+
+	// Set pairs to postgres in bulk and to redis cache.
+	err = relDB.SetExchangePairs(exchange, updatedPairs)
+	if err != nil {
+		log.Errorf("setting exchangepair table for pairs on exchange %s: %v", exchange, err)
+		return err
+	}
+	for _, pair := range updatedPairs {
+		err = relDB.SetExchangePairCache(exchange, pair)
 		if err != nil {
-			log.Errorf("setting exchangepair table for pair on exchange %s: %v", exchange, err)
-			return err
+			log.Errorf("setting pair %s to redis for exchange %s: %v", pair.ForeignName, exchange, err)
 		}
 	}
 
@@ -199,6 +235,36 @@ func fetchFromExchangeAndStore(relDB *models.RelDB) error {
 	return nil
 }
 
+// reconcileExchangePairs lists the pairs currently available on the exchange's API and delists
+// pairs which are not available anymore.
+func reconcileExchangePairs(relDB *models.RelDB) error {
+	config, err := dia.GetConfig(exchange)
+	if err != nil {
+		log.Info("No valid API config for exchange: ", exchange, " Error: ", err.Error())
+		config = &dia.ConfigApi{}
+	}
+	scraper := scrapers.NewAPIScraper(exchange, false, config.ApiKey, config.SecretKey, relDB)
+
+	pairs, err := scraper.FetchAvailablePairs()
+	if err != nil {
+		return err
+	}
+	// Sandbox, testnet and demo markets are stored as excluded and never verified.
+	for _, pair := range pairs {
+		if class := dia.ClassifyMarket(exchange, pair.ForeignName); class != dia.MARKET_CLASS_LIVE {
+			log.Infof("exclude %s market %s on %s.", class, pair.ForeignName, exchange)
+		}
+	}
+	report, err := relDB.ReconcileExchangePairs(exchange, pairs)
+	if err != nil {
+		return err
+	}
+	log.Infof("reconciled %s: %d pairs added, %d pairs delisted, %d pairs unchanged.", exchange, len(report.Added), len(report.Delisted), report.Unchanged)
+	log.Infof("added pairs: %v", report.Added)
+	log.Infof("delisted pairs: %v", report.Delisted)
+	return nil
+}
+
 // addPairsFromConfig adds pairs from the config file to @pairs, if not in there yet.
 // Equality refers to the unique identifier (exchange,foreignName).
 func addPairsFromConfig(exchange string, pairs []dia.ExchangePair) ([]dia.ExchangePair, error) {
@@ -273,12 +339,23 @@ func setGitcoinSymbols(submissions GitcoinSubmission, relDB *models.RelDB) {
 			continue
 		}
 		// Write into exchangesymbol table
-		success, err := relDB.VerifyExchangeSymbol(submission.Exchange, submission.Symbol, assetID)
+		success, err := relDB.VerifyExchangeSymbol(submission.Exchange, submission.Symbol, assetID, "gitcoin")
 		if err != nil || !success {
 			errorCount++
 			log.Errorf("verify symbol %s on %s: %v", submission.Symbol, submission.Exchange, err)
 			continue
 		}
+		// Gitcoin submissions are manually reviewed, so they serve as labeled examples for verification suggestions.
+		err = relDB.SetSymbolLabel(models.SymbolLabel{
+			Exchange: submission.Exchange,
+			Symbol:   submission.Symbol,
+			Asset:    asset,
+			Accepted: true,
+			Reviewer: "gitcoin",
+		})
+		if err != nil {
+			log.Errorf("set symbol label %s on %s: %v", submission.Symbol, submission.Exchange, err)
+		}
 	}
 	log.Warnf("could not integrate %v out of %v gitcoin verified symbols.", errorCount, len(submissions.AllItems))
 }
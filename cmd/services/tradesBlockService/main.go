@@ -3,12 +3,15 @@ package main
 import (
 	"context"
 	"flag"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/diadata-org/diadata/internal/pkg/tradesBlockService"
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/kafkaHelper"
 	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/segmentio/kafka-go"
 	log "github.com/sirupsen/logrus"
 )
@@ -79,6 +82,9 @@ func main() {
 	}
 
 	service := tradesBlockService.NewTradesBlockService(s, dia.BlockSizeSeconds, *historical)
+	if !*historical {
+		reloadConfig(service)
+	}
 
 	wg := sync.WaitGroup{}
 	go handleBlocks(service, &wg, kafkaWriter)
@@ -100,3 +106,30 @@ func main() {
 		}
 	}
 }
+
+// reloadConfig applies the configuration of tradesBlockService stored in postgres on startup, on SIGHUP
+// and every CONFIG_RELOAD_SECONDS. Invalid configurations are logged and not applied.
+func reloadConfig(service *tradesBlockService.TradesBlockService) {
+	relDB, err := models.NewRelDataStore()
+	if err != nil {
+		log.Error("config reload disabled. NewRelDataStore: ", err)
+		return
+	}
+	seconds, err := strconv.Atoi(utils.Getenv("CONFIG_RELOAD_SECONDS", "300"))
+	if err != nil {
+		log.Error("parse CONFIG_RELOAD_SECONDS: ", err)
+	}
+	reload := func() {
+		values, err := relDB.GetServiceConfig("tradesBlockService")
+		if err != nil {
+			log.Error("get service config: ", err)
+			return
+		}
+		err = service.ReloadConfig(values)
+		if err != nil {
+			log.Error("reject service config: ", err)
+		}
+	}
+	reload()
+	utils.OnReload(time.Duration(seconds)*time.Second, reload)
+}
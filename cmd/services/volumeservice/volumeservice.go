@@ -16,6 +16,16 @@ var (
 
 const (
 	lookbackDays = 2
+	// groupQuotationMinutes is the frequency of the aggregate quotations of cross-chain asset groups.
+	groupQuotationMinutes = 5
+	// summaryCountMinutes is the frequency of the recount of assets and pairs served by the API.
+	summaryCountMinutes = 10
+	// nftFloorMinutes is the frequency of the robust floor price computation of traded nft collections.
+	nftFloorMinutes = 30
+	// pegDeviationMinutes is the frequency of the peg deviation computation of stablecoins.
+	pegDeviationMinutes = 15
+	// downsampleBackfillDays is the number of days of volume filters downsampled at startup.
+	downsampleBackfillDays = 30
 )
 
 func main() {
@@ -30,6 +40,8 @@ func main() {
 		log.Errorln("NewRelDataStore:", err)
 	}
 
+	installDownsampledSeries()
+
 	// initial run.
 	fetchAndUpdateVolume()
 
@@ -38,6 +50,22 @@ func main() {
 	if err != nil {
 		log.Error("schedule cron job: ", err)
 	}
+	err = s.Every(groupQuotationMinutes).Minutes().Do(updateGroupQuotations)
+	if err != nil {
+		log.Error("schedule group quotation job: ", err)
+	}
+	err = s.Every(summaryCountMinutes).Minutes().Do(refreshSummaryCounts)
+	if err != nil {
+		log.Error("schedule summary count job: ", err)
+	}
+	err = s.Every(nftFloorMinutes).Minutes().Do(updateNFTFloorPrices)
+	if err != nil {
+		log.Error("schedule nft floor price job: ", err)
+	}
+	err = s.Every(pegDeviationMinutes).Minutes().Do(updatePegDeviations)
+	if err != nil {
+		log.Error("schedule peg deviation job: ", err)
+	}
 	<-s.Start()
 
 }
@@ -50,16 +78,116 @@ func fetchAndUpdateVolume() {
 	}
 	log.Infoln("Total Assets: ", totalAssets)
 
+	report, err := models.RecomputeAssetVolumes(datastore, relDB, models.VolumeRecomputeOptions{Assets: totalAssets})
+	if err != nil {
+		log.Error("recompute asset volumes: ", err)
+	}
+	log.Infof("updated volumes of %d/%d assets", report.Updated, report.Assets)
+
 	for _, asset := range totalAssets {
-		volume, err := datastore.Get24HoursAssetVolume(asset)
+		exchangeVolumes, err := datastore.GetVolumesAllExchanges(asset, time.Now().AddDate(0, 0, -1), time.Now())
 		if err != nil {
-			log.Errorf("get volume of asset %s, %s", asset.Symbol, err.Error())
-		} else {
-			err = relDB.SetAssetVolume24H(asset, *volume, time.Now())
+			log.Errorf("get volumes per exchange of asset %s: %v", asset.Symbol, err)
+			continue
+		}
+		for _, exchangeVolume := range exchangeVolumes.Volumes {
+			err = relDB.SetAssetVolumeByExchange(asset, exchangeVolume.Exchange, exchangeVolume.Volume)
+			if err != nil {
+				log.Error("set asset volume by exchange: ", err)
+			}
+		}
+	}
+
+}
+
+// installDownsampledSeries installs the continuous queries of the downsampled volume series and backfills
+// the last downsampleBackfillDays days, such that volume ranges of the API can be read from them.
+func installDownsampledSeries() {
+	err := datastore.InstallContinuousQueries(models.DefaultDownsampleSeries)
+	if err != nil {
+		log.Error("install continuous queries: ", err)
+		return
+	}
+	// The backfill ends at the bucket the continuous query is computing, such that the covered ranges are contiguous.
+	starttime := time.Now().Truncate(24*time.Hour).AddDate(0, 0, -downsampleBackfillDays)
+	for _, series := range models.DefaultDownsampleSeries {
+		endtime := time.Now().Truncate(series.Interval)
+		err = datastore.Downsample(series, starttime, endtime)
+		if err != nil {
+			log.Errorf("backfill downsampled series %s: %v", series.Measurement, err)
+		}
+	}
+}
+
+// updateGroupQuotations stores the volume weighted aggregate quotation of each cross-chain asset group.
+func updateGroupQuotations() {
+	groups, err := relDB.GetAssetGroups()
+	if err != nil {
+		log.Error("get asset groups: ", err)
+		return
+	}
+
+	for groupID, assetVolumes := range groups {
+		var (
+			quotations []models.AssetQuotation
+			volumes    []float64
+		)
+		for _, assetVolume := range assetVolumes {
+			quotation, err := datastore.GetAssetQuotationLatest(assetVolume.Asset)
 			if err != nil {
-				log.Error("set asset volume: ", err)
+				log.Warnf("get quotation of %s on %s: %v", assetVolume.Asset.Symbol, assetVolume.Asset.Blockchain, err)
+				continue
 			}
+			quotations = append(quotations, *quotation)
+			volumes = append(volumes, assetVolume.Volume)
+		}
+		groupQuotation, err := models.AggregateAssetGroupQuotation(groupID, quotations, volumes)
+		if err != nil {
+			log.Warn("aggregate group quotation: ", err)
+			continue
+		}
+		err = datastore.SetAssetGroupQuotation(&groupQuotation)
+		if err != nil {
+			log.Error("set group quotation: ", err)
 		}
 	}
 
+	err = datastore.ExecuteRedisPipe()
+	if err != nil {
+		log.Error("execute redis pipe: ", err)
+	}
+	err = datastore.Flush()
+	if err != nil {
+		log.Error("flush influx batch: ", err)
+	}
+}
+
+// refreshSummaryCounts recounts active assets and listed pairs, such that API reads are served from the caches.
+func refreshSummaryCounts() {
+	counts, err := relDB.RefreshSummaryCounts()
+	if err != nil {
+		log.Error("refresh summary counts: ", err)
+		return
+	}
+	log.Infof("summary counts: %d assets, %d pairs on %d exchanges", counts.Assets, counts.Pairs, len(counts.Exchanges))
+}
+
+// updateNFTFloorPrices stores the robust floor prices of all recently traded nft collections.
+func updateNFTFloorPrices() {
+	updated, err := relDB.UpdateNFTFloorPrices()
+	if err != nil {
+		log.Error("update nft floor prices: ", err)
+		return
+	}
+	log.Infof("stored %d nft floor prices", updated)
+}
+
+// updatePegDeviations stores the peg deviations of stablecoins.
+func updatePegDeviations() {
+	updated, err := datastore.UpdatePegDeviations()
+	if err != nil {
+		log.Error("update peg deviations: ", err)
+		return
+	}
+	log.Infof("stored %d peg deviations", updated)
 }
@@ -16,6 +16,53 @@ CREATE TABLE asset (
     UNIQUE (address, blockchain)
 );
 
+-- asset_history records all insertions and updates of assets together with the previous values.
+-- asset_id is not a foreign key so that the history outlives deleted assets.
+CREATE TABLE asset_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    asset_id UUID NOT NULL,
+    operation text NOT NULL,
+    old_symbol text,
+    old_name text,
+    old_decimals text,
+    new_symbol text,
+    new_name text,
+    new_decimals text,
+    actor text,
+    change_time timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX asset_history_asset_id ON asset_history (asset_id);
+
+-- pendingasset is the staging area for assets discovered by scrapers. Pending assets are
+-- promoted to the asset table once they are approved, either by a reviewer or by an auto-approval rule.
+CREATE TABLE pendingasset (
+    pending_id UUID DEFAULT gen_random_uuid(),
+    symbol text NOT NULL,
+    name text NOT NULL,
+    decimals text,
+    blockchain text,
+    address text NOT NULL,
+    source text,
+    discovered_time timestamp NOT NULL DEFAULT NOW(),
+    status text NOT NULL DEFAULT 'pending',
+    reviewer text,
+    review_time timestamp,
+    note text,
+    UNIQUE (pending_id),
+    UNIQUE (address, blockchain)
+);
+
+-- Deprecated assets (e.g. migrated or rugged tokens) are excluded from price feeds.
+-- Their historical data is retained.
+ALTER TABLE asset ADD COLUMN deprecated_at timestamp;
+
+-- Indices for the full-text search of assets.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX asset_search_tsv_idx ON asset USING GIN (to_tsvector('simple', symbol || ' ' || name || ' ' || address));
+CREATE INDEX asset_symbol_trgm_idx ON asset USING GIN (symbol gin_trgm_ops);
+CREATE INDEX asset_name_trgm_idx ON asset USING GIN (name gin_trgm_ops);
+
 -- Table exchangepair holds all trading pairs for the pair scrapers.
 -- The format has to be the same as emitted by the exchange's API in order
 -- for the pair scrapers to be able to scrape trading data from the API.
@@ -32,6 +79,21 @@ CREATE TABLE exchangepair (
     id_basetoken UUID REFERENCES asset(asset_id)
 );
 
+-- exchangepairhistory records the verified exchangepairs over time. A row is the validity
+-- interval [valid_from,valid_to) of a pair with the given tokens. Open intervals have valid_to NULL.
+CREATE TABLE exchangepairhistory (
+    exchange text NOT NULL,
+    foreignname text NOT NULL,
+    symbol text,
+    id_quotetoken UUID REFERENCES asset(asset_id),
+    id_basetoken UUID REFERENCES asset(asset_id),
+    valid_from timestamp NOT NULL,
+    valid_to timestamp
+);
+
+CREATE INDEX exchangepairhistory_quotetoken ON exchangepairhistory (id_quotetoken, valid_from);
+CREATE INDEX exchangepairhistory_basetoken ON exchangepairhistory (id_basetoken, valid_from);
+
 CREATE TABLE exchangesymbol (
     exchangesymbol_id UUID DEFAULT gen_random_uuid(),
     symbol text NOT NULL,
@@ -41,6 +103,88 @@ CREATE TABLE exchangesymbol (
     asset_id UUID REFERENCES asset(asset_id)
 );
 
+-- Delisted pairs and symbols are kept for reference but no longer scraped or verified.
+ALTER TABLE exchangepair ADD COLUMN delisted_at timestamp;
+ALTER TABLE exchangesymbol ADD COLUMN delisted_at timestamp;
+
+-- excluded marks sandbox, testnet and demo markets. Excluded pairs are never verified,
+-- so that their trades do not contribute to prices.
+ALTER TABLE exchangepair ADD COLUMN excluded boolean NOT NULL DEFAULT false;
+
+-- firstseen and lastseen record the first and the latest sighting of a symbol on its exchange.
+ALTER TABLE exchangesymbol ADD COLUMN firstseen timestamp;
+ALTER TABLE exchangesymbol ADD COLUMN lastseen timestamp;
+
+-- Symbols are stored as emitted by exchanges and looked up case insensitively.
+CREATE INDEX exchangesymbol_lower_symbol ON exchangesymbol (exchange, LOWER(symbol));
+
+-- symbolverificationlabel records manual verification decisions of exchange symbols.
+-- They serve as labeled examples for the scorer of verification suggestions.
+CREATE TABLE symbolverificationlabel (
+    label_id BIGSERIAL PRIMARY KEY,
+    exchange text NOT NULL,
+    symbol text NOT NULL,
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    accepted boolean NOT NULL,
+    reviewer text,
+    label_time timestamp NOT NULL DEFAULT NOW()
+);
+
+-- symbolverificationhistory records each verification and unverification of an exchange symbol
+-- together with the previously mapped asset, the actor and the candidate list the asset was chosen from.
+CREATE TABLE symbolverificationhistory (
+    history_id BIGSERIAL PRIMARY KEY,
+    exchange text NOT NULL,
+    symbol text NOT NULL,
+    operation text NOT NULL,
+    old_asset_id UUID,
+    new_asset_id UUID,
+    actor text,
+    source text,
+    change_time timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX symbolverificationhistory_symbol ON symbolverificationhistory (exchange, LOWER(symbol));
+
+-- assetalias maps alternative names under which exchanges emit an asset, such as
+-- 'Wrapped Bitcoin' for WBTC, to the canonical asset.
+CREATE TABLE assetalias (
+    alias text NOT NULL,
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    UNIQUE (alias, asset_id)
+);
+
+CREATE INDEX assetalias_lower_alias_idx ON assetalias (LOWER(alias));
+
+-- assetticker records former symbol tickers of an asset, such as MATIC for POL, together with
+-- the period in which they were in use. The current ticker is the symbol in table asset.
+CREATE TABLE assetticker (
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    symbol text NOT NULL,
+    valid_from timestamp,
+    valid_to timestamp NOT NULL,
+    UNIQUE (asset_id, symbol, valid_to)
+);
+
+CREATE INDEX assetticker_symbol_idx ON assetticker (symbol);
+
+-- assetgroup links assets on different blockchains which represent the same logical asset,
+-- such as USDC on Ethereum and on Polygon. Each asset belongs to at most one group.
+CREATE TABLE assetgroup (
+    group_id UUID NOT NULL,
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    UNIQUE (asset_id)
+);
+
+CREATE INDEX assetgroup_group_id_idx ON assetgroup (group_id);
+
+-- symbolcasing overrides the canonical casing of symbols such as cDAI or stETH.
+-- Symbols without an entry are canonically written in upper case.
+CREATE TABLE symbolcasing (
+    symbol_lower text PRIMARY KEY,
+    canonical text NOT NULL
+);
+
 CREATE TABLE exchange (
     exchange_id UUID DEFAULT gen_random_uuid(),
     name text NOT NULL,
@@ -57,6 +201,24 @@ CREATE TABLE exchange (
     UNIQUE (name)
 );
 
+-- exchangelisting stores listing and delisting announcements of exchanges.
+-- asset_id is null as long as the announced asset cannot be identified.
+-- effective_time is null if the exchange does not announce it. Such announcements
+-- are stored once per market and listing type.
+CREATE TABLE exchangelisting (
+    exchangelisting_id UUID DEFAULT gen_random_uuid(),
+    exchange text NOT NULL,
+    foreignname text NOT NULL,
+    symbol text,
+    asset_id UUID REFERENCES asset(asset_id),
+    listing_type text NOT NULL,
+    announced_time timestamp,
+    effective_time timestamp,
+    source text,
+    UNIQUE(exchangelisting_id),
+    UNIQUE NULLS NOT DISTINCT (exchange, foreignname, listing_type, effective_time)
+);
+
 CREATE TABLE pool (
     pool_id UUID DEFAULT gen_random_uuid(),
     exchange text NOT NULL,
@@ -78,6 +240,43 @@ CREATE TABLE poolasset (
     UNIQUE(pool_id,asset_id)
 );
 
+-- weight is the normalized share of an asset in weighted pools such as Balancer pools.
+-- It is NULL for pools without weights, such as constant product pools.
+ALTER TABLE poolasset ADD COLUMN weight numeric;
+
+-- exchangepairpool links DEX exchangepairs to their underlying pools, such that trades
+-- stored by pair can be joined to on-chain liquidity. A pair can trade on several pools.
+CREATE TABLE exchangepairpool (
+    exchange text NOT NULL,
+    foreignname text NOT NULL,
+    pool_id UUID REFERENCES pool(pool_id) ON DELETE CASCADE NOT NULL,
+    FOREIGN KEY (foreignname, exchange) REFERENCES exchangepair(foreignname, exchange) ON DELETE CASCADE,
+    UNIQUE (exchange, foreignname, pool_id)
+);
+
+CREATE INDEX exchangepairpool_pool ON exchangepairpool (pool_id);
+
+-- tvl holds the total value locked in USD of DeFi protocols per blockchain over time.
+CREATE TABLE tvl (
+    protocol text NOT NULL,
+    blockchain text NOT NULL,
+    tvl_usd numeric NOT NULL,
+    time_stamp timestamp NOT NULL,
+    UNIQUE (protocol, blockchain, time_stamp)
+);
+
+CREATE INDEX tvl_time ON tvl (time_stamp);
+
+-- defiprotocol holds the metadata of DeFi lending protocols. Their supply and borrow
+-- rates are stored in influx.
+CREATE TABLE defiprotocol (
+    name text NOT NULL,
+    blockchain text NOT NULL,
+    address text NOT NULL,
+    url text,
+    UNIQUE (name)
+);
+
 CREATE TABLE chainconfig (
     chain_config_id UUID DEFAULT gen_random_uuid(),
     rpcurl text NOT NULL,
@@ -94,16 +293,111 @@ CREATE TABLE blockchain (
     nativetoken_id UUID REFERENCES asset(asset_id),
 	verificationmechanism text,
     chain_id text,
+    is_testnet boolean NOT NULL DEFAULT false,
+    -- chain_type is the chain family such as evm, utxo or cosmos.
+    chain_type text,
     UNIQUE(blockchain_id),
     UNIQUE(name)
 );
 
+-- blockchain_rpc holds the RPC endpoints of a blockchain. Endpoints with lower priority values are
+-- preferred. healthy is maintained by the chainHeadMonitor, so that clients can fail over.
+CREATE TABLE blockchain_rpc (
+    blockchain text REFERENCES blockchain(name) NOT NULL,
+    resturl text NOT NULL,
+    wsurl text,
+    priority integer NOT NULL DEFAULT 0,
+    healthy boolean NOT NULL DEFAULT true,
+    last_checked timestamp,
+    UNIQUE (blockchain, resturl)
+);
+
+-- assetmetadata holds descriptive information such as logo and project website of an asset.
+-- social_links maps a platform name to the corresponding url.
+-- display_tick_size and display_max_decimals are optional rules for rounding displayed prices.
+CREATE TABLE assetmetadata (
+    asset_id UUID primary key REFERENCES asset(asset_id),
+    logo text,
+    website text,
+    description text,
+    social_links jsonb,
+    tags text[],
+    display_tick_size numeric,
+    display_max_decimals integer,
+    last_update timestamp
+);
+
+-- tokenlistversion holds the latest version of each exported token list. list_key identifies
+-- the list by name and blockchain or curated assets, tokens are the listed tokens of the version.
+CREATE TABLE tokenlistversion (
+    list_key text PRIMARY KEY,
+    major integer NOT NULL,
+    minor integer NOT NULL,
+    patch integer NOT NULL,
+    tokens jsonb NOT NULL,
+    time_stamp timestamp NOT NULL
+);
+
+-- quoteassetconstraint restricts the quote assets (i.e. basetokens of trades) which are
+-- accepted for price computation of an asset. Assets without an entry accept all quote assets.
+CREATE TABLE quoteassetconstraint (
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    quoteasset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    UNIQUE(asset_id, quoteasset_id)
+);
+
 CREATE TABLE assetvolume (
     asset_id UUID primary key,
     volume decimal,
     time_stamp timestamp
 );
 
+-- volume is denominated in the asset itself, volume_usd in USD. The native volume is derived
+-- from volume_usd with the price at price_timestamp. Rows written before volume_usd existed hold
+-- USD in volume and are migrated accordingly.
+ALTER TABLE assetvolume ADD COLUMN volume_usd decimal;
+ALTER TABLE assetvolume ADD COLUMN price_timestamp timestamp;
+UPDATE assetvolume SET volume_usd=volume, volume=NULL WHERE volume_usd IS NULL;
+
+-- supply holds the total and circulating supply of an asset over time. It mirrors the
+-- supplies in influx, so that market caps can be joined with assetvolume.
+CREATE TABLE supply (
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    circulating decimal,
+    total decimal,
+    source text,
+    time_stamp timestamp NOT NULL,
+    UNIQUE (asset_id, time_stamp)
+);
+
+-- assetvolume_history keeps the 24h volume of an asset per day, whereas assetvolume
+-- only holds the latest figure.
+CREATE TABLE assetvolume_history (
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    date date NOT NULL,
+    volume decimal,
+    UNIQUE (asset_id, date)
+);
+
+-- assetrank keeps the daily rank of an asset by 24h volume and by market cap.
+-- Ranks are NULL on days the asset was not ranked by the respective measure.
+CREATE TABLE assetrank (
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    date date NOT NULL,
+    volume_rank integer,
+    marketcap_rank integer,
+    UNIQUE (asset_id, date)
+);
+
+-- assetvolume_exchange holds the latest 24h volume of an asset per exchange.
+CREATE TABLE assetvolume_exchange (
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    exchange text NOT NULL,
+    volume decimal,
+    time_stamp timestamp,
+    UNIQUE (asset_id, exchange)
+);
+
 ---------------------------------------
 ------- tables for NFT storage --------
 ---------------------------------------
@@ -141,6 +435,16 @@ CREATE TABLE historicalquotation (
     UNIQUE(historicalquotation_id)
 );
 
+-- historicalquotationarchive is the cold tier of historicalquotation. Quotations older than the
+-- archive horizon are moved into one gzip compressed row per asset and month.
+CREATE TABLE historicalquotationarchive (
+    asset_id UUID REFERENCES asset(asset_id) NOT NULL,
+    month date NOT NULL,
+    num_quotations integer NOT NULL,
+    data bytea NOT NULL,
+    UNIQUE (asset_id, month)
+);
+
 -- an element from nft is a specific non-fungible nft, unqiuely
 -- identified by the pair (address(on blockchain), token_id)
 CREATE TABLE nft (
@@ -155,6 +459,9 @@ CREATE TABLE nft (
     UNIQUE(nft_id)
 );
 
+-- nft_attributes_idx supports trait filters on nft attributes by jsonb containment.
+CREATE INDEX nft_attributes_idx ON nft USING GIN (attributes jsonb_path_ops);
+
 CREATE TABLE nfttradecurrent (
     sale_id UUID DEFAULT gen_random_uuid(),
     nftclass_id UUID REFERENCES nftclass(nftclass_id),
@@ -213,6 +520,18 @@ CREATE TABLE nftoffer (
     UNIQUE(nft_id, from_address, offer_time)
 );
 
+-- nftfloor stores robust floor prices of nft collections, computed from the sales
+-- in the window of window_seconds before floor_time.
+CREATE TABLE nftfloor (
+    nftclass_id UUID REFERENCES nftclass(nftclass_id) NOT NULL,
+    floor numeric NOT NULL,
+    floor_usd numeric,
+    num_trades integer NOT NULL,
+    window_seconds bigint NOT NULL,
+    floor_time timestamp NOT NULL,
+    UNIQUE(nftclass_id, window_seconds, floor_time)
+);
+
 CREATE TABLE IF NOT EXISTS scrapers (
     name character varying(255) NOT NULL,
 	conf json,
@@ -229,6 +548,16 @@ CREATE TABLE blockdata (
     UNIQUE(blockdata_id)
 );
 
+-- scraperblock holds the latest block processed by each on-chain scraper.
+-- It is compared to the chain head by the chain head monitor.
+CREATE TABLE scraperblock (
+    scraper text NOT NULL,
+    blockchain text NOT NULL,
+    block_number numeric NOT NULL,
+    update_time timestamp NOT NULL,
+    UNIQUE(scraper, blockchain)
+);
+
 CREATE TABLE assetpriceident (
     priceident_id UUID DEFAULT gen_random_uuid(),
     asset_id UUID REFERENCES asset(asset_id),
@@ -252,6 +581,21 @@ CREATE TABLE synthassetdata (
     UNIQUE(synthasset_id,time_stamp)
 );
 
+-- benchmarkasset defines synthetic assets whose price is computed from other assets, such as baskets
+-- or FX crosses. method is one of sum and product, see dia.BenchmarkAsset.
+CREATE TABLE benchmarkasset (
+    asset_id UUID PRIMARY KEY REFERENCES asset(asset_id),
+    method text NOT NULL
+);
+
+CREATE TABLE benchmarkcomponent (
+    benchmark_id UUID REFERENCES benchmarkasset(asset_id) ON DELETE CASCADE NOT NULL,
+    component_id UUID REFERENCES asset(asset_id) NOT NULL,
+    weight numeric NOT NULL DEFAULT 0,
+    exponent numeric NOT NULL DEFAULT 0,
+    UNIQUE (benchmark_id, component_id)
+);
+
 CREATE TABLE nftexchange (
     exchange_id UUID DEFAULT gen_random_uuid(),
     name text NOT NULL,
@@ -302,3 +646,65 @@ CREATE TABLE feederresource (
 
 
 
+
+-- serviceconfig holds configuration values of pipeline services which are reloaded at runtime.
+-- key is the name of the environment variable the value overrides. Only the following keys can be reloaded:
+-- filtersBlockService: HEARTBEAT_INTERVAL_SECONDS, HEARTBEAT_INTERVALS, HEARTBEAT_MAX_AGE_SECONDS,
+-- VOLUME_FLOOR_TIERS and VOLUME_FLOOR_ASSETS.
+-- tradesBlockService: TRADE_VOLUME_THRESHOLD_EXPONENT and STABLECOIN_TOLERANCE.
+-- All other settings require a restart. Exchange weights and feature flags are not configured here.
+CREATE TABLE serviceconfig (
+    service text NOT NULL,
+    key text NOT NULL,
+    value text NOT NULL,
+    UNIQUE (service, key)
+);
+
+-- feedrestriction lists the regions in which a data product must not be distributed, for instance
+-- due to the license of an upstream source. Regions are upper case ISO 3166 codes.
+CREATE TABLE feedrestriction (
+    feed text NOT NULL,
+    region text NOT NULL,
+    UNIQUE (feed, region)
+);
+
+-- apikeyregion assigns API keys, stored as sha256 hex digests, to the region of their holder.
+CREATE TABLE apikeyregion (
+    api_key_hash text PRIMARY KEY,
+    region text NOT NULL
+);
+
+-- changelog records all changes of the asset and exchangepair tables together with the id of the
+-- writing transaction. change_ids are assigned at insert time, so they do not reflect commit order.
+-- Changes are therefore delivered by version, which a single sequencer assigns in the order of txid
+-- once all transactions with smaller txid have finished, see sequenceChanges in pkg/model/sync.go.
+-- The version is used by edge nodes for incremental synchronization.
+-- The triggers are plpgsql and not available on CockroachDB, see DialectCockroach.
+CREATE TABLE changelog (
+    change_id BIGSERIAL PRIMARY KEY,
+    entity text NOT NULL,
+    operation text NOT NULL,
+    payload jsonb,
+    change_time timestamp NOT NULL DEFAULT NOW(),
+    txid xid8 NOT NULL DEFAULT pg_current_xact_id(),
+    version bigint UNIQUE
+);
+
+CREATE SEQUENCE changelog_version_seq OWNED BY changelog.version;
+CREATE INDEX changelog_unsequenced ON changelog (txid, change_id) WHERE version IS NULL;
+
+CREATE OR REPLACE FUNCTION log_change() RETURNS trigger AS $$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        INSERT INTO changelog (entity,operation,payload) VALUES (TG_TABLE_NAME,TG_OP,row_to_json(OLD));
+        RETURN OLD;
+    END IF;
+    INSERT INTO changelog (entity,operation,payload) VALUES (TG_TABLE_NAME,TG_OP,row_to_json(NEW));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER asset_changelog AFTER INSERT OR UPDATE OR DELETE ON asset
+    FOR EACH ROW EXECUTE FUNCTION log_change();
+CREATE TRIGGER exchangepair_changelog AFTER INSERT OR UPDATE OR DELETE ON exchangepair
+    FOR EACH ROW EXECUTE FUNCTION log_change();
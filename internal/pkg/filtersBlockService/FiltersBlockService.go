@@ -51,6 +51,10 @@ type FiltersBlockService struct {
 	calculationValues    []int
 	previousBlockFilters []dia.FilterPoint
 	datastore            models.Datastore
+	heartbeat            *heartbeat
+	volumeFloor          *volumeFloor
+	benchmarks           []dia.BenchmarkAsset
+	benchmarksLock       sync.RWMutex
 }
 
 // NewFiltersBlockService returns a new FiltersBlockService and
@@ -68,6 +72,8 @@ func NewFiltersBlockService(previousBlockFilters []dia.FilterPoint, datastore mo
 		calculationValues:    make([]int, 0),
 		previousBlockFilters: previousBlockFilters,
 		datastore:            datastore,
+		heartbeat:            newHeartbeatFromEnv(),
+		volumeFloor:          newVolumeFloorFromEnv(),
 	}
 	s.calculationValues = append(s.calculationValues, dia.BlockSizeSeconds)
 
@@ -111,9 +117,30 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 
 	t0 = time.Now()
 
-	for _, filters := range s.filters {
+	var carriedQuotations []*models.AssetQuotation
+	for fa, filters := range s.filters {
 		for _, f := range filters {
 			f.finalCompute(tb.TradesBlockData.EndTime)
+		}
+
+		// Prices across exchanges are only updated if the block's volume reaches the floor of the asset.
+		// Below the floor, no price filter is published or saved and the last quotation is carried forward.
+		volumeUSD, trades := blockActivity(filters)
+		belowFloor := fa.Source == "" && !s.volumeFloor.reached(fa.Identifier, volumeUSD, trades)
+
+		for _, f := range filters {
+			if belowFloor {
+				held, modified := holdPrice(f)
+				if mair, ok := f.(*FilterMAIR); ok && modified {
+					log.Infof("volume of %s below floor: %v USD in %d trades. Carry forward last price.", mair.asset.Symbol, volumeUSD, trades)
+					if quotation := s.heartbeat.carry(mair.asset, tb.TradesBlockData.EndTime); quotation != nil {
+						carriedQuotations = append(carriedQuotations, quotation)
+					}
+				}
+				if held {
+					continue
+				}
+			}
 			fp := f.filterPointForBlock()
 			if fp != nil {
 				resultFilters = append(resultFilters, *fp)
@@ -151,6 +178,9 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 	t0 = time.Now()
 	for _, filters := range s.filters {
 		for _, f := range filters {
+			if mair, ok := f.(*FilterMAIR); ok && mair.exchange == "" && mair.modified {
+				s.heartbeat.fresh(mair.asset, mair.value, mair.currentTime)
+			}
 			err = f.save(s.datastore)
 			if err != nil {
 				log.Error(err)
@@ -159,6 +189,27 @@ func (s *FiltersBlockService) processTradesBlock(tb *dia.TradesBlock) {
 	}
 	log.Info("time spent for save filters: ", time.Since(t0))
 
+	for _, quotation := range carriedQuotations {
+		err = s.datastore.SetAssetQuotation(quotation)
+		if err != nil {
+			log.Errorf("carry forward price of %s below volume floor: %v", quotation.Asset.Symbol, err)
+		}
+	}
+
+	for _, quotation := range s.heartbeat.due(tb.TradesBlockData.EndTime) {
+		err = s.datastore.SetAssetQuotation(quotation)
+		if err != nil {
+			log.Errorf("carry forward price of %s: %v", quotation.Asset.Symbol, err)
+		}
+	}
+
+	for _, quotation := range s.benchmarkQuotations(tb.TradesBlockData.EndTime) {
+		err = s.datastore.SetAssetQuotation(quotation)
+		if err != nil {
+			log.Errorf("set benchmark price of %s: %v", quotation.Asset.Symbol, err)
+		}
+	}
+
 	err = s.datastore.ExecuteRedisPipe()
 	if err != nil {
 		log.Error("execute redis pipe: ", err)
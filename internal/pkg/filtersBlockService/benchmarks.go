@@ -0,0 +1,59 @@
+package filters
+
+import (
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetBenchmarks replaces the benchmark assets computed on each tradesBlock.
+func (s *FiltersBlockService) SetBenchmarks(benchmarks []dia.BenchmarkAsset) {
+	s.benchmarksLock.Lock()
+	defer s.benchmarksLock.Unlock()
+	s.benchmarks = benchmarks
+}
+
+// benchmarkQuotations computes the quotations of all benchmark assets at @timestamp.
+// Component prices are taken from the last fresh prices of this service and fall back
+// to the quotation cache for assets without trades since startup. Component prices older
+// than the maximal age of the heartbeat are skipped, so that their benchmarks are not computed.
+func (s *FiltersBlockService) benchmarkQuotations(timestamp time.Time) (quotations []*models.AssetQuotation) {
+	s.benchmarksLock.RLock()
+	defer s.benchmarksLock.RUnlock()
+
+	prices := make(map[string]float64)
+	for _, benchmark := range s.benchmarks {
+		for _, component := range benchmark.Components {
+			identifier := getIdentifier(component.Asset)
+			if _, ok := prices[identifier]; ok {
+				continue
+			}
+			if state, ok := s.heartbeat.last[identifier]; ok {
+				if !s.heartbeat.stale(state.priceTime, timestamp) {
+					prices[identifier] = state.price
+				}
+				continue
+			}
+			quotation, err := s.datastore.GetAssetQuotationCache(component.Asset)
+			if err != nil || s.heartbeat.stale(quotation.Time, timestamp) {
+				continue
+			}
+			prices[identifier] = quotation.Price
+		}
+
+		price, err := benchmark.Price(prices)
+		if err != nil {
+			log.Warnf("compute benchmark %s: %v", benchmark.Asset.Symbol, err)
+			continue
+		}
+		quotations = append(quotations, &models.AssetQuotation{
+			Asset:  benchmark.Asset,
+			Price:  price,
+			Source: dia.Diadata,
+			Time:   timestamp,
+		})
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package filters
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ReloadConfig replaces the heartbeat and volume floor configuration of @s by the env configuration with
+// @values applied. Keys are the names of the corresponding env vars. The current configuration is kept
+// if @values is invalid.
+func (s *FiltersBlockService) ReloadConfig(values map[string]string) error {
+	config := heartbeatConfigFromEnv()
+	for key, value := range volumeFloorConfigFromEnv() {
+		config[key] = value
+	}
+	for key, value := range values {
+		if _, ok := config[key]; !ok {
+			return fmt.Errorf("unknown config key %s", key)
+		}
+		config[key] = value
+	}
+	interval, intervals, maxAge, err := parseHeartbeatConfig(config)
+	if err != nil {
+		return err
+	}
+	tiers, assets, err := parseVolumeFloorConfig(config)
+	if err != nil {
+		return err
+	}
+	s.heartbeat.configure(interval, intervals, maxAge)
+	s.volumeFloor.configure(tiers, assets)
+	log.Infof("reloaded heartbeat config: interval %v, intervals %v, max age %v", interval, intervals, maxAge)
+	log.Infof("reloaded volume floor config: tiers %v, assets %v", tiers, assets)
+	return nil
+}
@@ -0,0 +1,178 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// heartbeat re-emits the last price of assets without recent trades, so that downstream
+// oracles keep updating. Re-emitted quotations are flagged as carried forward.
+type heartbeat struct {
+	// interval is the default heartbeat. Zero disables heartbeats for assets without specific interval.
+	interval time.Duration
+	// intervals holds asset specific heartbeats, keyed by asset identifier.
+	intervals map[string]time.Duration
+	// maxAge bounds the age of carried forward prices.
+	maxAge     time.Duration
+	configLock sync.RWMutex
+	last       map[string]*heartbeatState
+}
+
+type heartbeatState struct {
+	asset       dia.Asset
+	price       float64
+	priceTime   time.Time
+	lastEmitted time.Time
+}
+
+// heartbeatConfigFromEnv returns the heartbeat configuration given by env vars:
+// HEARTBEAT_INTERVAL_SECONDS is the default interval, HEARTBEAT_INTERVALS a comma separated list of
+// blockchain-address:seconds overrides and HEARTBEAT_MAX_AGE_SECONDS the maximal age of carried forward prices.
+func heartbeatConfigFromEnv() map[string]string {
+	return map[string]string{
+		"HEARTBEAT_INTERVAL_SECONDS": utils.Getenv("HEARTBEAT_INTERVAL_SECONDS", "0"),
+		"HEARTBEAT_INTERVALS":        utils.Getenv("HEARTBEAT_INTERVALS", ""),
+		"HEARTBEAT_MAX_AGE_SECONDS":  utils.Getenv("HEARTBEAT_MAX_AGE_SECONDS", "86400"),
+	}
+}
+
+// parseHeartbeatConfig parses the heartbeat configuration @values, keyed as in heartbeatConfigFromEnv.
+func parseHeartbeatConfig(values map[string]string) (interval time.Duration, intervals map[string]time.Duration, maxAge time.Duration, err error) {
+	seconds, err := strconv.ParseInt(values["HEARTBEAT_INTERVAL_SECONDS"], 10, 64)
+	if err != nil || seconds < 0 {
+		err = fmt.Errorf("invalid HEARTBEAT_INTERVAL_SECONDS: %s", values["HEARTBEAT_INTERVAL_SECONDS"])
+		return
+	}
+	interval = time.Duration(seconds) * time.Second
+	seconds, err = strconv.ParseInt(values["HEARTBEAT_MAX_AGE_SECONDS"], 10, 64)
+	if err != nil || seconds < 0 {
+		err = fmt.Errorf("invalid HEARTBEAT_MAX_AGE_SECONDS: %s", values["HEARTBEAT_MAX_AGE_SECONDS"])
+		return
+	}
+	maxAge = time.Duration(seconds) * time.Second
+	intervals, err = parseHeartbeatIntervals(values["HEARTBEAT_INTERVALS"])
+	return
+}
+
+func newHeartbeatFromEnv() *heartbeat {
+	interval, intervals, maxAge, err := parseHeartbeatConfig(heartbeatConfigFromEnv())
+	if err != nil {
+		log.Error("parse heartbeat config: ", err)
+	}
+	return newHeartbeat(interval, intervals, maxAge)
+}
+
+func newHeartbeat(interval time.Duration, intervals map[string]time.Duration, maxAge time.Duration) *heartbeat {
+	return &heartbeat{
+		interval:  interval,
+		intervals: intervals,
+		maxAge:    maxAge,
+		last:      make(map[string]*heartbeatState),
+	}
+}
+
+// configure replaces the intervals and the maximal age of @h. Recorded prices are kept.
+func (h *heartbeat) configure(interval time.Duration, intervals map[string]time.Duration, maxAge time.Duration) {
+	h.configLock.Lock()
+	defer h.configLock.Unlock()
+	h.interval = interval
+	h.intervals = intervals
+	h.maxAge = maxAge
+}
+
+// parseHeartbeatIntervals parses a list such as Ethereum-0xabc:300,Bitcoin-0x000:60.
+// The asset identifier is separated from the seconds by the last colon.
+func parseHeartbeatIntervals(s string) (map[string]time.Duration, error) {
+	intervals := make(map[string]time.Duration)
+	if s == "" {
+		return intervals, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		i := strings.LastIndex(item, ":")
+		if i < 0 {
+			return intervals, fmt.Errorf("missing interval in %s", item)
+		}
+		seconds, err := strconv.ParseInt(strings.TrimSpace(item[i+1:]), 10, 64)
+		if err != nil {
+			return intervals, err
+		}
+		intervals[strings.TrimSpace(item[:i])] = time.Duration(seconds) * time.Second
+	}
+	return intervals, nil
+}
+
+func (h *heartbeat) intervalFor(identifier string) time.Duration {
+	if interval, ok := h.intervals[identifier]; ok {
+		return interval
+	}
+	return h.interval
+}
+
+// fresh records a price computed from trades.
+func (h *heartbeat) fresh(asset dia.Asset, price float64, timestamp time.Time) {
+	h.last[getIdentifier(asset)] = &heartbeatState{
+		asset:       asset,
+		price:       price,
+		priceTime:   timestamp,
+		lastEmitted: timestamp,
+	}
+}
+
+// due returns carried forward quotations for all assets whose last emission is older than their heartbeat.
+func (h *heartbeat) due(now time.Time) (quotations []*models.AssetQuotation) {
+	h.configLock.RLock()
+	defer h.configLock.RUnlock()
+	for identifier, state := range h.last {
+		interval := h.intervalFor(identifier)
+		if interval <= 0 || now.Sub(state.lastEmitted) < interval {
+			continue
+		}
+		if quotation := h.carryState(state, now); quotation != nil {
+			quotations = append(quotations, quotation)
+		}
+	}
+	return
+}
+
+// carry returns the last fresh price of @asset as carried forward quotation at @now. It returns nil
+// if no price was recorded or the price exceeds the maximal age.
+func (h *heartbeat) carry(asset dia.Asset, now time.Time) *models.AssetQuotation {
+	h.configLock.RLock()
+	defer h.configLock.RUnlock()
+	state, ok := h.last[getIdentifier(asset)]
+	if !ok {
+		return nil
+	}
+	return h.carryState(state, now)
+}
+
+// stale returns true if a price from @priceTime exceeds the maximal age at @now.
+func (h *heartbeat) stale(priceTime time.Time, now time.Time) bool {
+	h.configLock.RLock()
+	defer h.configLock.RUnlock()
+	return h.maxAge > 0 && now.Sub(priceTime) > h.maxAge
+}
+
+func (h *heartbeat) carryState(state *heartbeatState, now time.Time) *models.AssetQuotation {
+	age := now.Sub(state.priceTime)
+	if h.maxAge > 0 && age > h.maxAge {
+		return nil
+	}
+	state.lastEmitted = now
+	return &models.AssetQuotation{
+		Asset:          state.asset,
+		Price:          state.price,
+		Source:         dia.Diadata,
+		Time:           now,
+		CarriedForward: true,
+		Age:            int64(age.Seconds()),
+	}
+}
@@ -0,0 +1,77 @@
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestHeartbeat(t *testing.T) {
+	illiquid := dia.Asset{Symbol: "ILQ", Blockchain: dia.ETHEREUM, Address: "0x1"}
+	liquid := dia.Asset{Symbol: "LQD", Blockchain: dia.ETHEREUM, Address: "0x2"}
+	intervals, err := parseHeartbeatIntervals(getIdentifier(liquid) + ":60")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newHeartbeat(5*time.Minute, intervals, time.Hour)
+
+	t0 := time.Unix(1700000000, 0)
+	h.fresh(illiquid, 1.5, t0)
+	h.fresh(liquid, 2, t0)
+
+	if quotations := h.due(t0.Add(30 * time.Second)); len(quotations) != 0 {
+		t.Errorf("expected no quotations, got %v", quotations)
+	}
+	quotations := h.due(t0.Add(time.Minute))
+	if len(quotations) != 1 || quotations[0].Asset != liquid || !quotations[0].CarriedForward || quotations[0].Age != 60 {
+		t.Errorf("expected carried forward quotation of %s, got %v", liquid.Symbol, quotations)
+	}
+	quotations = h.due(t0.Add(5 * time.Minute))
+	if len(quotations) != 2 {
+		t.Errorf("expected 2 quotations, got %v", quotations)
+	}
+	if quotations := h.due(t0.Add(2 * time.Hour)); len(quotations) != 0 {
+		t.Errorf("expected no quotations beyond max age, got %v", quotations)
+	}
+}
+
+func TestParseHeartbeatConfig(t *testing.T) {
+	values := map[string]string{
+		"HEARTBEAT_INTERVAL_SECONDS": "300",
+		"HEARTBEAT_INTERVALS":        "Ethereum-0x1:60",
+		"HEARTBEAT_MAX_AGE_SECONDS":  "3600",
+	}
+	interval, intervals, maxAge, err := parseHeartbeatConfig(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interval != 5*time.Minute || maxAge != time.Hour || intervals["Ethereum-0x1"] != time.Minute {
+		t.Errorf("unexpected config %v %v %v", interval, intervals, maxAge)
+	}
+
+	values["HEARTBEAT_INTERVAL_SECONDS"] = "-1"
+	if _, _, _, err := parseHeartbeatConfig(values); err == nil {
+		t.Error("expected error on negative interval")
+	}
+}
+
+func TestBenchmarkQuotationsMaxAge(t *testing.T) {
+	component := dia.Asset{Symbol: "CMP", Blockchain: dia.ETHEREUM, Address: "0x1"}
+	benchmark := dia.BenchmarkAsset{
+		Asset:      dia.Asset{Symbol: "BMK", Blockchain: dia.ETHEREUM, Address: "0x3"},
+		Method:     dia.BENCHMARK_SUM,
+		Components: []dia.BenchmarkComponent{{Asset: component, Weight: 2}},
+	}
+	s := &FiltersBlockService{heartbeat: newHeartbeat(0, nil, time.Hour)}
+	s.SetBenchmarks([]dia.BenchmarkAsset{benchmark})
+
+	t0 := time.Unix(1700000000, 0)
+	s.heartbeat.fresh(component, 1.5, t0)
+	if quotations := s.benchmarkQuotations(t0.Add(time.Minute)); len(quotations) != 1 || quotations[0].Price != 3 {
+		t.Errorf("expected benchmark price 3, got %v", quotations)
+	}
+	if quotations := s.benchmarkQuotations(t0.Add(2 * time.Hour)); len(quotations) != 0 {
+		t.Errorf("expected no benchmark quotation from stale components, got %v", quotations)
+	}
+}
@@ -0,0 +1,145 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultVolumeTier is the tier of all assets without assigned tier.
+const defaultVolumeTier = "default"
+
+// volumeFloor holds the minimal USD volume and number of trades a block must contain for an asset,
+// so that a new price is emitted. Otherwise the previous price is carried forward, such that single
+// dust trades cannot set the price of illiquid assets. Assets are assigned to tiers, each with its own floor.
+type volumeFloor struct {
+	tiers map[string]floor
+	// assets maps asset identifiers to tiers.
+	assets map[string]string
+	lock   sync.RWMutex
+}
+
+type floor struct {
+	volumeUSD float64
+	trades    int64
+}
+
+// volumeFloorConfigFromEnv returns the volume floor configuration given by env vars:
+// VOLUME_FLOOR_TIERS is a comma separated list of tier:usdVolume:numTrades and VOLUME_FLOOR_ASSETS
+// a comma separated list of blockchain-address:tier. Assets without tier are in the tier default.
+func volumeFloorConfigFromEnv() map[string]string {
+	return map[string]string{
+		"VOLUME_FLOOR_TIERS":  utils.Getenv("VOLUME_FLOOR_TIERS", ""),
+		"VOLUME_FLOOR_ASSETS": utils.Getenv("VOLUME_FLOOR_ASSETS", ""),
+	}
+}
+
+// parseVolumeFloorConfig parses the volume floor configuration @values, keyed as in volumeFloorConfigFromEnv.
+func parseVolumeFloorConfig(values map[string]string) (tiers map[string]floor, assets map[string]string, err error) {
+	tiers = make(map[string]floor)
+	assets = make(map[string]string)
+	if s := values["VOLUME_FLOOR_TIERS"]; s != "" {
+		for _, item := range strings.Split(s, ",") {
+			fields := strings.Split(strings.TrimSpace(item), ":")
+			if len(fields) != 3 {
+				return tiers, assets, fmt.Errorf("invalid volume floor tier %s", item)
+			}
+			var f floor
+			f.volumeUSD, err = strconv.ParseFloat(fields[1], 64)
+			if err != nil || f.volumeUSD < 0 {
+				return tiers, assets, fmt.Errorf("invalid volume in volume floor tier %s", item)
+			}
+			f.trades, err = strconv.ParseInt(fields[2], 10, 64)
+			if err != nil || f.trades < 0 {
+				return tiers, assets, fmt.Errorf("invalid number of trades in volume floor tier %s", item)
+			}
+			tiers[fields[0]] = f
+		}
+	}
+	if s := values["VOLUME_FLOOR_ASSETS"]; s != "" {
+		// The asset identifier is separated from the tier by the last colon.
+		for _, item := range strings.Split(s, ",") {
+			i := strings.LastIndex(item, ":")
+			if i < 0 {
+				return tiers, assets, fmt.Errorf("missing tier in %s", item)
+			}
+			tier := strings.TrimSpace(item[i+1:])
+			if _, ok := tiers[tier]; !ok && tier != defaultVolumeTier {
+				return tiers, assets, fmt.Errorf("unknown volume floor tier %s", tier)
+			}
+			assets[strings.TrimSpace(item[:i])] = tier
+		}
+	}
+	return
+}
+
+func newVolumeFloorFromEnv() *volumeFloor {
+	tiers, assets, err := parseVolumeFloorConfig(volumeFloorConfigFromEnv())
+	if err != nil {
+		log.Error("parse volume floor config: ", err)
+	}
+	return newVolumeFloor(tiers, assets)
+}
+
+func newVolumeFloor(tiers map[string]floor, assets map[string]string) *volumeFloor {
+	return &volumeFloor{
+		tiers:  tiers,
+		assets: assets,
+	}
+}
+
+// configure replaces the tiers and asset assignments of @v.
+func (v *volumeFloor) configure(tiers map[string]floor, assets map[string]string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.tiers = tiers
+	v.assets = assets
+}
+
+// reached returns true if @volumeUSD and @trades of the asset with @identifier in a block reach
+// the floor of its tier.
+func (v *volumeFloor) reached(identifier string, volumeUSD float64, trades int64) bool {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	tier, ok := v.assets[identifier]
+	if !ok {
+		tier = defaultVolumeTier
+	}
+	f := v.tiers[tier]
+	return volumeUSD >= f.volumeUSD && trades >= f.trades
+}
+
+// blockActivity returns the USD volume and the number of trades computed by the VOL and COUNT
+// filters in @filters for the current block.
+func blockActivity(filters []Filter) (volumeUSD float64, trades int64) {
+	for _, f := range filters {
+		switch filter := f.(type) {
+		case *FilterVOL:
+			volumeUSD = filter.value
+		case *FilterCOUNT:
+			trades = filter.value
+		}
+	}
+	return
+}
+
+// holdPrice withholds the price computed by @f in the current block, such that it is neither published nor saved.
+// It returns true if @f is a price filter and whether @f computed a new price in the block.
+// Volume, trade count and last trade time are not held, as they describe the block's activity.
+func holdPrice(f Filter) (held bool, modified bool) {
+	switch filter := f.(type) {
+	case *FilterMA:
+		modified, filter.modified = filter.modified, false
+	case *FilterMAIR:
+		modified, filter.modified = filter.modified, false
+	case *FilterMEDIR:
+		modified, filter.modified = filter.modified, false
+	default:
+		return false, false
+	}
+	return true, modified
+}
@@ -0,0 +1,94 @@
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestVolumeFloor(t *testing.T) {
+	values := map[string]string{
+		"VOLUME_FLOOR_TIERS":  "default:10:1,illiquid:1000:3",
+		"VOLUME_FLOOR_ASSETS": "Ethereum-0x1:illiquid",
+	}
+	tiers, assets, err := parseVolumeFloorConfig(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := newVolumeFloor(tiers, assets)
+
+	if v.reached("Ethereum-0x1", 500, 5) {
+		t.Error("expected illiquid asset below floor by volume")
+	}
+	if v.reached("Ethereum-0x1", 2000, 2) {
+		t.Error("expected illiquid asset below floor by number of trades")
+	}
+	if !v.reached("Ethereum-0x1", 2000, 3) {
+		t.Error("expected illiquid asset to reach floor")
+	}
+	if !v.reached("Ethereum-0x2", 10, 1) || v.reached("Ethereum-0x2", 5, 1) {
+		t.Error("expected default floor for assets without tier")
+	}
+	if !newVolumeFloor(nil, nil).reached("Ethereum-0x2", 0, 0) {
+		t.Error("expected no floor without configuration")
+	}
+
+	values["VOLUME_FLOOR_ASSETS"] = "Ethereum-0x1:unknown"
+	if _, _, err := parseVolumeFloorConfig(values); err == nil {
+		t.Error("expected error on unknown tier")
+	}
+}
+
+func TestHeartbeatCarry(t *testing.T) {
+	asset := dia.Asset{Symbol: "ILQ", Blockchain: dia.ETHEREUM, Address: "0x1"}
+	h := newHeartbeat(0, nil, time.Hour)
+	t0 := time.Unix(1700000000, 0)
+
+	if quotation := h.carry(asset, t0); quotation != nil {
+		t.Errorf("expected no quotation without price, got %v", quotation)
+	}
+	h.fresh(asset, 1.5, t0)
+	quotation := h.carry(asset, t0.Add(time.Minute))
+	if quotation == nil || quotation.Price != 1.5 || !quotation.CarriedForward || quotation.Age != 60 {
+		t.Errorf("expected carried forward quotation, got %v", quotation)
+	}
+	if quotation := h.carry(asset, t0.Add(2*time.Hour)); quotation != nil {
+		t.Errorf("expected no quotation beyond max age, got %v", quotation)
+	}
+}
+
+func TestHoldPrice(t *testing.T) {
+	asset := dia.Asset{Symbol: "ETH", Address: "0x0000000000000000000000000000000000000000", Blockchain: dia.ETHEREUM}
+	now := time.Now()
+	trade := dia.Trade{QuoteToken: asset, Price: 1000, EstimatedUSDPrice: 1000, Volume: 0.001, Time: now}
+	filters := []Filter{
+		NewFilterMA(asset, "", now, dia.BlockSizeSeconds),
+		NewFilterMAIR(asset, "", now, dia.BlockSizeSeconds),
+		NewFilterMEDIR(asset, "", now, dia.BlockSizeSeconds),
+		NewFilterVOL(asset, "", dia.BlockSizeSeconds),
+		NewFilterCOUNT(asset, "", dia.BlockSizeSeconds),
+		NewFilterTLT(asset, ""),
+	}
+	for _, f := range filters {
+		f.compute(trade)
+	}
+
+	var held []Filter
+	for _, f := range filters {
+		if ok, modified := holdPrice(f); ok {
+			if !modified {
+				t.Errorf("expected %T to have computed a price", f)
+			}
+			held = append(held, f)
+		}
+	}
+	if len(held) != 3 {
+		t.Fatalf("expected the 3 price filters to be held, got %d", len(held))
+	}
+	for _, f := range held {
+		if _, modified := holdPrice(f); modified {
+			t.Errorf("expected the price of %T not to be saved after holding it", f)
+		}
+	}
+}
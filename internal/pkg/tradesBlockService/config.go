@@ -0,0 +1,70 @@
+package tradesBlockService
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Config holds the parameters of a TradesBlockService which can be reloaded at runtime.
+type Config struct {
+	// TradeVolumeThreshold is the minimal absolute volume of a trade.
+	TradeVolumeThreshold float64
+	// StablecoinTolerance is the maximal deviation of a stablecoin's estimated USD price from 1.
+	StablecoinTolerance float64
+}
+
+// defaultConfig returns the configuration given by env vars.
+func defaultConfig() Config {
+	return Config{
+		TradeVolumeThreshold: tradeVolumeThreshold,
+		StablecoinTolerance:  tol,
+	}
+}
+
+// ParseConfig returns @base with @values applied. Keys are the names of the corresponding env vars.
+// Unknown keys and invalid values are rejected.
+func ParseConfig(base Config, values map[string]string) (Config, error) {
+	config := base
+	for key, value := range values {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return base, fmt.Errorf("parse %s: %v", key, err)
+		}
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return base, fmt.Errorf("invalid %s: %s", key, value)
+		}
+		switch key {
+		case "TRADE_VOLUME_THRESHOLD_EXPONENT":
+			config.TradeVolumeThreshold = math.Pow(10, -number)
+		case "STABLECOIN_TOLERANCE":
+			if number <= 0 {
+				return base, fmt.Errorf("invalid %s: %s", key, value)
+			}
+			config.StablecoinTolerance = number
+		default:
+			return base, fmt.Errorf("unknown config key %s", key)
+		}
+	}
+	return config, nil
+}
+
+// ReloadConfig replaces the configuration of @s by the env configuration with @values applied.
+// The current configuration is kept if @values is invalid.
+func (s *TradesBlockService) ReloadConfig(values map[string]string) error {
+	config, err := ParseConfig(defaultConfig(), values)
+	if err != nil {
+		return err
+	}
+	s.configLock.Lock()
+	s.config = config
+	s.configLock.Unlock()
+	log.Infof("reloaded config: %+v", config)
+	return nil
+}
+
+func (s *TradesBlockService) getConfig() Config {
+	s.configLock.RLock()
+	defer s.configLock.RUnlock()
+	return s.config
+}
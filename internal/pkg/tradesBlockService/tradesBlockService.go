@@ -32,6 +32,17 @@ func init() {
 		log.Error("Parse TRADE_VOLUME_THRESHOLD_EXPONENT: ", err)
 	}
 	tradeVolumeThreshold = math.Pow(10, -tradeVolumeThresholdExponent)
+	constraintsSeconds, err = strconv.Atoi(utils.Getenv("QUOTE_CONSTRAINTS_UPDATE_SECONDS", "600"))
+	if err != nil || constraintsSeconds <= 0 {
+		log.Errorf("invalid QUOTE_CONSTRAINTS_UPDATE_SECONDS, fall back to 600: %v", err)
+		constraintsSeconds = 600
+	}
+	idempotencySeconds, err := strconv.Atoi(utils.Getenv("TRADE_IDEMPOTENCY_TTL_SECONDS", "86400"))
+	if err != nil || idempotencySeconds <= 0 {
+		log.Errorf("invalid TRADE_IDEMPOTENCY_TTL_SECONDS, fall back to 86400: %v", err)
+		idempotencySeconds = 86400
+	}
+	tradeIdempotencyTTL = time.Duration(idempotencySeconds) * time.Second
 }
 
 var (
@@ -47,9 +58,14 @@ var (
 	log                  *logrus.Logger
 	batchTimeSeconds     int
 	tradeVolumeThreshold float64
+	constraintsSeconds   int
+	tradeIdempotencyTTL  time.Duration
 	checkTradesDuplicate = make(map[string]struct{})
 )
 
+// incomingBatchSize is the maximal number of buffered trades whose idempotency keys are looked up in one round trip.
+const incomingBatchSize = 1000
+
 type TradesBlockService struct {
 	shutdown         chan nothing
 	shutdownDone     chan nothing
@@ -61,27 +77,47 @@ type TradesBlockService struct {
 	started          bool
 	BlockDuration    int64
 	currentBlock     *dia.TradesBlock
-	priceCache       map[dia.Asset]float64
+	priceCache       map[dia.Asset]models.AssetQuotation
 	datastore        models.Datastore
 	historical       bool
 	writeMeasurement string
 	batchTicker      *time.Ticker
+	// quoteConstraints maps an asset to the quote assets accepted for its price computation.
+	quoteConstraints     map[dia.Asset]map[dia.Asset]struct{}
+	quoteConstraintsLock sync.RWMutex
+	// deprecatedAssets are excluded from price computation.
+	deprecatedAssets     map[dia.Asset]struct{}
+	deprecatedAssetsLock sync.RWMutex
+	// excludedPairs holds the pairs which are manually excluded in the exchangepair table, keyed by exchangePairKey.
+	excludedPairs     map[string]struct{}
+	excludedPairsLock sync.RWMutex
+	config            Config
+	configLock        sync.RWMutex
+	// pendingClaims are the idempotency keys of trades which are processed but not yet flushed to influx.
+	pendingClaims map[string]struct{}
+	// redeliveredTrades counts the trades dropped as redeliveries since the last flush.
+	redeliveredTrades int
+	// excludedTrades counts the dropped trades per excluded market since the last flush.
+	excludedTrades map[string]int
 }
 
 func NewTradesBlockService(datastore models.Datastore, blockDuration int64, historical bool) *TradesBlockService {
 	s := &TradesBlockService{
 		shutdown:        make(chan nothing),
 		shutdownDone:    make(chan nothing),
-		chanTrades:      make(chan *dia.Trade),
+		chanTrades:      make(chan *dia.Trade, incomingBatchSize),
 		chanTradesBlock: make(chan *dia.TradesBlock),
 		error:           nil,
 		started:         false,
 		currentBlock:    nil,
 		BlockDuration:   blockDuration,
-		priceCache:      make(map[dia.Asset]float64),
+		priceCache:      make(map[dia.Asset]models.AssetQuotation),
 		datastore:       datastore,
 		historical:      historical,
 		batchTicker:     time.NewTicker(time.Duration(batchTimeSeconds) * time.Second),
+		config:          defaultConfig(),
+		pendingClaims:   make(map[string]struct{}),
+		excludedTrades:  make(map[string]int),
 	}
 	if historical {
 		s.writeMeasurement = utils.Getenv("INFLUX_MEASUREMENT_WRITE", "tradesTmp")
@@ -89,10 +125,88 @@ func NewTradesBlockService(datastore models.Datastore, blockDuration int64, hist
 	log.Info("write measurement: ", s.writeMeasurement)
 	log.Info("historical: ", s.historical)
 	log.Info("batch ticker time: ", batchTimeSeconds)
+	if !historical {
+		go s.updateAssetRestrictions()
+	}
 	go s.mainLoop()
 	return s
 }
 
+// updateAssetRestrictions periodically fetches the quote asset constraints, deprecated assets
+// and excluded pairs from postgres.
+func (s *TradesBlockService) updateAssetRestrictions() {
+	relDB, err := models.NewPostgresDataStore()
+	if err != nil {
+		log.Error("asset restrictions are not enforced. New relational datastore: ", err)
+		return
+	}
+	ticker := time.NewTicker(time.Duration(constraintsSeconds) * time.Second)
+	for {
+		constraints, err := relDB.GetAllQuoteAssetConstraints()
+		if err != nil {
+			log.Error("get quote asset constraints: ", err)
+		} else {
+			s.SetQuoteAssetConstraints(constraints)
+		}
+		deprecatedAssets, err := relDB.GetDeprecatedAssets("")
+		if err != nil {
+			log.Error("get deprecated assets: ", err)
+		} else {
+			s.SetDeprecatedAssets(deprecatedAssets)
+		}
+		excludedPairs, err := relDB.GetExcludedExchangePairs()
+		if err != nil {
+			log.Error("get excluded pairs: ", err)
+		} else {
+			s.SetExcludedPairs(excludedPairs)
+		}
+		select {
+		case <-s.shutdown:
+			ticker.Stop()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetQuoteAssetConstraints replaces the quote assets accepted for price computation.
+// Assets without an entry in @constraints accept all quote assets.
+func (s *TradesBlockService) SetQuoteAssetConstraints(constraints map[dia.Asset][]dia.Asset) {
+	quoteConstraints := make(map[dia.Asset]map[dia.Asset]struct{})
+	for asset, quoteAssets := range constraints {
+		key := constraintKey(asset)
+		quoteConstraints[key] = make(map[dia.Asset]struct{})
+		for _, quoteAsset := range quoteAssets {
+			quoteConstraints[key][constraintKey(quoteAsset)] = struct{}{}
+		}
+	}
+	s.quoteConstraintsLock.Lock()
+	s.quoteConstraints = quoteConstraints
+	s.quoteConstraintsLock.Unlock()
+}
+
+// SetDeprecatedAssets replaces the assets which are excluded from price computation.
+func (s *TradesBlockService) SetDeprecatedAssets(assets []dia.Asset) {
+	deprecatedAssets := make(map[dia.Asset]struct{})
+	for _, asset := range assets {
+		deprecatedAssets[constraintKey(asset)] = struct{}{}
+	}
+	s.deprecatedAssetsLock.Lock()
+	s.deprecatedAssets = deprecatedAssets
+	s.deprecatedAssetsLock.Unlock()
+}
+
+// SetExcludedPairs replaces the pairs whose trades are dropped at ingestion.
+func (s *TradesBlockService) SetExcludedPairs(pairs []dia.ExchangePair) {
+	excludedPairs := make(map[string]struct{})
+	for _, pair := range pairs {
+		excludedPairs[exchangePairKey(pair.Exchange, pair.ForeignName)] = struct{}{}
+	}
+	s.excludedPairsLock.Lock()
+	s.excludedPairs = excludedPairs
+	s.excludedPairsLock.Unlock()
+}
+
 // runs in a goroutine until s is closed
 func (s *TradesBlockService) mainLoop() {
 	for {
@@ -102,62 +216,148 @@ func (s *TradesBlockService) mainLoop() {
 			s.cleanup(nil)
 			return
 		case t := <-s.chanTrades:
-			s.process(*t)
+			s.processBatch(s.receiveBatch(t))
 		case <-s.batchTicker.C:
-			err := s.datastore.Flush()
-			if err != nil {
-				log.Error("flush influx batch: ", err)
-			}
+			s.flush()
+		}
+	}
+}
+
+// receiveBatch returns @t together with all trades which are buffered in the trades channel.
+func (s *TradesBlockService) receiveBatch(t *dia.Trade) []dia.Trade {
+	trades := []dia.Trade{*t}
+	for len(trades) < incomingBatchSize {
+		select {
+		case t = <-s.chanTrades:
+			trades = append(trades, *t)
+		default:
+			return trades
 		}
 	}
+	return trades
+}
+
+// processBatch processes @trades after dropping redeliveries of already processed trades.
+func (s *TradesBlockService) processBatch(trades []dia.Trade) {
+	if !s.historical {
+		trades = s.dropRedelivered(trades)
+	}
+	for _, t := range trades {
+		s.process(t)
+	}
+}
+
+// dropRedelivered returns the trades in @trades which were neither stored before nor are pending to be stored.
+// Trades are delivered at least once, so replays after a restart would inflate volumes otherwise.
+// Idempotency keys of the returned trades are claimed once the trades are flushed to influx.
+func (s *TradesBlockService) dropRedelivered(trades []dia.Trade) []dia.Trade {
+	keys := make([]string, len(trades))
+	for i := range trades {
+		keys[i] = trades[i].IdempotencyKey()
+	}
+	claimed, err := s.datastore.TradesClaimed(keys)
+	if err != nil {
+		log.Error("look up trade claims: ", err)
+	}
+
+	accepted := trades[:0]
+	for i, t := range trades {
+		if _, ok := s.pendingClaims[keys[i]]; ok || claimed[i] {
+			s.redeliveredTrades++
+			continue
+		}
+		s.pendingClaims[keys[i]] = struct{}{}
+		accepted = append(accepted, t)
+	}
+	return accepted
+}
+
+// flush writes the batched trades to influx. Only once they are stored, their idempotency keys are claimed,
+// such that trades which are lost before storage are not dropped on redelivery.
+func (s *TradesBlockService) flush() {
+	err := s.datastore.Flush()
+	if err != nil {
+		log.Error("flush influx batch: ", err)
+		return
+	}
+	for market, count := range s.excludedTrades {
+		log.Infof("dropped %d trades from excluded market %s", count, market)
+	}
+	s.excludedTrades = make(map[string]int)
+	if s.redeliveredTrades > 0 {
+		log.Infof("dropped %d redelivered trades", s.redeliveredTrades)
+		s.redeliveredTrades = 0
+	}
+	if len(s.pendingClaims) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(s.pendingClaims))
+	for key := range s.pendingClaims {
+		keys = append(keys, key)
+	}
+	err = s.datastore.ClaimTrades(keys, tradeIdempotencyTTL)
+	if err != nil {
+		log.Error("claim trades: ", err)
+		return
+	}
+	s.pendingClaims = make(map[string]struct{})
 }
 
 func (s *TradesBlockService) process(t dia.Trade) {
 
 	var verifiedTrade bool
 
+	// Trades from sandbox, testnet and demo markets as well as from manually excluded
+	// pairs never contribute to prices.
+	if dia.IsExcludedMarket(t.Source, t.Pair) || s.isExcludedPair(t.Source, t.Pair) {
+		s.excludedTrades[t.Pair+" on "+t.Source]++
+		return
+	}
+
 	// Price estimation can only be done for verified pairs.
 	// Trades with unverified pairs are still saved, but not sent to the filtersBlockService.
-	if t.VerifiedPair && s.checkTrade(t) {
+	if t.VerifiedPair && s.checkTrade(t) && s.checkQuoteConstraint(t) && !s.isDeprecated(t.QuoteToken) {
 		if t.BaseToken.Address == "840" && t.BaseToken.Blockchain == dia.FIAT {
 			// All prices are measured in US-Dollar, so just price for base token == USD
-			t.EstimatedUSDPrice = t.Price
+			t.SetEstimation(dia.ESTIMATION_USD, 1, t.Time)
 			verifiedTrade = true
 		} else {
 			// Get price of base token.
 			var quotation *models.AssetQuotation
-			var price float64
+			var path string
 			var ok bool
 			var err error
 			if !s.historical {
 
 				// Bridge basetoken if necessary.
 				basetoken := buildBridge(t)
+				path = dia.EstimationPath(dia.ESTIMATION_LATEST, t.BaseToken, basetoken)
 
 				// Get latest price from cache.
-				if _, ok = s.priceCache[basetoken]; ok {
-					price = s.priceCache[basetoken]
+				if cached, ok := s.priceCache[basetoken]; ok {
+					quotation = &cached
 				} else {
 					quotation, err = s.datastore.GetAssetQuotationCache(basetoken)
-					price = quotation.Price
-					s.priceCache[basetoken] = price
-					log.Infof("quotation for %s from redis cache: %v", basetoken.Symbol, price)
+					s.priceCache[basetoken] = *quotation
+					log.Infof("quotation for %s from redis cache: %v", basetoken.Symbol, quotation.Price)
 				}
 
 			} else {
+				path = dia.ESTIMATION_HISTORICAL
 
 				// Look for historic price of base token at trade time.
 				if _, ok = s.priceCache[t.BaseToken]; ok {
-					price = s.priceCache[t.BaseToken]
+					cached := s.priceCache[t.BaseToken]
+					quotation = &cached
 				} else {
-					price, err = s.datastore.GetAssetPriceUSD(t.BaseToken, t.Time)
-					s.priceCache[t.BaseToken] = price
-					if t.BaseToken.Address == "0x0000000000000000000000000000000000000000" {
+					quotation, err = s.datastore.GetAssetQuotation(t.BaseToken, t.Time)
+					s.priceCache[t.BaseToken] = *quotation
+					if t.BaseToken.Address == dia.NATIVE_ASSET_ADDRESS {
 						if t.BaseToken.Blockchain == "Bitcoin" {
-							log.Infof("quotation for BTC from influx: %v", price)
+							log.Infof("quotation for BTC from influx: %v", quotation.Price)
 						}
 						if t.BaseToken.Blockchain == "Ethereum" {
-							log.Infof("quotation for ETH from influx: %v", price)
+							log.Infof("quotation for ETH from influx: %v", quotation.Price)
 						}
 					}
 				}
@@ -171,8 +371,8 @@ func (s *TradesBlockService) process(t dia.Trade) {
 					t.BaseToken.Blockchain,
 				)
 			} else {
-				if price > 0.0 {
-					t.EstimatedUSDPrice = t.Price * price
+				if quotation.Price > 0.0 {
+					t.SetEstimation(path, quotation.Price, quotation.Time)
 					if t.EstimatedUSDPrice > 0 {
 						verifiedTrade = true
 					}
@@ -183,7 +383,7 @@ func (s *TradesBlockService) process(t dia.Trade) {
 
 	// // If estimated price for stablecoin diverges too much ignore trade
 	if _, ok := stablecoins[t.Symbol]; ok {
-		if math.Abs(t.EstimatedUSDPrice-1) > tol {
+		if math.Abs(t.EstimatedUSDPrice-1) > s.getConfig().StablecoinTolerance {
 			log.Errorf("price for stablecoin %s diverges by %v", t.Symbol, math.Abs(t.EstimatedUSDPrice-1))
 			verifiedTrade = false
 		}
@@ -213,7 +413,7 @@ func (s *TradesBlockService) process(t dia.Trade) {
 		if s.currentBlock == nil || s.currentBlock.TradesBlockData.EndTime.Before(t.Time) {
 			if s.currentBlock != nil {
 				s.finaliseCurrentBlock()
-				s.priceCache = make(map[dia.Asset]float64)
+				s.priceCache = make(map[dia.Asset]models.AssetQuotation)
 			}
 
 			b := &dia.TradesBlock{
@@ -227,10 +427,7 @@ func (s *TradesBlockService) process(t dia.Trade) {
 				log.Info("created new block beginTime:", b.TradesBlockData.BeginTime, "previous block nb trades:", len(s.currentBlock.TradesBlockData.Trades))
 			}
 			s.currentBlock = b
-			err = s.datastore.Flush()
-			if err != nil {
-				log.Error(err)
-			}
+			s.flush()
 		}
 		// For centralized exchanges check if trade is not in the block yet
 		// (we have observed ws APIs sending identical trades).
@@ -298,13 +495,57 @@ func (s *TradesBlockService) Channel() chan *dia.TradesBlock {
 }
 
 func (s *TradesBlockService) checkTrade(t dia.Trade) bool {
-	if math.Abs(t.Volume) < tradeVolumeThreshold {
+	if math.Abs(t.Volume) < s.getConfig().TradeVolumeThreshold {
 		log.Info("low volume trade: ", t)
 		return false
 	}
 	return true
 }
 
+// checkQuoteConstraint returns false if the basetoken of @t is not accepted for price computation of its quotetoken.
+func (s *TradesBlockService) checkQuoteConstraint(t dia.Trade) bool {
+	s.quoteConstraintsLock.RLock()
+	defer s.quoteConstraintsLock.RUnlock()
+	accepted, ok := s.quoteConstraints[constraintKey(t.QuoteToken)]
+	if !ok {
+		return true
+	}
+	if _, ok = accepted[constraintKey(t.BaseToken)]; ok {
+		return true
+	}
+	if _, ok = accepted[constraintKey(buildBridge(t))]; ok {
+		return true
+	}
+	log.Debugf("quote asset %s not accepted for %s", t.BaseToken.Symbol, t.QuoteToken.Symbol)
+	return false
+}
+
+// isDeprecated returns true if @asset is excluded from price computation.
+func (s *TradesBlockService) isDeprecated(asset dia.Asset) bool {
+	s.deprecatedAssetsLock.RLock()
+	defer s.deprecatedAssetsLock.RUnlock()
+	_, ok := s.deprecatedAssets[constraintKey(asset)]
+	return ok
+}
+
+// isExcludedPair returns true if the pair with @foreignname on @exchange is manually excluded.
+func (s *TradesBlockService) isExcludedPair(exchange string, foreignname string) bool {
+	s.excludedPairsLock.RLock()
+	defer s.excludedPairsLock.RUnlock()
+	_, ok := s.excludedPairs[exchangePairKey(exchange, foreignname)]
+	return ok
+}
+
+// exchangePairKey identifies the pair with @foreignname on @exchange.
+func exchangePairKey(exchange string, foreignname string) string {
+	return exchange + ":" + foreignname
+}
+
+// constraintKey reduces @asset to the fields that uniquely identify it.
+func constraintKey(asset dia.Asset) dia.Asset {
+	return dia.Asset{Address: asset.Address, Blockchain: asset.Blockchain}
+}
+
 func buildBridge(t dia.Trade) dia.Asset {
 
 	basetoken := t.BaseToken
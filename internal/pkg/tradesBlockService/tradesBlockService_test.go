@@ -0,0 +1,25 @@
+package tradesBlockService
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestProcessExcludedPair(t *testing.T) {
+	s := &TradesBlockService{excludedTrades: make(map[string]int)}
+	s.SetExcludedPairs([]dia.ExchangePair{{Exchange: dia.BinanceExchange, ForeignName: "BTC-USDT"}})
+
+	s.process(dia.Trade{Source: dia.BinanceExchange, Pair: "BTC-USDT", VerifiedPair: true})
+	if count := s.excludedTrades["BTC-USDT on "+dia.BinanceExchange]; count != 1 {
+		t.Errorf("expected trade of excluded pair to be dropped, got %d drops", count)
+	}
+	if s.isExcludedPair(dia.KrakenExchange, "BTC-USDT") || s.isExcludedPair(dia.BinanceExchange, "ETH-USDT") {
+		t.Error("expected pairs on other exchanges resp. other pairs not to be excluded")
+	}
+
+	s.SetExcludedPairs(nil)
+	if s.isExcludedPair(dia.BinanceExchange, "BTC-USDT") {
+		t.Error("expected pair to be ingested again once the exclusion is lifted")
+	}
+}
@@ -35,8 +35,9 @@ const (
 	priceFrame = 1000 * 120
 )
 
+// pricetime is a quotation of a base token, cached at the time @Timestamp of the trade it was queried for.
 type pricetime struct {
-	Price     float64
+	Quotation models.AssetQuotation
 	Timestamp time.Time
 }
 
@@ -83,33 +84,33 @@ func (s *TradesEstimationService) mainLoop() {
 func (s *TradesEstimationService) process(t dia.Trade) {
 
 	var verifiedTrade bool
-	var price float64
 	var err error
 
 	// Price estimation can only be done for verified pairs.
 	if t.VerifiedPair {
 		if t.BaseToken.Address == "840" && t.BaseToken.Blockchain == dia.FIAT {
 			// All prices are measured in US-Dollar, so just price for base token == USD
-			t.EstimatedUSDPrice = t.Price
+			t.SetEstimation(dia.ESTIMATION_USD, 1, t.Time)
 			verifiedTrade = true
 		} else {
 			// Check if price cache is still valid:
-			_, ok := s.priceCache[t.BaseToken]
-			if ok && t.Time.Sub(s.priceCache[t.BaseToken].Timestamp) < time.Duration(priceFrame*time.Millisecond) {
-				price = s.priceCache[t.BaseToken].Price
+			var quotation *models.AssetQuotation
+			cached, ok := s.priceCache[t.BaseToken]
+			if ok && t.Time.Sub(cached.Timestamp) < time.Duration(priceFrame*time.Millisecond) {
+				quotation = &cached.Quotation
 			} else {
 				// Look for historic price of base token at trade time...
-				price, err = s.datastore.GetAssetPriceUSD(t.BaseToken, t.Time)
+				quotation, err = s.datastore.GetAssetQuotation(t.BaseToken, t.Time)
 				s.priceCache[t.BaseToken] = pricetime{
-					Price:     price,
+					Quotation: *quotation,
 					Timestamp: t.Time,
 				}
 			}
 			if err != nil {
 				log.Errorf("Cannot use trade %s. Can't find quotation for base token.", t.Pair)
 			} else {
-				if price > 0.0 {
-					t.EstimatedUSDPrice = t.Price * price
+				if quotation.Price > 0.0 {
+					t.SetEstimation(dia.ESTIMATION_HISTORICAL, quotation.Price, quotation.Time)
 					if t.EstimatedUSDPrice > 0 {
 						verifiedTrade = true
 					}
@@ -50,6 +50,17 @@ const (
 
 type VerificationMechanism string
 
+// ChainType is the family of a blockchain. Chains of the same type share address formats.
+type ChainType string
+
+const (
+	CHAIN_TYPE_EVM       ChainType = "evm"
+	CHAIN_TYPE_UTXO      ChainType = "utxo"
+	CHAIN_TYPE_COSMOS    ChainType = "cosmos"
+	CHAIN_TYPE_SOLANA    ChainType = "solana"
+	CHAIN_TYPE_SUBSTRATE ChainType = "substrate"
+)
+
 // NFTClass is the container for a nft class defined by
 // a contract (address) on a blockchain.
 type NFTClass struct {
@@ -237,6 +248,8 @@ type AssetVolume struct {
 	Volume    float64 `json:"Volume"`
 	VolumeUSD float64 `json:"VolumeUSD"`
 	Index     uint8   `json:"Index"`
+	// Weight is the share of the asset in a weighted pool. It is zero for pools without weights.
+	Weight float64 `json:"Weight,omitempty"`
 }
 
 type AssetLiquidity struct {
@@ -244,6 +257,7 @@ type AssetLiquidity struct {
 	Volume    float64 `json:"Liquidity"`
 	VolumeUSD float64 `json:"LiquidityUSD"`
 	Index     uint8   `json:"Index"`
+	Weight    float64 `json:"Weight,omitempty"`
 }
 
 type TopAsset struct {
@@ -291,6 +305,27 @@ type Exchange struct {
 	ScraperActive bool       `json:"ScraperActive"`
 }
 
+// ListingType distinguishes listing from delisting announcements.
+type ListingType string
+
+const (
+	LISTING   ListingType = "listing"
+	DELISTING ListingType = "delisting"
+)
+
+// ExchangeListing is an announcement of @Exchange about the (de)listing of a market.
+// @EffectiveTime is the announced start (resp. end) of trading and may lie in the future.
+type ExchangeListing struct {
+	Exchange      string      `json:"Exchange"`
+	ForeignName   string      `json:"ForeignName"`
+	Symbol        string      `json:"Symbol"`
+	Asset         Asset       `json:"Asset"`
+	Type          ListingType `json:"Type"`
+	AnnouncedTime time.Time   `json:"AnnouncedTime"`
+	EffectiveTime time.Time   `json:"EffectiveTime"`
+	Source        string      `json:"Source"`
+}
+
 type NFTExchange struct {
 	Name          string     `json:"Name"`
 	Centralized   bool       `json:"Centralized"`
@@ -322,6 +357,20 @@ func (asset *Asset) Identifier() string {
 	return asset.Blockchain + "-" + asset.Address
 }
 
+// AssetMetadata holds descriptive information on an asset such as its logo and project website.
+// SocialLinks maps a platform name such as "twitter" to the corresponding url.
+// Tags are free categories such as "stablecoin" which are exported to token lists.
+type AssetMetadata struct {
+	Asset       Asset             `json:"Asset"`
+	Logo        string            `json:"Logo"`
+	Website     string            `json:"Website"`
+	Description string            `json:"Description"`
+	SocialLinks map[string]string `json:"SocialLinks"`
+	Tags        []string          `json:"Tags"`
+	Display     DisplayFormat     `json:"Display"`
+	LastUpdate  time.Time         `json:"LastUpdate"`
+}
+
 // BlockChain is the type for blockchains. Uniquely defined by its @Name.
 type BlockChain struct {
 	Name string `json:"Name"`
@@ -331,7 +380,9 @@ type BlockChain struct {
 	// Verificationmechanism is in short notation, such as pos for proof-of-stake
 	VerificationMechanism VerificationMechanism `json:"VerificationMechanism"`
 	// ChainID refers to EVM based chains and is thereby optional.
-	ChainID string `json:"ChainID"`
+	ChainID   string    `json:"ChainID"`
+	IsTestnet bool      `json:"IsTestnet"`
+	ChainType ChainType `json:"ChainType"`
 }
 
 type ChainConfig struct {
@@ -475,6 +526,47 @@ type Trade struct {
 	EstimatedUSDPrice float64   `json:"EstimatedUSDPrice"` // will be filled by the TradesBlockService
 	Source            string    `json:"Source"`
 	VerifiedPair      bool      `json:"VerifiedPair"` // will be filled by the pairDiscoveryService
+	// Estimation records how EstimatedUSDPrice was derived. Will be filled by the TradesBlockService.
+	Estimation TradeEstimation `json:"Estimation"`
+}
+
+// Methods for the estimation of the USD price of a trade.
+const (
+	// ESTIMATION_USD is used for trades with US-Dollar as base token.
+	ESTIMATION_USD = "usd"
+	// ESTIMATION_LATEST is used for trades priced with the latest quotation of the base token.
+	ESTIMATION_LATEST = "latest"
+	// ESTIMATION_HISTORICAL is used for trades priced with the quotation of the base token at trade time.
+	ESTIMATION_HISTORICAL = "historical"
+)
+
+// TradeEstimation holds the base token price which was used to estimate the USD price of a trade.
+// @Path is the estimation method, followed by the asset whose quotation was used if the
+// base token was bridged to another asset, such as "latest:Ethereum:0x0000000000000000000000000000000000000000".
+type TradeEstimation struct {
+	Path          string    `json:"Path"`
+	BasePrice     float64   `json:"BasePrice"`
+	BasePriceTime time.Time `json:"BasePriceTime"`
+}
+
+// EstimationPath returns the path of an estimation with @method, where @basetoken was priced
+// using the quotation of @pricedAsset.
+func EstimationPath(method string, basetoken Asset, pricedAsset Asset) string {
+	if basetoken.Blockchain == pricedAsset.Blockchain && basetoken.Address == pricedAsset.Address {
+		return method
+	}
+	return method + ":" + pricedAsset.Blockchain + ":" + pricedAsset.Address
+}
+
+// SetEstimation sets the estimated USD price of @t from the USD price @basePrice of its base token,
+// valid at @basePriceTime, and records the estimation with @path.
+func (t *Trade) SetEstimation(path string, basePrice float64, basePriceTime time.Time) {
+	t.EstimatedUSDPrice = t.Price * basePrice
+	t.Estimation = TradeEstimation{
+		Path:          path,
+		BasePrice:     basePrice,
+		BasePriceTime: basePriceTime,
+	}
 }
 
 func (t *Trade) VolumeUSD() float64 {
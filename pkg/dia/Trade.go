@@ -1,6 +1,8 @@
 package dia
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
@@ -105,6 +107,13 @@ func (t *Trade) TradeIdentifierFull() string {
 		t.BaseToken.Blockchain
 }
 
+// IdempotencyKey returns a deterministic hash of all fields of a trade. Redeliveries of a trade
+// by the message bus share the key, so that they can be recognised at the storage layer.
+func (t *Trade) IdempotencyKey() string {
+	hash := sha256.Sum256([]byte(t.TradeIdentifierFull()))
+	return hex.EncodeToString(hash[:])
+}
+
 // TradeIdentifierTagset returns an identifier with respect to the tagset of a trade in Influx.
 // In other words, a trade with this same tagset is overwritten in Influx trades table.
 func (t *Trade) TradeIdentifierTagset() string {
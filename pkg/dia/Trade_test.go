@@ -2,6 +2,7 @@ package dia
 
 import (
 	"testing"
+	"time"
 )
 
 func TestTrade(t *testing.T) {
@@ -43,3 +44,45 @@ func TestBitfinexAndHitBTCSecondPairAreUSDT(t *testing.T) {
 		t.Errorf("error base token %v", r)
 	}
 }
+
+func TestSetEstimation(t *testing.T) {
+	weth := Asset{Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", Blockchain: ETHEREUM}
+	eth := Asset{Address: NATIVE_ASSET_ADDRESS, Blockchain: ETHEREUM}
+	priceTime := time.Unix(1650000000, 0)
+
+	trade := &Trade{Price: 0.5, BaseToken: weth}
+	trade.SetEstimation(EstimationPath(ESTIMATION_LATEST, weth, eth), 3000, priceTime)
+	if trade.EstimatedUSDPrice != 1500 {
+		t.Errorf("error estimated price %v", trade.EstimatedUSDPrice)
+	}
+	if trade.Estimation.Path != "latest:Ethereum:"+NATIVE_ASSET_ADDRESS {
+		t.Errorf("error estimation path %v", trade.Estimation.Path)
+	}
+	if trade.Estimation.BasePrice != 3000 || !trade.Estimation.BasePriceTime.Equal(priceTime) {
+		t.Errorf("error estimation %v", trade.Estimation)
+	}
+
+	if path := EstimationPath(ESTIMATION_HISTORICAL, weth, weth); path != ESTIMATION_HISTORICAL {
+		t.Errorf("error estimation path %v", path)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	trade := Trade{
+		Source:         "Binance",
+		Price:          20000,
+		Volume:         0.5,
+		Time:           time.Unix(1700000000, 0),
+		ForeignTradeID: "42",
+		QuoteToken:     Asset{Blockchain: BITCOIN, Address: "0x0000000000000000000000000000000000000000"},
+		BaseToken:      Asset{Blockchain: ETHEREUM, Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7"},
+	}
+	redelivered := trade
+	if trade.IdempotencyKey() != redelivered.IdempotencyKey() {
+		t.Error("expected equal keys for redelivered trade")
+	}
+	redelivered.ForeignTradeID = "43"
+	if trade.IdempotencyKey() == redelivered.IdempotencyKey() {
+		t.Error("expected different keys for different trades")
+	}
+}
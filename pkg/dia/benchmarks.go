@@ -0,0 +1,84 @@
+package dia
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// BENCHMARK is the blockchain of benchmark assets. Their address is a free identifier such as BTC-ETH-5050.
+const BENCHMARK = "Benchmark"
+
+// Methods for the computation of the price of a benchmark asset from the prices of its components.
+const (
+	// BENCHMARK_SUM is the weighted sum of the component prices, as for a basket of @Weight units per component.
+	BENCHMARK_SUM = "sum"
+	// BENCHMARK_PRODUCT is the product of the component prices raised to their @Exponent, as for
+	// an FX cross such as EUR/JPY with exponents 1 and -1.
+	BENCHMARK_PRODUCT = "product"
+)
+
+// BenchmarkComponent is an asset whose price enters the price of a benchmark asset.
+// @Weight is used by BENCHMARK_SUM and @Exponent by BENCHMARK_PRODUCT.
+type BenchmarkComponent struct {
+	Asset    Asset   `json:"Asset"`
+	Weight   float64 `json:"Weight"`
+	Exponent float64 `json:"Exponent"`
+}
+
+// BenchmarkAsset is a synthetic asset whose price is computed from the prices of its @Components
+// according to @Method.
+type BenchmarkAsset struct {
+	Asset      Asset                `json:"Asset"`
+	Method     string               `json:"Method"`
+	Components []BenchmarkComponent `json:"Components"`
+}
+
+// Validate returns an error if the price of @b cannot be computed.
+func (b *BenchmarkAsset) Validate() error {
+	if b.Asset.Address == "" || b.Asset.Blockchain == "" {
+		return errors.New("benchmark asset without address or blockchain")
+	}
+	if len(b.Components) == 0 {
+		return errors.New("benchmark asset without components")
+	}
+	if b.Method != BENCHMARK_SUM && b.Method != BENCHMARK_PRODUCT {
+		return fmt.Errorf("unknown benchmark method %q", b.Method)
+	}
+	for _, component := range b.Components {
+		if component.Asset.Identifier() == b.Asset.Identifier() {
+			return errors.New("benchmark asset cannot be its own component")
+		}
+		if b.Method == BENCHMARK_SUM && component.Weight == 0 {
+			return fmt.Errorf("component %s has zero weight", component.Asset.Identifier())
+		}
+		if b.Method == BENCHMARK_PRODUCT && component.Exponent == 0 {
+			return fmt.Errorf("component %s has zero exponent", component.Asset.Identifier())
+		}
+	}
+	return nil
+}
+
+// Price returns the price of @b given the prices of its components, keyed by asset identifier.
+// All component prices must be positive.
+func (b *BenchmarkAsset) Price(prices map[string]float64) (float64, error) {
+	price := 0.0
+	if b.Method == BENCHMARK_PRODUCT {
+		price = 1
+	}
+	for _, component := range b.Components {
+		componentPrice, ok := prices[component.Asset.Identifier()]
+		if !ok || componentPrice <= 0 {
+			return 0, fmt.Errorf("no price for component %s", component.Asset.Identifier())
+		}
+		switch b.Method {
+		case BENCHMARK_SUM:
+			price += component.Weight * componentPrice
+		case BENCHMARK_PRODUCT:
+			price *= math.Pow(componentPrice, component.Exponent)
+		default:
+			return 0, fmt.Errorf("unknown benchmark method %q", b.Method)
+		}
+	}
+	return price, nil
+}
@@ -0,0 +1,61 @@
+package dia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBenchmarkAssetPrice(t *testing.T) {
+	btc := Asset{Address: NATIVE_ASSET_ADDRESS, Blockchain: BITCOIN}
+	eth := Asset{Address: NATIVE_ASSET_ADDRESS, Blockchain: ETHEREUM}
+	eur := Asset{Address: "978", Blockchain: FIAT}
+	jpy := Asset{Address: "392", Blockchain: FIAT}
+	prices := map[string]float64{
+		btc.Identifier(): 30000,
+		eth.Identifier(): 2000,
+		eur.Identifier(): 1.1,
+		jpy.Identifier(): 0.0073,
+	}
+
+	basket := BenchmarkAsset{
+		Asset:  Asset{Address: "BTC-ETH", Blockchain: BENCHMARK},
+		Method: BENCHMARK_SUM,
+		Components: []BenchmarkComponent{
+			{Asset: btc, Weight: 0.5},
+			{Asset: eth, Weight: 7.5},
+		},
+	}
+	if err := basket.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	price, err := basket.Price(prices)
+	if err != nil || price != 30000 {
+		t.Errorf("basket price %v, %v", price, err)
+	}
+
+	cross := BenchmarkAsset{
+		Asset:  Asset{Address: "EUR-JPY", Blockchain: BENCHMARK},
+		Method: BENCHMARK_PRODUCT,
+		Components: []BenchmarkComponent{
+			{Asset: eur, Exponent: 1},
+			{Asset: jpy, Exponent: -1},
+		},
+	}
+	if err := cross.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	price, err = cross.Price(prices)
+	if err != nil || math.Abs(price-1.1/0.0073) > 1e-9 {
+		t.Errorf("cross price %v, %v", price, err)
+	}
+
+	delete(prices, jpy.Identifier())
+	if _, err = cross.Price(prices); err == nil {
+		t.Error("expected error for missing component price")
+	}
+
+	cross.Components[1].Exponent = 0
+	if err = cross.Validate(); err == nil {
+		t.Error("expected error for zero exponent")
+	}
+}
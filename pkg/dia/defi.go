@@ -0,0 +1,37 @@
+package dia
+
+import "time"
+
+// ProtocolTVL is the total value locked in USD of a DeFi @Protocol on @Blockchain at @Time.
+type ProtocolTVL struct {
+	Protocol   string    `json:"Protocol"`
+	Blockchain string    `json:"Blockchain"`
+	TVL        float64   `json:"TVL"`
+	Time       time.Time `json:"Time"`
+}
+
+// ChainTVL is the aggregated total value locked in USD of the @NumProtocols DeFi protocols on @Blockchain.
+type ChainTVL struct {
+	Blockchain   string    `json:"Blockchain"`
+	TVL          float64   `json:"TVL"`
+	NumProtocols int       `json:"NumProtocols"`
+	Time         time.Time `json:"Time"`
+}
+
+// DefiProtocol is a DeFi lending protocol on @Blockchain, such as a money market.
+// @Address is the address of the protocol's main contract, for instance its lending pool or comptroller.
+type DefiProtocol struct {
+	Name       string `json:"Name"`
+	Blockchain string `json:"Blockchain"`
+	Address    string `json:"Address"`
+	URL        string `json:"URL"`
+}
+
+// DefiRate holds the annual percentage yields for supplying and borrowing @Asset on @Protocol at @Time.
+type DefiRate struct {
+	Protocol  string    `json:"Protocol"`
+	Asset     Asset     `json:"Asset"`
+	SupplyAPY float64   `json:"SupplyAPY"`
+	BorrowAPY float64   `json:"BorrowAPY"`
+	Time      time.Time `json:"Time"`
+}
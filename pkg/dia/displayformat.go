@@ -0,0 +1,45 @@
+package dia
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DisplayFormat holds the rules for displaying prices of an asset. Prices are rounded to a multiple
+// of @TickSize and to at most @MaxDecimals decimal places. Zero values impose no restriction.
+type DisplayFormat struct {
+	TickSize    float64 `json:"TickSize"`
+	MaxDecimals int     `json:"MaxDecimals"`
+}
+
+// IsZero returns true if @f imposes no formatting.
+func (f DisplayFormat) IsZero() bool {
+	return f.TickSize <= 0 && f.MaxDecimals <= 0
+}
+
+// Round returns @price rounded according to @f. The result has no more decimal places than
+// @f.TickSize, so that rounding to a tick does not leave floating point residues.
+func (f DisplayFormat) Round(price float64) float64 {
+	if f.IsZero() {
+		return price
+	}
+	decimals := f.MaxDecimals
+	if f.TickSize > 0 {
+		price = math.Round(price/f.TickSize) * f.TickSize
+		if tickDecimals := decimalPlaces(f.TickSize); f.MaxDecimals <= 0 || tickDecimals < decimals {
+			decimals = tickDecimals
+		}
+	}
+	pow := math.Pow10(decimals)
+	return math.Round(price*pow) / pow
+}
+
+// decimalPlaces returns the number of decimal places in the shortest representation of @x.
+func decimalPlaces(x float64) int {
+	s := strconv.FormatFloat(x, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
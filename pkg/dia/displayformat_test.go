@@ -0,0 +1,24 @@
+package dia
+
+import "testing"
+
+func TestDisplayFormatRound(t *testing.T) {
+	cases := []struct {
+		format DisplayFormat
+		price  float64
+		want   float64
+	}{
+		{DisplayFormat{}, 1.23456789, 1.23456789},
+		{DisplayFormat{TickSize: 0.01}, 1.23456789, 1.23},
+		{DisplayFormat{TickSize: 0.1}, 0.25, 0.3},
+		{DisplayFormat{TickSize: 0.05}, 1.23456789, 1.25},
+		{DisplayFormat{TickSize: 5}, 1234.5, 1235},
+		{DisplayFormat{MaxDecimals: 4}, 1.23456789, 1.2346},
+		{DisplayFormat{TickSize: 0.0001, MaxDecimals: 2}, 1.23456789, 1.23},
+	}
+	for _, c := range cases {
+		if got := c.format.Round(c.price); got != c.want {
+			t.Errorf("round %v with %+v: got %v, want %v", c.price, c.format, got, c.want)
+		}
+	}
+}
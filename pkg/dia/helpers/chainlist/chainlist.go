@@ -0,0 +1,111 @@
+package chainlist
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+// CHAINLIST_URL is the public list of EVM chains by EIP-155 chain ID as used by chainlist.org.
+const CHAINLIST_URL = "https://chainid.network/chains.json"
+
+// Chain is an entry of the chainlist.
+type Chain struct {
+	Name           string         `json:"name"`
+	ChainID        int64          `json:"chainId"`
+	NativeCurrency NativeCurrency `json:"nativeCurrency"`
+	RPC            []string       `json:"rpc"`
+	Faucets        []string       `json:"faucets"`
+	Status         string         `json:"status"`
+}
+
+// NativeCurrency is the native coin of a chain in the chainlist.
+type NativeCurrency struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// FetchChains returns all chains listed at @url.
+func FetchChains(url string) (chains []Chain, err error) {
+	data, _, err := utils.GetRequest(url)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &chains)
+	return
+}
+
+// IsMainnet returns false for testnets, which come with faucets, and for deprecated chains.
+func (c *Chain) IsMainnet() bool {
+	return len(c.Faucets) == 0 && c.Status != "deprecated" && !strings.Contains(strings.ToLower(c.Name), "testnet")
+}
+
+// BlockchainName returns the name of @c in DIA notation, i.e. in camel case without spaces
+// and without a Mainnet suffix, such as BinanceSmartChain.
+func (c *Chain) BlockchainName() string {
+	var name strings.Builder
+	for _, word := range strings.Fields(c.Name) {
+		if strings.EqualFold(word, "Mainnet") {
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		for _, r := range runes {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				name.WriteRune(r)
+			}
+		}
+	}
+	return name.String()
+}
+
+// DIAChainID returns the chain ID of @c as stored in the blockchain table, such as Ethereum56.
+func (c *Chain) DIAChainID() string {
+	return dia.ETHEREUM + strconv.FormatInt(c.ChainID, 10)
+}
+
+// NativeToken returns the native coin of @c as an asset.
+func (c *Chain) NativeToken() dia.Asset {
+	return dia.Asset{
+		Symbol:     c.NativeCurrency.Symbol,
+		Name:       c.NativeCurrency.Name,
+		Address:    dia.NATIVE_ASSET_ADDRESS,
+		Decimals:   c.NativeCurrency.Decimals,
+		Blockchain: c.BlockchainName(),
+	}
+}
+
+// BlockChain returns @c as a blockchain. The genesis date and verification mechanism are not
+// part of the chainlist and left empty.
+func (c *Chain) BlockChain() dia.BlockChain {
+	return dia.BlockChain{
+		Name:        c.BlockchainName(),
+		NativeToken: c.NativeToken(),
+		ChainID:     c.DIAChainID(),
+		IsTestnet:   !c.IsMainnet(),
+		ChainType:   dia.CHAIN_TYPE_EVM,
+	}
+}
+
+// ChainConfig returns the first public http and websocket RPC URLs of @c. RPC URLs requiring an
+// API key are skipped. ok is false if @c has no public http RPC.
+func (c *Chain) ChainConfig() (config dia.ChainConfig, ok bool) {
+	for _, rpc := range c.RPC {
+		if strings.Contains(rpc, "${") {
+			continue
+		}
+		if config.RestURL == "" && strings.HasPrefix(rpc, "http") {
+			config.RestURL = rpc
+		}
+		if config.WSURL == "" && strings.HasPrefix(rpc, "ws") {
+			config.WSURL = rpc
+		}
+	}
+	config.ChainID = strconv.FormatInt(c.ChainID, 10)
+	return config, config.RestURL != ""
+}
@@ -0,0 +1,46 @@
+package chainlist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testChains = `[
+	{
+		"name": "Gnosis Mainnet",
+		"chainId": 100,
+		"nativeCurrency": {"name": "xDAI", "symbol": "XDAI", "decimals": 18},
+		"rpc": ["https://rpc.gnosischain.com/${API_KEY}", "https://rpc.gnosischain.com", "wss://rpc.gnosischain.com/wss"],
+		"faucets": []
+	},
+	{
+		"name": "Sepolia",
+		"chainId": 11155111,
+		"nativeCurrency": {"name": "Sepolia Ether", "symbol": "ETH", "decimals": 18},
+		"rpc": ["https://rpc.sepolia.org"],
+		"faucets": ["https://faucet.sepolia.dev"]
+	}
+]`
+
+func TestChain(t *testing.T) {
+	var chains []Chain
+	if err := json.Unmarshal([]byte(testChains), &chains); err != nil {
+		t.Fatal(err)
+	}
+	gnosis, sepolia := chains[0], chains[1]
+
+	if !gnosis.IsMainnet() || sepolia.IsMainnet() {
+		t.Error("expected Gnosis to be a mainnet and Sepolia a testnet")
+	}
+	blockchain := gnosis.BlockChain()
+	if blockchain.Name != "Gnosis" || blockchain.ChainID != "Ethereum100" {
+		t.Errorf("unexpected blockchain %v", blockchain)
+	}
+	if blockchain.NativeToken.Symbol != "XDAI" || blockchain.NativeToken.Blockchain != "Gnosis" || blockchain.NativeToken.Decimals != 18 {
+		t.Errorf("unexpected native token %v", blockchain.NativeToken)
+	}
+	config, ok := gnosis.ChainConfig()
+	if !ok || config.RestURL != "https://rpc.gnosischain.com" || config.WSURL != "wss://rpc.gnosischain.com/wss" || config.ChainID != "100" {
+		t.Errorf("unexpected chain config %v", config)
+	}
+}
@@ -12,23 +12,24 @@ func init() {
 	log = logrus.New()
 }
 
+// GetInfluxConfig returns the config of an influx client connecting through the
+// URL given in the environment variable INFLUXURL.
+// If INFLUXURL is not set, it connects to @url per default.
+func GetInfluxConfig(url string) clientInfluxdb.HTTPConfig {
+	return clientInfluxdb.HTTPConfig{
+		Addr:     utils.Getenv("INFLUXURL", url),
+		Username: utils.Getenv("INFLUXUSER", ""),
+		Password: utils.Getenv("INFLUXPASSWORD", ""),
+	}
+}
+
 // GetInfluxClient returns an influx client connecting through the
 // URL given in the environment variable INFLUXURL.
 // If INFLUXURL is not set, it connects to @url per default.
 func GetInfluxClient(url string) clientInfluxdb.Client {
-	var influxClient clientInfluxdb.Client
-	var err error
-
-	address := utils.Getenv("INFLUXURL", url)
-	log.Info("INFLUXURL: ", address)
-	username := utils.Getenv("INFLUXUSER", "")
-	password := utils.Getenv("INFLUXPASSWORD", "")
-	influxClient, err = clientInfluxdb.NewHTTPClient(clientInfluxdb.HTTPConfig{
-		Addr:     address,
-		Username: username,
-		Password: password,
-	})
-
+	config := GetInfluxConfig(url)
+	log.Info("INFLUXURL: ", config.Addr)
+	influxClient, err := clientInfluxdb.NewHTTPClient(config)
 	if err != nil {
 		log.Error("NewDataStore influxdb", err)
 	}
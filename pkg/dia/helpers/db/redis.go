@@ -1,30 +1,87 @@
 package db
 
 import (
+	"context"
 	"strconv"
+	"strings"
 
 	"github.com/diadata-org/diadata/pkg/utils"
-	"github.com/go-redis/redis"
+	"github.com/go-redis/redis/v8"
 )
 
-func GetRedisClient() *redis.Client {
-	var redisClient *redis.Client
+// RedisConfig is the configuration of a redis client.
+// If @MasterName is set, @Addrs are sentinels which are asked for the address of the current master.
+// If @Cluster is set, @Addrs are seed nodes of a redis cluster. Otherwise @Addrs[0] is a single redis server.
+type RedisConfig struct {
+	Addrs            []string
+	MasterName       string
+	SentinelPassword string
+	Cluster          bool
+	Password         string
+	DB               int
+}
 
+// GetRedisConfig returns the redis config given by the environment variables REDISURL, a comma separated
+// list of addresses, REDISMASTERNAME for sentinel failover and REDISCLUSTER for a redis cluster.
+func GetRedisConfig() RedisConfig {
 	// This environment variable is either set in docker-compose or empty
-	address := utils.Getenv("REDISURL", "localhost:6379")
-	password := utils.Getenv("REDISPASSWORD", "")
+	var addrs []string
+	for _, addr := range strings.Split(utils.Getenv("REDISURL", "localhost:6379"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
 	defaultDB, err := strconv.Atoi(utils.Getenv("REDISUSEDEFAULTDB", "0"))
 	if err != nil {
 		log.Error("wrong value for redis default db", err)
 	}
+	cluster, err := strconv.ParseBool(utils.Getenv("REDISCLUSTER", "false"))
+	if err != nil {
+		log.Error("wrong value for redis cluster", err)
+	}
 
-	redisClient = redis.NewClient(&redis.Options{
-		Addr:     address,
-		Password: password,  // no password set
-		DB:       defaultDB, // use default DB
-	})
+	return RedisConfig{
+		Addrs:            addrs,
+		MasterName:       utils.Getenv("REDISMASTERNAME", ""),
+		SentinelPassword: utils.Getenv("REDISSENTINELPASSWORD", ""),
+		Cluster:          cluster,
+		Password:         utils.Getenv("REDISPASSWORD", ""),
+		DB:               defaultDB,
+	}
+}
 
-	pong2, err := redisClient.Ping().Result()
+// NewRedisClient returns a client for the single server, sentinel or cluster setup given by @config.
+func NewRedisClient(config RedisConfig) redis.UniversalClient {
+	var redisClient redis.UniversalClient
+
+	switch {
+	case config.MasterName != "":
+		redisClient = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       config.MasterName,
+			SentinelAddrs:    config.Addrs,
+			SentinelPassword: config.SentinelPassword,
+			Password:         config.Password,
+			DB:               config.DB,
+		})
+	case config.Cluster:
+		// Redis cluster only supports database 0.
+		redisClient = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    config.Addrs,
+			Password: config.Password,
+		})
+	default:
+		addr := "localhost:6379"
+		if len(config.Addrs) > 0 {
+			addr = config.Addrs[0]
+		}
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: config.Password,
+			DB:       config.DB,
+		})
+	}
+
+	pong2, err := redisClient.Ping(context.Background()).Result()
 	if err != nil {
 		log.Error("NewDataStore redis: ", err)
 	}
@@ -33,3 +90,8 @@ func GetRedisClient() *redis.Client {
 
 	return redisClient
 }
+
+// GetRedisClient returns a redis client configured by the environment, see GetRedisConfig.
+func GetRedisClient() redis.UniversalClient {
+	return NewRedisClient(GetRedisConfig())
+}
@@ -0,0 +1,139 @@
+// Package tradeContract validates the trades emitted by a scraper against invariants which every
+// scraper must satisfy before its trades are written to production storage.
+package tradeContract
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// Mode determines how a collector treats the trades of a scraper with respect to its contract.
+type Mode string
+
+const (
+	// ModeOff forwards all trades without checks.
+	ModeOff Mode = "off"
+	// ModeReport checks all trades and counts violations, but forwards all trades as usual.
+	// It is meant for existing scrapers before their contract is enforced.
+	ModeReport Mode = "report"
+	// ModeShadow checks all trades and reports violations, but keeps the scraper away from production
+	// storage. It is meant for new integrations.
+	ModeShadow Mode = "shadow"
+	// ModeEnforce forwards only trades which satisfy the contract.
+	ModeEnforce Mode = "enforce"
+)
+
+// ParseMode returns the mode named @s.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case ModeOff, ModeReport, ModeShadow, ModeEnforce:
+		return Mode(s), nil
+	default:
+		return "", fmt.Errorf("unknown contract mode %s", s)
+	}
+}
+
+// Contract holds the invariants of the trades of a scraper:
+//   - price is positive and finite and volume is non-zero and finite. The sign of the volume
+//     encodes the side of the trade.
+//   - the trade time lies within @MaxLag before and @MaxLead after the time of the check.
+//   - the source is the scraped exchange.
+//   - the pair is one of the known pairs of the exchange, if pairs are given.
+type Contract struct {
+	Exchange string
+	MaxLag   time.Duration
+	MaxLead  time.Duration
+	// foreignNames and assetPairs hold the known pairs by foreign name and by the identifiers of
+	// quote and base token respectively.
+	foreignNames map[string]struct{}
+	assetPairs   map[string]struct{}
+
+	mu         sync.Mutex
+	checked    int
+	violations map[string]int
+}
+
+// Report summarizes the checks of a contract.
+type Report struct {
+	Checked    int
+	Violations map[string]int
+}
+
+// New returns the contract for the trades of @exchange on @pairs. If @pairs is empty, the pair set is not checked.
+func New(exchange string, pairs []dia.ExchangePair, maxLag time.Duration, maxLead time.Duration) *Contract {
+	c := &Contract{
+		Exchange:     exchange,
+		MaxLag:       maxLag,
+		MaxLead:      maxLead,
+		foreignNames: make(map[string]struct{}),
+		assetPairs:   make(map[string]struct{}),
+		violations:   make(map[string]int),
+	}
+	for _, pair := range pairs {
+		if pair.ForeignName != "" {
+			c.foreignNames[strings.ToUpper(pair.ForeignName)] = struct{}{}
+		}
+		if pair.UnderlyingPair.QuoteToken.Address != "" && pair.UnderlyingPair.BaseToken.Address != "" {
+			c.assetPairs[assetPairKey(pair.UnderlyingPair.QuoteToken, pair.UnderlyingPair.BaseToken)] = struct{}{}
+		}
+	}
+	return c
+}
+
+// Check returns the violations of the contract by @t at time @now. The trade satisfies the contract
+// if no violations are returned.
+func (c *Contract) Check(t *dia.Trade, now time.Time) (violations []string) {
+	if t.Price <= 0 || math.IsNaN(t.Price) || math.IsInf(t.Price, 0) {
+		violations = append(violations, "price")
+	}
+	if t.Volume == 0 || math.IsNaN(t.Volume) || math.IsInf(t.Volume, 0) {
+		violations = append(violations, "volume")
+	}
+	if t.Time.Before(now.Add(-c.MaxLag)) || t.Time.After(now.Add(c.MaxLead)) {
+		violations = append(violations, "time")
+	}
+	if t.Source != c.Exchange {
+		violations = append(violations, "source")
+	}
+	if !c.knownPair(t) {
+		violations = append(violations, "pair")
+	}
+
+	c.mu.Lock()
+	c.checked++
+	for _, violation := range violations {
+		c.violations[violation]++
+	}
+	c.mu.Unlock()
+	return
+}
+
+// Report returns the number of checked trades and of violations per invariant since the last report.
+func (c *Contract) Report() Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	report := Report{Checked: c.checked, Violations: c.violations}
+	c.checked = 0
+	c.violations = make(map[string]int)
+	return report
+}
+
+func (c *Contract) knownPair(t *dia.Trade) bool {
+	if len(c.foreignNames) == 0 && len(c.assetPairs) == 0 {
+		return true
+	}
+	if _, ok := c.foreignNames[strings.ToUpper(t.Pair)]; ok {
+		return true
+	}
+	_, ok := c.assetPairs[assetPairKey(t.QuoteToken, t.BaseToken)]
+	return ok
+}
+
+func assetPairKey(quoteToken dia.Asset, baseToken dia.Asset) string {
+	return strings.ToLower(quoteToken.Blockchain + "-" + quoteToken.Address + "-" + baseToken.Blockchain + "-" + baseToken.Address)
+}
@@ -0,0 +1,48 @@
+package tradeContract
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	btc := dia.Asset{Symbol: "BTC", Address: "0x0000000000000000000000000000000000000000", Blockchain: "Bitcoin"}
+	usdt := dia.Asset{Symbol: "USDT", Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Blockchain: "Ethereum"}
+	pairs := []dia.ExchangePair{
+		{ForeignName: "BTC-USDT", UnderlyingPair: dia.Pair{QuoteToken: btc, BaseToken: usdt}},
+	}
+	contract := New(dia.BinanceExchange, pairs, time.Minute, 5*time.Second)
+
+	cases := []struct {
+		name  string
+		trade dia.Trade
+		want  []string
+	}{
+		{"valid sell", dia.Trade{Pair: "btc-usdt", Price: 20000, Volume: -0.5, Time: now, Source: dia.BinanceExchange}, nil},
+		{"valid by assets", dia.Trade{Pair: "XBTUSDT", QuoteToken: btc, BaseToken: usdt, Price: 20000, Volume: 1, Time: now, Source: dia.BinanceExchange}, nil},
+		{"zero price", dia.Trade{Pair: "BTC-USDT", Price: 0, Volume: 1, Time: now, Source: dia.BinanceExchange}, []string{"price"}},
+		{"nan volume", dia.Trade{Pair: "BTC-USDT", Price: 1, Volume: math.NaN(), Time: now, Source: dia.BinanceExchange}, []string{"volume"}},
+		{"stale", dia.Trade{Pair: "BTC-USDT", Price: 1, Volume: 1, Time: now.Add(-time.Hour), Source: dia.BinanceExchange}, []string{"time"}},
+		{"future", dia.Trade{Pair: "BTC-USDT", Price: 1, Volume: 1, Time: now.Add(time.Minute), Source: dia.BinanceExchange}, []string{"time"}},
+		{"unknown pair and source", dia.Trade{Pair: "ETH-USDT", Price: 1, Volume: 1, Time: now, Source: dia.KrakenExchange}, []string{"source", "pair"}},
+	}
+	for _, c := range cases {
+		trade := c.trade
+		if got := contract.Check(&trade, now); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	report := contract.Report()
+	if report.Checked != len(cases) || report.Violations["time"] != 2 || report.Violations["pair"] != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if report = contract.Report(); report.Checked != 0 || len(report.Violations) != 0 {
+		t.Errorf("report not reset: %+v", report)
+	}
+}
@@ -0,0 +1,50 @@
+package dia
+
+import "regexp"
+
+// MarketClass classifies a market of an exchange. Only live markets contribute to prices.
+type MarketClass string
+
+const (
+	MARKET_CLASS_LIVE    MarketClass = "live"
+	MARKET_CLASS_TESTNET MarketClass = "testnet"
+	MARKET_CLASS_DEMO    MarketClass = "demo"
+)
+
+// marketRule assigns @class to all markets whose foreign names match @pattern.
+type marketRule struct {
+	pattern *regexp.Regexp
+	class   MarketClass
+}
+
+// defaultMarketRules apply to all exchanges. They match foreign names carrying a separate test,
+// demo or sandbox marker, such as BTC-USD-TEST or DEMO_ETHUSDT.
+var defaultMarketRules = []marketRule{
+	{regexp.MustCompile(`(?i)(^|[^A-Z0-9])TEST(NET)?([^A-Z0-9]|$)`), MARKET_CLASS_TESTNET},
+	{regexp.MustCompile(`(?i)(^|[^A-Z0-9])(DEMO|SANDBOX|PAPER)([^A-Z0-9]|$)`), MARKET_CLASS_DEMO},
+}
+
+// exchangeMarketRules hold the rules for markets of single exchanges which are not covered by
+// defaultMarketRules. They are applied before the default rules.
+var exchangeMarketRules = map[string][]marketRule{
+	// Paper trading markets on Bitfinex are listed among the regular symbols, such as TESTBTC:TESTUSD.
+	BitfinexExchange: {
+		{regexp.MustCompile(`(?i)^TEST[A-Z0-9]*:TEST[A-Z0-9]*$`), MARKET_CLASS_DEMO},
+	},
+}
+
+// ClassifyMarket returns the class of the market with @foreignName on @exchange.
+func ClassifyMarket(exchange string, foreignName string) MarketClass {
+	for _, rule := range append(exchangeMarketRules[exchange], defaultMarketRules...) {
+		if rule.pattern.MatchString(foreignName) {
+			return rule.class
+		}
+	}
+	return MARKET_CLASS_LIVE
+}
+
+// IsExcludedMarket returns true if the market with @foreignName on @exchange is a sandbox, testnet
+// or demo market. Such markets must not contribute to prices.
+func IsExcludedMarket(exchange string, foreignName string) bool {
+	return ClassifyMarket(exchange, foreignName) != MARKET_CLASS_LIVE
+}
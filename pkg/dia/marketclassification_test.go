@@ -0,0 +1,25 @@
+package dia
+
+import "testing"
+
+func TestClassifyMarket(t *testing.T) {
+	cases := []struct {
+		exchange    string
+		foreignName string
+		want        MarketClass
+	}{
+		{BinanceExchange, "BTCUSDT", MARKET_CLASS_LIVE},
+		{BinanceExchange, "TESTUSDT", MARKET_CLASS_LIVE},
+		{CoinBaseExchange, "BTC-USD-TEST", MARKET_CLASS_TESTNET},
+		{KuCoinExchange, "ETH-USDT_TESTNET", MARKET_CLASS_TESTNET},
+		{OKExExchange, "DEMO_ETH-USDT", MARKET_CLASS_DEMO},
+		{BitfinexExchange, "TESTBTC:TESTUSD", MARKET_CLASS_DEMO},
+		{BitfinexExchange, "BTCUSD", MARKET_CLASS_LIVE},
+		{KrakenExchange, "TESTBTC:TESTUSD", MARKET_CLASS_LIVE},
+	}
+	for _, c := range cases {
+		if got := ClassifyMarket(c.exchange, c.foreignName); got != c.want {
+			t.Errorf("ClassifyMarket(%s, %s) = %s, want %s", c.exchange, c.foreignName, got, c.want)
+		}
+	}
+}
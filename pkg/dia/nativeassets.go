@@ -0,0 +1,83 @@
+package dia
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NATIVE_ASSET_ADDRESS is the address of the native coin of a blockchain, such as ETH on Ethereum
+// or BTC on Bitcoin. Native coins have no contract, so the zero address is used unless the
+// blockchain is listed in nativeAssetAddresses.
+const NATIVE_ASSET_ADDRESS = "0x0000000000000000000000000000000000000000"
+
+// nativeAssetAddresses holds the addresses of native coins which differ from NATIVE_ASSET_ADDRESS,
+// as the native coin is also available under a contract or a denomination on these blockchains.
+var nativeAssetAddresses = map[string]string{
+	POLYGON: "0x0000000000000000000000000000000000001010",
+	CELO:    "0x471EcE3750Da237f93B8E339c536989b8978a438",
+	METIS:   "0xDeadDeAddeAddEAddeadDEaDDEAdDeaDDeAD0000",
+	ACALA:   "Token:ACA",
+	OSMOSIS: "uosmo",
+	BIFROST: "0",
+}
+
+// nativeAddressAliases are addresses used by clients and scrapers to denote a native coin.
+var nativeAddressAliases = []string{"", "0x", "0x0", "native"}
+
+// NativeAssetAddress returns the address of the native coin of @blockchain.
+func NativeAssetAddress(blockchain string) string {
+	if address, ok := nativeAssetAddresses[blockchain]; ok {
+		return address
+	}
+	return NATIVE_ASSET_ADDRESS
+}
+
+// IsNativeAssetAddress returns true if @address denotes the native coin of @blockchain. Besides the
+// address of the native coin, the zero address and the aliases "", "0x", "0x0" and "native" are
+// accepted, ignoring case.
+func IsNativeAssetAddress(blockchain string, address string) bool {
+	address = strings.TrimSpace(address)
+	if strings.EqualFold(address, NativeAssetAddress(blockchain)) || strings.EqualFold(address, NATIVE_ASSET_ADDRESS) {
+		return true
+	}
+	for _, alias := range nativeAddressAliases {
+		if strings.EqualFold(address, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeNativeAddress returns the address of the native coin of @blockchain if @address denotes it
+// and @address unchanged otherwise. All lookups of assets by address should go through it.
+func NormalizeNativeAddress(blockchain string, address string) string {
+	if IsNativeAssetAddress(blockchain, address) {
+		return NativeAssetAddress(blockchain)
+	}
+	return address
+}
+
+// ValidNativeAddress returns an error if @address denotes the native coin of @blockchain
+// without being its address.
+func ValidNativeAddress(blockchain string, address string) error {
+	if IsNativeAssetAddress(blockchain, address) && address != NativeAssetAddress(blockchain) {
+		return fmt.Errorf("native asset on %s must have address %s instead of %q", blockchain, NativeAssetAddress(blockchain), address)
+	}
+	return nil
+}
+
+// NewNativeAsset returns the native coin of @blockchain.
+func NewNativeAsset(blockchain string, symbol string, name string, decimals uint8) Asset {
+	return Asset{
+		Symbol:     symbol,
+		Name:       name,
+		Address:    NativeAssetAddress(blockchain),
+		Decimals:   decimals,
+		Blockchain: blockchain,
+	}
+}
+
+// IsNative returns true if @asset is the native coin of its blockchain.
+func (asset *Asset) IsNative() bool {
+	return IsNativeAssetAddress(asset.Blockchain, asset.Address)
+}
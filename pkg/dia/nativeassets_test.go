@@ -0,0 +1,30 @@
+package dia
+
+import "testing"
+
+func TestNormalizeNativeAddress(t *testing.T) {
+	cases := []struct {
+		blockchain string
+		address    string
+		want       string
+	}{
+		{ETHEREUM, "0x0", NATIVE_ASSET_ADDRESS},
+		{ETHEREUM, "", NATIVE_ASSET_ADDRESS},
+		{BITCOIN, "Native", NATIVE_ASSET_ADDRESS},
+		{POLYGON, NATIVE_ASSET_ADDRESS, "0x0000000000000000000000000000000000001010"},
+		{OSMOSIS, "UOSMO", "uosmo"},
+		{ETHEREUM, "0xdAC17F958D2ee523a2206206994597C13D831ec7", "0xdAC17F958D2ee523a2206206994597C13D831ec7"},
+	}
+	for _, c := range cases {
+		if got := NormalizeNativeAddress(c.blockchain, c.address); got != c.want {
+			t.Errorf("NormalizeNativeAddress(%s, %q) = %s, want %s", c.blockchain, c.address, got, c.want)
+		}
+	}
+
+	if err := ValidNativeAddress(POLYGON, NATIVE_ASSET_ADDRESS); err == nil {
+		t.Error("expected error for zero address on Polygon")
+	}
+	if asset := NewNativeAsset(CELO, "CELO", "Celo", 18); !asset.IsNative() || asset.Address != NativeAssetAddress(CELO) {
+		t.Errorf("unexpected native asset %+v", asset)
+	}
+}
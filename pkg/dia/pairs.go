@@ -123,3 +123,25 @@ func MergeExchangePairs(pairs1, pairs2 []ExchangePair) []ExchangePair {
 	}
 	return pairs1
 }
+
+// AddListedPairs adds the markets announced by the listings in @listings to @pairs, unless they are
+// in there yet. Delistings are ignored. Equality refers to the unique identifier (exchange,foreignName).
+func AddListedPairs(pairs []ExchangePair, listings []ExchangeListing) []ExchangePair {
+	known := make(map[string]struct{}, len(pairs))
+	for _, pair := range pairs {
+		known[pair.Exchange+"_"+pair.ForeignName] = struct{}{}
+	}
+	for _, listing := range listings {
+		key := listing.Exchange + "_" + listing.ForeignName
+		if _, ok := known[key]; ok || listing.Type != LISTING {
+			continue
+		}
+		known[key] = struct{}{}
+		pairs = append(pairs, ExchangePair{
+			Symbol:      listing.Symbol,
+			ForeignName: listing.ForeignName,
+			Exchange:    listing.Exchange,
+		})
+	}
+	return pairs
+}
@@ -0,0 +1,21 @@
+package dia
+
+import "testing"
+
+func TestAddListedPairs(t *testing.T) {
+	pairs := []ExchangePair{{Symbol: "BTC", ForeignName: "BTCUSDT", Exchange: BinanceExchange}}
+	listings := []ExchangeListing{
+		{Exchange: BinanceExchange, ForeignName: "BTCUSDT", Symbol: "BTC", Type: LISTING},
+		{Exchange: BinanceExchange, ForeignName: "NEWUSDT", Symbol: "NEW", Type: LISTING},
+		{Exchange: BinanceExchange, ForeignName: "NEWUSDT", Symbol: "NEW", Type: LISTING},
+		{Exchange: BinanceExchange, ForeignName: "OLDBTC", Symbol: "OLD", Type: DELISTING},
+	}
+
+	pairs = AddListedPairs(pairs, listings)
+	if len(pairs) != 2 {
+		t.Fatalf("got %d pairs but expected 2", len(pairs))
+	}
+	if pairs[1].ForeignName != "NEWUSDT" || pairs[1].Symbol != "NEW" || pairs[1].Exchange != BinanceExchange {
+		t.Errorf("unexpected listed pair %v", pairs[1])
+	}
+}
@@ -28,12 +28,16 @@ var (
 
 var evmID map[string]string
 
+// rpcDB provides the healthy RPC endpoints of blockchains for scraper clients.
+var rpcDB *models.RelDB
+
 func init() {
 
 	relDB, err := models.NewRelDataStore()
 	if err != nil {
 		log.Fatal("get rel datastore: ", err)
 	}
+	rpcDB = relDB
 
 	exchanges, err := relDB.GetAllExchanges()
 	if err != nil {
@@ -109,13 +109,14 @@ func NewBalancerV2Scraper(exchange dia.Exchange, scrape bool, relDB *models.RelD
 
 	var err error
 
-	ws, err := ethclient.Dial(utils.Getenv("ETH_URI_WS", balancerV2WSDial))
+	restURL, wsURL := rpcURLs(dia.ETHEREUM, "ETH", balancerV2RestDial, balancerV2WSDial)
+	ws, err := ethclient.Dial(wsURL)
 	if err != nil {
 		log.Error(err)
 		return nil
 	}
 
-	rest, err := ethclient.Dial(utils.Getenv("ETH_URI_REST", balancerV2RestDial))
+	rest, err := ethclient.Dial(restURL)
 	if err != nil {
 		log.Error(err)
 		return nil
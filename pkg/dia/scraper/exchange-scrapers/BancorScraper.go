@@ -108,12 +108,13 @@ func NewBancorScraper(exchange dia.Exchange, scrape bool) *BancorScraper {
 	var wsClient, restClient *ethclient.Client
 	var err error
 
-	restClient, err = ethclient.Dial(utils.Getenv("ETH_URI_REST", restDialEth))
+	restURL, wsURL := rpcURLs(dia.ETHEREUM, "ETH", restDialEth, wsDialEth)
+	restClient, err = ethclient.Dial(restURL)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	wsClient, err = ethclient.Dial(utils.Getenv("ETH_URI_WS", wsDialEth))
+	wsClient, err = ethclient.Dial(wsURL)
 	if err != nil {
 		log.Fatal(err)
 	}
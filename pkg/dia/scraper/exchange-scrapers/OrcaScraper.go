@@ -11,7 +11,6 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	orcaWhirlpoolIdlBind "github.com/diadata-org/diadata/pkg/dia/scraper/exchange-scrapers/orca/whirlpool"
-	"github.com/diadata-org/diadata/pkg/utils"
 
 	bin "github.com/gagliardetto/binary"
 	tokenmetadata "github.com/gagliardetto/metaplex-go/clients/token-metadata"
@@ -56,8 +55,9 @@ type OrcaScraper struct {
 func NewOrcaScraper(exchange dia.Exchange, scrape bool) *OrcaScraper {
 
 	log.Infof("init rest and ws client for %s", exchange.BlockChain.Name)
-	restClient := rpc.New(utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_REST", orcaSolanaHttpEndpoint))
-	wsClient, err := ws.Connect(context.Background(), utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_WS", orcaSolanaWsEndpoint))
+	restURL, wsURL := rpcURLs(exchange.BlockChain.Name, strings.ToUpper(exchange.BlockChain.Name), orcaSolanaHttpEndpoint, orcaSolanaWsEndpoint)
+	restClient := rpc.New(restURL)
+	wsClient, err := ws.Connect(context.Background(), wsURL)
 	if err != nil {
 		log.Fatal("init ws client: ", err)
 	}
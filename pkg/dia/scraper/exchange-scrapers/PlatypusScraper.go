@@ -110,11 +110,12 @@ func NewPlatypusScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB)
 	}
 
 	log.Infof("init rest and ws client for %s", exchange.BlockChain.Name)
-	restClient, err := ethclient.Dial(utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_REST", platypusRestDialEth))
+	restURL, wsURL := rpcURLs(exchange.BlockChain.Name, strings.ToUpper(exchange.BlockChain.Name), platypusRestDialEth, platypusWsDialEth)
+	restClient, err := ethclient.Dial(restURL)
 	if err != nil {
 		log.Fatal("init rest client: ", err)
 	}
-	wsClient, err := ethclient.Dial(utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_WS", platypusWsDialEth))
+	wsClient, err := ethclient.Dial(wsURL)
 	if err != nil {
 		log.Fatal("init ws client: ", err)
 	}
@@ -1,6 +1,7 @@
 package scrapers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
@@ -21,6 +22,7 @@ import (
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -97,6 +99,9 @@ const (
 	astarWaitMilliseconds       = "1000"
 	moonbeamWaitMilliseconds    = "1000"
 	wanchainWaitMilliseconds    = "1000"
+
+	// scraperBlockReportInterval is the minimal time between two reports of the processed block.
+	scraperBlockReportInterval = 10 * time.Second
 )
 
 type UniswapToken struct {
@@ -145,6 +150,8 @@ type UniswapScraper struct {
 	// If true, only pairs given in config file are scraped. Default is false.
 	listenByAddress  bool
 	fetchPoolsFromDB bool
+	// blockReporter reports the latest block processed by the scraper to the chain head monitor.
+	blockReporter *models.ScraperBlockReporter
 }
 
 // NewUniswapScraper returns a new UniswapScraper for the given pair
@@ -226,6 +233,9 @@ func NewUniswapScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB)
 	}
 
 	s.relDB = relDB
+	if relDB != nil {
+		s.blockReporter = relDB.NewScraperBlockReporter(exchange.Name, exchange.BlockChain.Name, scraperBlockReportInterval)
+	}
 
 	// Only include pools with (minimum) liquidity bigger than given env var.
 	liquidityThreshold, err := strconv.ParseFloat(utils.Getenv("LIQUIDITY_THRESHOLD", "0"), 64)
@@ -248,10 +258,49 @@ func NewUniswapScraper(exchange dia.Exchange, scrape bool, relDB *models.RelDB)
 
 	if scrape {
 		go s.mainLoop()
+		if s.blockReporter != nil {
+			go s.reportChainHead()
+		}
 	}
 	return s
 }
 
+// reportChainHead reports each new head received on the websocket connection as processed block.
+// Swap events are delivered on the same connection, so quiet pools do not show up as lag, while a
+// stalled connection or node does. The subscription is renewed until s is closed.
+func (s *UniswapScraper) reportChainHead() {
+	for {
+		if err := s.watchChainHead(); err != nil {
+			log.Error("watch chain head: ", err)
+		}
+		select {
+		case <-s.shutdown:
+			return
+		case <-time.After(scraperBlockReportInterval):
+		}
+	}
+}
+
+// watchChainHead reports new heads until the subscription fails or s is closed.
+func (s *UniswapScraper) watchChainHead() error {
+	headers := make(chan *types.Header)
+	sub, err := s.WsClient.SubscribeNewHead(context.Background(), headers)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case header := <-headers:
+			s.blockReporter.Report(header.Number.Uint64())
+		case err := <-sub.Err():
+			return err
+		case <-s.shutdown:
+			return nil
+		}
+	}
+}
+
 // makeUniswapScraper returns a uniswap scraper as used in NewUniswapScraper.
 func makeUniswapScraper(exchange dia.Exchange, listenByAddress bool, fetchPoolsFromDB bool, restDial string, wsDial string, waitMilliseconds string) *UniswapScraper {
 	var (
@@ -262,11 +311,12 @@ func makeUniswapScraper(exchange dia.Exchange, listenByAddress bool, fetchPoolsF
 	)
 
 	log.Infof("Init rest and ws client for %s.", exchange.BlockChain.Name)
-	restClient, err = ethclient.Dial(utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_REST", restDial))
+	restURL, wsURL := rpcURLs(exchange.BlockChain.Name, strings.ToUpper(exchange.BlockChain.Name), restDial, wsDial)
+	restClient, err = ethclient.Dial(restURL)
 	if err != nil {
 		log.Fatal("init rest client: ", err)
 	}
-	wsClient, err = ethclient.Dial(utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_WS", wsDial))
+	wsClient, err = ethclient.Dial(wsURL)
 	if err != nil {
 		log.Fatal("init ws client: ", err)
 	}
@@ -399,6 +449,7 @@ func (s *UniswapScraper) ListenToPair(i int, address common.Address) {
 		for {
 			rawSwap, ok := <-sink
 			if ok {
+				s.blockReporter.Report(rawSwap.Raw.BlockNumber)
 				swap, err := s.normalizeUniswapSwap(*rawSwap, pair)
 				if err != nil {
 					log.Error("error normalizing swap: ", err)
@@ -118,11 +118,12 @@ func makeUniswapV3Scraper(exchange dia.Exchange, listenByAddress bool, restDial
 	var s *UniswapV3Scraper
 
 	log.Infof("Init rest and ws client for %s.", exchange.BlockChain.Name)
-	restClient, err = ethclient.Dial(utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_REST", restDial))
+	restURL, wsURL := rpcURLs(exchange.BlockChain.Name, strings.ToUpper(exchange.BlockChain.Name), restDial, wsDial)
+	restClient, err = ethclient.Dial(restURL)
 	if err != nil {
 		log.Fatal("init rest client: ", err)
 	}
-	wsClient, err = ethclient.Dial(utils.Getenv(strings.ToUpper(exchange.BlockChain.Name)+"_URI_WS", wsDial))
+	wsClient, err = ethclient.Dial(wsURL)
 	if err != nil {
 		log.Fatal("init ws client: ", err)
 	}
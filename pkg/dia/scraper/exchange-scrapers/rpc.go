@@ -0,0 +1,27 @@
+package scrapers
+
+import (
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+// rpcURLs returns the rest and websocket urls for clients of @blockchain. The healthy endpoint with the
+// highest priority in the blockchain_rpc table is preferred, so that restarted scrapers fail over to the
+// next endpoint. Otherwise the urls are given by the env vars <@envPrefix>_URI_REST and <@envPrefix>_URI_WS,
+// falling back to @restDial and @wsDial. Endpoints without websocket url fall back alike.
+func rpcURLs(blockchain string, envPrefix string, restDial string, wsDial string) (restURL string, wsURL string) {
+	restURL = utils.Getenv(envPrefix+"_URI_REST", restDial)
+	wsURL = utils.Getenv(envPrefix+"_URI_WS", wsDial)
+	if rpcDB == nil {
+		return
+	}
+	rpc, err := rpcDB.GetHealthyRPC(blockchain)
+	if err != nil {
+		log.Warnf("use rpc of env for %s: %v", blockchain, err)
+		return
+	}
+	restURL = rpc.RestURL
+	if rpc.WSURL != "" {
+		wsURL = rpc.WSURL
+	}
+	return
+}
@@ -0,0 +1,161 @@
+package listingscrapers
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/diadata-org/diadata/pkg/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	binanceExchangeInfoURL = "https://api.binance.com/api/v3/exchangeInfo"
+	// Binance announces upcoming markets with status PRE_TRADING. Status BREAK is a trading halt
+	// which may be lifted again, so it is no delisting announcement.
+	binanceStatusPreTrading = "PRE_TRADING"
+)
+
+var (
+	binanceListingUpdateSeconds int64
+)
+
+type binanceExchangeInfo struct {
+	Symbols []struct {
+		Symbol     string `json:"symbol"`
+		Status     string `json:"status"`
+		BaseAsset  string `json:"baseAsset"`
+		QuoteAsset string `json:"quoteAsset"`
+	} `json:"symbols"`
+}
+
+// BinanceListingScraper derives listing announcements from the market status of Binance's exchangeInfo API.
+type BinanceListingScraper struct {
+	rdb            *models.RelDB
+	ticker         *time.Ticker
+	listingChannel chan dia.ExchangeListing
+	shutdown       chan struct{}
+	closed         bool
+	// seen avoids emitting the same announcement on each poll.
+	seen     map[string]struct{}
+	seenLock sync.Mutex
+}
+
+func init() {
+	var err error
+	binanceListingUpdateSeconds, err = strconv.ParseInt(utils.Getenv("BINANCE_LISTING_UPDATE_SECONDS", "3600"), 10, 64)
+	if err != nil {
+		log.Error("Parse BINANCE_LISTING_UPDATE_SECONDS: ", err)
+	}
+}
+
+func NewBinanceListingScraper(rdb *models.RelDB) *BinanceListingScraper {
+	s := &BinanceListingScraper{
+		rdb:            rdb,
+		ticker:         time.NewTicker(time.Duration(binanceListingUpdateSeconds) * time.Second),
+		listingChannel: make(chan dia.ExchangeListing),
+		shutdown:       make(chan struct{}),
+		seen:           make(map[string]struct{}),
+	}
+	go s.mainLoop()
+	return s
+}
+
+func (s *BinanceListingScraper) mainLoop() {
+	err := s.FetchListings()
+	if err != nil {
+		log.Error(err)
+	}
+	for {
+		select {
+		case <-s.ticker.C:
+			err := s.FetchListings()
+			if err != nil {
+				log.Error(err)
+			}
+		case <-s.shutdown:
+			log.Info("BinanceListingScraper shutting down")
+			close(s.listingChannel)
+			return
+		}
+	}
+}
+
+// FetchListings emits an announcement for each market in pre-trading status.
+// It returns early if the scraper is closed while an announcement is waiting to be read.
+func (s *BinanceListingScraper) FetchListings() error {
+	data, _, err := utils.GetRequest(binanceExchangeInfoURL)
+	if err != nil {
+		return err
+	}
+	var info binanceExchangeInfo
+	err = json.Unmarshal(data, &info)
+	if err != nil {
+		return err
+	}
+
+	for _, listing := range binanceListings(info, time.Now()) {
+		key := listing.ForeignName + "_" + string(listing.Type)
+		s.seenLock.Lock()
+		if _, ok := s.seen[key]; ok {
+			s.seenLock.Unlock()
+			continue
+		}
+		s.seen[key] = struct{}{}
+		s.seenLock.Unlock()
+
+		// Reference the asset if the base symbol is already verified on Binance.
+		asset, err := s.rdb.GetExchangeSymbol(dia.BinanceExchange, listing.Symbol)
+		if err == nil {
+			listing.Asset = asset
+		}
+		select {
+		case s.listingChannel <- listing:
+		case <-s.shutdown:
+			return nil
+		}
+	}
+	return nil
+}
+
+// binanceListings returns the announcements given by the market status in @info, detected at @now.
+// The exchangeInfo API does not provide the scheduled time, so the effective time is left unknown.
+func binanceListings(info binanceExchangeInfo, now time.Time) (listings []dia.ExchangeListing) {
+	for _, market := range info.Symbols {
+		var listingType dia.ListingType
+		switch market.Status {
+		case binanceStatusPreTrading:
+			listingType = dia.LISTING
+		default:
+			continue
+		}
+		listings = append(listings, dia.ExchangeListing{
+			Exchange:      dia.BinanceExchange,
+			ForeignName:   market.Symbol,
+			Symbol:        market.BaseAsset,
+			Type:          listingType,
+			AnnouncedTime: now,
+			Source:        binanceExchangeInfoURL,
+		})
+	}
+	return
+}
+
+func (s *BinanceListingScraper) ListingChannel() chan dia.ExchangeListing {
+	return s.listingChannel
+}
+
+// Close stops the scraper and closes its listing channel.
+func (s *BinanceListingScraper) Close() error {
+	if s.closed {
+		return errors.New("BinanceListingScraper: Already closed")
+	}
+	s.ticker.Stop()
+	close(s.shutdown)
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,33 @@
+package listingscrapers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestBinanceListings(t *testing.T) {
+	data := []byte(`{"symbols":[
+		{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT"},
+		{"symbol":"NEWUSDT","status":"PRE_TRADING","baseAsset":"NEW","quoteAsset":"USDT"},
+		{"symbol":"OLDBTC","status":"BREAK","baseAsset":"OLD","quoteAsset":"BTC"}
+	]}`)
+	var info binanceExchangeInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2022, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	listings := binanceListings(info, now)
+	if len(listings) != 1 {
+		t.Fatalf("got %d listings but expected 1", len(listings))
+	}
+	if listings[0].ForeignName != "NEWUSDT" || listings[0].Symbol != "NEW" || listings[0].Type != dia.LISTING {
+		t.Errorf("unexpected listing %v", listings[0])
+	}
+	if !listings[0].AnnouncedTime.Equal(now) || !listings[0].EffectiveTime.IsZero() {
+		t.Errorf("announced time must be the detection time and effective time unknown, got %v and %v", listings[0].AnnouncedTime, listings[0].EffectiveTime)
+	}
+}
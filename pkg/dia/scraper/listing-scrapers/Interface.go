@@ -0,0 +1,35 @@
+package listingscrapers
+
+import (
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// ListingScraper emits listing and delisting announcements of an exchange.
+type ListingScraper interface {
+	FetchListings() error
+	ListingChannel() chan dia.ExchangeListing
+	Close() error
+}
+
+// NewListingScraper returns the listing scraper for @exchange.
+func NewListingScraper(exchange string, rdb *models.RelDB) ListingScraper {
+	switch exchange {
+	case dia.BinanceExchange:
+		return NewBinanceListingScraper(rdb)
+	default:
+		log.Errorf("no listing scraper available for %s", exchange)
+		return nil
+	}
+}
+
+// HandleListings stores all announcements from @scraper in postgres until its channel is closed.
+func HandleListings(scraper ListingScraper, rdb *models.RelDB) {
+	for listing := range scraper.ListingChannel() {
+		err := rdb.SetExchangeListing(listing)
+		if err != nil {
+			log.Errorf("set %s of %s on %s: %v", listing.Type, listing.ForeignName, listing.Exchange, err)
+		}
+	}
+}
@@ -1003,6 +1003,9 @@ func makeAddressEIP55Compliant(address string, blockchain string) string {
 
 // Normalize address depending on the blockchain.
 func normalizeAddress(address string, blockchain string) string {
+	if dia.IsNativeAssetAddress(blockchain, address) {
+		return dia.NativeAssetAddress(blockchain)
+	}
 	if strings.Contains(BLOCKCHAINS[blockchain].ChainID, "Ethereum") {
 		return makeAddressEIP55Compliant(address, blockchain)
 	}
@@ -21,7 +21,8 @@ import (
 	"github.com/diadata-org/diadata/pkg/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
-	"github.com/go-redis/redis"
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v4"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -33,9 +34,10 @@ var (
 )
 
 type Env struct {
-	DataStore models.Datastore
-	RelDB     models.RelDB
-	signer    *utils.AssetQuotationSigner
+	DataStore  models.Datastore
+	RelDB      models.RelDB
+	signer     *utils.AssetQuotationSigner
+	tokenLists *models.TokenListExporter
 }
 
 func init() {
@@ -54,7 +56,9 @@ func init() {
 }
 
 func NewEnv(ds models.Datastore, rdb models.RelDB, signer *utils.AssetQuotationSigner) *Env {
-	return &Env{DataStore: ds, RelDB: rdb, signer: signer}
+	env := &Env{DataStore: ds, RelDB: rdb, signer: signer}
+	env.tokenLists = models.NewTokenListExporter(&env.RelDB)
+	return env
 }
 
 // PostSupply deprecated? TO DO
@@ -195,7 +199,12 @@ func (env *Env) GetAssetQuotation(c *gin.Context) {
 	quotationExtended.Time = quotation.Time
 	quotationExtended.Source = quotation.Source
 
-	signedData, err := env.signer.Sign(quotation.Asset.Symbol, quotation.Asset.Address, quotation.Asset.Blockchain, quotation.Price, quotationExtended.Time)
+	// Round prices for display if requested.
+	format := env.displayFormat(c, asset)
+	quotationExtended.Price = format.Round(quotationExtended.Price)
+	quotationExtended.PriceYesterday = format.Round(quotationExtended.PriceYesterday)
+
+	signedData, err := env.signer.Sign(quotation.Asset.Symbol, quotation.Asset.Address, quotation.Asset.Blockchain, quotationExtended.Price, quotationExtended.Time)
 	if err != nil {
 		log.Warn("error signing data: ", err)
 	}
@@ -205,6 +214,49 @@ func (env *Env) GetAssetQuotation(c *gin.Context) {
 
 }
 
+// GetQuotationEnvelope returns the quotation of an asset in the uniform envelope with source count,
+// staleness and quality. Missing prices are reported with quality unavailable instead of an error,
+// only unknown assets yield 404. Prices older than maxAge seconds (default 3600) are stale.
+func (env *Env) GetQuotationEnvelope(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	timestampInt, err := strconv.ParseInt(c.DefaultQuery("timestamp", strconv.Itoa(int(time.Now().Unix()))), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("could not parse Unix timestamp"))
+		return
+	}
+	maxAgeSeconds, err := strconv.ParseInt(c.DefaultQuery("maxAge", "3600"), 10, 64)
+	if err != nil || maxAgeSeconds <= 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("maxAge must be a positive number of seconds"))
+		return
+	}
+
+	asset, err := env.RelDB.GetAsset(address, blockchain)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	envelope := env.DataStore.GetQuotationEnvelope(asset, time.Unix(timestampInt, 0), time.Duration(maxAgeSeconds)*time.Second)
+	c.JSON(http.StatusOK, envelope)
+}
+
+// displayFormat returns the display format of @asset if the query parameter display=true is set and
+// an empty format otherwise, so that prices are only rounded for display consumers.
+func (env *Env) displayFormat(c *gin.Context, asset dia.Asset) dia.DisplayFormat {
+	if display, _ := strconv.ParseBool(c.Query("display")); !display {
+		return dia.DisplayFormat{}
+	}
+	format, err := env.RelDB.GetAssetDisplayFormat(asset)
+	if err != nil {
+		log.Warn("get display format: ", err)
+	}
+	return format
+}
+
 // GetQuotation returns quotation of asset with highest market cap among
 // all assets with symbol ticker @symbol.
 func (env *Env) GetQuotation(c *gin.Context) {
@@ -309,7 +361,7 @@ func (env *Env) GetAssetMap(c *gin.Context) {
 		if err != nil {
 			log.Warn("get volume yesterday: ", err)
 		} else {
-			quotationExtended.VolumeYesterdayUSD = volumeYesterday
+			quotationExtended.VolumeYesterdayUSD = volumeYesterday.VolumeUSD
 		}
 		quotationExtended.Symbol = topAsset.Symbol
 		quotationExtended.Name = topAsset.Name
@@ -364,7 +416,7 @@ func (env *Env) GetAssetSupply(c *gin.Context) {
 		return
 	}
 
-	values, err := env.DataStore.GetSupplyInflux(dia.Asset{Address: address, Blockchain: blockchain}, starttime, endtime)
+	values, err := env.DataStore.WithContext(c.Request.Context()).GetSupplyInflux(dia.Asset{Address: address, Blockchain: blockchain}, starttime, endtime)
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			restApi.SendError(c, http.StatusNotFound, err)
@@ -595,7 +647,7 @@ func (env *Env) GetAssetChartPoints(c *gin.Context) {
 		return
 	}
 
-	p, err := env.DataStore.GetFilterPointsAsset(filter, exchange, address, blockchain, starttime, endtime)
+	p, err := env.DataStore.WithContext(c.Request.Context()).GetFilterPointsAsset(filter, exchange, address, blockchain, starttime, endtime)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
 	} else {
@@ -603,6 +655,58 @@ func (env *Env) GetAssetChartPoints(c *gin.Context) {
 	}
 }
 
+// GetAssetVolumeHistory returns the daily 24h volumes of an asset from postgres.
+// The time range defaults to the last 30 days and may span at most one year.
+func (env *Env) GetAssetVolumeHistory(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), time.Duration(30*24*time.Hour))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("parse time range"))
+		return
+	}
+	if ok := utils.ValidTimeRange(starttime, endtime, time.Duration(366*24*time.Hour)); !ok {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("time-range too big. max duration is %v", 366*24*time.Hour))
+		return
+	}
+
+	volumes, err := env.RelDB.GetAssetVolumeSeries(dia.Asset{Address: address, Blockchain: blockchain}, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(volumes) == 0 {
+		c.JSON(http.StatusOK, make([]models.AssetVolumeDay, 0))
+		return
+	}
+	c.JSON(http.StatusOK, volumes)
+}
+
+// GetAssetVolumeBreakdown returns the 24h volume of an asset per exchange.
+func (env *Env) GetAssetVolumeBreakdown(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	breakdown, err := env.RelDB.GetAssetVolumeBreakdown(dia.Asset{Address: address, Blockchain: blockchain})
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(breakdown.Volumes) == 0 {
+		breakdown.Volumes = make([]dia.ExchangeVolume, 0)
+	}
+	c.JSON(http.StatusOK, breakdown)
+}
+
 // GetChartPoints returns Filter points for given symbol -> Deprecated?
 func (env *Env) GetChartPoints(c *gin.Context) {
 	if !validateInputParams(c) {
@@ -625,7 +729,7 @@ func (env *Env) GetChartPoints(c *gin.Context) {
 		return
 	}
 
-	p, err := env.DataStore.GetFilterPoints(filter, exchange, symbol, scale, starttime, endtime)
+	p, err := env.DataStore.WithContext(c.Request.Context()).GetFilterPoints(filter, exchange, symbol, scale, starttime, endtime)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
 	} else {
@@ -654,7 +758,7 @@ func (env *Env) GetChartPointsAllExchanges(c *gin.Context) {
 		return
 	}
 
-	p, err := env.DataStore.GetFilterPoints(filter, "", symbol, scale, starttime, endtime)
+	p, err := env.DataStore.WithContext(c.Request.Context()).GetFilterPoints(filter, "", symbol, scale, starttime, endtime)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
 	} else {
@@ -689,7 +793,7 @@ func (env *Env) GetFilterPerSource(c *gin.Context) {
 		return
 	}
 
-	assetQuotations, err := env.DataStore.GetFilterAllExchanges(filter, address, blockchain, starttime, endtime)
+	assetQuotations, err := env.DataStore.WithContext(c.Request.Context()).GetFilterAllExchanges(filter, address, blockchain, starttime, endtime)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, nil)
 		return
@@ -738,11 +842,11 @@ func (env *Env) GetAllSymbols(c *gin.Context) {
 
 	// Filter results by substring. @exchange is disabled.
 	if substring != "" {
-		s, err = env.RelDB.GetExchangeSymbols("", substring)
+		exchangeSymbols, err := env.RelDB.GetExchangeSymbols(models.ExchangeSymbolFilter{Pattern: substring})
 		if err != nil {
 			restApi.SendError(c, http.StatusInternalServerError, errors.New("cannot find symbols"))
 		}
-		s = utils.UniqueStrings(s)
+		s = utils.UniqueStrings(exchangeSymbolNames(exchangeSymbols))
 
 		sort.Strings(s)
 		// Sort all symbols by volume, append if they have no volume.
@@ -774,11 +878,11 @@ func (env *Env) GetAllSymbols(c *gin.Context) {
 			c.JSON(http.StatusOK, s)
 		} else {
 			// -- Get all symbols across all exchanges. --
-			s, err = env.RelDB.GetExchangeSymbols("", "")
+			exchangeSymbols, err := env.RelDB.GetExchangeSymbols(models.ExchangeSymbolFilter{})
 			if err != nil {
 				restApi.SendError(c, http.StatusInternalServerError, errors.New("cannot find symbols"))
 			}
-			s = utils.UniqueStrings(s)
+			s = utils.UniqueStrings(exchangeSymbolNames(exchangeSymbols))
 
 			sort.Strings(s)
 			// Sort all symbols by volume, append if they have no volume.
@@ -797,13 +901,60 @@ func (env *Env) GetAllSymbols(c *gin.Context) {
 		}
 	} else {
 		// -- Get all symbols on @exchange. --
-		symbols, err := env.RelDB.GetExchangeSymbols(exchange, "")
+		symbols, err := env.RelDB.GetExchangeSymbols(models.ExchangeSymbolFilter{Exchanges: []string{exchange}})
 		if err != nil {
 			restApi.SendError(c, http.StatusInternalServerError, errors.New("cannot find symbols"))
 		}
-		c.JSON(http.StatusOK, symbols)
+		c.JSON(http.StatusOK, exchangeSymbolNames(symbols))
+	}
+
+}
+
+func exchangeSymbolNames(exchangeSymbols []models.ExchangeSymbol) (symbols []string) {
+	for _, exchangeSymbol := range exchangeSymbols {
+		symbols = append(symbols, exchangeSymbol.Symbol)
+	}
+	return
+}
+
+// GetExchangeSymbols returns a page of symbols together with exchange and verification status
+// and the total number of matching symbols. Query parameters are exchanges (comma separated), pattern (* as wildcard, prefix match otherwise),
+// verified, limit and offset.
+func (env *Env) GetExchangeSymbols(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("limit must be in (0,1000]"))
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("invalid offset"))
+		return
+	}
+	verifiedOnly, err := strconv.ParseBool(c.DefaultQuery("verified", "false"))
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("verified must be a boolean"))
+		return
+	}
+	filter := models.ExchangeSymbolFilter{
+		Pattern:      c.Query("pattern"),
+		VerifiedOnly: verifiedOnly,
+		Limit:        limit,
+		Offset:       offset,
+	}
+	if exchanges := c.Query("exchanges"); exchanges != "" {
+		filter.Exchanges = strings.Split(exchanges, ",")
 	}
 
+	page, err := env.RelDB.WithContext(c.Request.Context()).GetExchangeSymbolsPage(filter)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
 }
 
 // -----------------------------------------------------------------------------
@@ -1239,6 +1390,156 @@ func (env *Env) GetAssetPairs(c *gin.Context) {
 
 }
 
+// GetAssetPairsAt returns the verified pairs of an asset as configured at the unix time given
+// by the query parameter time. It allows to reproduce which pairs entered a historical quotation.
+func (env *Env) GetAssetPairsAt(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+	timestamp, err := strconv.ParseInt(c.Query("time"), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("time must be a unix timestamp"))
+		return
+	}
+
+	pairs, err := env.RelDB.GetExchangePairsAt(dia.Asset{Address: address, Blockchain: blockchain}, time.Unix(timestamp, 0))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, pairs)
+}
+
+// GetAssetGroup returns all assets on other blockchains which are linked to the given asset,
+// including the asset itself.
+func (env *Env) GetAssetGroup(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	assets, err := env.RelDB.GetAssetGroup(address, blockchain)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, assets)
+}
+
+// GetAssetTickers returns the current and all former symbol tickers of an asset.
+func (env *Env) GetAssetTickers(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	tickers, err := env.RelDB.GetAssetTickers(dia.Asset{Address: address, Blockchain: blockchain})
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, tickers)
+}
+
+// GetAssetsByTicker returns all assets which use or used the symbol ticker @symbol.
+// The ticker of assets which currently use @symbol is flagged as current.
+func (env *Env) GetAssetsByTicker(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	symbol := c.Param("symbol")
+
+	matches, err := env.RelDB.GetAssetsByTicker(symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(matches) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no asset with ticker "+symbol))
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// PostBenchmarkAsset stores the definition of a benchmark asset. Its price is computed by the
+// filtersBlockService and served through the asset quotation endpoints.
+func (env *Env) PostBenchmarkAsset(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var benchmark dia.BenchmarkAsset
+	err = json.Unmarshal(body, &benchmark)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err = benchmark.Validate(); err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = env.RelDB.SetBenchmarkAsset(benchmark)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, benchmark)
+}
+
+// DeleteBenchmarkAsset removes the definition of a benchmark asset.
+func (env *Env) DeleteBenchmarkAsset(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	err := env.RelDB.DeleteBenchmarkAsset(dia.Asset{Address: address, Blockchain: blockchain})
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, dia.Asset{Address: address, Blockchain: blockchain})
+}
+
+// GetBenchmarkAssets returns the definitions of all benchmark assets.
+func (env *Env) GetBenchmarkAssets(c *gin.Context) {
+	benchmarks, err := env.RelDB.GetBenchmarkAssets()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, benchmarks)
+}
+
+// GetAssetGroupQuotation returns the volume weighted quotation across all blockchains of the
+// asset group the given asset is linked to.
+func (env *Env) GetAssetGroupQuotation(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	groupID, err := env.RelDB.GetAssetGroupID(dia.Asset{Address: address, Blockchain: blockchain})
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, errors.New("asset is not linked to an asset group"))
+		return
+	}
+	quotation, err := env.DataStore.GetAssetGroupQuotationLatest(groupID)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, quotation)
+}
+
 func (env *Env) SearchAsset(c *gin.Context) {
 	if !validateInputParams(c) {
 		return
@@ -1343,10 +1644,17 @@ func (env *Env) GetTopAssets(c *gin.Context) {
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, errors.New("number of assets must be an integer"))
 	}
+	if numAssets == 0 {
+		numAssets = 100
+	}
 
 	offset = (pageNumber - 1) * numAssets
 
-	sortedAssets, err = env.RelDB.GetAssetsWithVOL(time.Now().AddDate(0, 0, -7), numAssets, offset, onlycex, blockchain)
+	if onlycex {
+		sortedAssets, err = env.RelDB.GetAssetsWithVOL(time.Now().AddDate(0, 0, -7), numAssets, offset, onlycex, blockchain)
+	} else {
+		sortedAssets, err = env.RelDB.GetTopAssetsByVolume(time.Now().AddDate(0, 0, -7), int(numAssets), int(offset), blockchain)
+	}
 	if err != nil {
 		log.Error("get assets with volume: ", err)
 
@@ -1367,13 +1675,9 @@ func (env *Env) GetTopAssets(c *gin.Context) {
 		}
 		aqf.Volume = v.Volume
 
-		sources["CEX"], err = env.RelDB.GetAssetSource(v.Asset, true)
-		if err != nil {
-			log.Warn("get GetAssetSource: ", err)
-		}
-		sources["DEX"], err = env.RelDB.GetAssetSource(v.Asset, false)
+		sources["CEX"], sources["DEX"], err = env.RelDB.GetAssetSources(v.Asset)
 		if err != nil {
-			log.Warn("get GetAssetSource: ", err)
+			log.Warn("get GetAssetSources: ", err)
 		}
 		aqf.Source = sources
 
@@ -1399,7 +1703,7 @@ func (env *Env) GetQuotedAssets(c *gin.Context) {
 
 	endtime := time.Now()
 	starttime := endtime.AddDate(0, 0, -7)
-	assetvolumes, err := env.RelDB.GetAssetsWithVolByBlockchain(starttime, endtime, c.Query("blockchain"))
+	assetvolumes, err := env.RelDB.WithContext(c.Request.Context()).GetAssetsWithVolByBlockchain(starttime, endtime, c.Query("blockchain"))
 	if err != nil {
 		log.Error("get assets with volume: ", err)
 	}
@@ -1968,7 +2272,7 @@ func (env *Env) GetLastTradesAsset(c *gin.Context) {
 		return
 	}
 
-	q, err := env.DataStore.GetLastTrades(asset, exchange, time.Now(), int(numTrades), true)
+	q, err := env.DataStore.WithContext(c.Request.Context()).GetLastTrades(asset, exchange, time.Now(), int(numTrades), true)
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			restApi.SendError(c, http.StatusNotFound, err)
@@ -1997,62 +2301,246 @@ func (env *Env) GetMissingExchangeSymbol(c *gin.Context) {
 	}
 }
 
-func (env *Env) GetAsset(c *gin.Context) {
+// GetSymbolSuggestions returns candidate assets for the unverified symbol given by @symbol on @exchange
+// together with the confidence learned from previous verification decisions.
+func (env *Env) GetSymbolSuggestions(c *gin.Context) {
 	if !validateInputParams(c) {
 		return
 	}
-
+	exchange := c.Param("exchange")
 	symbol := c.Param("symbol")
 
-	symbols, err := env.RelDB.GetAssets(symbol)
+	_, err := env.RelDB.GetExchange(exchange)
+	if errors.Is(err, pgx.ErrNoRows) {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("exchange %s not found", exchange))
+		return
+	}
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
-	} else {
-		c.JSON(http.StatusOK, symbols)
+		return
 	}
-}
 
-func (env *Env) GetAssetExchanges(c *gin.Context) {
-	if !validateInputParams(c) {
+	scorer, err := env.RelDB.GetSymbolScorer()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
 		return
 	}
-
-	symbol := c.Param("symbol")
-
-	symbols, err := env.RelDB.GetAssetExchange(symbol)
+	suggestions, err := env.RelDB.GetSymbolSuggestions(exchange, symbol, scorer)
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
-	} else {
-		c.JSON(http.StatusOK, symbols)
+		return
 	}
+	c.JSON(http.StatusOK, suggestions)
 }
 
-func (env *Env) GetAllBlockchains(c *gin.Context) {
-	blockchains, err := env.RelDB.GetAllAssetsBlockchains()
+// PostSymbolLabel records a verification decision on mapping an exchange symbol to an asset.
+// Accepted mappings are verified in the exchangesymbol table.
+func (env *Env) PostSymbolLabel(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var label models.SymbolLabel
+	err = json.Unmarshal(body, &label)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if label.Exchange == "" || label.Symbol == "" || label.Reviewer == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing exchange, symbol or reviewer"))
+		return
+	}
+	if label.Asset.Address == "" || label.Asset.Blockchain == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing asset address or blockchain"))
+		return
+	}
+	_, err = env.RelDB.GetAssetID(label.Asset)
+	if errors.Is(err, pgx.ErrNoRows) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("asset %s on %s not found", label.Asset.Address, label.Asset.Blockchain))
+		return
+	}
 	if err != nil {
 		restApi.SendError(c, http.StatusInternalServerError, err)
-	} else {
-		c.JSON(http.StatusOK, blockchains)
+		return
 	}
-}
-
-// -----------------------------------------------------------------------------
-// NFT
-// -----------------------------------------------------------------------------
 
-// GetNFTCategories returns all available NFT categories.
-func (env *Env) GetNFTCategories(c *gin.Context) {
-	q, err := env.RelDB.GetNFTCategories()
-	if len(q) == 0 || err != nil {
-		restApi.SendError(c, http.StatusInternalServerError, nil)
+	err = env.RelDB.SetSymbolLabel(label)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
 	}
-	c.JSON(http.StatusOK, q)
+	c.JSON(http.StatusOK, label)
 }
 
-// GetAllNFTClasses returns all NFT classes.
-func (env *Env) GetAllNFTClasses(c *gin.Context) {
-	if !validateInputParams(c) {
-		return
+// PostSymbolCasing sets the canonical casing of a symbol, such as stETH, for all exchanges.
+func (env *Env) PostSymbolCasing(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var casing struct {
+		Symbol string `json:"Symbol"`
+	}
+	err = json.Unmarshal(body, &casing)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if casing.Symbol == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing symbol"))
+		return
+	}
+
+	err = env.RelDB.SetSymbolCasing(casing.Symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, casing)
+}
+
+// DeleteSymbolVerification removes the verification of an exchange symbol. It is kept in the verification
+// history together with the reviewer given in the query parameter reviewer.
+func (env *Env) DeleteSymbolVerification(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	exchange, symbol, reviewer := c.Param("exchange"), c.Param("symbol"), c.Query("reviewer")
+	if reviewer == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing reviewer"))
+		return
+	}
+	found, err := env.RelDB.UnverifyExchangeSymbol(exchange, symbol, reviewer)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if !found {
+		restApi.SendError(c, http.StatusNotFound, fmt.Errorf("symbol %s not found on %s", symbol, exchange))
+		return
+	}
+	c.JSON(http.StatusOK, models.ExchangeSymbol{Symbol: symbol, Exchange: exchange})
+}
+
+// PostExcludedPair marks an exchange pair as excluded resp. not excluded. Excluded pairs are
+// unverified and do not contribute to prices. Foreign names can contain slashes, so the pair is
+// given in the request body.
+func (env *Env) PostExcludedPair(c *gin.Context) {
+	var request struct {
+		Exchange    string `json:"Exchange"`
+		ForeignName string `json:"ForeignName"`
+		Excluded    bool   `json:"Excluded"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if request.Exchange == "" || request.ForeignName == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("exchange and foreign name are required"))
+		return
+	}
+	err := env.RelDB.SetExchangePairExcluded(request.Exchange, request.ForeignName, request.Excluded)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// GetVerificationHistory returns all recorded verifications of an exchange symbol.
+func (env *Env) GetVerificationHistory(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	history, err := env.RelDB.GetVerificationHistory(c.Param("exchange"), c.Param("symbol"))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+func (env *Env) GetAsset(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	symbol := c.Param("symbol")
+
+	symbols, err := env.RelDB.GetAssets(symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+	} else {
+		c.JSON(http.StatusOK, symbols)
+	}
+}
+
+func (env *Env) GetAssetExchanges(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	symbol := c.Param("symbol")
+
+	symbols, err := env.RelDB.GetAssetExchange(symbol)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+	} else {
+		c.JSON(http.StatusOK, symbols)
+	}
+}
+
+// GetAllBlockchains returns the names of all blockchains with assets.
+// If the query parameter full=true is given, it returns the complete blockchain records
+// including genesis date, chain ID, native token and verification mechanism instead.
+// If the query parameter type is given, such as type=evm, it returns the complete records of all
+// blockchains of that type. Testnets are excluded unless testnets=true.
+func (env *Env) GetAllBlockchains(c *gin.Context) {
+	if chainType := c.Query("type"); chainType != "" {
+		blockchains, err := env.RelDB.GetBlockchainsByType(dia.ChainType(chainType), c.Query("testnets") == "true")
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, blockchains)
+		return
+	}
+	if c.Query("full") == "true" {
+		blockchains, err := env.RelDB.GetAllBlockchains(true)
+		if err != nil {
+			restApi.SendError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, blockchains)
+		return
+	}
+	blockchains, err := env.RelDB.GetAllAssetsBlockchains()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+	} else {
+		c.JSON(http.StatusOK, blockchains)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// NFT
+// -----------------------------------------------------------------------------
+
+// GetNFTCategories returns all available NFT categories.
+func (env *Env) GetNFTCategories(c *gin.Context) {
+	q, err := env.RelDB.GetNFTCategories()
+	if len(q) == 0 || err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, nil)
+	}
+	c.JSON(http.StatusOK, q)
+}
+
+// GetAllNFTClasses returns all NFT classes.
+func (env *Env) GetAllNFTClasses(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
 	}
 
 	blockchain := c.Param("blockchain")
@@ -2216,65 +2704,372 @@ func (env *Env) GetNFTFloor(c *gin.Context) {
 		timestamp = time.Now()
 	}
 
-	floorWindowSeconds := c.Query("floorWindow")
-
-	// Exclude bundle sales by default.
-	bundlesString := c.DefaultQuery("bundles", "false")
-	bundles, err := strconv.ParseBool(bundlesString)
+	floorWindowSeconds := c.Query("floorWindow")
+
+	// Exclude bundle sales by default.
+	bundlesString := c.DefaultQuery("bundles", "false")
+	bundles, err := strconv.ParseBool(bundlesString)
+	if err != nil {
+		log.Error("parse bundles string: ", err)
+	}
+
+	// Optional parameter @exchange.
+	exchange := c.Query("exchange")
+
+	// ------ Set vars -----
+	nftClass := dia.NFTClass{Address: address, Blockchain: blockchain}
+
+	type returnStruct struct {
+		Floor  float64   `json:"Floor_Price"`
+		Time   time.Time `json:"Time"`
+		Source string    `json:"Source"`
+	}
+
+	var (
+		floorWindow    int64
+		floorWindowSet bool
+		stepBackLimit  int
+		floor          float64
+		resp           returnStruct
+	)
+
+	if floorWindowSeconds != "" {
+		floorWindow, err = strconv.ParseInt(floorWindowSeconds, 10, 64)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+		}
+		floorWindowSet = true
+	} else {
+		// Set floor window to default 24h.
+		floorWindow = 24 * 60 * 60
+	}
+
+	// In default case, i.e. floorWindow is not set by user, go back up to 30
+	// days in order to find a floor price. Otherwise, don't go back at all.
+	if floorWindowSet {
+		stepBackLimit = 1
+	} else {
+		stepBackLimit = 30
+	}
+
+	// ------ Get Floor Price -----
+	floor, err = env.RelDB.GetNFTFloorRecursive(nftClass, timestamp, time.Duration(floorWindow)*time.Second, stepBackLimit, !bundles, exchange)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	resp.Floor = floor
+	resp.Time = timestamp
+	resp.Source = dia.Diadata
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetNFTRobustFloor returns the latest robust floor price of a collection, i.e. the 10th percentile of
+// its outlier-filtered sales in the window given by @floorWindow in seconds, which defaults to 24h.
+// Floor prices are stored by a scheduled job. If the stored floor price is stale, it is computed from the sales.
+func (env *Env) GetNFTRobustFloor(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+	nftClass := dia.NFTClass{Address: address, Blockchain: blockchain}
+
+	floorWindow, err := strconv.ParseInt(c.DefaultQuery("floorWindow", "86400"), 10, 64)
+	window := time.Duration(floorWindow) * time.Second
+	if err != nil || !models.ValidNFTFloorWindow(window) {
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("floorWindow must be one of %v seconds", nftFloorWindowSeconds()))
+		return
+	}
+
+	floorPrice, err := env.RelDB.GetRecentNFTFloorPrice(nftClass, window)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, floorPrice)
+}
+
+// nftFloorWindowSeconds returns the accepted floor windows in seconds.
+func nftFloorWindowSeconds() (seconds []int64) {
+	for _, window := range models.NFTFloorWindows {
+		seconds = append(seconds, int64(window.Seconds()))
+	}
+	return
+}
+
+// GetTVL returns the total value locked of a DeFi protocol on a blockchain at @timestamp.
+func (env *Env) GetTVL(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	protocol := c.Param("protocol")
+
+	timestampInt, err := strconv.ParseInt(c.DefaultQuery("timestamp", strconv.Itoa(int(time.Now().Unix()))), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("could not parse Unix timestamp"))
+		return
+	}
+
+	tvl, err := env.RelDB.GetTVL(protocol, blockchain, time.Unix(timestampInt, 0))
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, tvl)
+}
+
+// GetTVLByChain returns the total value locked of all DeFi protocols per blockchain at @timestamp.
+func (env *Env) GetTVLByChain(c *gin.Context) {
+	timestampInt, err := strconv.ParseInt(c.DefaultQuery("timestamp", strconv.Itoa(int(time.Now().Unix()))), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("could not parse Unix timestamp"))
+		return
+	}
+
+	tvls, err := env.RelDB.GetTVLByChain(time.Unix(timestampInt, 0))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, tvls)
+}
+
+// GetDefiProtocols returns all lending protocols for which rates are stored.
+func (env *Env) GetDefiProtocols(c *gin.Context) {
+	protocols, err := env.RelDB.GetDefiProtocols()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, protocols)
+}
+
+// GetDefiRates returns the supply and borrow rates of an asset on a lending protocol in the given time-range.
+func (env *Env) GetDefiRates(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	protocol := c.Param("protocol")
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), time.Duration(24*time.Hour))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("parse time range"))
+		return
+	}
+	if ok := utils.ValidTimeRange(starttime, endtime, time.Duration(30*24*time.Hour)); !ok {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("time-range too big. max duration is %v", 30*24*time.Hour))
+		return
+	}
+
+	rates, err := env.DataStore.WithContext(c.Request.Context()).GetDefiRatesInflux(protocol, dia.Asset{Address: address, Blockchain: blockchain}, starttime, endtime)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+// GetPegDeviation returns the latest deviation of a stablecoin from its peg in the last @window seconds,
+// which defaults to 24h. Deviations are computed by a scheduled job for a fixed set of stablecoins and windows.
+func (env *Env) GetPegDeviation(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	windowSeconds, err := strconv.ParseInt(c.DefaultQuery("window", "86400"), 10, 64)
+	window := time.Duration(windowSeconds) * time.Second
+	if err != nil || !models.ValidPegDeviationWindow(window) {
+		var seconds []int64
+		for _, w := range models.PegDeviationWindows {
+			seconds = append(seconds, int64(w.Seconds()))
+		}
+		restApi.SendError(c, http.StatusBadRequest, fmt.Errorf("window must be one of %v seconds", seconds))
+		return
+	}
+
+	deviation, err := env.DataStore.GetPegDeviation(dia.Asset{Address: address, Blockchain: blockchain}, window)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, deviation)
+}
+
+// GetMarketCap returns the market cap of an asset from its latest price and circulating supply.
+func (env *Env) GetMarketCap(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	asset, err := env.RelDB.GetAsset(address, blockchain)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	marketCap, err := env.DataStore.GetMarketCap(asset, &env.RelDB)
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, marketCap)
+}
+
+// GetTopAssetsByMarketCap returns the assets with the highest market caps, optionally restricted to a blockchain.
+func (env *Env) GetTopAssetsByMarketCap(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("limit must be a number between 1 and 1000"))
+		return
+	}
+
+	marketCaps, err := env.DataStore.GetTopAssetsByMarketCap(limit, c.Query("blockchain"), &env.RelDB)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, marketCaps)
+}
+
+// GetAssetRankHistory returns the daily ranks of an asset by volume and by market cap.
+func (env *Env) GetAssetRankHistory(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	blockchain := c.Param("blockchain")
+	address := normalizeAddress(c.Param("address"), blockchain)
+
+	ranks, err := env.RelDB.GetAssetRankHistory(dia.Asset{Address: address, Blockchain: blockchain})
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, ranks)
+}
+
+// GetFiatQuotation returns the rate of the fiat currency @quote in units of @base at @timestamp.
+func (env *Env) GetFiatQuotation(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	base := strings.ToUpper(c.Param("base"))
+	quote := strings.ToUpper(c.Param("quote"))
+
+	timestampInt, err := strconv.ParseInt(c.DefaultQuery("timestamp", strconv.Itoa(int(time.Now().Unix()))), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("could not parse Unix timestamp"))
+		return
+	}
+
+	fiatQuotation, err := env.DataStore.GetFiatQuotation(base, quote, time.Unix(timestampInt, 0))
+	if err != nil {
+		restApi.SendError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, fiatQuotation)
+}
+
+// GetForeignQuotationRange returns the quotations of @symbol from a foreign @source in a time-range.
+func (env *Env) GetForeignQuotationRange(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+
+	source := c.Param("source")
+	symbol := c.Param("symbol")
+
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), time.Duration(24*time.Hour))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("parse time range"))
+		return
+	}
+	if ok := utils.ValidTimeRange(starttime, endtime, time.Duration(30*24*time.Hour)); !ok {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("time-range too big. max duration is %v", 30*24*time.Hour))
+		return
+	}
+
+	quotations, err := env.DataStore.GetForeignQuotationsInflux(symbol, source, starttime, endtime)
 	if err != nil {
-		log.Error("parse bundles string: ", err)
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
 	}
+	c.JSON(http.StatusOK, quotations)
+}
 
-	// Optional parameter @exchange.
-	exchange := c.Query("exchange")
+// GetLastTradesPair returns the last trades of an exchangepair, most recent first.
+// The number of trades is given by @numTrades, 100 by default.
+func (env *Env) GetLastTradesPair(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
 
-	// ------ Set vars -----
-	nftClass := dia.NFTClass{Address: address, Blockchain: blockchain}
+	exchange := c.Param("exchange")
+	foreignname := c.Param("pair")
 
-	type returnStruct struct {
-		Floor  float64   `json:"Floor_Price"`
-		Time   time.Time `json:"Time"`
-		Source string    `json:"Source"`
+	numTrades, err := strconv.Atoi(c.DefaultQuery("numTrades", "100"))
+	if err != nil || numTrades <= 0 || numTrades > 1000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("numTrades must be a number between 1 and 1000"))
+		return
 	}
 
-	var (
-		floorWindow    int64
-		floorWindowSet bool
-		stepBackLimit  int
-		floor          float64
-		resp           returnStruct
-	)
-
-	if floorWindowSeconds != "" {
-		floorWindow, err = strconv.ParseInt(floorWindowSeconds, 10, 64)
-		if err != nil {
-			restApi.SendError(c, http.StatusBadRequest, err)
-		}
-		floorWindowSet = true
-	} else {
-		// Set floor window to default 24h.
-		floorWindow = 24 * 60 * 60
+	trades, err := env.DataStore.WithContext(c.Request.Context()).GetLastTradesByExchangePair(exchange, foreignname, numTrades)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
 	}
+	c.JSON(http.StatusOK, trades)
+}
 
-	// In default case, i.e. floorWindow is not set by user, go back up to 30
-	// days in order to find a floor price. Otherwise, don't go back at all.
-	if floorWindowSet {
-		stepBackLimit = 1
-	} else {
-		stepBackLimit = 30
+// GetTradeGaps returns the gaps between trades of an exchangepair which are longer than @maxGap seconds,
+// 300 by default, in the given time-range.
+func (env *Env) GetTradeGaps(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
 	}
 
-	// ------ Get Floor Price -----
-	floor, err = env.RelDB.GetNFTFloorRecursive(nftClass, timestamp, time.Duration(floorWindow)*time.Second, stepBackLimit, !bundles, exchange)
+	exchange := c.Param("exchange")
+	foreignname := c.Param("pair")
+
+	starttime, endtime, err := utils.MakeTimerange(c.Query("starttime"), c.Query("endtime"), time.Duration(24*time.Hour))
 	if err != nil {
-		restApi.SendError(c, http.StatusBadRequest, err)
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("parse time range"))
+		return
+	}
+	if ok := utils.ValidTimeRange(starttime, endtime, time.Duration(7*24*time.Hour)); !ok {
+		restApi.SendError(c, http.StatusInternalServerError, fmt.Errorf("time-range too big. max duration is %v", 7*24*time.Hour))
+		return
+	}
+	maxGap, err := strconv.ParseInt(c.DefaultQuery("maxGap", "300"), 10, 64)
+	if err != nil || maxGap <= 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("maxGap must be a positive number of seconds"))
 		return
 	}
 
-	resp.Floor = floor
-	resp.Time = timestamp
-	resp.Source = dia.Diadata
-	c.JSON(http.StatusOK, resp)
+	gaps, err := env.DataStore.WithContext(c.Request.Context()).GetTradeGaps(exchange, foreignname, starttime, endtime, time.Duration(maxGap)*time.Second)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, gaps)
 }
 
 // GetNFTFloorMA returns the moving average floor price of the nft class over the last 30 days.
@@ -2617,13 +3412,13 @@ func (env *Env) GetNFTDistribution(c *gin.Context) {
 
 	switch nftClass.Blockchain {
 	case dia.ETHEREUM:
-		paymentAddresses = append(paymentAddresses, "0x0000000000000000000000000000000000000000")
+		paymentAddresses = append(paymentAddresses, dia.NATIVE_ASSET_ADDRESS)
 		paymentAddresses = append(paymentAddresses, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
 	case dia.ASTAR:
-		paymentAddresses = append(paymentAddresses, "0x0000000000000000000000000000000000000000")
+		paymentAddresses = append(paymentAddresses, dia.NATIVE_ASSET_ADDRESS)
 		paymentAddresses = append(paymentAddresses, "0x9dA4A3a345bf6371f8e47c63Cad2293e532022dE")
 	case dia.BINANCESMARTCHAIN:
-		paymentAddresses = append(paymentAddresses, "0x0000000000000000000000000000000000000000")
+		paymentAddresses = append(paymentAddresses, dia.NATIVE_ASSET_ADDRESS)
 		paymentAddresses = append(paymentAddresses, "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
 	}
 
@@ -3009,7 +3804,7 @@ func (env *Env) GetNFTMarketCap(c *gin.Context) {
 
 	// Determine first and last trade time in order to set time range for querying quotes of payment currency.
 	// Remark: For now, this only works for ETH/WETH as payment currency.
-	eth := dia.Asset{Address: "0x0000000000000000000000000000000000000000", Blockchain: dia.ETHEREUM}
+	eth := dia.Asset{Address: dia.NATIVE_ASSET_ADDRESS, Blockchain: dia.ETHEREUM}
 	endtime := nftTrades[len(nftTrades)-1].Timestamp
 	starttime := nftTrades[0].Timestamp.AddDate(0, 0, -1)
 	prices, err := env.RelDB.GetHistoricalQuotations(
@@ -3674,6 +4469,305 @@ func (env *Env) GetAvailableAssets(c *gin.Context) {
 	}
 }
 
+// GetSectorHeatmap returns volume, average 24h price change and number of constituents per sector.
+func (env *Env) GetSectorHeatmap(c *gin.Context) {
+	aggregates, err := env.RelDB.GetSectorAggregates()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, aggregates)
+}
+
+// GetSyncSnapshot returns a page of the assets or exchangepairs given by @entity together with the version
+// it was read at. The page after the cursor given by the query parameter after is returned, its size is
+// bounded by the query parameter limit.
+func (env *Env) GetSyncSnapshot(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+	if err != nil || limit <= 0 || limit > 10000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("limit must be in (0,10000]"))
+		return
+	}
+	after := c.Query("after")
+
+	var page models.SyncSnapshotPage
+	switch c.Param("entity") {
+	case "assets":
+		page, err = env.RelDB.GetSyncAssetsPage(after, limit)
+	case "exchangepairs":
+		page, err = env.RelDB.GetSyncExchangePairsPage(after, limit)
+	default:
+		restApi.SendError(c, http.StatusBadRequest, errors.New("entity must be either assets or exchangepairs"))
+		return
+	}
+	if errors.Is(err, models.ErrDialectUnsupported) {
+		restApi.SendError(c, http.StatusNotImplemented, err)
+		return
+	}
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// GetSyncChanges returns all changes of the asset universe after the version given by @version.
+// The number of changes per request is bounded by the query parameter limit.
+func (env *Env) GetSyncChanges(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	version, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+	if err != nil || limit <= 0 || limit > 10000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("limit must be in (0,10000]"))
+		return
+	}
+
+	changes, err := env.RelDB.GetSyncChanges(version, limit)
+	if errors.Is(err, models.ErrDialectUnsupported) {
+		restApi.SendError(c, http.StatusNotImplemented, err)
+		return
+	}
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	currentVersion, err := env.RelDB.GetSyncVersion()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	type syncChangesResponse struct {
+		CurrentVersion int64               `json:"CurrentVersion"`
+		Changes        []models.SyncChange `json:"Changes"`
+	}
+	c.JSON(http.StatusOK, syncChangesResponse{CurrentVersion: currentVersion, Changes: changes})
+}
+
+// PostSyncQuotations returns the latest quotations of all assets in the request body.
+// The body is a json list of assets, identified by blockchain and address.
+func (env *Env) PostSyncQuotations(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var assets []dia.Asset
+	err = json.Unmarshal(body, &assets)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if len(assets) > 1000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("at most 1000 assets per request"))
+		return
+	}
+
+	quotations, err := env.DataStore.GetAssetQuotationsCache(assets)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, quotations)
+}
+
+// GetTokenList returns a token list in Uniswap token list format of all active assets
+// with metadata on the blockchain given by @blockchain.
+func (env *Env) GetTokenList(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	blockchain := c.Param("blockchain")
+	tokenList, err := env.tokenLists.Export("DIA "+blockchain, blockchain, nil)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(tokenList.Tokens) == 0 {
+		restApi.SendError(c, http.StatusNotFound, errors.New("no tokens found"))
+		return
+	}
+	c.JSON(http.StatusOK, tokenList)
+}
+
+// PostTokenList returns a token list in Uniswap token list format of a curated set of assets.
+// The body contains the name of the list and the assets, identified by blockchain and address.
+func (env *Env) PostTokenList(c *gin.Context) {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var request struct {
+		Name   string      `json:"Name"`
+		Assets []dia.Asset `json:"Assets"`
+	}
+	err = json.Unmarshal(body, &request)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if request.Name == "" || len(request.Name) > 30 || containsSpecialChars(request.Name) {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("invalid name"))
+		return
+	}
+	if len(request.Assets) == 0 || len(request.Assets) > 1000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("between 1 and 1000 assets per request"))
+		return
+	}
+
+	tokenList, err := env.tokenLists.Export(request.Name, "", request.Assets)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokenList)
+}
+
+// GetAllExchangePairs returns a page of all pairs on @exchange including pairs whose underlying
+// tokens are not yet resolved. Page size and start are given by the query parameters limit and offset.
+func (env *Env) GetAllExchangePairs(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("limit must be in (0,1000]"))
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("invalid offset"))
+		return
+	}
+
+	pairs, err := env.RelDB.GetAllExchangePairs(c.Param("exchange"), limit, offset)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, pairs)
+}
+
+// GetSummaryCounts returns the cached number of active assets and listed pairs, globally and per exchange.
+func (env *Env) GetSummaryCounts(c *gin.Context) {
+	counts, err := env.RelDB.GetSummaryCounts()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, counts)
+}
+
+// GetExchangeSummaryCounts returns the cached number of listed pairs and traded assets on an exchange.
+func (env *Env) GetExchangeSummaryCounts(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	counts, err := env.RelDB.GetExchangeSummaryCounts(c.Param("exchange"))
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, counts)
+}
+
+// PostSummaryCountsRefresh recounts the summary counters and returns the result.
+func (env *Env) PostSummaryCountsRefresh(c *gin.Context) {
+	counts, err := env.RelDB.RefreshSummaryCounts()
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, counts)
+}
+
+// GetPendingAssets returns the staged assets with review status given by the query parameter status.
+func (env *Env) GetPendingAssets(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	status := models.PendingAssetStatus(c.DefaultQuery("status", string(models.PendingAssetPending)))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("limit must be in (0,1000]"))
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("invalid offset"))
+		return
+	}
+
+	pendingAssets, err := env.RelDB.GetPendingAssets(status, limit, offset)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, pendingAssets)
+}
+
+// PostPendingAssetReview approves, rejects or edits the staged asset with id given by @id.
+// The body contains the Action (approve, reject or edit), the Reviewer and, depending on the action,
+// a Note explaining a rejection or the corrected Asset.
+func (env *Env) PostPendingAssetReview(c *gin.Context) {
+	if !validateInputParams(c) {
+		return
+	}
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, errors.New("ReadAll"))
+		return
+	}
+	var review struct {
+		Action   string    `json:"Action"`
+		Reviewer string    `json:"Reviewer"`
+		Note     string    `json:"Note"`
+		Asset    dia.Asset `json:"Asset"`
+	}
+	err = json.Unmarshal(body, &review)
+	if err != nil {
+		restApi.SendError(c, http.StatusBadRequest, err)
+		return
+	}
+	if review.Reviewer == "" {
+		restApi.SendError(c, http.StatusBadRequest, errors.New("missing reviewer"))
+		return
+	}
+
+	id := c.Param("id")
+	switch review.Action {
+	case "approve":
+		err = env.RelDB.ApprovePendingAsset(id, review.Reviewer)
+	case "reject":
+		err = env.RelDB.RejectPendingAsset(id, review.Reviewer, review.Note)
+	case "edit":
+		err = models.ValidPendingAsset(review.Asset)
+		if err != nil {
+			restApi.SendError(c, http.StatusBadRequest, err)
+			return
+		}
+		err = env.RelDB.EditPendingAsset(id, review.Asset)
+	default:
+		restApi.SendError(c, http.StatusBadRequest, errors.New("action must be one of approve, reject, edit"))
+		return
+	}
+	if err != nil {
+		restApi.SendError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
 func validateInputParams(c *gin.Context) bool {
 
 	// Validate input parameters.
@@ -3701,20 +4795,39 @@ func containsSpecialChars(s string) bool {
 	return strings.ContainsAny(s, "!@#$%^&*()'\"|{}[];><?/`~,")
 }
 
-// Returns the EIP55 compliant address in case @blockchain has an Ethereum ChainID.
+// chainType returns the chain type of @blockchain. Blockchains without a stored chain type
+// are classified by their ChainID resp. name.
+func chainType(blockchain string) dia.ChainType {
+	chain := BLOCKCHAINS[blockchain]
+	if chain.ChainType != "" {
+		return chain.ChainType
+	}
+	if strings.Contains(chain.ChainID, "Ethereum") {
+		return dia.CHAIN_TYPE_EVM
+	}
+	if chain.Name == dia.OSMOSIS {
+		return dia.CHAIN_TYPE_COSMOS
+	}
+	return ""
+}
+
+// Returns the EIP55 compliant address in case @blockchain is an EVM chain.
 func makeAddressEIP55Compliant(address string, blockchain string) string {
-	if strings.Contains(BLOCKCHAINS[blockchain].ChainID, "Ethereum") {
+	if chainType(blockchain) == dia.CHAIN_TYPE_EVM {
 		return common.HexToAddress(address).Hex()
 	}
 	return address
 }
 
-// Normalize address depending on the blockchain.
+// Normalize address depending on the chain type of the blockchain.
 func normalizeAddress(address string, blockchain string) string {
-	if strings.Contains(BLOCKCHAINS[blockchain].ChainID, "Ethereum") {
-		return makeAddressEIP55Compliant(address, blockchain)
+	if dia.IsNativeAssetAddress(blockchain, address) {
+		return dia.NativeAssetAddress(blockchain)
 	}
-	if BLOCKCHAINS[blockchain].Name == dia.OSMOSIS {
+	switch chainType(blockchain) {
+	case dia.CHAIN_TYPE_EVM:
+		return makeAddressEIP55Compliant(address, blockchain)
+	case dia.CHAIN_TYPE_COSMOS:
 		if strings.Contains(address, "ibc-") && len(strings.Split(address, "-")[1]) > 1 {
 			return "ibc/" + strings.Split(address, "-")[1]
 		}
@@ -0,0 +1,59 @@
+package diaApi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/diadata-org/diadata/pkg/http/restApi"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// apiKey returns the API key of the request, given by the header X-API-KEY or the query parameter apikey.
+func apiKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-KEY"); key != "" {
+		return key
+	}
+	return c.Query("apikey")
+}
+
+// RestrictRegions returns a middleware which rejects requests for the data product @feed from
+// regions in which its distribution is prohibited. The region of a request is the region of its
+// API key. The values of the path parameters @params are appended to @feed, separated by slashes,
+// so that restrictions can be set per source, e.g. RestrictRegions("foreignQuotation", "source").
+// It has to precede the page cache, as cached pages are served regardless of the region.
+func (env *Env) RestrictRegions(feed string, params ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feedName := feed
+		for _, param := range params {
+			feedName += "/" + c.Param(param)
+		}
+
+		// Feeds without restrictions are available in all regions, so the API key is only
+		// resolved for restricted feeds.
+		available, err := env.RelDB.IsFeedAvailable(feedName, "")
+		var region string
+		if err == nil && !available {
+			region, err = env.RelDB.GetAPIKeyRegion(apiKey(c))
+			if err == nil && region != "" {
+				available, err = env.RelDB.IsFeedAvailable(feedName, region)
+			}
+		}
+		if err != nil {
+			log.Errorf("check availability of %s: %v", feedName, err)
+			restApi.SendError(c, http.StatusInternalServerError, errors.New("availability of feed could not be checked"))
+			c.Abort()
+			return
+		}
+		if !available {
+			if region == "" {
+				restApi.SendError(c, http.StatusForbidden, errors.New("feed "+feedName+" requires an api key"))
+			} else {
+				restApi.SendError(c, http.StatusUnavailableForLegalReasons, errors.New("feed "+feedName+" is not available in region "+region))
+			}
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -4,13 +4,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
+	"sort"
 	"time"
 
+	"github.com/diadata-org/diadata/pkg/dia"
 	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
 )
 
-const influxDbForeignQuotationTable = "foreignquotation"
+const (
+	influxDbForeignQuotationTable = "foreignquotation"
+
+	// foreignComparisonMaxLag is the maximal age of a DIA quotation compared with a foreign quotation.
+	foreignComparisonMaxLag = 10 * time.Minute
+)
 
 // SaveForeignQuotationInflux stores a quotation which is not from DIA to an influx batch
 func (datastore *DB) SaveForeignQuotationInflux(fq ForeignQuotation) error {
@@ -42,15 +48,11 @@ func (datastore *DB) SaveForeignQuotationInflux(fq ForeignQuotation) error {
 func (datastore *DB) GetForeignQuotationInflux(symbol, source string, timestamp time.Time) (ForeignQuotation, error) {
 	retval := ForeignQuotation{}
 
-	unixtime := timestamp.UnixNano()
 	q := fmt.Sprintf(
-		"SELECT price,priceYesterday,volumeYesterdayUSD,\"name\" FROM %s WHERE source='%s' and \"symbol\"='%s' and time<%d order by time desc limit 1",
+		"SELECT price,priceYesterday,volumeYesterdayUSD,\"name\" FROM %s WHERE source=$source and \"symbol\"=$symbol and time<$time order by time desc limit 1",
 		influxDbForeignQuotationTable,
-		source,
-		symbol,
-		unixtime,
 	)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"source": source, "symbol": symbol, "time": timestamp.UnixNano()})
 	if err != nil {
 		fmt.Println("Error querying influx")
 		return retval, err
@@ -95,12 +97,15 @@ func (datastore *DB) GetForeignPriceYesterday(symbol, source string) (float64, e
 	secondsFromYesterday := now.Hour()*60*60 + now.Minute()*60 + now.Second()
 	timeFinal := int(now.Unix()) - secondsFromYesterday - 1
 	timeInit := timeFinal - 24*60*60
-	unixtimeFinal := strconv.Itoa(timeFinal) + "000000000"
-	unixtimeInit := strconv.Itoa(timeInit) + "000000000"
 
 	// Make corresponding influx query
-	q := fmt.Sprintf("SELECT price FROM %s WHERE source='%s' and symbol='%s' and time>%s and time<%s", influxDbForeignQuotationTable, source, symbol, unixtimeInit, unixtimeFinal)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	q := fmt.Sprintf("SELECT price FROM %s WHERE source=$source and symbol=$symbol and time>$starttime and time<$endtime", influxDbForeignQuotationTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"source":    source,
+		"symbol":    symbol,
+		"starttime": time.Unix(int64(timeInit), 0).UnixNano(),
+		"endtime":   time.Unix(int64(timeFinal), 0).UnixNano(),
+	})
 	if err != nil {
 		fmt.Println("Error querying influx")
 		return 0, err
@@ -131,8 +136,8 @@ func (datastore *DB) GetForeignPriceYesterday(symbol, source string) (float64, e
 // GetForeignSymbolsInflux returns a list with all symbols available for quotation from @source.
 func (datastore *DB) GetForeignSymbolsInflux(source string) (symbols []string, err error) {
 
-	q := fmt.Sprintf("SELECT symbol,source FROM %s WHERE time>now()-7d and source='%s'", influxDbForeignQuotationTable, source)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	q := fmt.Sprintf("SELECT symbol,source FROM %s WHERE time>now()-7d and source=$source", influxDbForeignQuotationTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"source": source})
 	if err != nil {
 		fmt.Println("Error querying influx")
 		return
@@ -157,3 +162,86 @@ func (datastore *DB) GetForeignSymbolsInflux(source string) (symbols []string, e
 	}
 	return
 }
+
+// GetForeignQuotationsInflux returns all quotations of @symbol from @source in the time-range [starttime, endtime),
+// ordered by time.
+func (datastore *DB) GetForeignQuotationsInflux(symbol, source string, starttime, endtime time.Time) ([]ForeignQuotation, error) {
+	quotations := []ForeignQuotation{}
+	q := fmt.Sprintf(
+		"SELECT price,priceYesterday,volumeYesterdayUSD,\"name\" FROM %s WHERE source=$source AND \"symbol\"=$symbol AND time>=$starttime AND time<$endtime ORDER BY ASC",
+		influxDbForeignQuotationTable,
+	)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"source":    source,
+		"symbol":    symbol,
+		"starttime": starttime.UnixNano(),
+		"endtime":   endtime.UnixNano(),
+	})
+	if err != nil {
+		return quotations, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return quotations, nil
+	}
+
+	for _, vals := range res[0].Series[0].Values {
+		fq := ForeignQuotation{Symbol: symbol, Source: source}
+		fq.Time, err = time.Parse(time.RFC3339, vals[0].(string))
+		if err != nil {
+			return quotations, err
+		}
+		fq.Price = influxFloat(vals[1])
+		fq.PriceYesterday = influxFloat(vals[2])
+		fq.VolumeYesterdayUSD = influxFloat(vals[3])
+		fq.Name, _ = vals[4].(string)
+		quotations = append(quotations, fq)
+	}
+	return quotations, nil
+}
+
+// CompareForeignQuotations benchmarks the DIA prices of @asset against the quotations of @asset's symbol
+// from @source in the time-range [starttime, endtime).
+func (datastore *DB) CompareForeignQuotations(asset dia.Asset, source string, starttime, endtime time.Time) ([]QuotationComparison, error) {
+	foreignQuotations, err := datastore.GetForeignQuotationsInflux(asset.Symbol, source, starttime, endtime)
+	if err != nil {
+		return nil, err
+	}
+	if len(foreignQuotations) == 0 {
+		return []QuotationComparison{}, nil
+	}
+	// Include DIA quotations before the first foreign quotation, so that it can be matched as well.
+	quotations, err := datastore.GetAssetQuotations(asset, starttime.Add(-foreignComparisonMaxLag), endtime)
+	if err != nil {
+		return nil, err
+	}
+	return compareQuotations(quotations, foreignQuotations), nil
+}
+
+// compareQuotations matches each of the @foreignQuotations with the latest of the DIA @quotations at or before
+// its time and at most foreignComparisonMaxLag older. Foreign quotations without a match are skipped.
+func compareQuotations(quotations []AssetQuotation, foreignQuotations []ForeignQuotation) []QuotationComparison {
+	sorted := make([]AssetQuotation, len(quotations))
+	copy(sorted, quotations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	comparisons := []QuotationComparison{}
+	for _, fq := range foreignQuotations {
+		// Index of the first DIA quotation after the foreign quotation.
+		i := sort.Search(len(sorted), func(i int) bool { return sorted[i].Time.After(fq.Time) })
+		if i == 0 || fq.Price <= 0 {
+			continue
+		}
+		quotation := sorted[i-1]
+		if fq.Time.Sub(quotation.Time) > foreignComparisonMaxLag {
+			continue
+		}
+		comparisons = append(comparisons, QuotationComparison{
+			Time:         fq.Time,
+			Price:        quotation.Price,
+			ForeignPrice: fq.Price,
+			Deviation:    quotation.Price/fq.Price - 1,
+			Source:       fq.Source,
+		})
+	}
+	return comparisons
+}
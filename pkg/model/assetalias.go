@@ -0,0 +1,62 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// SetAssetAlias records @alias as alternative name of @asset. IdentifyAsset resolves names
+// which exactly match a recorded alias to @asset.
+func (rdb *RelDB) SetAssetAlias(asset dia.Asset, alias string) error {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return errors.New("empty alias")
+	}
+	assetID, err := rdb.GetAssetID(asset)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (alias,asset_id) VALUES ($1,$2) ON CONFLICT (alias,asset_id) DO NOTHING", assetAliasTable)
+	_, err = rdb.postgresClient.Exec(context.Background(), query, alias, assetID)
+	return err
+}
+
+// GetAssetByAlias returns all assets with alternative name @alias. The alias is matched case insensitively.
+// An alias can refer to several assets, for instance to the same token on different blockchains.
+func (rdb *RelDB) GetAssetByAlias(alias string) (assets []dia.Asset, err error) {
+	query := fmt.Sprintf(`
+	SELECT DISTINCT a.symbol,a.name,a.address,a.decimals,a.blockchain
+	FROM %s al
+	INNER JOIN %s a
+	ON al.asset_id=a.asset_id
+	WHERE LOWER(al.alias)=LOWER($1)
+	ORDER BY a.blockchain,a.address
+	`, assetAliasTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, strings.TrimSpace(alias))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// assetCSVHeader are the columns of asset CSV files. Imported files may order them arbitrarily.
+var assetCSVHeader = []string{"symbol", "name", "address", "decimals", "blockchain"}
+
+// AssetImportReport summarizes an import of assets from CSV. Line numbers count the header as line 1.
+type AssetImportReport struct {
+	DryRun    bool                `json:"DryRun"`
+	Inserted  int                 `json:"Inserted"`
+	Unchanged int                 `json:"Unchanged"`
+	Conflicts []AssetConflict     `json:"Conflicts"`
+	Invalid   []AssetImportRecord `json:"Invalid"`
+}
+
+// AssetConflict is an imported asset which exists already with different symbol, name or decimals.
+// Conflicting assets are not overwritten.
+type AssetConflict struct {
+	Line     int       `json:"Line"`
+	Existing dia.Asset `json:"Existing"`
+	Imported dia.Asset `json:"Imported"`
+}
+
+// AssetImportRecord is a line of an asset CSV file which cannot be imported.
+type AssetImportRecord struct {
+	Line  int    `json:"Line"`
+	Error string `json:"Error"`
+}
+
+type assetCSVRecord struct {
+	line  int
+	asset dia.Asset
+}
+
+// ExportAssetsCSV writes all assets on @blockchain as CSV to @w, ordered by address.
+// If @blockchain is the empty string, the assets of all blockchains are written.
+func (rdb *RelDB) ExportAssetsCSV(w io.Writer, blockchain string) (err error) {
+	query := fmt.Sprintf(`
+	SELECT symbol,name,address,decimals,blockchain
+	FROM %s
+	WHERE ($1='' OR blockchain=$1)
+	ORDER BY blockchain,address
+	`, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, blockchain)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	writer := csv.NewWriter(w)
+	err = writer.Write(assetCSVHeader)
+	if err != nil {
+		return
+	}
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		err = writer.Write([]string{asset.Symbol, asset.Name, asset.Address, strconv.FormatInt(decimals.Int64, 10), asset.Blockchain})
+		if err != nil {
+			return
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportAssetsCSV stores the assets read as CSV from @r in the asset table. The file must have
+// a header with the columns written by ExportAssetsCSV. Malformed lines and assets which exist already
+// with different symbol, name or decimals are reported and skipped. With @dryRun, nothing is written
+// and the report shows the outcome of the import.
+func (rdb *RelDB) ImportAssetsCSV(r io.Reader, dryRun bool) (report AssetImportReport, err error) {
+	report.DryRun = dryRun
+	records, invalid, err := parseAssetCSV(r)
+	if err != nil {
+		return
+	}
+	report.Invalid = invalid
+
+	for _, record := range records {
+		var existing dia.Asset
+		existing, err = getAsset(rdb.postgresClient, record.asset.Address, record.asset.Blockchain)
+		if err == nil {
+			if existing.Symbol == record.asset.Symbol && existing.Name == record.asset.Name && existing.Decimals == record.asset.Decimals {
+				report.Unchanged++
+			} else {
+				report.Conflicts = append(report.Conflicts, AssetConflict{Line: record.line, Existing: existing, Imported: record.asset})
+			}
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return
+		}
+		err = nil
+		if !dryRun {
+			err = rdb.SetAsset(record.asset)
+			if err != nil {
+				return
+			}
+		}
+		report.Inserted++
+	}
+	return
+}
+
+// parseAssetCSV reads assets from the CSV in @r. It returns an error if the file cannot be read
+// or the header misses a column. Lines which do not form a valid asset are returned as @invalid.
+func parseAssetCSV(r io.Reader) (records []assetCSVRecord, invalid []AssetImportRecord, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return
+	}
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range assetCSVHeader {
+		if _, ok := columns[column]; !ok {
+			err = fmt.Errorf("missing column %s", column)
+			return
+		}
+	}
+
+	seen := make(map[string]int)
+	for line := 2; ; line++ {
+		var fields []string
+		fields, err = reader.Read()
+		if errors.Is(err, io.EOF) {
+			err = nil
+			return
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return
+			}
+			invalid = append(invalid, AssetImportRecord{Line: line, Error: parseErr.Err.Error()})
+			continue
+		}
+		if len(fields) != len(header) {
+			invalid = append(invalid, AssetImportRecord{Line: line, Error: fmt.Sprintf("expected %d fields, got %d", len(header), len(fields))})
+			continue
+		}
+
+		asset := dia.Asset{
+			Symbol:     strings.TrimSpace(fields[columns["symbol"]]),
+			Name:       strings.TrimSpace(fields[columns["name"]]),
+			Address:    strings.TrimSpace(fields[columns["address"]]),
+			Blockchain: strings.TrimSpace(fields[columns["blockchain"]]),
+		}
+		decimals, errDecimals := strconv.ParseUint(strings.TrimSpace(fields[columns["decimals"]]), 10, 8)
+		if errDecimals != nil {
+			invalid = append(invalid, AssetImportRecord{Line: line, Error: fmt.Sprintf("invalid decimals %q", fields[columns["decimals"]])})
+			continue
+		}
+		asset.Decimals = uint8(decimals)
+		if common.IsHexAddress(asset.Address) {
+			asset.Address = common.HexToAddress(asset.Address).Hex()
+		}
+		if errValid := ValidPendingAsset(asset); errValid != nil {
+			invalid = append(invalid, AssetImportRecord{Line: line, Error: errValid.Error()})
+			continue
+		}
+		key := asset.Blockchain + "-" + asset.Address
+		if previous, ok := seen[key]; ok {
+			invalid = append(invalid, AssetImportRecord{Line: line, Error: fmt.Sprintf("duplicate of line %d", previous)})
+			continue
+		}
+		seen[key] = line
+		records = append(records, assetCSVRecord{line: line, asset: asset})
+	}
+}
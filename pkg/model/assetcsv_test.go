@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAssetCSV(t *testing.T) {
+	input := `blockchain,address,symbol,name,decimals
+Ethereum,0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48,USDC,USD Coin,6
+Ethereum,0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48,USDC,USD Coin,6
+Ethereum,0x2260fac5e5542a773aa44fbcfedf7c193bc2c599,WBTC,Wrapped BTC,300
+Bitcoin,0x0000000000000000000000000000000000000000,,Bitcoin,8
+Bitcoin,0x0000000000000000000000000000000000000000,BTC,Bitcoin
+`
+	records, invalid, err := parseAssetCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].line != 2 || records[0].asset.Address != "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48" || records[0].asset.Decimals != 6 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	wantLines := []int{3, 4, 5, 6}
+	if len(invalid) != len(wantLines) {
+		t.Fatalf("got %d invalid lines, want %d: %+v", len(invalid), len(wantLines), invalid)
+	}
+	for i, line := range wantLines {
+		if invalid[i].Line != line {
+			t.Errorf("invalid record %d: got line %d, want %d", i, invalid[i].Line, line)
+		}
+	}
+
+	_, _, err = parseAssetCSV(strings.NewReader("symbol,name,address\n"))
+	if err == nil {
+		t.Error("expected error for missing columns")
+	}
+}
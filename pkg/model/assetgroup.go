@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// LinkAssets groups @assets into one logical asset, such as USDC on different blockchains.
+// Groups which already contain one of @assets are merged into the resulting group.
+func (rdb *RelDB) LinkAssets(assets []dia.Asset) (groupID string, err error) {
+	if len(assets) < 2 {
+		err = errors.New("at least two assets are needed to form a group")
+		return
+	}
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		var assetIDs []string
+		for _, asset := range assets {
+			assetID, err := txRDB.GetAssetID(asset)
+			if err != nil {
+				return fmt.Errorf("get asset id of %s on %s: %v", asset.Address, asset.Blockchain, err)
+			}
+			assetIDs = append(assetIDs, assetID)
+		}
+
+		query := fmt.Sprintf("SELECT DISTINCT group_id FROM %s WHERE asset_id=ANY($1::uuid[]) ORDER BY group_id", assetGroupTable)
+		rows, err := txRDB.postgresClient.Query(context.Background(), query, assetIDs)
+		if err != nil {
+			return err
+		}
+		var groupIDs []string
+		for rows.Next() {
+			var id string
+			if err = rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			groupIDs = append(groupIDs, id)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		if len(groupIDs) == 0 {
+			err = txRDB.postgresClient.QueryRow(context.Background(), "SELECT gen_random_uuid()").Scan(&groupID)
+			if err != nil {
+				return err
+			}
+		} else {
+			groupID = groupIDs[0]
+			query = fmt.Sprintf("UPDATE %s SET group_id=$1 WHERE group_id=ANY($2::uuid[])", assetGroupTable)
+			_, err = txRDB.postgresClient.Exec(context.Background(), query, groupID, groupIDs[1:])
+			if err != nil {
+				return err
+			}
+		}
+
+		query = fmt.Sprintf(`
+		INSERT INTO %s (group_id,asset_id)
+		SELECT $1,unnest($2::uuid[])
+		ON CONFLICT (asset_id) DO UPDATE SET group_id=EXCLUDED.group_id
+		`, assetGroupTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, groupID, assetIDs)
+		return err
+	})
+	return
+}
+
+// UnlinkAsset removes @asset from its group. The remaining assets of the group stay linked.
+func (rdb *RelDB) UnlinkAsset(asset dia.Asset) error {
+	query := fmt.Sprintf(`
+	DELETE FROM %s
+	WHERE asset_id=(SELECT asset_id FROM %s WHERE address=$1 AND blockchain=$2)
+	`, assetGroupTable, assetTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, asset.Address, asset.Blockchain)
+	return err
+}
+
+// GetAssetGroup returns all assets which are linked to the asset with @address on @blockchain,
+// including the asset itself. An asset without group is returned on its own.
+func (rdb *RelDB) GetAssetGroup(address string, blockchain string) (assets []dia.Asset, err error) {
+	query := fmt.Sprintf(`
+	SELECT a.symbol,a.name,a.address,a.decimals,a.blockchain
+	FROM %s a
+	INNER JOIN %s g
+	ON a.asset_id=g.asset_id
+	WHERE g.group_id=(
+		SELECT gg.group_id FROM %s gg
+		INNER JOIN %s ga
+		ON gg.asset_id=ga.asset_id
+		WHERE ga.address=$1 AND ga.blockchain=$2
+	)
+	ORDER BY a.blockchain,a.address
+	`, assetTable, assetGroupTable, assetGroupTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, address, blockchain)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+	}
+	err = rows.Err()
+	if err != nil || len(assets) > 0 {
+		return
+	}
+
+	asset, err := rdb.GetAsset(address, blockchain)
+	if err != nil {
+		return
+	}
+	assets = append(assets, asset)
+	return
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AssetHistoryEntry is a recorded change of an asset. The old values are empty for insertions.
+type AssetHistoryEntry struct {
+	AssetID     string    `json:"AssetID"`
+	Operation   string    `json:"Operation"`
+	OldSymbol   string    `json:"OldSymbol"`
+	OldName     string    `json:"OldName"`
+	OldDecimals uint8     `json:"OldDecimals"`
+	NewSymbol   string    `json:"NewSymbol"`
+	NewName     string    `json:"NewName"`
+	NewDecimals uint8     `json:"NewDecimals"`
+	Actor       string    `json:"Actor"`
+	Time        time.Time `json:"Time"`
+}
+
+// GetAssetHistory returns all recorded changes of the asset with @assetID in chronological order.
+func (rdb *RelDB) GetAssetHistory(assetID string) (history []AssetHistoryEntry, err error) {
+	query := fmt.Sprintf(`
+	SELECT asset_id,operation,old_symbol,old_name,old_decimals,new_symbol,new_name,new_decimals,actor,change_time
+	FROM %s
+	WHERE asset_id=$1
+	ORDER BY history_id ASC
+	`, assetHistoryTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, assetID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry       AssetHistoryEntry
+			oldSymbol   sql.NullString
+			oldName     sql.NullString
+			oldDecimals sql.NullString
+			newSymbol   sql.NullString
+			newName     sql.NullString
+			newDecimals sql.NullString
+			actor       sql.NullString
+		)
+		err = rows.Scan(
+			&entry.AssetID,
+			&entry.Operation,
+			&oldSymbol,
+			&oldName,
+			&oldDecimals,
+			&newSymbol,
+			&newName,
+			&newDecimals,
+			&actor,
+			&entry.Time,
+		)
+		if err != nil {
+			return
+		}
+		entry.OldSymbol = oldSymbol.String
+		entry.OldName = oldName.String
+		entry.OldDecimals = parseHistoryDecimals(oldDecimals)
+		entry.NewSymbol = newSymbol.String
+		entry.NewName = newName.String
+		entry.NewDecimals = parseHistoryDecimals(newDecimals)
+		entry.Actor = actor.String
+		history = append(history, entry)
+	}
+	err = rows.Err()
+	return
+}
+
+func parseHistoryDecimals(decimals sql.NullString) uint8 {
+	if !decimals.Valid {
+		return 0
+	}
+	d, err := strconv.ParseUint(decimals.String, 10, 8)
+	if err != nil {
+		log.Warnf("parse decimals %s: %v", decimals.String, err)
+	}
+	return uint8(d)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	defaultAssetCacheCapacity = 10000
+	defaultAssetCacheExpiry   = time.Hour
+)
+
+// assetLRU is a size-limited in-memory cache of assets keyed by their asset_id in postgres.
+// It is safe for concurrent use. Entries older than expiry are treated as missing.
+// A nil *assetLRU is a valid cache which never holds any entries.
+type assetLRU struct {
+	mu       sync.Mutex
+	capacity int
+	expiry   time.Duration
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type assetLRUEntry struct {
+	key     string
+	asset   dia.Asset
+	created time.Time
+}
+
+func newAssetLRU(capacity int, expiry time.Duration) *assetLRU {
+	return &assetLRU{
+		capacity: capacity,
+		expiry:   expiry,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the asset cached under @key and marks it as recently used.
+func (c *assetLRU) Get(key string) (dia.Asset, bool) {
+	if c == nil {
+		return dia.Asset{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return dia.Asset{}, false
+	}
+	entry := element.Value.(*assetLRUEntry)
+	if c.expiry > 0 && time.Since(entry.created) > c.expiry {
+		c.removeElement(element)
+		return dia.Asset{}, false
+	}
+	c.order.MoveToFront(element)
+	return entry.asset, true
+}
+
+// Add caches @asset under @key and evicts the least recently used entry if the capacity is exceeded.
+func (c *assetLRU) Add(key string, asset dia.Asset) {
+	if c == nil || c.capacity <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value = &assetLRUEntry{key: key, asset: asset, created: time.Now()}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&assetLRUEntry{key: key, asset: asset, created: time.Now()})
+	if c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Remove deletes the entry with @key.
+func (c *assetLRU) Remove(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// RemoveAsset deletes all entries holding the asset given by (@asset.Address,@asset.Blockchain).
+func (c *assetLRU) RemoveAsset(asset dia.Asset) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, element := range c.entries {
+		cached := element.Value.(*assetLRUEntry).asset
+		if cached.Address == asset.Address && cached.Blockchain == asset.Blockchain {
+			c.removeElement(element)
+		}
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *assetLRU) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Full returns true if adding a new entry evicts another one. A disabled cache is always full.
+func (c *assetLRU) Full() bool {
+	if c == nil || c.capacity <= 0 {
+		return true
+	}
+	return c.Len() >= c.capacity
+}
+
+func (c *assetLRU) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*assetLRUEntry).key)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestAssetLRUEviction(t *testing.T) {
+	c := newAssetLRU(2, 0)
+	c.Add("a", dia.Asset{Symbol: "A"})
+	c.Add("b", dia.Asset{Symbol: "B"})
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a in cache")
+	}
+	c.Add("c", dia.Asset{Symbol: "C"})
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected least recently used entry b to be evicted")
+	}
+	if c.Len() != 2 {
+		t.Errorf("cache has %d entries but should have 2", c.Len())
+	}
+}
+
+func TestAssetLRUExpiry(t *testing.T) {
+	c := newAssetLRU(2, time.Millisecond)
+	c.Add("a", dia.Asset{Symbol: "A"})
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected expired entry to be missing")
+	}
+}
+
+func TestAssetLRURemoveAsset(t *testing.T) {
+	c := newAssetLRU(10, 0)
+	c.Add("a", dia.Asset{Address: "0x1", Blockchain: dia.ETHEREUM})
+	c.Add("b", dia.Asset{Address: "0x2", Blockchain: dia.ETHEREUM})
+	c.RemoveAsset(dia.Asset{Address: "0x1", Blockchain: dia.ETHEREUM})
+	if _, ok := c.Get("a"); ok {
+		t.Error("expected a to be removed")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Error("expected b in cache")
+	}
+
+	var nilCache *assetLRU
+	nilCache.Add("a", dia.Asset{})
+	if _, ok := nilCache.Get("a"); ok {
+		t.Error("nil cache must not hold entries")
+	}
+}
+
+func TestAssetLRUFull(t *testing.T) {
+	c := newAssetLRU(2, 0)
+	c.Add("a", dia.Asset{Symbol: "A"})
+	if c.Full() {
+		t.Error("cache with one of two entries must not be full")
+	}
+	c.Add("b", dia.Asset{Symbol: "B"})
+	if !c.Full() {
+		t.Error("expected cache with two of two entries to be full")
+	}
+
+	var nilCache *assetLRU
+	if !nilCache.Full() {
+		t.Error("nil cache must be full")
+	}
+}
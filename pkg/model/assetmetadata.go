@@ -0,0 +1,128 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+// SetAssetMetadata stores descriptive information on @metadata.Asset in postgres.
+// Existing metadata of the asset is overwritten.
+func (rdb *RelDB) SetAssetMetadata(metadata dia.AssetMetadata) error {
+	assetID, err := rdb.GetAssetID(metadata.Asset)
+	if err != nil {
+		return err
+	}
+	socialLinks := metadata.SocialLinks
+	if socialLinks == nil {
+		socialLinks = make(map[string]string)
+	}
+	tags := metadata.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (asset_id,logo,website,description,social_links,tags,display_tick_size,display_max_decimals,last_update)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	ON CONFLICT (asset_id)
+	DO UPDATE SET logo=EXCLUDED.logo,website=EXCLUDED.website,description=EXCLUDED.description,social_links=EXCLUDED.social_links,tags=EXCLUDED.tags,display_tick_size=EXCLUDED.display_tick_size,display_max_decimals=EXCLUDED.display_max_decimals,last_update=EXCLUDED.last_update
+	`, assetMetadataTable)
+	_, err = rdb.postgresClient.Exec(
+		context.Background(),
+		query,
+		assetID,
+		metadata.Logo,
+		metadata.Website,
+		metadata.Description,
+		socialLinks,
+		tags,
+		sql.NullFloat64{Float64: metadata.Display.TickSize, Valid: metadata.Display.TickSize > 0},
+		sql.NullInt64{Int64: int64(metadata.Display.MaxDecimals), Valid: metadata.Display.MaxDecimals > 0},
+		metadata.LastUpdate,
+	)
+	return err
+}
+
+// GetAssetMetadata returns the descriptive information stored for @asset.
+func (rdb *RelDB) GetAssetMetadata(asset dia.Asset) (metadata dia.AssetMetadata, err error) {
+	var (
+		logo        sql.NullString
+		website     sql.NullString
+		description sql.NullString
+		lastUpdate  sql.NullTime
+		decimals    sql.NullInt64
+		tickSize    sql.NullFloat64
+		maxDecimals sql.NullInt64
+	)
+	query := fmt.Sprintf(`
+	SELECT a.symbol,a.name,a.address,a.blockchain,a.decimals,am.logo,am.website,am.description,am.social_links,am.tags,am.display_tick_size,am.display_max_decimals,am.last_update
+	FROM %s am
+	INNER JOIN %s a
+	ON am.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	`, assetMetadataTable, assetTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(
+		&metadata.Asset.Symbol,
+		&metadata.Asset.Name,
+		&metadata.Asset.Address,
+		&metadata.Asset.Blockchain,
+		&decimals,
+		&logo,
+		&website,
+		&description,
+		&metadata.SocialLinks,
+		&metadata.Tags,
+		&tickSize,
+		&maxDecimals,
+		&lastUpdate,
+	)
+	if err != nil {
+		return
+	}
+	if decimals.Valid {
+		metadata.Asset.Decimals = uint8(decimals.Int64)
+	}
+	if logo.Valid {
+		metadata.Logo = logo.String
+	}
+	if website.Valid {
+		metadata.Website = website.String
+	}
+	if description.Valid {
+		metadata.Description = description.String
+	}
+	if lastUpdate.Valid {
+		metadata.LastUpdate = lastUpdate.Time
+	}
+	metadata.Display = dia.DisplayFormat{TickSize: tickSize.Float64, MaxDecimals: int(maxDecimals.Int64)}
+	return
+}
+
+// GetAssetDisplayFormat returns the rules for displaying prices of @asset.
+// Assets without metadata have an empty display format.
+func (rdb *RelDB) GetAssetDisplayFormat(asset dia.Asset) (format dia.DisplayFormat, err error) {
+	var (
+		tickSize    sql.NullFloat64
+		maxDecimals sql.NullInt64
+	)
+	query := fmt.Sprintf(`
+	SELECT am.display_tick_size,am.display_max_decimals
+	FROM %s am
+	INNER JOIN %s a
+	ON am.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	`, assetMetadataTable, assetTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&tickSize, &maxDecimals)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return format, nil
+	}
+	if err != nil {
+		return
+	}
+	format = dia.DisplayFormat{TickSize: tickSize.Float64, MaxDecimals: int(maxDecimals.Int64)}
+	return
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// defaultMissingAssetCapacity is the number of unknown assets remembered in memory.
+const defaultMissingAssetCapacity = 10000
+
+// WithNegativeAssetCache remembers assets which are not in postgres for @ttl, in redis and in memory,
+// such that repeated reads of unknown assets do not reach postgres. Inserting an asset through a RelDB
+// removes the redis marker and the in-memory marker of that RelDB only. Other processes keep their
+// in-memory marker, so they can report a new asset as missing for up to @ttl.
+// It defaults to REDIS_TTL_MISSING_ASSET_SECONDS, which disables it if unset.
+func WithNegativeAssetCache(ttl time.Duration) RelDBOption {
+	return func(rdb *RelDB) {
+		if ttl <= 0 {
+			rdb.missingAssets = nil
+			rdb.missingAssetTTL = 0
+			return
+		}
+		rdb.missingAssets = newAssetLRU(defaultMissingAssetCapacity, ttl)
+		rdb.missingAssetTTL = ttl
+	}
+}
+
+// missingAssetKey returns the key of the negative cache entry of the asset given by @address and @blockchain.
+func missingAssetKey(address string, blockchain string) string {
+	asset := dia.Asset{Address: dia.NormalizeNativeAddress(blockchain, address), Blockchain: blockchain}
+	return asset.Identifier()
+}
+
+// isAssetMissingInMemory returns true if the asset given by @address and @blockchain is known to be
+// missing in postgres from the in-memory negative cache.
+func (rdb *RelDB) isAssetMissingInMemory(address string, blockchain string) bool {
+	if rdb.missingAssetTTL <= 0 {
+		return false
+	}
+	_, ok := rdb.missingAssets.Get(missingAssetKey(address, blockchain))
+	return ok
+}
+
+// isAssetMissingInCache returns true if redis holds a marker for the asset given by @address and
+// @blockchain not being in postgres. The marker is copied to the in-memory negative cache.
+func (rdb *RelDB) isAssetMissingInCache(address string, blockchain string) bool {
+	if rdb.missingAssetTTL <= 0 || !cacheAvailable(rdb.redisClient) {
+		return false
+	}
+	key := missingAssetKey(address, blockchain)
+	n, err := rdb.redisClient.Exists(rdb.cacheContext(), keyMissingAssetCache+key).Result()
+	if err != nil {
+		checkCacheError(err)
+		return false
+	}
+	if n == 0 {
+		return false
+	}
+	rdb.missingAssets.Add(key, dia.Asset{})
+	return true
+}
+
+// setAssetMissing marks the asset given by @address and @blockchain as missing in postgres.
+func (rdb *RelDB) setAssetMissing(address string, blockchain string) {
+	if rdb.missingAssetTTL <= 0 {
+		return
+	}
+	key := missingAssetKey(address, blockchain)
+	rdb.missingAssets.Add(key, dia.Asset{})
+	if !cacheAvailable(rdb.redisClient) {
+		skipCacheWrite()
+		return
+	}
+	err := rdb.redisClient.Set(rdb.cacheContext(), keyMissingAssetCache+key, 1, rdb.missingAssetTTL).Err()
+	if err != nil && !checkCacheError(err) {
+		log.Warn("set missing asset marker: ", err)
+	}
+}
+
+// clearAssetMissing removes the markers of @asset being missing in postgres from redis and from
+// the in-memory negative cache of @rdb.
+func (rdb *RelDB) clearAssetMissing(asset dia.Asset) {
+	if rdb.missingAssetTTL <= 0 {
+		return
+	}
+	key := missingAssetKey(asset.Address, asset.Blockchain)
+	rdb.missingAssets.Remove(key)
+	if !cacheAvailable(rdb.redisClient) {
+		return
+	}
+	err := rdb.redisClient.Del(rdb.cacheContext(), keyMissingAssetCache+key).Err()
+	if err != nil && !checkCacheError(err) {
+		log.Warn("delete missing asset marker: ", err)
+	}
+}
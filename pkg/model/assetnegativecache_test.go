@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestNegativeAssetCache(t *testing.T) {
+	rdb := &RelDB{}
+	rdb.setAssetMissing("0x1", dia.ETHEREUM)
+	if rdb.isAssetMissingInMemory("0x1", dia.ETHEREUM) {
+		t.Error("disabled negative cache must not hold entries")
+	}
+
+	WithNegativeAssetCache(time.Minute)(rdb)
+	rdb.setAssetMissing("0x1", dia.ETHEREUM)
+	if !rdb.isAssetMissingInMemory("0x1", dia.ETHEREUM) {
+		t.Error("expected 0x1 to be marked as missing")
+	}
+	if rdb.isAssetMissingInMemory("0x2", dia.ETHEREUM) {
+		t.Error("expected 0x2 not to be marked as missing")
+	}
+
+	rdb.clearAssetMissing(dia.Asset{Address: "0x1", Blockchain: dia.ETHEREUM})
+	if rdb.isAssetMissingInMemory("0x1", dia.ETHEREUM) {
+		t.Error("expected marker of inserted asset to be removed")
+	}
+}
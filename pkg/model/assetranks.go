@@ -0,0 +1,97 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// AssetRank is the rank of an asset by 24h volume and by market cap on @Date.
+// A zero rank means that the asset was not ranked on that day.
+type AssetRank struct {
+	Date          time.Time `json:"Date"`
+	VolumeRank    int       `json:"VolumeRank"`
+	MarketCapRank int       `json:"MarketCapRank"`
+}
+
+// SnapshotAssetRanks records the rank of all assets by their latest 24h volume and by market cap for the
+// UTC day of @timestamp. @marketCaps must be ordered by market cap descending, as returned by
+// GetTopAssetsByMarketCap. It is meant to run once per day, a repeated snapshot on the same day
+// replaces the former one. It returns the number of ranked assets.
+func (rdb *RelDB) SnapshotAssetRanks(timestamp time.Time, marketCaps []MarketCap) (ranked int64, err error) {
+	date := volumeDate(timestamp)
+	var addresses, blockchains []string
+	for _, marketCap := range marketCaps {
+		addresses = append(addresses, marketCap.Asset.Address)
+		blockchains = append(blockchains, marketCap.Asset.Blockchain)
+	}
+
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE date=$1", assetRankTable)
+		if _, errRank := txRDB.postgresClient.Exec(context.Background(), query, date); errRank != nil {
+			return errRank
+		}
+
+		query = fmt.Sprintf(`
+		INSERT INTO %s (asset_id,date,volume_rank)
+		SELECT asset_id,$1,RANK() OVER (ORDER BY volume_usd DESC)
+		FROM %s
+		WHERE volume_usd>0`,
+			assetRankTable, assetVolumeTable)
+		if _, errRank := txRDB.postgresClient.Exec(context.Background(), query, date); errRank != nil {
+			return errRank
+		}
+
+		query = fmt.Sprintf(`
+		INSERT INTO %s (asset_id,date,marketcap_rank)
+		SELECT a.asset_id,$1,r.rank
+		FROM unnest($2::text[],$3::text[]) WITH ORDINALITY AS r(address,blockchain,rank)
+		INNER JOIN %s a
+		ON a.address=r.address AND a.blockchain=r.blockchain
+		ON CONFLICT (asset_id,date) DO UPDATE SET marketcap_rank=EXCLUDED.marketcap_rank`,
+			assetRankTable, assetTable)
+		if _, errRank := txRDB.postgresClient.Exec(context.Background(), query, date, addresses, blockchains); errRank != nil {
+			return errRank
+		}
+
+		query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE date=$1", assetRankTable)
+		return txRDB.postgresClient.QueryRow(context.Background(), query, date).Scan(&ranked)
+	})
+	return
+}
+
+// GetAssetRankHistory returns the daily ranks of @asset, sorted by date in ascending order.
+func (rdb *RelDB) GetAssetRankHistory(asset dia.Asset) (ranks []AssetRank, err error) {
+	query := fmt.Sprintf(`
+	SELECT r.date,r.volume_rank,r.marketcap_rank
+	FROM %s r
+	INNER JOIN %s a
+	ON r.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	ORDER BY r.date ASC`,
+		assetRankTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, asset.Address, asset.Blockchain)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rank          AssetRank
+			volumeRank    sql.NullInt64
+			marketCapRank sql.NullInt64
+		)
+		if err = rows.Scan(&rank.Date, &volumeRank, &marketCapRank); err != nil {
+			return
+		}
+		rank.VolumeRank = int(volumeRank.Int64)
+		rank.MarketCapRank = int(marketCapRank.Int64)
+		ranks = append(ranks, rank)
+	}
+	err = rows.Err()
+	return
+}
@@ -5,14 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/go-redis/redis"
-	"github.com/jackc/pgtype"
+	"github.com/go-redis/redis/v8"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -35,19 +35,33 @@ func (rdb *RelDB) GetKeyAsset(asset dia.Asset) (string, error) {
 // 		-------------------------------------------------------------
 
 // SetAsset stores an asset into postgres.
+// The insertion is recorded in the asset history.
 func (rdb *RelDB) SetAsset(asset dia.Asset) error {
-	query := fmt.Sprintf("INSERT INTO %s (symbol,name,address,decimals,blockchain) VALUES ($1,$2,$3,$4,$5) ON CONFLICT (address,blockchain) DO NOTHING", assetTable)
-	_, err := rdb.postgresClient.Exec(context.Background(), query, asset.Symbol, asset.Name, asset.Address, strconv.Itoa(int(asset.Decimals)), asset.Blockchain)
-	if err != nil {
-		return err
+	err := setAsset(rdb.postgresClient, asset, rdb.actor)
+	if err == nil {
+		rdb.clearAssetMissing(asset)
 	}
-	return nil
+	return err
+}
+
+func setAsset(q pgQuerier, asset dia.Asset, actor string) error {
+	query := fmt.Sprintf(`
+	WITH inserted AS (
+		INSERT INTO %s (symbol,name,address,decimals,blockchain) VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT (address,blockchain) DO NOTHING
+		RETURNING asset_id,symbol,name,decimals
+	)
+	INSERT INTO %s (asset_id,operation,new_symbol,new_name,new_decimals,actor)
+	SELECT asset_id,'INSERT',symbol,name,decimals,$6 FROM inserted
+	`, assetTable, assetHistoryTable)
+	_, err := q.Exec(context.Background(), query, asset.Symbol, asset.Name, asset.Address, strconv.Itoa(int(asset.Decimals)), asset.Blockchain, actor)
+	return err
 }
 
 // GetAssetID returns the unique identifier of @asset in postgres table asset, if the entry exists.
 func (rdb *RelDB) GetAssetID(asset dia.Asset) (ID string, err error) {
 	query := fmt.Sprintf("SELECT asset_id FROM %s WHERE address=$1 AND blockchain=$2", assetTable)
-	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&ID)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, dia.NormalizeNativeAddress(asset.Blockchain, asset.Address), asset.Blockchain).Scan(&ID)
 	if err != nil {
 		return
 	}
@@ -114,15 +128,41 @@ func (rdb *RelDB) InsertNewAssetMap(asset_id string) error {
 }
 
 // GetAsset is the standard method in order to uniquely retrieve an asset from asset table.
-func (rdb *RelDB) GetAsset(address, blockchain string) (asset dia.Asset, err error) {
-	cachedAsset, errCache := rdb.GetAssetCache(blockchain, address)
-	if errCache == nil {
-		asset = cachedAsset
-		return
+// By default, the asset is read from the redis cache first, see ReadConsistency for other semantics.
+func (rdb *RelDB) GetAsset(address, blockchain string, opts ...ReadOption) (asset dia.Asset, err error) {
+	o := newReadOptions(opts)
+	if o.readCache() {
+		if rdb.isAssetMissingInMemory(address, blockchain) {
+			err = pgx.ErrNoRows
+			return
+		}
+		cachedAsset, errCache := rdb.GetAssetCache(blockchain, address)
+		if errCache == nil {
+			asset = cachedAsset
+			return
+		}
+		if rdb.isAssetMissingInCache(address, blockchain) {
+			err = pgx.ErrNoRows
+			return
+		}
 	}
+	asset, err = getAsset(rdb.postgresClient, address, blockchain)
+	if errors.Is(err, pgx.ErrNoRows) && o.writeCache() {
+		rdb.setAssetMissing(address, blockchain)
+	}
+	if err == nil && o.consistency == CacheRefresh {
+		if errCache := rdb.SetAssetCache(asset); errCache != nil {
+			log.Warn("refresh asset cache: ", errCache)
+		}
+	}
+	return
+}
+
+func getAsset(q pgQuerier, address, blockchain string) (asset dia.Asset, err error) {
 	var decimals sql.NullInt64
+	address = dia.NormalizeNativeAddress(blockchain, address)
 	query := fmt.Sprintf("SELECT symbol,name,address,decimals,blockchain FROM %s WHERE address=$1 AND blockchain=$2", assetTable)
-	err = rdb.postgresClient.QueryRow(context.Background(), query, address, blockchain).Scan(
+	err = q.QueryRow(context.Background(), query, address, blockchain).Scan(
 		&asset.Symbol,
 		&asset.Name,
 		&asset.Address,
@@ -139,8 +179,59 @@ func (rdb *RelDB) GetAsset(address, blockchain string) (asset dia.Asset, err err
 	return
 }
 
-// GetAssetByID returns an asset by its uuid
-func (rdb *RelDB) GetAssetByID(assetID string) (asset dia.Asset, err error) {
+// SearchAssets returns up to @limit assets matching @query in symbol, name or address, ordered by relevance.
+// Exact symbol matches rank first, followed by full-text and trigram similarity matches.
+func (rdb *RelDB) SearchAssets(query string, limit int) (assets []dia.Asset, err error) {
+	q := fmt.Sprintf(`
+	SELECT symbol,name,address,decimals,blockchain
+	FROM (
+		SELECT symbol,name,address,decimals,blockchain,
+			(CASE WHEN LOWER(symbol)=LOWER($1) THEN 1 ELSE 0 END)
+			+ ts_rank(to_tsvector('simple', symbol || ' ' || name || ' ' || address), plainto_tsquery('simple', $1))
+			+ GREATEST(similarity(symbol, $1), similarity(name, $1)) AS rank
+		FROM %s
+		WHERE to_tsvector('simple', symbol || ' ' || name || ' ' || address) @@ plainto_tsquery('simple', $1)
+		OR symbol %% $1
+		OR name %% $1
+		OR address=$1
+	) s
+	ORDER BY rank DESC
+	LIMIT $2
+	`, assetTable)
+	var rows pgx.Rows
+	rows, err = rdb.postgresClient.Query(context.Background(), q, query, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+	}
+	return
+}
+
+// GetAssetByID returns an asset by its uuid.
+// By default, the asset is read from postgres only. Callers opt in to the in-memory asset cache with
+// WithReadConsistency(CacheFirst) or CacheRefresh.
+func (rdb *RelDB) GetAssetByID(assetID string, opts ...ReadOption) (asset dia.Asset, err error) {
+	o := newReadOptionsDefault(DatabaseOnly, opts)
+	if o.readCache() {
+		if cachedAsset, ok := rdb.assetCache.Get(assetID); ok {
+			return cachedAsset, nil
+		}
+	}
 	var decimals sql.NullInt64
 	query := fmt.Sprintf("SELECT symbol,name,address,decimals,blockchain FROM %s WHERE asset_id=$1", assetTable)
 	err = rdb.postgresClient.QueryRow(context.Background(), query, assetID).Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
@@ -150,9 +241,62 @@ func (rdb *RelDB) GetAssetByID(assetID string) (asset dia.Asset, err error) {
 	if decimals.Valid {
 		asset.Decimals = uint8(decimals.Int64)
 	}
+	if o.writeCache() {
+		rdb.assetCache.Add(assetID, asset)
+	}
 	return
 }
 
+// GetAssetsByIDs returns the assets with uuids in @assetIDs, keyed by uuid, using a single query.
+// By default, assets held in the in-memory asset cache are not queried, see ReadConsistency for
+// other semantics. Unknown uuids are missing in the map.
+func (rdb *RelDB) GetAssetsByIDs(assetIDs []string, opts ...ReadOption) (map[string]dia.Asset, error) {
+	o := newReadOptions(opts)
+	assets := make(map[string]dia.Asset, len(assetIDs))
+	var missing []string
+	for _, assetID := range assetIDs {
+		if !o.readCache() {
+			missing = append(missing, assetID)
+			continue
+		}
+		if asset, ok := rdb.assetCache.Get(assetID); ok {
+			assets[assetID] = asset
+			continue
+		}
+		missing = append(missing, assetID)
+	}
+	if len(missing) == 0 {
+		return assets, nil
+	}
+
+	query := fmt.Sprintf("SELECT asset_id::text,symbol,name,address,decimals,blockchain FROM %s WHERE asset_id=ANY($1::uuid[])", assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, missing)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			assetID  string
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&assetID, &asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return nil, err
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		if o.writeCache() {
+			rdb.assetCache.Add(assetID, asset)
+		}
+		assets[assetID] = asset
+	}
+	return assets, rows.Err()
+}
+
 // GetAllAssets returns all assets on @blockchain from asset table.
 func (rdb *RelDB) GetAllAssets(blockchain string) (assets []dia.Asset, err error) {
 	var rows pgx.Rows
@@ -179,6 +323,123 @@ func (rdb *RelDB) GetAllAssets(blockchain string) (assets []dia.Asset, err error
 	return
 }
 
+// GetAssetsByBlockchain returns up to @limit assets on @blockchain, skipping the first @offset assets.
+// If @symbolPrefix is not empty, only assets whose symbol starts with @symbolPrefix (case insensitive) are returned.
+// Assets are sorted according to @order.
+func (rdb *RelDB) GetAssetsByBlockchain(blockchain string, symbolPrefix string, order AssetOrder, limit int, offset int) (assets []dia.Asset, err error) {
+	orderClause, ok := assetOrderClauses[order]
+	if !ok {
+		err = fmt.Errorf("unknown asset order %s", order)
+		return
+	}
+
+	var qb queryBuilder
+	qb.where("a.blockchain=%s", blockchain)
+	if symbolPrefix != "" {
+		qb.where("a.symbol ILIKE %s || '%%'", likeEscaper.Replace(symbolPrefix))
+	}
+	query := fmt.Sprintf(`
+	SELECT a.symbol,a.name,a.address,a.decimals,a.blockchain
+	FROM %s a
+	LEFT JOIN %s av
+	ON a.asset_id=av.asset_id
+	WHERE %s
+	ORDER BY %s
+	LIMIT %d OFFSET %d
+	`, assetTable, assetVolumeTable, qb.conditions(), orderClause, limit, offset)
+
+	var rows pgx.Rows
+	rows, err = rdb.postgresClient.Query(context.Background(), query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+	}
+	return
+}
+
+// DeprecateAsset marks @asset as deprecated at @timestamp. Deprecated assets are excluded from price feeds.
+func (rdb *RelDB) DeprecateAsset(asset dia.Asset, timestamp time.Time) error {
+	return rdb.setAssetDeprecation(asset, sql.NullTime{Time: timestamp, Valid: true})
+}
+
+// ReactivateAsset removes the deprecation mark of @asset.
+func (rdb *RelDB) ReactivateAsset(asset dia.Asset) error {
+	return rdb.setAssetDeprecation(asset, sql.NullTime{})
+}
+
+func (rdb *RelDB) setAssetDeprecation(asset dia.Asset, deprecatedAt sql.NullTime) error {
+	query := fmt.Sprintf("UPDATE %s SET deprecated_at=$1 WHERE address=$2 AND blockchain=$3", assetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, deprecatedAt, asset.Address, asset.Blockchain)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("asset with address %s on %s not found", asset.Address, asset.Blockchain)
+	}
+	err = rdb.InvalidateAssetCache(asset)
+	if err != nil {
+		log.Errorf("remove asset %s from cache: %v", asset.Identifier(), err)
+	}
+	return nil
+}
+
+// GetActiveAssetsOnly returns all assets on @blockchain which are not deprecated.
+func (rdb *RelDB) GetActiveAssetsOnly(blockchain string) ([]dia.Asset, error) {
+	return rdb.getAssetsByDeprecation(blockchain, false)
+}
+
+// GetDeprecatedAssets returns all deprecated assets on @blockchain.
+// If @blockchain is the empty string, deprecated assets on all blockchains are returned.
+func (rdb *RelDB) GetDeprecatedAssets(blockchain string) ([]dia.Asset, error) {
+	return rdb.getAssetsByDeprecation(blockchain, true)
+}
+
+func (rdb *RelDB) getAssetsByDeprecation(blockchain string, deprecated bool) (assets []dia.Asset, err error) {
+	query := fmt.Sprintf(`
+	SELECT symbol,name,address,decimals,blockchain
+	FROM %s
+	WHERE ($1='' OR blockchain=$1)
+	AND (deprecated_at IS NOT NULL)=$2
+	`, assetTable)
+	var rows pgx.Rows
+	rows, err = rdb.postgresClient.Query(context.Background(), query, blockchain, deprecated)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+	}
+	return
+}
+
 // GetAssetsBySymbolName returns a (possibly multiple) dia.Asset by its symbol and name from postgres.
 // If @name is an empty string, it returns all assets with @symbol.
 // If @symbol is an empty string, it returns all assets with @name.
@@ -194,10 +455,10 @@ func (rdb *RelDB) GetAssetsBySymbolName(symbol, name string) (assets []dia.Asset
 		FROM %s a
 		INNER JOIN %s av
 		ON av.asset_id=a.asset_id
-		WHERE av.volume>0
+		WHERE av.volume_usd>0
 		AND av.time_stamp IS NOT NULL
 		AND symbol ILIKE '%s%%'
-		ORDER BY av.volume DESC`,
+		ORDER BY av.volume_usd DESC`,
 			assetTable,
 			assetVolumeTable,
 			symbol,
@@ -208,10 +469,10 @@ func (rdb *RelDB) GetAssetsBySymbolName(symbol, name string) (assets []dia.Asset
 		FROM %s a
 		INNER JOIN %s av
 		ON av.asset_id=a.asset_id
-		WHERE av.volume>0
+		WHERE av.volume_usd>0
 		AND av.time_stamp IS NOT NULL
 		AND name ILIKE '%s%%'
-		ORDER BY av.volume DESC`,
+		ORDER BY av.volume_usd DESC`,
 			assetTable,
 			assetVolumeTable,
 			symbol,
@@ -222,10 +483,10 @@ func (rdb *RelDB) GetAssetsBySymbolName(symbol, name string) (assets []dia.Asset
 		FROM %s a 
 		INNER JOIN %s av 
 		ON av.asset_id=a.asset_id 
-		WHERE av.volume>0
+		WHERE av.volume_usd>0
 		AND av.time_stamp IS NOT NULL
 		AND (symbol ILIKE '%s%%' OR name ILIKE '%s%%')
-		ORDER BY av.volume DESC`,
+		ORDER BY av.volume_usd DESC`,
 			assetTable,
 			assetVolumeTable,
 			name,
@@ -264,10 +525,10 @@ func (rdb *RelDB) GetAssetsByAddress(address string) (assets []dia.Asset, err er
 	FROM %s a 
 	INNER JOIN %s av 
 	ON a.asset_id=av.asset_id
-	WHERE av.volume>0
+	WHERE av.volume_usd>0
 	AND av.time_stamp IS NOT NULL
 	AND address ILIKE '%s%%'
-	ORDER BY av.volume DESC`,
+	ORDER BY av.volume_usd DESC`,
 		assetTable,
 		assetVolumeTable,
 		address,
@@ -314,67 +575,202 @@ func (rdb *RelDB) GetFiatAssetBySymbol(symbol string) (asset dia.Asset, err erro
 // asset is allowed to have zero decimals as well (for instance sngls, trxc).
 // Comment 2: Should we add a preprocessing step in which notation is corrected corresponding
 // to the notation in the underlying contract on the blockchain?
-// Comment 3: Can we improve this? How to treat cases like CoinBase emitting symbol name
-// 'Wrapped Bitcoin' instead of the correct 'Wrapped BTC', or 'United States Dollar' instead
-// of 'United States dollar'? On idea would be to add a table with alternative names for
-// symbol tickers, so WBTC -> [Wrapped Bitcoin, Wrapped bitcoin, Wrapped BTC,...]
+// Comment 3: Exchanges such as CoinBase emit non-standard names like 'Wrapped Bitcoin' instead
+// of the correct 'Wrapped BTC'. Such names are resolved by the alternative names recorded
+// with SetAssetAlias.
 func (rdb *RelDB) IdentifyAsset(asset dia.Asset) (assets []dia.Asset, err error) {
-	query := fmt.Sprintf("SELECT symbol,name,address,decimals,blockchain FROM %s WHERE ", assetTable)
-	var and string
+	matches, err := rdb.IdentifyAssetMatches(asset)
+	if err != nil {
+		return
+	}
+	for _, match := range matches {
+		if match.IsExact() {
+			assets = append(assets, match.Asset)
+		}
+	}
+	return
+}
+
+// IdentifyAssetMatches looks for all assets in postgres which match the non-null fields in @asset.
+// Symbol and name are matched case insensitively. The confidence of each given field is 1 for
+// an exact match and 0.5 for a match which only differs in case. Names are also matched against
+// the aliases of an asset, with the same confidence as against its name.
+func (rdb *RelDB) IdentifyAssetMatches(asset dia.Asset) (matches []AssetMatch, err error) {
+	address := asset.Address
+	if common.IsHexAddress(address) {
+		address = common.HexToAddress(address).Hex()
+	}
+
+	var qb queryBuilder
 	if asset.Symbol != "" {
-		query += fmt.Sprintf("symbol='%s'", asset.Symbol)
-		and = " AND "
+		qb.where("LOWER(symbol)=LOWER(%s)", asset.Symbol)
 	}
+	// matchedAlias selects the alias matching the given name, preferring the one with identical case.
+	matchedAlias := "NULL"
 	if asset.Name != "" {
-		query += fmt.Sprintf(and+"name='%s'", asset.Name)
-		and = " AND "
+		qb.where(fmt.Sprintf("(LOWER(name)=LOWER(%%[1]s) OR asset_id IN (SELECT asset_id FROM %s WHERE LOWER(alias)=LOWER(%%[1]s)))", assetAliasTable), asset.Name)
+		matchedAlias = fmt.Sprintf(
+			"(SELECT alias FROM %s al WHERE al.asset_id=%s.asset_id AND LOWER(al.alias)=LOWER($%d) ORDER BY al.alias=$%d DESC LIMIT 1)",
+			assetAliasTable,
+			assetTable,
+			len(qb.args),
+			len(qb.args),
+		)
 	}
-	if asset.Address != "" {
-		query += fmt.Sprintf(and+"address='%s'", common.HexToAddress(asset.Address).Hex())
-		and = " AND "
+	if address != "" {
+		qb.where("address=%s", address)
 	}
 	if asset.Decimals != 0 {
-		query += fmt.Sprintf(and+"decimals='%d'", asset.Decimals)
-		and = " AND "
+		qb.where("decimals=%s", strconv.Itoa(int(asset.Decimals)))
 	}
 	if asset.Blockchain != "" {
-		query += fmt.Sprintf(and+"blockchain='%s'", asset.Blockchain)
+		qb.where("blockchain=%s", asset.Blockchain)
 	}
-	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if len(qb.args) == 0 {
+		err = errors.New("at least one field of the asset must be given")
+		return
+	}
+
+	query := fmt.Sprintf("SELECT symbol,name,address,decimals,blockchain,%s FROM %s WHERE %s", matchedAlias, assetTable, qb.conditions())
+	rows, err := rdb.postgresClient.Query(context.Background(), query, qb.args...)
 	if err != nil {
 		return
 	}
 	defer rows.Close()
 
-	var decimals sql.NullInt64
+	var (
+		decimals sql.NullInt64
+		alias    sql.NullString
+	)
 	for rows.Next() {
-		asset := dia.Asset{}
-		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		match := AssetMatch{Confidence: make(map[string]float64)}
+		err = rows.Scan(&match.Asset.Symbol, &match.Asset.Name, &match.Asset.Address, &decimals, &match.Asset.Blockchain, &alias)
 		if err != nil {
 			return
 		}
 		if decimals.Valid {
-			asset.Decimals = uint8(decimals.Int64)
+			match.Asset.Decimals = uint8(decimals.Int64)
 		}
-		assets = append(assets, asset)
+		if asset.Symbol != "" {
+			match.Confidence["Symbol"] = matchConfidence(asset.Symbol, match.Asset.Symbol)
+		}
+		if asset.Name != "" {
+			match.Confidence["Name"] = matchConfidence(asset.Name, match.Asset.Name)
+			if alias.Valid {
+				match.Confidence["Name"] = math.Max(match.Confidence["Name"], matchConfidence(asset.Name, alias.String))
+			}
+		}
+		if address != "" {
+			match.Confidence["Address"] = 1
+		}
+		if asset.Decimals != 0 {
+			match.Confidence["Decimals"] = 1
+		}
+		if asset.Blockchain != "" {
+			match.Confidence["Blockchain"] = 1
+		}
+		matches = append(matches, match)
 	}
 
 	return
 }
 
+// matchConfidence returns 1 if @got equals @want and 0.5 if they only differ in case.
+func matchConfidence(want string, got string) float64 {
+	if want == got {
+		return 1
+	}
+	if strings.EqualFold(want, got) {
+		return 0.5
+	}
+	return 0
+}
+
+// UpdateAsset updates the mutable fields symbol, name and decimals of the asset
+// uniquely identified by (@asset.Address,@asset.Blockchain).
+// The change is recorded in the asset history together with the previous values.
+// Stale entries of the asset are removed from the redis cache and the in-memory cache.
+func (rdb *RelDB) UpdateAsset(asset dia.Asset) error {
+	// The FROM clause reads the row as it was before the update, so the old values can be recorded in the asset history.
+	query := fmt.Sprintf(`
+	WITH updated AS (
+		UPDATE %s a SET symbol=$1,name=$2,decimals=$3
+		FROM (SELECT asset_id,symbol,name,decimals FROM %s WHERE address=$4 AND blockchain=$5 FOR UPDATE) old
+		WHERE a.asset_id=old.asset_id
+		RETURNING a.asset_id,old.symbol AS old_symbol,old.name AS old_name,old.decimals AS old_decimals,a.symbol,a.name,a.decimals
+	), ticker AS (
+		INSERT INTO %s (asset_id,symbol,valid_from,valid_to)
+		SELECT u.asset_id,u.old_symbol,(SELECT MAX(t.valid_to) FROM %s t WHERE t.asset_id=u.asset_id),NOW()
+		FROM updated u
+		WHERE u.old_symbol<>u.symbol
+	)
+	INSERT INTO %s (asset_id,operation,old_symbol,old_name,old_decimals,new_symbol,new_name,new_decimals,actor)
+	SELECT asset_id,'UPDATE',old_symbol,old_name,old_decimals,symbol,name,decimals,$6 FROM updated
+	RETURNING asset_id
+	`, assetTable, assetTable, assetTickerTable, assetTickerTable, assetHistoryTable)
+	var assetID string
+	err := rdb.postgresClient.QueryRow(
+		context.Background(),
+		query,
+		asset.Symbol,
+		asset.Name,
+		strconv.Itoa(int(asset.Decimals)),
+		asset.Address,
+		asset.Blockchain,
+		rdb.actor,
+	).Scan(&assetID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("asset with address %s on %s not found", asset.Address, asset.Blockchain)
+		}
+		return err
+	}
+
+	rdb.assetCache.Remove(assetID)
+	err = rdb.InvalidateAssetCache(asset)
+	if err != nil {
+		log.Errorf("remove asset %s from cache: %v", asset.Identifier(), err)
+	}
+	return nil
+}
+
+// DeleteAsset removes the asset uniquely identified by (@asset.Address,@asset.Blockchain) from postgres
+// and from all caches. It fails if the asset is still referenced by other tables.
+func (rdb *RelDB) DeleteAsset(asset dia.Asset) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE address=$1 AND blockchain=$2", assetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, asset.Address, asset.Blockchain)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("asset with address %s on %s not found", asset.Address, asset.Blockchain)
+	}
+	err = rdb.InvalidateAssetCache(asset)
+	if err != nil {
+		log.Errorf("remove asset %s from cache: %v", asset.Identifier(), err)
+	}
+	return nil
+}
+
 // 		-------------------------------------------------------------
 // 		exchangesymbol TABLE methods
 // 		-------------------------------------------------------------
 
 // SetExchangeSymbol writes unique data into exchangesymbol table if not yet in there.
+// A new @symbol is stored in its canonical casing, see CanonicalSymbol. Case variations of a stored
+// symbol are sightings of the stored symbol and are neither stored twice nor rewritten.
+// Each call is a sighting of the symbol, so its lastseen timestamp is updated if it exists already
+// and a delisted symbol is listed again.
 func (rdb *RelDB) SetExchangeSymbol(exchange string, symbol string) error {
 	query := fmt.Sprintf(`
-	INSERT INTO %s (symbol,exchange) 
-	SELECT $1,$2 
-	WHERE NOT EXISTS 
-	(SELECT 1 FROM exchangesymbol WHERE symbol=$1 AND exchange=$2)
+	WITH seen AS (
+		UPDATE %[1]s SET lastseen=NOW(),delisted_at=NULL WHERE LOWER(symbol)=LOWER($1) AND exchange=$2 RETURNING 1
+	)
+	INSERT INTO %[1]s (symbol,exchange,firstseen,lastseen) 
+	SELECT $1,$2,NOW(),NOW() 
+	WHERE NOT EXISTS (SELECT 1 FROM seen)
 	`, exchangesymbolTable)
-	_, err := rdb.postgresClient.Exec(context.Background(), query, symbol, exchange)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, rdb.CanonicalSymbol(symbol), exchange)
 	if err != nil {
 		return err
 	}
@@ -389,7 +785,8 @@ func (rdb *RelDB) GetExchangeSymbol(exchange string, symbol string) (asset dia.A
 		INNER JOIN %s a
 		ON es.asset_id=a.asset_id
 		WHERE es.exchange=$1
-		AND es.symbol ILIKE $2
+		AND LOWER(es.symbol)=LOWER($2)
+		AND es.delisted_at IS NULL
 		`,
 		exchangesymbolTable,
 		assetTable,
@@ -410,9 +807,15 @@ func (rdb *RelDB) GetExchangeSymbol(exchange string, symbol string) (asset dia.A
 	return
 }
 
-// GetAssets returns all assets which share the symbol ticker @symbol.
+// GetAssets returns all assets which share the symbol ticker @symbol, including assets which
+// used @symbol as a former ticker. Assets with @symbol as current ticker come first.
 func (rdb *RelDB) GetAssets(symbol string) (assets []dia.Asset, err error) {
-	query := fmt.Sprintf("SELECT symbol,name,address,decimals,blockchain FROM %s WHERE symbol=$1 ", assetTable)
+	query := fmt.Sprintf(`
+	SELECT symbol,name,address,decimals,blockchain
+	FROM %s
+	WHERE symbol=$1 OR asset_id IN (SELECT asset_id FROM %s WHERE symbol=$1)
+	ORDER BY (symbol=$1) DESC
+	`, assetTable, assetTickerTable)
 	var rows pgx.Rows
 	rows, err = rdb.postgresClient.Query(context.Background(), query, symbol)
 	if err != nil {
@@ -464,9 +867,9 @@ func (rdb *RelDB) GetAssetExchange(symbol string) (exchanges []string, err error
 	return
 }
 
-// GetUnverifiedExchangeSymbols returns all symbols from @exchange which haven't been verified yet.
+// GetUnverifiedExchangeSymbols returns all listed symbols from @exchange which haven't been verified yet.
 func (rdb *RelDB) GetUnverifiedExchangeSymbols(exchange string) (symbols []string, err error) {
-	query := fmt.Sprintf("SELECT symbol FROM %s WHERE exchange=$1 AND verified=false ORDER BY symbol ASC", exchangesymbolTable)
+	query := fmt.Sprintf("SELECT symbol FROM %s WHERE exchange=$1 AND verified=false AND delisted_at IS NULL ORDER BY symbol ASC", exchangesymbolTable)
 	var rows pgx.Rows
 	rows, err = rdb.postgresClient.Query(context.Background(), query, exchange)
 	if err != nil {
@@ -484,80 +887,162 @@ func (rdb *RelDB) GetUnverifiedExchangeSymbols(exchange string) (symbols []strin
 	return
 }
 
-// GetExchangeSymbols returns all symbols traded on @exchange.
-// If @exchange is the empty string, all symbols are returned.
-// If @substring is not the empty string, all symbols that begin with @substring (case insensitive) are returned.
-func (rdb *RelDB) GetExchangeSymbols(exchange string, substring string) (symbols []string, err error) {
-	var query string
-	var rows pgx.Rows
-	if exchange != "" {
-		if substring != "" {
-			query = fmt.Sprintf("SELECT symbol FROM %s WHERE exchange=$1 AND symbol ILIKE '%s%%'", exchangesymbolTable, substring)
-			rows, err = rdb.postgresClient.Query(context.Background(), query, exchange)
-
-		} else {
-			query = fmt.Sprintf("SELECT symbol FROM %s WHERE exchange=$1", exchangesymbolTable)
-			rows, err = rdb.postgresClient.Query(context.Background(), query, exchange)
+// GetStaleExchangeSymbols returns all listed symbols on @exchange which were not seen for more than
+// @olderThan, i.e. symbols the exchange presumably stopped trading. Symbols without a recorded
+// sighting are left out.
+func (rdb *RelDB) GetStaleExchangeSymbols(exchange string, olderThan time.Duration) (symbols []string, err error) {
+	query := fmt.Sprintf(`
+	SELECT symbol FROM %s
+	WHERE exchange=$1 AND delisted_at IS NULL AND lastseen<NOW()-$2*INTERVAL '1 second'
+	ORDER BY symbol ASC
+	`, exchangesymbolTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange, olderThan.Seconds())
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var symbol string
+		err = rows.Scan(&symbol)
+		if err != nil {
+			return
 		}
-	} else {
-		if substring != "" {
-			query = fmt.Sprintf("SELECT symbol FROM %s WHERE symbol ILIKE '%s%%'", exchangesymbolTable, substring)
-			log.Info("query: ", query)
-			rows, err = rdb.postgresClient.Query(context.Background(), query)
-		} else {
-			query = fmt.Sprintf("SELECT symbol FROM %s", exchangesymbolTable)
-			rows, err = rdb.postgresClient.Query(context.Background(), query)
+		symbols = append(symbols, symbol)
+	}
+	err = rows.Err()
+	return
+}
+
+// GetExchangeSymbols returns the symbols in exchangesymbol table which satisfy @filter, sorted by exchange and symbol.
+// Delisted symbols are left out.
+func (rdb *RelDB) GetExchangeSymbols(filter ExchangeSymbolFilter) ([]ExchangeSymbol, error) {
+	return getExchangeSymbols(rdb.postgresClient, filter)
+}
+
+// GetExchangeSymbolsPage returns the page of symbols given by @filter.Limit and @filter.Offset together
+// with the total number of symbols which satisfy @filter. Both are read from one consistent snapshot.
+func (rdb *RelDB) GetExchangeSymbolsPage(filter ExchangeSymbolFilter) (page ExchangeSymbolPage, err error) {
+	page.Limit = filter.Limit
+	page.Offset = filter.Offset
+	err = rdb.ReadSnapshot(context.Background(), func(tx pgx.Tx) error {
+		var errSnapshot error
+		page.Symbols, errSnapshot = getExchangeSymbols(tx, filter)
+		if errSnapshot != nil {
+			return errSnapshot
 		}
+		page.Total, errSnapshot = countExchangeSymbols(tx, filter)
+		return errSnapshot
+	})
+	return
+}
+
+// exchangeSymbolConditions returns the conditions on exchangesymbol table given by @filter.
+func exchangeSymbolConditions(filter ExchangeSymbolFilter) queryBuilder {
+	qb := queryBuilder{clauses: []string{"delisted_at IS NULL"}}
+	if len(filter.Exchanges) > 0 {
+		qb.where("exchange=ANY(%s)", filter.Exchanges)
+	}
+	if filter.Pattern != "" {
+		qb.where("symbol ILIKE %s", symbolLikePattern(filter.Pattern))
+	}
+	if filter.VerifiedOnly {
+		qb.clauses = append(qb.clauses, "verified=true")
+	}
+	return qb
+}
+
+func getExchangeSymbols(q pgQuerier, filter ExchangeSymbolFilter) (symbols []ExchangeSymbol, err error) {
+	qb := exchangeSymbolConditions(filter)
+	query := fmt.Sprintf(`
+	SELECT symbol,exchange,verified
+	FROM %s
+	WHERE %s
+	ORDER BY exchange,symbol
+	`, exchangesymbolTable, qb.conditions())
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
 	}
+	if filter.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
+	}
+
+	var rows pgx.Rows
+	rows, err = q.Query(context.Background(), query, qb.args...)
 	if err != nil {
 		return
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		symbol := ""
-		err = rows.Scan(&symbol)
+		var symbol ExchangeSymbol
+		err = rows.Scan(&symbol.Symbol, &symbol.Exchange, &symbol.Verified)
 		if err != nil {
-			return []string{}, err
+			return
 		}
 		symbols = append(symbols, symbol)
 	}
+	err = rows.Err()
 	return
 }
 
-// VerifyExchangeSymbol verifies @symbol on @exchange and maps it uniquely to @assetID in asset table.
-// It returns true if symbol,exchange is present and succesfully updated.
-func (rdb *RelDB) VerifyExchangeSymbol(exchange string, symbol string, assetID string) (bool, error) {
-	query := fmt.Sprintf("UPDATE %s SET verified=true,asset_id=$1 WHERE symbol=$2 AND exchange=$3", exchangesymbolTable)
-	resp, err := rdb.postgresClient.Exec(context.Background(), query, assetID, symbol, exchange)
-	if err != nil {
-		return false, err
+func countExchangeSymbols(q pgQuerier, filter ExchangeSymbolFilter) (total int64, err error) {
+	qb := exchangeSymbolConditions(filter)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", exchangesymbolTable, qb.conditions())
+	err = q.QueryRow(context.Background(), query, qb.args...).Scan(&total)
+	return
+}
+
+// symbolLikePattern returns the ILIKE pattern for the symbol @pattern. The wildcard * matches any
+// sequence of characters. A pattern without wildcard matches all symbols beginning with it.
+func symbolLikePattern(pattern string) string {
+	escaped := likeEscaper.Replace(pattern)
+	if !strings.Contains(pattern, "*") {
+		return escaped + "%"
 	}
-	var success bool
-	respSlice := strings.Split(string(resp), " ")
-	numUpdates := respSlice[1]
-	if numUpdates != "0" {
-		success = true
+	return strings.ReplaceAll(escaped, "*", "%")
+}
+
+// DeleteExchangeSymbol removes @symbol on @exchange from exchangesymbol table, ignoring case.
+// If @markDelisted is true, the symbol is kept and only marked as delisted. Its verification is removed
+// and recorded in the verification history, so a relisted symbol has to be verified again.
+func (rdb *RelDB) DeleteExchangeSymbol(exchange string, symbol string, markDelisted bool) error {
+	if !markDelisted {
+		query := fmt.Sprintf("DELETE FROM %s WHERE exchange=$1 AND LOWER(symbol)=LOWER($2)", exchangesymbolTable)
+		tag, err := rdb.postgresClient.Exec(context.Background(), query, exchange, symbol)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("symbol %s on %s not found", symbol, exchange)
+		}
+		return nil
 	}
-	return success, nil
+
+	return rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		found, err := txRDB.setSymbolVerification(symbolOperationUnverify, exchange, symbol, sql.NullString{}, "delisting", txRDB.actor)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("symbol %s on %s not found", symbol, exchange)
+		}
+		query := fmt.Sprintf("UPDATE %s SET delisted_at=COALESCE(delisted_at,NOW()) WHERE exchange=$1 AND LOWER(symbol)=LOWER($2)", exchangesymbolTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, exchange, symbol)
+		return err
+	})
 }
 
 // GetExchangeSymbolAssetID returns the ID of the unique asset associated to @symbol on @exchange
-// in case the symbol is verified. An empty string if not.
+// in case the symbol is verified. An empty string if not. Delisted symbols are not found.
 func (rdb *RelDB) GetExchangeSymbolAssetID(exchange string, symbol string) (assetID string, verified bool, err error) {
-	var uuid pgtype.UUID
-	query := fmt.Sprintf("SELECT asset_id, verified FROM %s WHERE symbol=$1 AND exchange=$2", exchangesymbolTable)
-	err = rdb.postgresClient.QueryRow(context.Background(), query, symbol, exchange).Scan(&uuid, &verified)
+	// The asset id is cast to text, so that it is scanned independently of the driver's UUID representation.
+	var id sql.NullString
+	query := fmt.Sprintf("SELECT asset_id::text, verified FROM %s WHERE LOWER(symbol)=LOWER($1) AND exchange=$2 AND delisted_at IS NULL ORDER BY verified DESC LIMIT 1", exchangesymbolTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, symbol, exchange).Scan(&id, &verified)
 	if err != nil {
 		return
 	}
-	val, err := uuid.Value()
-	if err != nil {
-		log.Error(err)
-	}
-	if val != nil {
-		assetID = val.(string)
-	}
+	assetID = id.String
 	return
 }
 
@@ -565,14 +1050,15 @@ func (rdb *RelDB) GetExchangeSymbolAssetID(exchange string, symbol string) (asse
 // Blockchain methods
 // -------------------------------------------------------------
 
+// SetBlockchain stores @blockchain or updates it if it exists. An empty ChainType keeps the stored chain type.
 func (rdb *RelDB) SetBlockchain(blockchain dia.BlockChain) (err error) {
-	fields := fmt.Sprintf("INSERT INTO %s (name,genesisdate,nativetoken_id,verificationmechanism,chain_id) VALUES ", blockchainTable)
-	values := "($1,$2,(SELECT asset_id FROM asset WHERE address=$3 AND blockchain=$1),$4,NULLIF($5,'')) "
-	conflict := `
+	fields := fmt.Sprintf("INSERT INTO %s (name,genesisdate,nativetoken_id,verificationmechanism,chain_id,is_testnet,chain_type) VALUES ", blockchainTable)
+	values := "($1,$2,(SELECT asset_id FROM asset WHERE address=$3 AND blockchain=$1),$4,NULLIF($5,''),$6,NULLIF($7,'')) "
+	conflict := fmt.Sprintf(`
 	ON CONFLICT (name) 
 	DO UPDATE SET 
-	genesisdate=$2,verificationmechanism=$4,chain_id=NULLIF($5,''),nativetoken_id=(SELECT asset_id FROM asset WHERE address=$3 AND blockchain=$1)
-	`
+	genesisdate=$2,verificationmechanism=$4,chain_id=NULLIF($5,''),nativetoken_id=(SELECT asset_id FROM asset WHERE address=$3 AND blockchain=$1),is_testnet=$6,chain_type=COALESCE(NULLIF($7,''),%s.chain_type)
+	`, blockchainTable)
 
 	query := fields + values + conflict
 	_, err = rdb.postgresClient.Exec(context.Background(), query,
@@ -581,6 +1067,8 @@ func (rdb *RelDB) SetBlockchain(blockchain dia.BlockChain) (err error) {
 		blockchain.NativeToken.Address,
 		blockchain.VerificationMechanism,
 		blockchain.ChainID,
+		blockchain.IsTestnet,
+		string(blockchain.ChainType),
 	)
 	if err != nil {
 		return err
@@ -590,51 +1078,157 @@ func (rdb *RelDB) SetBlockchain(blockchain dia.BlockChain) (err error) {
 
 func (rdb *RelDB) GetBlockchain(name string) (blockchain dia.BlockChain, err error) {
 	query := fmt.Sprintf(`
-	SELECT genesisdate,verificationmechanism,chain_id,address,symbol 
+	SELECT genesisdate,verificationmechanism,chain_id,is_testnet,chain_type,address,symbol 
 	FROM %s 
 	INNER JOIN %s 
 	ON %s.nativetoken_id=%s.asset_id 
 	WHERE %s.name=$1
 	`, blockchainTable, assetTable, blockchainTable, assetTable, blockchainTable)
+	var chainType sql.NullString
 	err = rdb.postgresClient.QueryRow(context.Background(), query, name).Scan(
 		&blockchain.GenesisDate,
 		&blockchain.VerificationMechanism,
 		&blockchain.ChainID,
+		&blockchain.IsTestnet,
+		&chainType,
 		&blockchain.NativeToken.Address,
 		&blockchain.NativeToken.Symbol,
 	)
 	if err != nil {
 		return
 	}
+	blockchain.ChainType = dia.ChainType(chainType.String)
 	blockchain.Name = name
 	return
 }
 
+// BlockchainUpdate holds the fields of a blockchain to be changed by UpdateBlockchain.
+// Nil fields are left unchanged.
+type BlockchainUpdate struct {
+	GenesisDate           *int64                     `json:"GenesisDate"`
+	NativeTokenAddress    *string                    `json:"NativeTokenAddress"`
+	VerificationMechanism *dia.VerificationMechanism `json:"VerificationMechanism"`
+	ChainID               *string                    `json:"ChainID"`
+	IsTestnet             *bool                      `json:"IsTestnet"`
+	ChainType             *dia.ChainType             `json:"ChainType"`
+}
+
+// UpdateBlockchain changes the non-nil fields of @update for the blockchain @name.
+// A new native token must exist in the asset table on blockchain @name.
+func (rdb *RelDB) UpdateBlockchain(name string, update BlockchainUpdate) error {
+	var qb queryBuilder
+	if update.GenesisDate != nil {
+		qb.set("genesisdate=%s", *update.GenesisDate)
+	}
+	if update.VerificationMechanism != nil {
+		qb.set("verificationmechanism=%s", string(*update.VerificationMechanism))
+	}
+	if update.ChainID != nil {
+		qb.set("chain_id=NULLIF(%s,'')", *update.ChainID)
+	}
+	if update.IsTestnet != nil {
+		qb.set("is_testnet=%s", *update.IsTestnet)
+	}
+	if update.ChainType != nil {
+		qb.set("chain_type=NULLIF(%s,'')", string(*update.ChainType))
+	}
+	if update.NativeTokenAddress != nil {
+		nativeTokenID, err := rdb.GetAssetID(dia.Asset{Address: *update.NativeTokenAddress, Blockchain: name})
+		if err != nil {
+			return fmt.Errorf("native token %s on %s not found", *update.NativeTokenAddress, name)
+		}
+		qb.set("nativetoken_id=%s", nativeTokenID)
+	}
+	if len(qb.clauses) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	qb.args = append(qb.args, name)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE name=$%d", blockchainTable, qb.assignments(), len(qb.args))
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, qb.args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("blockchain %s not found", name)
+	}
+	return nil
+}
+
+// DeleteBlockchain removes the blockchain @name. It fails if assets on the blockchain exist.
+func (rdb *RelDB) DeleteBlockchain(name string) error {
+	return rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		var numAssets int
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE blockchain=$1", assetTable)
+		err := txRDB.postgresClient.QueryRow(context.Background(), query, name).Scan(&numAssets)
+		if err != nil {
+			return err
+		}
+		if numAssets > 0 {
+			return fmt.Errorf("blockchain %s is referenced by %d assets", name, numAssets)
+		}
+
+		query = fmt.Sprintf("DELETE FROM %s WHERE name=$1", blockchainTable)
+		tag, err := txRDB.postgresClient.Exec(context.Background(), query, name)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("blockchain %s not found", name)
+		}
+		return nil
+	})
+}
+
 // GetAllBlockchains returns all blockchains from the blockchain table.
 // If fullAsset=true it returns the complete native token as asset, otherwise only its symbol string.
 func (rdb *RelDB) GetAllBlockchains(fullAsset bool) ([]dia.BlockChain, error) {
+	return rdb.getBlockchains(fullAsset, queryBuilder{})
+}
+
+// GetBlockchainsByType returns all blockchains of type @chainType with their full native tokens.
+// Testnets are only included if @includeTestnets is true.
+func (rdb *RelDB) GetBlockchainsByType(chainType dia.ChainType, includeTestnets bool) ([]dia.BlockChain, error) {
+	var qb queryBuilder
+	qb.where("b.chain_type=%s", string(chainType))
+	if !includeTestnets {
+		qb.where("b.is_testnet=%s", false)
+	}
+	return rdb.getBlockchains(true, qb)
+}
+
+// getBlockchains returns all blockchains matching the conditions in @qb.
+func (rdb *RelDB) getBlockchains(fullAsset bool, qb queryBuilder) ([]dia.BlockChain, error) {
 	var (
 		blockchains []dia.BlockChain
 		query       string
+		where       string
 	)
 
+	if len(qb.clauses) > 0 {
+		where = "WHERE " + qb.conditions()
+	}
 	if fullAsset {
 		query = fmt.Sprintf(`
-		SELECT b.name,b.genesisdate,a.Symbol,a.Name,a.Address,a.Decimals,b.verificationmechanism,b.chain_id 
+		SELECT b.name,b.genesisdate,a.Symbol,a.Name,a.Address,a.Decimals,b.verificationmechanism,b.chain_id,b.is_testnet,b.chain_type 
 		FROM %s b 
 		LEFT JOIN %s a 
 		ON nativetoken_id = a.asset_id
-		`, blockchainTable, assetTable)
+		%s
+		ORDER BY b.name ASC
+		`, blockchainTable, assetTable, where)
 	} else {
 		query = fmt.Sprintf(`
-		SELECT b.name,b.genesisdate,a.Symbol,b.verificationmechanism,b.chain_id 
+		SELECT b.name,b.genesisdate,a.Symbol,b.verificationmechanism,b.chain_id,b.is_testnet,b.chain_type 
 		FROM %s b 
 		LEFT JOIN %s a 
 		ON nativetoken_id = a.asset_id
-		`, blockchainTable, assetTable)
+		%s
+		ORDER BY b.name ASC
+		`, blockchainTable, assetTable, where)
 	}
 
-	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, qb.args...)
 	if err != nil {
 		return []dia.BlockChain{}, err
 	}
@@ -647,6 +1241,7 @@ func (rdb *RelDB) GetAllBlockchains(fullAsset bool) ([]dia.BlockChain, error) {
 			symbol         sql.NullString
 			verifMechanism sql.NullString
 			chainID        sql.NullString
+			chainType      sql.NullString
 			//  fullAsset
 			name     sql.NullString
 			address  sql.NullString
@@ -663,6 +1258,8 @@ func (rdb *RelDB) GetAllBlockchains(fullAsset bool) ([]dia.BlockChain, error) {
 				&decimals,
 				&verifMechanism,
 				&chainID,
+				&blockchain.IsTestnet,
+				&chainType,
 			)
 		} else {
 			err = rows.Scan(
@@ -671,6 +1268,8 @@ func (rdb *RelDB) GetAllBlockchains(fullAsset bool) ([]dia.BlockChain, error) {
 				&symbol,
 				&verifMechanism,
 				&chainID,
+				&blockchain.IsTestnet,
+				&chainType,
 			)
 		}
 		if err != nil {
@@ -688,6 +1287,9 @@ func (rdb *RelDB) GetAllBlockchains(fullAsset bool) ([]dia.BlockChain, error) {
 		if chainID.Valid {
 			blockchain.ChainID = chainID.String
 		}
+		if chainType.Valid {
+			blockchain.ChainType = dia.ChainType(chainType.String)
+		}
 		if fullAsset {
 			if name.Valid {
 				blockchain.NativeToken.Name = name.String
@@ -703,7 +1305,7 @@ func (rdb *RelDB) GetAllBlockchains(fullAsset bool) ([]dia.BlockChain, error) {
 		blockchains = append(blockchains, blockchain)
 	}
 
-	return blockchains, nil
+	return blockchains, rows.Err()
 }
 
 // GetAllAssetsBlockchains returns all blockchain names existent in the asset table.
@@ -733,6 +1335,9 @@ func (rdb *RelDB) GetAllAssetsBlockchains() ([]string, error) {
 // -------------------------------------------------------------
 
 // GetPage returns assets per page number. @hasNext is true iff there is a non-empty next page.
+//
+// Deprecated: GetPage paginates by offset, which is slow on large tables and yields inconsistent
+// pages under concurrent writes. Use GetAssetsPage instead.
 func (rdb *RelDB) GetPage(pageNumber uint32) (assets []dia.Asset, hasNextPage bool, err error) {
 
 	pagesize := rdb.pagesize
@@ -768,6 +1373,52 @@ func (rdb *RelDB) GetPage(pageNumber uint32) (assets []dia.Asset, hasNextPage bo
 	return
 }
 
+// GetAssetsPage returns up to @pagesize assets ordered by asset_id, starting after @cursor.
+// An empty @cursor returns the first page. @nextCursor is passed to the subsequent call in order
+// to obtain the next page and is empty if there are no more assets.
+func (rdb *RelDB) GetAssetsPage(cursor string, pagesize int) (assets []dia.Asset, nextCursor string, err error) {
+	if pagesize <= 0 {
+		err = errors.New("pagesize must be positive")
+		return
+	}
+	query := fmt.Sprintf(`
+	SELECT asset_id,symbol,name,address,decimals,blockchain
+	FROM %s
+	WHERE ($1='' OR asset_id>$1::uuid)
+	ORDER BY asset_id ASC
+	LIMIT $2
+	`, assetTable)
+	// Request one additional asset in order to determine whether there is a next page.
+	rows, err := rdb.postgresClient.Query(context.Background(), query, cursor, pagesize+1)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var lastID string
+	for rows.Next() {
+		var (
+			assetID  string
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&assetID, &asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if len(assets) == pagesize {
+			nextCursor = lastID
+			break
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+		lastID = assetID
+	}
+	return
+}
+
 // Count returns the number of assets stored in postgres
 func (rdb *RelDB) Count() (count uint32, err error) {
 	err = rdb.postgresClient.QueryRow(context.Background(), "SELECT COUNT(*) FROM asset").Scan(&count)
@@ -783,37 +1434,227 @@ func (rdb *RelDB) Count() (count uint32, err error) {
 
 // SetAssetCache stores @asset in redis, using its primary key in postgres as key.
 // As a consequence, @asset is only cached iff it exists in postgres.
+// If redis is unavailable, the write is skipped.
 func (rdb *RelDB) SetAssetCache(asset dia.Asset) error {
-	return rdb.redisClient.Set(keyAssetCache+asset.Identifier(), &asset, 0).Err()
+	if !cacheAvailable(rdb.redisClient) {
+		skipCacheWrite()
+		return nil
+	}
+	err := rdb.redisClient.Set(rdb.cacheContext(), keyAssetCache+asset.Identifier(), &asset, assetCacheTTL).Err()
+	if checkCacheError(err) {
+		skipCacheWrite()
+		return nil
+	}
+	return err
+}
+
+// SetAssetCacheContext stores @asset in redis. The write is cancelled when @ctx is done.
+func (rdb *RelDB) SetAssetCacheContext(ctx context.Context, asset dia.Asset) error {
+	return rdb.WithContext(ctx).SetAssetCache(asset)
+}
+
+// SetAssetCacheBatch stores all @assets in redis using a single pipelined round trip.
+// If redis is unavailable, the write is skipped.
+func (rdb *RelDB) SetAssetCacheBatch(assets []dia.Asset) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	if !cacheAvailable(rdb.redisClient) {
+		skipCacheWrite()
+		return nil
+	}
+	pipe := rdb.redisClient.Pipeline()
+	for i := range assets {
+		pipe.Set(rdb.cacheContext(), keyAssetCache+assets[i].Identifier(), &assets[i], assetCacheTTL)
+	}
+	_, err := pipe.Exec(rdb.cacheContext())
+	if checkCacheError(err) {
+		skipCacheWrite()
+		return nil
+	}
+	return err
+}
+
+// GetAssetCacheBatch returns the assets identified by blockchain and address of @assets using a single MGET.
+// Assets which are not cached are read from postgres, assets which do not exist in postgres are omitted.
+// If redis is unavailable, all assets are read from postgres.
+func (rdb *RelDB) GetAssetCacheBatch(assets []dia.Asset) ([]dia.Asset, error) {
+	result := []dia.Asset{}
+	if len(assets) == 0 {
+		return result, nil
+	}
+	keys := make([]string, len(assets))
+	lookups := make([]dia.Asset, len(assets))
+	for i, asset := range assets {
+		lookups[i] = dia.Asset{Blockchain: asset.Blockchain, Address: dia.NormalizeNativeAddress(asset.Blockchain, asset.Address)}
+		keys[i] = keyAssetCache + lookups[i].Identifier()
+	}
+
+	values := make([]interface{}, len(assets))
+	if cacheAvailable(rdb.redisClient) {
+		var err error
+		values, err = cacheMGet(rdb.cacheContext(), rdb.redisClient, keys)
+		if err != nil {
+			if !checkCacheError(err) {
+				return result, err
+			}
+			values = make([]interface{}, len(assets))
+		}
+	}
+
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			var asset dia.Asset
+			if err := asset.UnmarshalBinary([]byte(s)); err == nil {
+				result = append(result, asset)
+				continue
+			}
+			log.Warnf("malformed asset cache entry %s", keys[i])
+		}
+		fallthroughCacheRead()
+		asset, err := getAsset(rdb.postgresClient, lookups[i].Address, lookups[i].Blockchain)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result = append(result, asset)
+	}
+	return result, nil
 }
 
 // GetAssetCache returns an asset by its asset_id as defined in asset table in postgres
+// If redis is unavailable, the asset is read from postgres.
 func (rdb *RelDB) GetAssetCache(blockchain string, address string) (asset dia.Asset, err error) {
+	address = dia.NormalizeNativeAddress(blockchain, address)
+	if !cacheAvailable(rdb.redisClient) {
+		fallthroughCacheRead()
+		return getAsset(rdb.postgresClient, address, blockchain)
+	}
 	asset.Blockchain = blockchain
 	asset.Address = address
-	err = rdb.redisClient.Get(keyAssetCache + asset.Identifier()).Scan(&asset)
+	err = rdb.redisClient.Get(rdb.cacheContext(), keyAssetCache+asset.Identifier()).Scan(&asset)
+	if checkCacheError(err) {
+		fallthroughCacheRead()
+		return getAsset(rdb.postgresClient, address, blockchain)
+	}
 	return
 }
 
+// GetAssetCacheContext returns an asset from redis, or from postgres if it is not cached.
+// Both reads are cancelled when @ctx is done.
+func (rdb *RelDB) GetAssetCacheContext(ctx context.Context, blockchain string, address string) (dia.Asset, error) {
+	return rdb.WithContext(ctx).GetAssetCache(blockchain, address)
+}
+
+// InvalidateAssetCache removes @asset from the redis cache and the in-memory cache.
+func (rdb *RelDB) InvalidateAssetCache(asset dia.Asset) error {
+	rdb.assetCache.RemoveAsset(asset)
+	if !cacheAvailable(rdb.redisClient) {
+		return nil
+	}
+	err := rdb.redisClient.Del(rdb.cacheContext(), keyAssetCache+asset.Identifier()).Err()
+	if checkCacheError(err) {
+		return nil
+	}
+	return err
+}
+
 // CountCache returns the number of assets in the cache
 func (rdb *RelDB) CountCache() (uint32, error) {
-	keysPattern := keyAssetCache + "*"
-	allAssets := rdb.redisClient.Keys(keysPattern).Val()
-	return uint32(len(allAssets)), nil
+	counts, err := rdb.CountCacheByClass()
+	if err != nil {
+		return 0, err
+	}
+	return counts[CacheClassAsset], nil
+}
+
+// CountCacheByClass returns the number of cached entries per key class.
+// As cache entries expire, counts cannot be maintained on writes. Instead, the keyspaces are
+// counted using SCAN, which does not block redis, and the result is reused for cacheCountInterval.
+func (rdb *RelDB) CountCacheByClass() (map[string]uint32, error) {
+	cacheCounts.Lock()
+	defer cacheCounts.Unlock()
+	if cacheCounts.counts != nil && time.Since(cacheCounts.timestamp) < cacheCountInterval {
+		return copyCacheCounts(cacheCounts.counts), nil
+	}
+	if !cacheAvailable(rdb.redisClient) {
+		return nil, ErrCacheUnavailable
+	}
+
+	counts := make(map[string]uint32)
+	for class, prefix := range cacheClassPrefixes {
+		var count uint32
+		err := rdb.scanCache(prefix+"*", func(key string) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			checkCacheError(err)
+			return nil, err
+		}
+		counts[class] = count
+	}
+	cacheCounts.counts = counts
+	cacheCounts.timestamp = time.Now()
+	return copyCacheCounts(counts), nil
+}
+
+func copyCacheCounts(counts map[string]uint32) map[string]uint32 {
+	countsCopy := make(map[string]uint32, len(counts))
+	for class, count := range counts {
+		countsCopy[class] = count
+	}
+	return countsCopy
 }
 
 // -------------- Caching exchange pairs -------------------
 
 // SetExchangePairCache stores @pairs in redis
+// If redis is unavailable, the write is skipped. Pairs of excluded markets are stored as unverified.
 func (rdb *RelDB) SetExchangePairCache(exchange string, pair dia.ExchangePair) error {
+	if !cacheAvailable(rdb.redisClient) {
+		skipCacheWrite()
+		return nil
+	}
+	if dia.IsExcludedMarket(exchange, pair.ForeignName) {
+		pair.Verified = false
+	}
 	key := keyExchangePairCache + exchange + "_" + pair.ForeignName
-	return rdb.redisClient.Set(key, &pair, 0).Err()
+	err := rdb.redisClient.Set(rdb.cacheContext(), key, &pair, exchangePairCacheTTL).Err()
+	if checkCacheError(err) {
+		skipCacheWrite()
+		return nil
+	}
+	return err
+}
+
+// deleteExchangePairCache removes the pair with @foreignName on @exchange from redis.
+func (rdb *RelDB) deleteExchangePairCache(exchange string, foreignName string) error {
+	if !cacheAvailable(rdb.redisClient) {
+		return nil
+	}
+	err := rdb.redisClient.Del(rdb.cacheContext(), keyExchangePairCache+exchange+"_"+foreignName).Err()
+	if checkCacheError(err) {
+		return nil
+	}
+	return err
 }
 
 // GetExchangePairCache returns an exchange pair by @exchange and @foreigName
+// If redis is unavailable, the pair is read from postgres.
 func (rdb *RelDB) GetExchangePairCache(exchange string, foreignName string) (dia.ExchangePair, error) {
+	if !cacheAvailable(rdb.redisClient) {
+		fallthroughCacheRead()
+		return rdb.GetExchangePair(exchange, foreignName, true)
+	}
 	exchangePair := dia.ExchangePair{}
-	err := rdb.redisClient.Get(keyExchangePairCache + exchange + "_" + foreignName).Scan(&exchangePair)
+	err := rdb.redisClient.Get(rdb.cacheContext(), keyExchangePairCache+exchange+"_"+foreignName).Scan(&exchangePair)
+	if checkCacheError(err) {
+		fallthroughCacheRead()
+		return rdb.GetExchangePair(exchange, foreignName, true)
+	}
 	if err != nil {
 		if !errors.Is(err, redis.Nil) {
 			log.Errorf("GetExchangePairCache on %s with foreign name %s: %v\n", exchange, foreignName, err)
@@ -823,41 +1664,90 @@ func (rdb *RelDB) GetExchangePairCache(exchange string, foreignName string) (dia
 	return exchangePair, nil
 }
 
-func (rdb *RelDB) SetAssetVolume24H(asset dia.Asset, volume float64, timestamp time.Time) error {
+// SetExchangePairCacheContext stores @pair in redis. The write is cancelled when @ctx is done.
+func (rdb *RelDB) SetExchangePairCacheContext(ctx context.Context, exchange string, pair dia.ExchangePair) error {
+	return rdb.WithContext(ctx).SetExchangePairCache(exchange, pair)
+}
 
-	initialStr := fmt.Sprintf("INSERT INTO %s (asset_id,volume,time_stamp) VALUES ", assetVolumeTable)
-	substring := fmt.Sprintf(
-		"((SELECT asset_id FROM asset WHERE address='%s' AND blockchain='%s'),%f,to_timestamp(%v))",
-		asset.Address,
-		asset.Blockchain,
-		volume,
-		timestamp.Unix(),
-	)
-	conflict := " ON CONFLICT (asset_id) DO UPDATE SET volume=EXCLUDED.volume,time_stamp=EXCLUDED.time_stamp"
+// GetExchangePairCacheContext returns an exchange pair from redis, or from postgres if it is not cached.
+// Both reads are cancelled when @ctx is done.
+func (rdb *RelDB) GetExchangePairCacheContext(ctx context.Context, exchange string, foreignName string) (dia.ExchangePair, error) {
+	return rdb.WithContext(ctx).GetExchangePairCache(exchange, foreignName)
+}
 
-	query := initialStr + substring + conflict
-	_, err := rdb.postgresClient.Exec(context.Background(), query)
+// SetAssetVolume24H stores the latest 24h @volume of @asset. The native volume is only stored
+// if it comes with the time of the price it was converted with.
+func (rdb *RelDB) SetAssetVolume24H(asset dia.Asset, volume AssetVolume24H) error {
+	var (
+		native    sql.NullFloat64
+		priceTime sql.NullTime
+	)
+	if !volume.PriceTime.IsZero() {
+		native = sql.NullFloat64{Float64: volume.Volume, Valid: true}
+		priceTime = sql.NullTime{Time: volume.PriceTime, Valid: true}
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (asset_id,volume,volume_usd,price_timestamp,time_stamp)
+	SELECT asset_id,$3,$4,$5,$6 FROM %s WHERE address=$1 AND blockchain=$2
+	ON CONFLICT (asset_id) DO UPDATE SET volume=EXCLUDED.volume,volume_usd=EXCLUDED.volume_usd,price_timestamp=EXCLUDED.price_timestamp,time_stamp=EXCLUDED.time_stamp
+	`, assetVolumeTable, assetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, asset.Address, asset.Blockchain, native, volume.VolumeUSD, priceTime, volume.Time)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("asset %s on %s not found", asset.Address, asset.Blockchain)
+	}
 	return nil
 }
 
-func (rdb *RelDB) GetLastAssetVolume24H(asset dia.Asset) (volume float64, err error) {
-	query := fmt.Sprintf("SELECT volume FROM %s INNER JOIN %s ON assetvolume.asset_id = asset.asset_id WHERE address=$1 AND blockchain=$2", assetVolumeTable, assetTable)
-	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&volume)
+// GetLastAssetVolume24H returns the latest 24h volume of @asset in native units and in USD.
+func (rdb *RelDB) GetLastAssetVolume24H(asset dia.Asset) (AssetVolume24H, error) {
+	return getLastAssetVolume24H(rdb.postgresClient, asset)
+}
+
+func getLastAssetVolume24H(q pgQuerier, asset dia.Asset) (volume AssetVolume24H, err error) {
+	var (
+		native    sql.NullFloat64
+		volumeUSD sql.NullFloat64
+		priceTime sql.NullTime
+		t         sql.NullTime
+	)
+	query := fmt.Sprintf(`
+	SELECT av.volume,av.volume_usd,av.price_timestamp,av.time_stamp
+	FROM %s av
+	INNER JOIN %s a
+	ON av.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	`, assetVolumeTable, assetTable)
+	err = q.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&native, &volumeUSD, &priceTime, &t)
+	if err != nil {
+		return
+	}
+	if native.Valid && priceTime.Valid {
+		volume.Volume = native.Float64
+		volume.PriceTime = priceTime.Time
+	}
+	if volumeUSD.Valid {
+		volume.VolumeUSD = volumeUSD.Float64
+	}
+	if t.Valid {
+		volume.Time = t.Time
+	}
 	return
 }
 
+// GetTopAssetByVolume returns all assets with symbol ticker @symbol sorted by volume in descending order.
+// Assets which used @symbol as a former ticker follow the assets with @symbol as current ticker.
 func (rdb *RelDB) GetTopAssetByVolume(symbol string) (assets []dia.Asset, err error) {
 	query := fmt.Sprintf(`
 	SELECT symbol,name,address,decimals,blockchain 
 	FROM %s 
 	INNER JOIN %s 
 	ON asset.asset_id = assetvolume.asset_id 
-	WHERE symbol=$1 
-	ORDER BY volume DESC
-	`, assetTable, assetVolumeTable)
+	WHERE symbol=$1 OR asset.asset_id IN (SELECT asset_id FROM %s WHERE symbol=$1)
+	ORDER BY (symbol=$1) DESC, volume_usd DESC
+	`, assetTable, assetVolumeTable, assetTickerTable)
 
 	var rows pgx.Rows
 	rows, err = rdb.postgresClient.Query(context.Background(), query, symbol)
@@ -881,6 +1771,54 @@ func (rdb *RelDB) GetTopAssetByVolume(symbol string) (assets []dia.Asset, err er
 	return
 }
 
+// GetTopAssetsByVolume returns up to @limit non-deprecated assets with the highest volume updated after @starttime,
+// skipping the first @offset. A zero @limit defaults to 100 assets. If @blockchain is not empty, only assets on
+// @blockchain are returned. Assets without USD volume come last.
+func (rdb *RelDB) GetTopAssetsByVolume(starttime time.Time, limit int, offset int, blockchain string) (volumeSortedAssets []dia.AssetVolume, err error) {
+	if limit == 0 {
+		limit = 100
+	}
+	qb := queryBuilder{clauses: []string{"a.deprecated_at IS NULL"}}
+	qb.where("av.time_stamp>%s", starttime)
+	if blockchain != "" {
+		qb.where("a.blockchain=%s", blockchain)
+	}
+	query := fmt.Sprintf(`
+	SELECT a.symbol,a.name,a.address,a.decimals,a.blockchain,av.volume_usd
+	FROM %s a
+	INNER JOIN %s av
+	ON a.asset_id=av.asset_id
+	WHERE %s
+	ORDER BY av.volume_usd DESC NULLS LAST
+	LIMIT %d OFFSET %d
+	`, assetTable, assetVolumeTable, qb.conditions(), limit, offset)
+
+	var rows pgx.Rows
+	rows, err = rdb.postgresClient.Query(context.Background(), query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+			volume   sql.NullFloat64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain, &volume)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		volumeSortedAssets = append(volumeSortedAssets, dia.AssetVolume{Asset: asset, Volume: volume.Float64})
+	}
+	err = rows.Err()
+	return
+}
+
 func (rdb *RelDB) GetByLimit(limit, skip uint32) (assets []dia.Asset, assetIds []string, err error) {
 
 	rows, err := rdb.postgresClient.Query(
@@ -926,7 +1864,7 @@ func (rdb *RelDB) GetAssetsWithVolByBlockchain(starttime time.Time, endtime time
 
 	query = fmt.Sprintf(`
 	SELECT * FROM (
-		SELECT DISTINCT ON (address,blockchain) symbol,name,address,decimals,blockchain,volume
+		SELECT DISTINCT ON (address,blockchain) symbol,name,address,decimals,blockchain,volume_usd
 		FROM %s 
 		INNER JOIN %s
 		ON (asset.asset_id = assetvolume.asset_id)
@@ -941,7 +1879,7 @@ func (rdb *RelDB) GetAssetsWithVolByBlockchain(starttime time.Time, endtime time
 	} else {
 		query += (")")
 	}
-	query += " sub ORDER BY volume DESC"
+	query += " sub ORDER BY volume_usd DESC"
 
 	rows, err = rdb.postgresClient.Query(context.Background(), query)
 	if err != nil {
@@ -978,17 +1916,17 @@ func (rdb *RelDB) GetSortedAssetSymbols(numAssets int64, skip int64, search stri
 
 	if numAssets == 0 {
 		queryString = `
-		SELECT a.symbol,a.name,a.address,a.decimals,a.blockchain,av.volume 
+		SELECT a.symbol,a.name,a.address,a.decimals,a.blockchain,av.volume_usd 
 		FROM %s 
 		INNER JOIN %s 
 		ON (asset.asset_id = assetvolume.asset_id) 
 		WHERE symbol ILIKE '%s%%' 
-		ORDER BY assetvolume.volume 
+		ORDER BY assetvolume.volume_usd 
 		DESC LIMIT 100`
 		query = fmt.Sprintf(queryString, assetTable, assetVolumeTable, search)
 	} else {
 		queryString = `
-		SELECT DISTINCT ON (av.volume,av.asset_id)  a.symbol,a.name,a.address,a.decimals,a.blockchain,av.volume 
+		SELECT DISTINCT ON (av.volume_usd,av.asset_id)  a.symbol,a.name,a.address,a.decimals,a.blockchain,av.volume_usd 
 		FROM %s av 
 		INNER JOIN %s a 
 		ON av.asset_id=a.asset_id 
@@ -997,7 +1935,7 @@ func (rdb *RelDB) GetSortedAssetSymbols(numAssets int64, skip int64, search stri
 		ON es.exchange=e.name 
 		WHERE e.centralized=true 
 		AND a.symbol ILIKE '%s%%' 
-		ORDER BY av.volume 
+		ORDER BY av.volume_usd 
 		DESC LIMIT %d 
 		OFFSET %d`
 		query = fmt.Sprintf(queryString, assetVolumeTable, assetTable, exchangesymbolTable, exchangeTable, search, numAssets, skip)
@@ -1048,19 +1986,19 @@ func (rdb *RelDB) GetAssetsWithVOL(starttime time.Time, numAssets int64, skip in
 
 		if blockchain == "" {
 			queryString = `
-			SELECT symbol,name,address,decimals,blockchain,volume 
+			SELECT symbol,name,address,decimals,blockchain,volume_usd 
 			FROM %s a INNER JOIN %s av ON (a.asset_id = av.asset_id) 
 			WHERE av.time_stamp>to_timestamp(%v)
-			ORDER BY av.volume 
+			ORDER BY av.volume_usd 
 			DESC LIMIT %d OFFSET %d`
 			query = fmt.Sprintf(queryString, assetTable, assetVolumeTable, starttime.Unix(), numAssets, skip)
 		} else {
 			queryString = `
-			SELECT symbol,name,address,decimals,blockchain,volume 
+			SELECT symbol,name,address,decimals,blockchain,volume_usd 
 			FROM %s a INNER JOIN %s av ON (a.asset_id = av.asset_id) 
 			WHERE blockchain= '%s'
 			AND av.time_stamp>to_timestamp(%v)
-			ORDER BY av.volume 
+			ORDER BY av.volume_usd 
 			DESC LIMIT %d OFFSET %d`
 			query = fmt.Sprintf(queryString, assetTable, assetVolumeTable, blockchain, starttime.Unix(), numAssets, skip)
 		}
@@ -1068,25 +2006,25 @@ func (rdb *RelDB) GetAssetsWithVOL(starttime time.Time, numAssets int64, skip in
 	} else {
 		if blockchain == "" {
 			queryString = `
-			SELECT DISTINCT ON (av.volume,av.asset_id)  a.symbol,a.name,
-			a.address,a.decimals,a.blockchain,av.volume 
+			SELECT DISTINCT ON (av.volume_usd,av.asset_id)  a.symbol,a.name,
+			a.address,a.decimals,a.blockchain,av.volume_usd 
 			FROM %s  av INNER JOIN %s a ON av.asset_id=a.asset_id 
 			INNER JOIN %s es ON av.asset_id=es.asset_id INNER JOIN %s e 
 			ON es.exchange=e.name 
 			WHERE e.centralized=true 
-			ORDER BY av.volume 
+			ORDER BY av.volume_usd 
 			DESC  LIMIT %d OFFSET %d`
 			query = fmt.Sprintf(queryString, assetVolumeTable, assetTable, exchangesymbolTable, exchangeTable, numAssets, skip)
 		} else {
 			queryString = `
-			SELECT DISTINCT ON (av.volume,av.asset_id) 
-			a.symbol,a.name,a.address,a.decimals,a.blockchain,av.volume 
+			SELECT DISTINCT ON (av.volume_usd,av.asset_id) 
+			a.symbol,a.name,a.address,a.decimals,a.blockchain,av.volume_usd 
 			FROM %s  av 
 			INNER JOIN %s a  ON av.asset_id=a.asset_id 
 			INNER JOIN %s es ON av.asset_id=es.asset_id 
 			INNER JOIN %s e ON es.exchange=e.name 
 			WHERE e.centralized=true AND a.blockchain = '%s' 
-			ORDER BY av.volume 
+			ORDER BY av.volume_usd 
 			DESC  LIMIT %d OFFSET %d`
 			query = fmt.Sprintf(queryString, assetVolumeTable, assetTable, exchangesymbolTable, exchangeTable, blockchain, numAssets, skip)
 		}
@@ -1124,6 +2062,10 @@ func (rdb *RelDB) GetAssetsWithVOL(starttime time.Time, numAssets int64, skip in
 // GetAssetSource returns all exchanges @asset is traded on.
 // For @cex true, only CEXes are returned. Otherwise only DEXes.
 func (rdb *RelDB) GetAssetSource(asset dia.Asset, cex bool) (exchanges []string, err error) {
+	return getAssetSource(rdb.postgresClient, asset, cex)
+}
+
+func getAssetSource(q pgQuerier, asset dia.Asset, cex bool) (exchanges []string, err error) {
 	var query string
 	if cex {
 		query = fmt.Sprintf(`
@@ -1142,7 +2084,7 @@ func (rdb *RelDB) GetAssetSource(asset dia.Asset, cex bool) (exchanges []string,
 		`, poolTable, poolassetTable, assetTable, asset.Blockchain, asset.Address)
 	}
 
-	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	rows, err := q.Query(context.Background(), query)
 	if err != nil {
 		return
 	}
@@ -1164,11 +2106,54 @@ func (rdb *RelDB) GetAssetSource(asset dia.Asset, cex bool) (exchanges []string,
 
 }
 
+// GetAssetSources returns all CEXes and DEXes @asset is traded on, read from one consistent snapshot.
+func (rdb *RelDB) GetAssetSources(asset dia.Asset) (cex []string, dex []string, err error) {
+	err = rdb.ReadSnapshot(context.Background(), func(tx pgx.Tx) error {
+		var errSnapshot error
+		cex, errSnapshot = getAssetSource(tx, asset, true)
+		if errSnapshot != nil {
+			return errSnapshot
+		}
+		dex, errSnapshot = getAssetSource(tx, asset, false)
+		return errSnapshot
+	})
+	return
+}
+
+// GetAssetSnapshot returns @asset together with its latest 24h volume, its sources and the exchangepairs
+// it is involved in. All data is read from one consistent snapshot of the database.
+func (rdb *RelDB) GetAssetSnapshot(asset dia.Asset) (snapshot AssetSnapshot, err error) {
+	err = rdb.ReadSnapshot(context.Background(), func(tx pgx.Tx) error {
+		var errSnapshot error
+		snapshot.Asset, errSnapshot = getAsset(tx, asset.Address, asset.Blockchain)
+		if errSnapshot != nil {
+			return errSnapshot
+		}
+		var volume AssetVolume24H
+		volume, errSnapshot = getLastAssetVolume24H(tx, asset)
+		if errSnapshot != nil && !errors.Is(errSnapshot, pgx.ErrNoRows) {
+			return errSnapshot
+		}
+		snapshot.Volume, snapshot.VolumeUSD, snapshot.VolumeTime = volume.Volume, volume.VolumeUSD, volume.Time
+		snapshot.CEXSources, errSnapshot = getAssetSource(tx, asset, true)
+		if errSnapshot != nil {
+			return errSnapshot
+		}
+		snapshot.DEXSources, errSnapshot = getAssetSource(tx, asset, false)
+		if errSnapshot != nil {
+			return errSnapshot
+		}
+		snapshot.Pairs, errSnapshot = getPairsForAsset(tx, asset, false, false)
+		return errSnapshot
+	})
+	return
+}
+
 // GetAssetsWithVOLInflux returns all assets that have an entry in Influx's volumes table and hence have been traded since @timeInit.
 func (datastore *DB) GetAssetsWithVOLInflux(timeInit time.Time) ([]dia.Asset, error) {
 	var quotedAssets []dia.Asset
-	q := fmt.Sprintf("SELECT address,blockchain,value FROM %s WHERE filter='VOL120' AND exchange='' AND time>%d AND time<now()", influxDbFiltersTable, timeInit.UnixNano())
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	q := fmt.Sprintf("SELECT address,blockchain,value FROM %s WHERE filter=$filter AND exchange='' AND time>$starttime AND time<now()", influxDbFiltersTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"filter": volumeKey, "starttime": timeInit.UnixNano()})
 	if err != nil {
 		return quotedAssets, err
 	}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// SetSupply stores the total and circulating supply of an asset in postgres, in addition to the supplies
+// kept in influx, such that market caps can be computed relationally. A supply at the same time is overwritten.
+func (rdb *RelDB) SetSupply(supply dia.Supply) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (asset_id,circulating,total,source,time_stamp)
+	SELECT asset_id,$3,$4,NULLIF($5,''),$6 FROM %s WHERE address=$1 AND blockchain=$2
+	ON CONFLICT (asset_id,time_stamp) DO UPDATE SET circulating=EXCLUDED.circulating,total=EXCLUDED.total,source=EXCLUDED.source`,
+		supplyTable, assetTable)
+	tag, err := rdb.postgresClient.Exec(
+		context.Background(),
+		query,
+		supply.Asset.Address,
+		supply.Asset.Blockchain,
+		supply.CirculatingSupply,
+		supply.Supply,
+		supply.Source,
+		supply.Time,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("asset %s on %s not found", supply.Asset.Address, supply.Asset.Blockchain)
+	}
+	return nil
+}
+
+// GetLatestSupply returns the latest supply of @asset stored in postgres.
+func (rdb *RelDB) GetLatestSupply(asset dia.Asset) (supply dia.Supply, err error) {
+	var (
+		decimals sql.NullInt64
+		source   sql.NullString
+	)
+	query := fmt.Sprintf(`
+	SELECT a.symbol,a.name,a.address,a.blockchain,a.decimals,s.circulating,s.total,s.source,s.time_stamp
+	FROM %s s
+	INNER JOIN %s a
+	ON s.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	ORDER BY s.time_stamp DESC LIMIT 1`,
+		supplyTable, assetTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(
+		&supply.Asset.Symbol,
+		&supply.Asset.Name,
+		&supply.Asset.Address,
+		&supply.Asset.Blockchain,
+		&decimals,
+		&supply.CirculatingSupply,
+		&supply.Supply,
+		&source,
+		&supply.Time,
+	)
+	if err != nil {
+		return
+	}
+	if decimals.Valid {
+		supply.Asset.Decimals = uint8(decimals.Int64)
+	}
+	supply.Source = source.String
+	return
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// AssetTicker is a symbol ticker of an asset together with the period in which it was in use.
+// The current ticker has no end of validity. Tickers recorded without a start of validity have
+// a zero @ValidFrom.
+type AssetTicker struct {
+	Symbol    string    `json:"Symbol"`
+	ValidFrom time.Time `json:"ValidFrom"`
+	ValidTo   time.Time `json:"ValidTo"`
+	Current   bool      `json:"Current"`
+}
+
+// AssetTickerMatch is an asset which uses or used the ticker it was looked up by. @Asset always
+// carries the current ticker.
+type AssetTickerMatch struct {
+	Asset  dia.Asset   `json:"Asset"`
+	Ticker AssetTicker `json:"Ticker"`
+}
+
+// AddAssetTicker records @symbol as a former ticker of @asset which was in use until @validTo.
+// A zero @validFrom leaves the start of validity open. Tickers changed through UpdateAsset are
+// recorded automatically, so this is needed for rebrands which happened before.
+func (rdb *RelDB) AddAssetTicker(asset dia.Asset, symbol string, validFrom time.Time, validTo time.Time) error {
+	symbol = strings.TrimSpace(symbol)
+	if symbol == "" {
+		return errors.New("empty ticker")
+	}
+	if validTo.IsZero() || (!validFrom.IsZero() && !validFrom.Before(validTo)) {
+		return errors.New("invalid period of validity")
+	}
+	assetID, err := rdb.GetAssetID(asset)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (asset_id,symbol,valid_from,valid_to)
+	VALUES ($1,$2,$3,$4)
+	ON CONFLICT (asset_id,symbol,valid_to) DO UPDATE SET valid_from=EXCLUDED.valid_from
+	`, assetTickerTable)
+	_, err = rdb.postgresClient.Exec(context.Background(), query, assetID, symbol, sql.NullTime{Time: validFrom, Valid: !validFrom.IsZero()}, validTo)
+	return err
+}
+
+// GetAssetTickers returns all tickers of @asset in chronological order, ending with the current one.
+// The current ticker is valid from the end of the latest former ticker.
+func (rdb *RelDB) GetAssetTickers(asset dia.Asset) (tickers []AssetTicker, err error) {
+	query := fmt.Sprintf(`
+	SELECT t.symbol,t.valid_from,t.valid_to
+	FROM %s t
+	INNER JOIN %s a
+	ON t.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	ORDER BY t.valid_to ASC
+	`, assetTickerTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, asset.Address, asset.Blockchain)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ticker    AssetTicker
+			validFrom sql.NullTime
+		)
+		err = rows.Scan(&ticker.Symbol, &validFrom, &ticker.ValidTo)
+		if err != nil {
+			return
+		}
+		ticker.ValidFrom = validFrom.Time
+		tickers = append(tickers, ticker)
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	current, err := rdb.GetAsset(asset.Address, asset.Blockchain)
+	if err != nil {
+		return
+	}
+	currentTicker := AssetTicker{Symbol: current.Symbol, Current: true}
+	if len(tickers) > 0 {
+		currentTicker.ValidFrom = tickers[len(tickers)-1].ValidTo
+	}
+	tickers = append(tickers, currentTicker)
+	return
+}
+
+// GetAssetsByTicker returns all assets which use or used the ticker @symbol. Assets with @symbol as
+// current ticker come first, followed by former users of the ticker, most recent first.
+func (rdb *RelDB) GetAssetsByTicker(symbol string) (matches []AssetTickerMatch, err error) {
+	query := fmt.Sprintf(`
+	SELECT a.symbol,a.name,a.address,a.decimals,a.blockchain,t.valid_from,t.valid_to
+	FROM %[1]s a
+	LEFT JOIN %[2]s t
+	ON t.asset_id=a.asset_id AND t.symbol=$1
+	WHERE a.symbol=$1 OR t.symbol=$1
+	ORDER BY (a.symbol=$1) DESC,t.valid_to DESC NULLS FIRST,a.blockchain,a.address
+	`, assetTable, assetTickerTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, symbol)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	seenCurrent := make(map[string]bool)
+	for rows.Next() {
+		var (
+			match     AssetTickerMatch
+			decimals  sql.NullInt64
+			validFrom sql.NullTime
+			validTo   sql.NullTime
+		)
+		err = rows.Scan(
+			&match.Asset.Symbol,
+			&match.Asset.Name,
+			&match.Asset.Address,
+			&decimals,
+			&match.Asset.Blockchain,
+			&validFrom,
+			&validTo,
+		)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			match.Asset.Decimals = uint8(decimals.Int64)
+		}
+		match.Ticker = AssetTicker{Symbol: symbol, ValidFrom: validFrom.Time, ValidTo: validTo.Time}
+		if match.Asset.Symbol == symbol {
+			// An asset which readopted a former ticker is only listed with its current use.
+			if seenCurrent[match.Asset.Identifier()] {
+				continue
+			}
+			seenCurrent[match.Asset.Identifier()] = true
+			match.Ticker = AssetTicker{Symbol: symbol, Current: true}
+		}
+		matches = append(matches, match)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// AssetVolumeDay is the 24h volume of an asset as recorded on @Date.
+type AssetVolumeDay struct {
+	Date   time.Time `json:"Date"`
+	Volume float64   `json:"Volume"`
+}
+
+// AssetVolume24H is the latest 24h volume of an asset. @Volume is denominated in the asset itself
+// and was converted from @VolumeUSD with the price at @PriceTime. A zero @PriceTime means that no
+// price was available, so only @VolumeUSD is known.
+type AssetVolume24H struct {
+	Volume    float64   `json:"Volume"`
+	VolumeUSD float64   `json:"VolumeUSD"`
+	PriceTime time.Time `json:"PriceTime"`
+	Time      time.Time `json:"Time"`
+}
+
+// volumeDate returns the UTC day of @t, which is the key of a volume in the history table.
+func volumeDate(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// SetAssetVolumeAt stores the 24h @volume of @asset for the UTC day of @timestamp in the
+// assetvolume_history table. A volume which is set again on the same day overwrites the former one.
+func (rdb *RelDB) SetAssetVolumeAt(asset dia.Asset, volume float64, timestamp time.Time) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (asset_id,date,volume)
+	SELECT asset_id,$3,$4 FROM %s WHERE address=$1 AND blockchain=$2
+	ON CONFLICT (asset_id,date) DO UPDATE SET volume=EXCLUDED.volume
+	`, assetVolumeHistoryTable, assetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, asset.Address, asset.Blockchain, volumeDate(timestamp), volume)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("asset %s on %s not found", asset.Address, asset.Blockchain)
+	}
+	return nil
+}
+
+// GetAssetVolumeSeries returns the daily 24h volumes of @asset for all days in [@starttime,@endtime],
+// sorted by date in ascending order.
+func (rdb *RelDB) GetAssetVolumeSeries(asset dia.Asset, starttime time.Time, endtime time.Time) (volumes []AssetVolumeDay, err error) {
+	query := fmt.Sprintf(`
+	SELECT h.date,h.volume
+	FROM %s h
+	INNER JOIN %s a
+	ON h.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2 AND h.date>=$3 AND h.date<=$4
+	ORDER BY h.date ASC
+	`, assetVolumeHistoryTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, asset.Address, asset.Blockchain, volumeDate(starttime), volumeDate(endtime))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var volume AssetVolumeDay
+		err = rows.Scan(&volume.Date, &volume.Volume)
+		if err != nil {
+			return
+		}
+		volumes = append(volumes, volume)
+	}
+	err = rows.Err()
+	return
+}
+
+// SetAssetVolumeByExchange stores the 24h @volume of @asset on @exchange in the assetvolume_exchange table.
+func (rdb *RelDB) SetAssetVolumeByExchange(asset dia.Asset, exchange string, volume float64) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (asset_id,exchange,volume,time_stamp)
+	SELECT asset_id,$3,$4,NOW() FROM %s WHERE address=$1 AND blockchain=$2
+	ON CONFLICT (asset_id,exchange) DO UPDATE SET volume=EXCLUDED.volume,time_stamp=EXCLUDED.time_stamp
+	`, assetVolumeExchangeTable, assetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, asset.Address, asset.Blockchain, exchange, volume)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("asset %s on %s not found", asset.Address, asset.Blockchain)
+	}
+	return nil
+}
+
+// GetAssetVolumeBreakdown returns the 24h volumes of @asset per exchange, sorted by volume in descending order.
+// Volumes which were not updated within the last 24 hours are outdated and omitted. The timestamp of the
+// list is the time of the latest update.
+func (rdb *RelDB) GetAssetVolumeBreakdown(asset dia.Asset) (breakdown dia.ExchangeVolumesList, err error) {
+	query := fmt.Sprintf(`
+	SELECT v.exchange,v.volume,v.time_stamp
+	FROM %s v
+	INNER JOIN %s a
+	ON v.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2 AND v.time_stamp>NOW()-INTERVAL '24 hours'
+	ORDER BY v.volume DESC
+	`, assetVolumeExchangeTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, asset.Address, asset.Blockchain)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			volume    dia.ExchangeVolume
+			timestamp time.Time
+		)
+		err = rows.Scan(&volume.Exchange, &volume.Volume, &timestamp)
+		if err != nil {
+			return
+		}
+		if timestamp.After(breakdown.Timestamp) {
+			breakdown.Timestamp = timestamp
+		}
+		breakdown.Volumes = append(breakdown.Volumes, volume)
+	}
+	err = rows.Err()
+	return
+}
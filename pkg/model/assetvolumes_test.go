@@ -0,0 +1,23 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVolumeDate(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	cases := []struct {
+		t    time.Time
+		want time.Time
+	}{
+		{time.Date(2023, 3, 14, 15, 9, 26, 0, time.UTC), time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2023, 3, 15, 0, 30, 0, 0, berlin), time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		if got := volumeDate(c.t); !got.Equal(c.want) {
+			t.Errorf("volumeDate(%v) = %v, want %v", c.t, got, c.want)
+		}
+	}
+}
@@ -33,8 +33,8 @@ func (datastore *DB) SaveIndexEngineTimeInflux(tags map[string]string, fields ma
 
 func (datastore *DB) GetBenchmarkedIndexValuesInflux(symbol string, starttime time.Time, endtime time.Time) (BenchmarkedIndex, error) {
 	var retval BenchmarkedIndex
-	q := fmt.Sprintf("SELECT time,\"name\",value from %s WHERE time > %d and time < %d and \"name\" = '%s' ORDER BY time DESC", influxDbBenchmarkedIndexTableName, starttime.UnixNano(), endtime.UnixNano(), symbol)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	q := fmt.Sprintf("SELECT time,\"name\",value from %s WHERE time > $starttime and time < $endtime and \"name\" = $name ORDER BY time DESC", influxDbBenchmarkedIndexTableName)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"starttime": starttime.UnixNano(), "endtime": endtime.UnixNano(), "name": symbol})
 	if err != nil {
 		return retval, err
 	}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// SetBenchmarkAsset stores the definition of @benchmark. The benchmark asset is added to the asset
+// table if not yet in there, so that its quotations are served like those of any other asset.
+// All components must exist in the asset table. Existing components of the benchmark are replaced.
+func (rdb *RelDB) SetBenchmarkAsset(benchmark dia.BenchmarkAsset) error {
+	if err := benchmark.Validate(); err != nil {
+		return err
+	}
+	return rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		err := txRDB.SetAsset(benchmark.Asset)
+		if err != nil {
+			return err
+		}
+		benchmarkID, err := txRDB.GetAssetID(benchmark.Asset)
+		if err != nil {
+			return err
+		}
+
+		var components []dia.Asset
+		for _, component := range benchmark.Components {
+			components = append(components, component.Asset)
+		}
+		componentIDs, err := txRDB.getAssetIDs(components)
+		if err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (asset_id,method) VALUES ($1,$2) ON CONFLICT (asset_id) DO UPDATE SET method=EXCLUDED.method", benchmarkAssetTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, benchmarkID, benchmark.Method)
+		if err != nil {
+			return err
+		}
+		query = fmt.Sprintf("DELETE FROM %s WHERE benchmark_id=$1", benchmarkComponentTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, benchmarkID)
+		if err != nil {
+			return err
+		}
+		query = fmt.Sprintf("INSERT INTO %s (benchmark_id,component_id,weight,exponent) VALUES ($1,$2,$3,$4)", benchmarkComponentTable)
+		for _, component := range benchmark.Components {
+			componentID, ok := componentIDs[component.Asset.Identifier()]
+			if !ok {
+				return fmt.Errorf("component %s not found", component.Asset.Identifier())
+			}
+			_, err = txRDB.postgresClient.Exec(context.Background(), query, benchmarkID, componentID, component.Weight, component.Exponent)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetBenchmarkAssets returns the definitions of all benchmark assets.
+func (rdb *RelDB) GetBenchmarkAssets() (benchmarks []dia.BenchmarkAsset, err error) {
+	query := fmt.Sprintf(`
+	SELECT b.symbol,b.name,b.address,b.blockchain,b.decimals,ba.method,c.symbol,c.name,c.address,c.blockchain,c.decimals,bc.weight,bc.exponent
+	FROM %s ba
+	INNER JOIN %s b
+	ON ba.asset_id=b.asset_id
+	INNER JOIN %s bc
+	ON bc.benchmark_id=ba.asset_id
+	INNER JOIN %s c
+	ON bc.component_id=c.asset_id
+	ORDER BY b.blockchain,b.address,c.blockchain,c.address
+	`, benchmarkAssetTable, assetTable, benchmarkComponentTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			benchmark         dia.BenchmarkAsset
+			component         dia.BenchmarkComponent
+			decimals          sql.NullInt64
+			componentDecimals sql.NullInt64
+		)
+		err = rows.Scan(
+			&benchmark.Asset.Symbol,
+			&benchmark.Asset.Name,
+			&benchmark.Asset.Address,
+			&benchmark.Asset.Blockchain,
+			&decimals,
+			&benchmark.Method,
+			&component.Asset.Symbol,
+			&component.Asset.Name,
+			&component.Asset.Address,
+			&component.Asset.Blockchain,
+			&componentDecimals,
+			&component.Weight,
+			&component.Exponent,
+		)
+		if err != nil {
+			return
+		}
+		benchmark.Asset.Decimals = uint8(decimals.Int64)
+		component.Asset.Decimals = uint8(componentDecimals.Int64)
+
+		// Rows are ordered by benchmark, so components of a benchmark are consecutive.
+		if n := len(benchmarks); n > 0 && benchmarks[n-1].Asset.Identifier() == benchmark.Asset.Identifier() {
+			benchmarks[n-1].Components = append(benchmarks[n-1].Components, component)
+			continue
+		}
+		benchmark.Components = []dia.BenchmarkComponent{component}
+		benchmarks = append(benchmarks, benchmark)
+	}
+	err = rows.Err()
+	return
+}
+
+// DeleteBenchmarkAsset removes the definition of the benchmark @asset. The asset itself and its
+// quotations are kept.
+func (rdb *RelDB) DeleteBenchmarkAsset(asset dia.Asset) error {
+	query := fmt.Sprintf(`
+	DELETE FROM %s WHERE asset_id=(SELECT asset_id FROM %s WHERE address=$1 AND blockchain=$2)
+	`, benchmarkAssetTable, assetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, asset.Address, asset.Blockchain)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("benchmark asset %s not found", asset.Identifier())
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// BlockchainRPC is an RPC endpoint of a blockchain. Endpoints with lower @Priority are preferred.
+type BlockchainRPC struct {
+	Blockchain  string    `json:"Blockchain"`
+	RestURL     string    `json:"RestURL"`
+	WSURL       string    `json:"WSURL"`
+	Priority    int       `json:"Priority"`
+	Healthy     bool      `json:"Healthy"`
+	LastChecked time.Time `json:"LastChecked"`
+}
+
+// SetBlockchainRPC adds the endpoint @rpc or updates its websocket url and priority.
+// The health status of an existing endpoint is kept.
+func (rdb *RelDB) SetBlockchainRPC(rpc BlockchainRPC) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (blockchain,resturl,wsurl,priority) VALUES ($1,$2,NULLIF($3,''),$4)
+	ON CONFLICT (blockchain,resturl) DO UPDATE SET wsurl=EXCLUDED.wsurl,priority=EXCLUDED.priority
+	`, blockchainRPCTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, rpc.Blockchain, rpc.RestURL, rpc.WSURL, rpc.Priority)
+	return err
+}
+
+// DeleteBlockchainRPC removes the endpoint @restURL of @blockchain.
+func (rdb *RelDB) DeleteBlockchainRPC(blockchain string, restURL string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE blockchain=$1 AND resturl=$2", blockchainRPCTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, blockchain, restURL)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("rpc %s of %s not found", restURL, blockchain)
+	}
+	return nil
+}
+
+// GetBlockchainRPCs returns all endpoints of @blockchain ordered by priority.
+func (rdb *RelDB) GetBlockchainRPCs(blockchain string) (rpcs []BlockchainRPC, err error) {
+	query := fmt.Sprintf(`
+	SELECT resturl,wsurl,priority,healthy,last_checked
+	FROM %s
+	WHERE blockchain=$1
+	ORDER BY priority ASC,resturl ASC
+	`, blockchainRPCTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, blockchain)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rpc         BlockchainRPC
+			wsURL       sql.NullString
+			lastChecked sql.NullTime
+		)
+		err = rows.Scan(&rpc.RestURL, &wsURL, &rpc.Priority, &rpc.Healthy, &lastChecked)
+		if err != nil {
+			return
+		}
+		rpc.Blockchain = blockchain
+		rpc.WSURL = wsURL.String
+		rpc.LastChecked = lastChecked.Time
+		rpcs = append(rpcs, rpc)
+	}
+	err = rows.Err()
+	return
+}
+
+// SetBlockchainRPCHealth records the result of a health check of the endpoint @restURL of @blockchain.
+func (rdb *RelDB) SetBlockchainRPCHealth(blockchain string, restURL string, healthy bool) error {
+	query := fmt.Sprintf("UPDATE %s SET healthy=$3,last_checked=NOW() WHERE blockchain=$1 AND resturl=$2", blockchainRPCTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, blockchain, restURL, healthy)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("rpc %s of %s not found", restURL, blockchain)
+	}
+	return nil
+}
+
+// GetHealthyRPC returns the healthy endpoint of @blockchain with the highest priority.
+func (rdb *RelDB) GetHealthyRPC(blockchain string) (rpc BlockchainRPC, err error) {
+	query := fmt.Sprintf(`
+	SELECT resturl,wsurl,priority,last_checked
+	FROM %s
+	WHERE blockchain=$1 AND healthy
+	ORDER BY priority ASC,resturl ASC
+	LIMIT 1
+	`, blockchainRPCTable)
+	var (
+		wsURL       sql.NullString
+		lastChecked sql.NullTime
+	)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, blockchain).Scan(&rpc.RestURL, &wsURL, &rpc.Priority, &lastChecked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = fmt.Errorf("no healthy rpc for %s", blockchain)
+		}
+		return
+	}
+	rpc.Blockchain = blockchain
+	rpc.WSURL = wsURL.String
+	rpc.Healthy = true
+	rpc.LastChecked = lastChecked.Time
+	return
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrCacheUnavailable is returned by cache reads while redis is bypassed.
+var ErrCacheUnavailable = errors.New("redis cache unavailable")
+
+var (
+	// cacheRetryInterval is the time redis is bypassed after a connection failure.
+	cacheRetryInterval time.Duration
+	// cacheDownUntil is the unix time in nanoseconds until which redis is bypassed.
+	cacheDownUntil         int64
+	cacheSkippedWrites     uint64
+	cacheFallthroughReads  uint64
+	cacheConnectionFailure uint64
+)
+
+// CacheMetrics reports on the degradation of the redis cache.
+type CacheMetrics struct {
+	Available          bool   `json:"Available"`
+	SkippedWrites      uint64 `json:"SkippedWrites"`
+	FallthroughReads   uint64 `json:"FallthroughReads"`
+	ConnectionFailures uint64 `json:"ConnectionFailures"`
+}
+
+func init() {
+	retrySeconds, err := strconv.Atoi(utils.Getenv("REDIS_RETRY_SECONDS", "10"))
+	if err != nil {
+		log.Error("parse REDIS_RETRY_SECONDS: ", err)
+		retrySeconds = 10
+	}
+	cacheRetryInterval = time.Duration(retrySeconds) * time.Second
+}
+
+// GetCacheMetrics returns the current state of the redis cache and the number of
+// cache operations that were bypassed since startup.
+func GetCacheMetrics() CacheMetrics {
+	return CacheMetrics{
+		Available:          atomic.LoadInt64(&cacheDownUntil) < time.Now().UnixNano(),
+		SkippedWrites:      atomic.LoadUint64(&cacheSkippedWrites),
+		FallthroughReads:   atomic.LoadUint64(&cacheFallthroughReads),
+		ConnectionFailures: atomic.LoadUint64(&cacheConnectionFailure),
+	}
+}
+
+// cacheAvailable returns false if @client is not initialized or redis is bypassed after a connection failure.
+func cacheAvailable(client redis.UniversalClient) bool {
+	return client != nil && atomic.LoadInt64(&cacheDownUntil) < time.Now().UnixNano()
+}
+
+// cacheMGet returns the values of @keys like MGET, with nil for missing keys. On a redis cluster, keys
+// of different slots cannot be read by a single MGET, so they are read in a pipeline split by node instead.
+func cacheMGet(ctx context.Context, client redis.UniversalClient, keys []string) ([]interface{}, error) {
+	if _, ok := client.(*redis.ClusterClient); !ok {
+		return client.MGet(ctx, keys...).Result()
+	}
+	pipe := client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	values := make([]interface{}, len(keys))
+	for i, cmd := range cmds {
+		if value, err := cmd.Result(); err == nil {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+// isConnectionError returns true if @err is caused by an unreachable redis server.
+// Cancelled commands are not, as their context is done rather than the server.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF)
+}
+
+// checkCacheError bypasses redis for cacheRetryInterval if @err is a connection error.
+// It returns true in that case.
+func checkCacheError(err error) bool {
+	if !isConnectionError(err) {
+		return false
+	}
+	atomic.AddUint64(&cacheConnectionFailure, 1)
+	atomic.StoreInt64(&cacheDownUntil, time.Now().Add(cacheRetryInterval).UnixNano())
+	log.Warnf("redis unavailable, bypass cache for %v: %v", cacheRetryInterval, err)
+	return true
+}
+
+// skipCacheWrite counts a cache write that was skipped due to an unavailable cache.
+func skipCacheWrite() {
+	atomic.AddUint64(&cacheSkippedWrites, 1)
+}
+
+// fallthroughCacheRead counts a cache read that was served by the underlying database.
+func fallthroughCacheRead() {
+	atomic.AddUint64(&cacheFallthroughReads, 1)
+}
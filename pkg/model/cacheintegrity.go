@@ -0,0 +1,181 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v4"
+)
+
+// cacheScanCount is the number of keys requested per SCAN iteration.
+const cacheScanCount = 1000
+
+// CacheCheckCounts counts the outcome of an integrity check for one key class.
+type CacheCheckCounts struct {
+	Scanned  int `json:"Scanned"`
+	Valid    int `json:"Valid"`
+	Repaired int `json:"Repaired"`
+	Deleted  int `json:"Deleted"`
+}
+
+// CacheIntegrityReport is the result of CheckCacheIntegrity.
+// For a check without repair, Repaired and Deleted count the entries that would have been changed.
+type CacheIntegrityReport struct {
+	Repair        bool             `json:"Repair"`
+	Assets        CacheCheckCounts `json:"Assets"`
+	ExchangePairs CacheCheckCounts `json:"ExchangePairs"`
+}
+
+// CheckCacheIntegrity scans the asset and exchangepair keyspaces of the redis cache and compares all
+// entries against postgres. Entries that differ from postgres are overwritten and entries which are
+// malformed or do not exist in postgres are deleted, if @repair is true.
+func (rdb *RelDB) CheckCacheIntegrity(repair bool) (report CacheIntegrityReport, err error) {
+	if !cacheAvailable(rdb.redisClient) {
+		err = ErrCacheUnavailable
+		return
+	}
+	report.Repair = repair
+
+	err = rdb.scanCache(keyAssetCache+"*", func(key string) error {
+		report.Assets.Scanned++
+		return rdb.checkAssetCacheEntry(key, repair, &report.Assets)
+	})
+	if err != nil {
+		return
+	}
+
+	err = rdb.scanCache(keyExchangePairCache+"*", func(key string) error {
+		report.ExchangePairs.Scanned++
+		return rdb.checkExchangePairCacheEntry(key, repair, &report.ExchangePairs)
+	})
+	return
+}
+
+// scanCache iterates over all keys matching @pattern using SCAN and calls @fn on each key.
+// On a redis cluster, the keyspaces of all masters are scanned. As masters are scanned concurrently,
+// calls of @fn are serialized.
+func (rdb *RelDB) scanCache(pattern string, fn func(key string) error) error {
+	if cluster, ok := rdb.redisClient.(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		return cluster.ForEachMaster(rdb.cacheContext(), func(ctx context.Context, node *redis.Client) error {
+			return scanCacheNode(ctx, node, pattern, func(key string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				return fn(key)
+			})
+		})
+	}
+	return scanCacheNode(rdb.cacheContext(), rdb.redisClient, pattern, fn)
+}
+
+func scanCacheNode(ctx context.Context, client redis.Cmdable, pattern string, fn func(key string) error) error {
+	var cursor uint64
+	for {
+		keys, nextCursor, err := client.Scan(ctx, cursor, pattern, cacheScanCount).Result()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err = fn(key); err != nil {
+				return err
+			}
+		}
+		if nextCursor == 0 {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+func (rdb *RelDB) checkAssetCacheEntry(key string, repair bool, counts *CacheCheckCounts) error {
+	var cached dia.Asset
+	err := rdb.redisClient.Get(rdb.cacheContext(), key).Scan(&cached)
+	if errors.Is(err, redis.Nil) {
+		// Key expired in the meantime.
+		counts.Valid++
+		return nil
+	}
+	if err != nil || key != keyAssetCache+cached.Identifier() {
+		log.Warnf("malformed asset cache entry %s", key)
+		return rdb.deleteCacheEntry(key, repair, counts)
+	}
+
+	asset, err := getAsset(rdb.postgresClient, cached.Address, cached.Blockchain)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Warnf("cached asset %s not in postgres", cached.Identifier())
+		return rdb.deleteCacheEntry(key, repair, counts)
+	}
+	if err != nil {
+		return err
+	}
+	if asset == cached {
+		counts.Valid++
+		return nil
+	}
+
+	log.Warnf("cached asset %s diverges from postgres: %v -- %v", cached.Identifier(), cached, asset)
+	counts.Repaired++
+	if !repair {
+		return nil
+	}
+	return rdb.redisClient.Set(rdb.cacheContext(), key, &asset, assetCacheTTL).Err()
+}
+
+func (rdb *RelDB) checkExchangePairCacheEntry(key string, repair bool, counts *CacheCheckCounts) error {
+	var cached dia.ExchangePair
+	err := rdb.redisClient.Get(rdb.cacheContext(), key).Scan(&cached)
+	if errors.Is(err, redis.Nil) {
+		counts.Valid++
+		return nil
+	}
+	// The key is composed as prefix+exchange+"_"+foreignname. Foreign names can contain underscores,
+	// so the exchange is obtained by trimming the foreign name of the cached pair.
+	if err != nil || cached.ForeignName == "" || !strings.HasSuffix(key, "_"+cached.ForeignName) {
+		log.Warnf("malformed exchangepair cache entry %s", key)
+		return rdb.deleteCacheEntry(key, repair, counts)
+	}
+	exchange := strings.TrimSuffix(strings.TrimPrefix(key, keyExchangePairCache), "_"+cached.ForeignName)
+
+	pair, err := rdb.GetExchangePair(exchange, cached.ForeignName, true)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Warnf("cached pair %s on %s not in postgres", cached.ForeignName, exchange)
+		return rdb.deleteCacheEntry(key, repair, counts)
+	}
+	if err != nil {
+		return err
+	}
+	if exchangePairsMatch(cached, pair) {
+		counts.Valid++
+		return nil
+	}
+
+	log.Warnf("cached pair %s on %s diverges from postgres", cached.ForeignName, exchange)
+	counts.Repaired++
+	if !repair {
+		return nil
+	}
+	return rdb.redisClient.Set(rdb.cacheContext(), key, &pair, exchangePairCacheTTL).Err()
+}
+
+func (rdb *RelDB) deleteCacheEntry(key string, repair bool, counts *CacheCheckCounts) error {
+	counts.Deleted++
+	if !repair {
+		return nil
+	}
+	return rdb.redisClient.Del(rdb.cacheContext(), key).Err()
+}
+
+// exchangePairsMatch compares the fields of two exchange pairs which are persisted in postgres.
+func exchangePairsMatch(cached dia.ExchangePair, stored dia.ExchangePair) bool {
+	return cached.Symbol == stored.Symbol &&
+		cached.ForeignName == stored.ForeignName &&
+		cached.Verified == stored.Verified &&
+		cached.UnderlyingPair.QuoteToken.Address == stored.UnderlyingPair.QuoteToken.Address &&
+		cached.UnderlyingPair.QuoteToken.Blockchain == stored.UnderlyingPair.QuoteToken.Blockchain &&
+		cached.UnderlyingPair.BaseToken.Address == stored.UnderlyingPair.BaseToken.Address &&
+		cached.UnderlyingPair.BaseToken.Blockchain == stored.UnderlyingPair.BaseToken.Blockchain
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+)
+
+const (
+	// Key classes of the redis cache.
+	CacheClassAsset        = "asset"
+	CacheClassExchangePair = "exchangepair"
+	CacheClassMissingAsset = "missingasset"
+
+	// cacheCountInterval is the time for which counted cache entries are reused.
+	cacheCountInterval = 5 * time.Minute
+)
+
+var (
+	// Expiration of redis cache entries per entity type. Zero means no expiration.
+	assetCacheTTL        time.Duration
+	exchangePairCacheTTL time.Duration
+	// missingAssetCacheTTL is the default TTL of the negative asset cache. Zero disables it.
+	missingAssetCacheTTL time.Duration
+	// assetQuotationCacheTTL is the expiration of the latest asset quotations in redis.
+	assetQuotationCacheTTL time.Duration
+	// marketCapCacheTTL is the expiration of cached market caps. Zero disables caching.
+	marketCapCacheTTL time.Duration
+
+	cacheClassPrefixes = map[string]string{
+		CacheClassAsset:        keyAssetCache,
+		CacheClassExchangePair: keyExchangePairCache,
+		CacheClassMissingAsset: keyMissingAssetCache,
+	}
+
+	// cacheCounts holds the latest count of cache entries per key class.
+	cacheCounts struct {
+		sync.Mutex
+		counts    map[string]uint32
+		timestamp time.Time
+	}
+)
+
+func init() {
+	assetCacheTTL = getCacheTTL("REDIS_TTL_ASSET_SECONDS", "86400")
+	exchangePairCacheTTL = getCacheTTL("REDIS_TTL_EXCHANGEPAIR_SECONDS", "86400")
+	missingAssetCacheTTL = getCacheTTL("REDIS_TTL_MISSING_ASSET_SECONDS", "0")
+	marketCapCacheTTL = getCacheTTL("REDIS_TTL_MARKETCAP_SECONDS", "60")
+	assetQuotationCacheTTL = getCacheTTL("REDIS_TTL_ASSET_QUOTATION_SECONDS", strconv.Itoa(WindowYesterday))
+}
+
+// getCacheTTL parses the TTL in seconds from the environment variable @key.
+func getCacheTTL(key string, defaultSeconds string) time.Duration {
+	seconds, err := strconv.ParseInt(utils.Getenv(key, defaultSeconds), 10, 64)
+	if err != nil {
+		log.Errorf("parse %s: %v", key, err)
+		seconds, _ = strconv.ParseInt(defaultSeconds, 10, 64)
+	}
+	return time.Duration(seconds) * time.Second
+}
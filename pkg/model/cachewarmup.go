@@ -0,0 +1,112 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// assetCacheWarmupBatchSize is the number of assets written to redis in one pipeline during warm-up.
+const assetCacheWarmupBatchSize = 1000
+
+// AssetCacheWarmup counts the assets loaded by WarmAssetCache.
+type AssetCacheWarmup struct {
+	// Assets is the number of assets read from postgres.
+	Assets int `json:"Assets"`
+	// Cached is the number of assets written to redis.
+	Cached int `json:"Cached"`
+	// InMemory is the number of assets added to the in-memory asset cache.
+	InMemory int `json:"InMemory"`
+}
+
+// WarmupOption configures a cache warm-up.
+type WarmupOption func(*warmupOptions)
+
+type warmupOptions struct {
+	inMemory bool
+}
+
+// WarmInMemoryCache additionally fills the in-memory asset cache during warm-up until its capacity is reached.
+func WarmInMemoryCache() WarmupOption {
+	return func(o *warmupOptions) {
+		o.inMemory = true
+	}
+}
+
+// WarmAssetCache streams all assets on @blockchain, or on all blockchains if @blockchain is empty, from
+// postgres and writes them to redis in pipelined batches. It is meant to be run on service startup, such
+// that asset reads do not fall through to postgres while the cache fills up.
+// Warm-up stops when @ctx is done and returns the counts reached so far.
+func (rdb *RelDB) WarmAssetCache(ctx context.Context, blockchain string, opts ...WarmupOption) (warmup AssetCacheWarmup, err error) {
+	var o warmupOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !cacheAvailable(rdb.redisClient) {
+		err = ErrCacheUnavailable
+		return
+	}
+	ctxRDB := rdb.WithContext(ctx)
+
+	var qb queryBuilder
+	query := fmt.Sprintf("SELECT asset_id::text,symbol,name,address,decimals,blockchain FROM %s", assetTable)
+	if blockchain != "" {
+		qb.where("blockchain=%s", blockchain)
+		query += " WHERE " + qb.conditions()
+	}
+	rows, err := ctxRDB.postgresClient.Query(ctx, query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	batch := make([]dia.Asset, 0, assetCacheWarmupBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := ctxRDB.SetAssetCacheBatch(batch); err != nil {
+			return err
+		}
+		// Writes are skipped silently if redis became unavailable.
+		if !cacheAvailable(rdb.redisClient) {
+			return ErrCacheUnavailable
+		}
+		warmup.Cached += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for rows.Next() {
+		var (
+			assetID  string
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&assetID, &asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		warmup.Assets++
+		if o.inMemory && !rdb.assetCache.Full() {
+			rdb.assetCache.Add(assetID, asset)
+			warmup.InMemory++
+		}
+		batch = append(batch, asset)
+		if len(batch) == assetCacheWarmupBatchSize {
+			if err = flush(); err != nil {
+				return
+			}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	err = flush()
+	return
+}
@@ -3,7 +3,7 @@ package models
 func (datastore *DB) SetCurrencyChange(cc *Change) error {
 	key := "dia_currencyChange"
 	log.Debug("setting ", key, cc)
-	err := datastore.redisClient.Set(key, cc, 0).Err()
+	err := datastore.redisClient.Set(datastore.cacheContext(), key, cc, 0).Err()
 	if err != nil {
 		log.Errorln("Error: on SetCurrencyChange", err)
 	}
@@ -13,7 +13,7 @@ func (datastore *DB) SetCurrencyChange(cc *Change) error {
 func (datastore *DB) GetCurrencyChange() (*Change, error) {
 	key := "dia_currencyChange"
 	value := &Change{}
-	err := datastore.redisClient.Get(key).Scan(value)
+	err := datastore.redisClient.Get(datastore.cacheContext(), key).Scan(value)
 	if err != nil {
 		log.Errorln("Error: on GetCurrencyChange", err, key)
 		return nil, err
@@ -1,29 +1,37 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia/helpers/db"
 
 	"github.com/diadata-org/diadata/pkg/dia"
-	"github.com/go-redis/redis"
+	"github.com/go-redis/redis/v8"
 	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
 )
 
 type Datastore interface {
 	SetInfluxClient(url string)
+	WithContext(ctx context.Context) Datastore
 	SetBatchFiatPriceInflux(fqs []*FiatQuotation) error
 	SetSingleFiatPriceRedis(fiatQuotation *FiatQuotation) error
+	SetFiatQuotation(fiatQuotation *FiatQuotation) error
+	GetFiatQuotation(base string, quote string, timestamp time.Time) (*FiatQuotation, error)
+	ConvertUSDPrice(priceUSD float64, currency string, timestamp time.Time) (float64, error)
 
 	GetLatestSupply(string, *RelDB) (*dia.Supply, error)
 	GetSupplyCache(asset dia.Asset) (dia.Supply, error)
 	GetSupply(string, time.Time, time.Time, *RelDB) ([]dia.Supply, error)
 	SetSupply(supply *dia.Supply) error
 	GetSupplyInflux(dia.Asset, time.Time, time.Time) ([]dia.Supply, error)
+	GetSupplyInfluxRange(asset dia.Asset, starttime time.Time, endtime time.Time, interval time.Duration) ([]dia.Supply, error)
+	GetSupplyAt(asset dia.Asset, timestamp time.Time) (dia.Supply, error)
 	SaveSynthSupplyInfluxToTable(*dia.SynthAssetSupply, string) error
 	SaveSynthSupplyInflux(*dia.SynthAssetSupply) error
 	GetSynthSupplyInflux(string, string, string, int, time.Time, time.Time) ([]dia.SynthAssetSupply, error)
@@ -40,10 +48,14 @@ type Datastore interface {
 	GetFirstTradeDate(table string) (time.Time, error)
 	SaveTradeInflux(t *dia.Trade) error
 	SaveTradeInfluxToTable(t *dia.Trade, table string) error
+	TradesClaimed(keys []string) ([]bool, error)
+	ClaimTrades(keys []string, ttl time.Duration) error
 	GetTradeInflux(dia.Asset, string, time.Time, time.Duration) (*dia.Trade, error)
 	SaveFilterInflux(filter string, asset dia.Asset, exchange string, value float64, t time.Time) error
 	GetFilterAllExchanges(filter string, address string, blockchain string, starttime time.Time, endtime time.Time) ([]AssetQuotation, error)
 	GetLastTrades(asset dia.Asset, exchange string, timestamp time.Time, maxTrades int, fullAsset bool) ([]dia.Trade, error)
+	GetLastTradesByExchangePair(exchange string, foreignname string, n int) ([]dia.Trade, error)
+	GetTradeGaps(exchange string, foreignname string, starttime time.Time, endtime time.Time, maxGap time.Duration) ([]TradeGap, error)
 	GetAllTrades(t time.Time, maxTrades int) ([]dia.Trade, error)
 
 	GetTradesByExchangesFull(asset dia.Asset, baseAssets []dia.Asset, exchanges []string, returnBasetoken bool, startTime, endTime time.Time, maxTrades int) ([]dia.Trade, error)
@@ -61,10 +73,21 @@ type Datastore interface {
 	CopyInfluxMeasurements(dbOrigin string, dbDestination string, tableOrigin string, tableDestination string, timeInit time.Time, timeFinal time.Time) (int64, error)
 
 	Flush() error
+	CreateRetentionPolicy(rp InfluxRetentionPolicy) error
+	AlterRetentionPolicy(rp InfluxRetentionPolicy) error
+	EnsureRetentionPolicy(rp InfluxRetentionPolicy) error
+	GetRetentionPolicies() ([]InfluxRetentionPolicy, error)
+	SetWriteRetentionPolicy(measurement string, rp string)
+	InstallContinuousQueries(series []DownsampleSeries) error
+	GetContinuousQueries() (map[string]string, error)
+	DropContinuousQuery(name string) error
+	Downsample(series DownsampleSeries, starttime time.Time, endtime time.Time) error
+	SetDownsampledSeries(series []DownsampleSeries) error
 	ExecuteRedisPipe() error
 	FlushRedisPipe() error
 	GetFilterPoints(filter string, exchange string, symbol string, scale string, starttime time.Time, endtime time.Time) (*Points, error)
 	GetFilterPointsAsset(filter string, exchange string, address string, blockchain string, starttime time.Time, endtime time.Time) (*Points, error)
+	GetFilterPointsRange(filter string, asset dia.Asset, exchange string, starttime time.Time, endtime time.Time, resolution time.Duration) ([]dia.FilterPoint, error)
 	SetFilter(filterName string, asset dia.Asset, exchange string, value float64, t time.Time) error
 	GetLastPriceBefore(asset dia.Asset, filter string, exchange string, timestamp time.Time) (Price, error)
 	SetAvailablePairs(exchange string, pairs []dia.ExchangePair) error
@@ -75,6 +98,7 @@ type Datastore interface {
 	// Volume methods
 	GetVolumeInflux(asset dia.Asset, exchange string, starttime time.Time, endtime time.Time) (*float64, error)
 	Get24HoursAssetVolume(asset dia.Asset) (*float64, error)
+	GetVolumeInfluxRange(asset dia.Asset, starttime time.Time, endtime time.Time, resolution time.Duration) ([]VolumePoint, error)
 	Get24HoursExchangeVolume(exchange string) (*float64, error)
 	GetNumTradesExchange24H(exchange string) (int64, error)
 	GetNumTrades(exchange string, address string, blockchain string, starttime time.Time, endtime time.Time) (int64, error)
@@ -88,12 +112,19 @@ type Datastore interface {
 	GetAssetPriceUSDLatest(asset dia.Asset) (price float64, err error)
 	SetAssetQuotation(quotation *AssetQuotation) error
 	GetAssetQuotation(asset dia.Asset, timestamp time.Time) (*AssetQuotation, error)
+	GetQuotationEnvelope(asset dia.Asset, timestamp time.Time, maxAge time.Duration) QuotationEnvelope
 	GetAssetQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) ([]AssetQuotation, error)
-	GetAssetQuotationLatest(asset dia.Asset) (*AssetQuotation, error)
+	GetAssetQuotationLatest(asset dia.Asset, opts ...ReadOption) (*AssetQuotation, error)
 	GetSortedAssetQuotations(assets []dia.Asset) ([]AssetQuotation, error)
 	AddAssetQuotationsToBatch(quotations []*AssetQuotation) error
 	SetAssetQuotationCache(quotation *AssetQuotation, check bool) (bool, error)
 	GetAssetQuotationCache(asset dia.Asset) (*AssetQuotation, error)
+	GetAssetQuotationsCache(assets []dia.Asset) ([]AssetQuotation, error)
+	SetAssetGroupQuotation(quotation *AssetGroupQuotation) error
+	GetAssetGroupQuotation(groupID string, timestamp time.Time) (*AssetGroupQuotation, error)
+	GetAssetGroupQuotationLatest(groupID string) (*AssetGroupQuotation, error)
+	SetScraperLag(lag ScraperLag) error
+	GetScraperLags(scraper string, blockchain string, starttime time.Time, endtime time.Time) ([]ScraperLag, error)
 	GetAssetPriceUSDCache(asset dia.Asset) (price float64, err error)
 	GetTopAssetByMcap(symbol string, relDB *RelDB) (dia.Asset, error)
 	GetTopAssetByVolume(symbol string, relDB *RelDB) (topAsset dia.Asset, err error)
@@ -103,10 +134,24 @@ type Datastore interface {
 	// DEX Pool  methods
 	SavePoolInflux(p dia.Pool) error
 	GetPoolInflux(poolAddress string, starttime time.Time, endtime time.Time) ([]dia.Pool, error)
+	SavePoolLiquidityInflux(pool dia.Pool, assetvolumes []dia.AssetVolume, t time.Time) error
+	GetPoolLiquidity(pool dia.Pool, starttime time.Time, endtime time.Time) ([]dia.Pool, error)
 	GetPoolLiquiditiesUSD(p *dia.Pool, priceCache map[string]float64)
 
+	// DeFi lending rate methods
+	SaveDefiRateInflux(rate dia.DefiRate) error
+	GetDefiRatesInflux(protocol string, asset dia.Asset, starttime time.Time, endtime time.Time) ([]dia.DefiRate, error)
+
+	// Stablecoin peg methods
+	ComputePegDeviation(asset dia.Asset, peg float64, window time.Duration) (PegDeviation, error)
+	SavePegDeviationInflux(deviation PegDeviation) error
+	UpdatePegDeviations() (int, error)
+	GetPegDeviation(asset dia.Asset, window time.Duration) (PegDeviation, error)
+
 	// Market Measures
 	GetAssetsMarketCap(asset dia.Asset) (float64, error)
+	GetMarketCap(asset dia.Asset, relDB *RelDB) (MarketCap, error)
+	GetTopAssetsByMarketCap(limit int, blockchain string, relDB *RelDB) ([]MarketCap, error)
 
 	// Interest rates' methods
 	SetInterestRate(ir *InterestRate) error
@@ -124,6 +169,8 @@ type Datastore interface {
 	GetForeignQuotationInflux(symbol, source string, timestamp time.Time) (ForeignQuotation, error)
 	GetForeignPriceYesterday(symbol, source string) (float64, error)
 	GetForeignSymbolsInflux(source string) ([]string, error)
+	GetForeignQuotationsInflux(symbol, source string, starttime, endtime time.Time) ([]ForeignQuotation, error)
+	CompareForeignQuotations(asset dia.Asset, source string, starttime, endtime time.Time) ([]QuotationComparison, error)
 
 	SetVWAPFirefly(foreignName string, value float64, timestamp time.Time) error
 	GetVWAPFirefly(foreignName string, starttime time.Time, endtime time.Time) ([]float64, []time.Time, error)
@@ -147,11 +194,23 @@ const (
 )
 
 type DB struct {
-	redisClient         *redis.Client
+	redisClient         redis.UniversalClient
 	redisPipe           redis.Pipeliner
 	influxClient        clientInfluxdb.Client
+	influxConfig        clientInfluxdb.HTTPConfig
 	influxBatchPoints   clientInfluxdb.BatchPoints
 	influxPointsInBatch int
+	// influxHTTPClient runs the influx queries of datastores bound to a context by WithContext.
+	influxHTTPClient *http.Client
+	// writeRetentionPolicies maps measurements to the retention policy their points are written to.
+	writeRetentionPolicies map[string]string
+	influxRPBatchPoints    map[string]clientInfluxdb.BatchPoints
+	// downsampled holds the downsampled series which range queries may read from.
+	downsampled *downsampledCoverage
+	// ctx is the context cache operations are bound to by WithContext.
+	ctx context.Context
+	// volumesYesterday holds the 24h volumes attached to cached quotations.
+	volumesYesterday *volumeYesterdayCache
 }
 
 var EscapeReplacer = strings.NewReplacer("\n", `\n`)
@@ -163,8 +222,13 @@ const (
 	influxDbFiatQuotationsTable       = "fiat"
 	influxDbSupplyTable               = "supplies"
 	influxDbDEXPoolTable              = "DEXPools"
+	influxDbPoolLiquidityTable        = "poolLiquidity"
+	influxDbDefiRateTable             = "defiRate"
+	influxDbPegDeviationTable         = "pegDeviation"
 	influxDbStockQuotationsTable      = "stockquotations"
 	influxDBAssetQuotationsTable      = "assetQuotations"
+	influxDBAssetGroupQuotationsTable = "assetGroupQuotations"
+	influxDBScraperLagTable           = "scraperLag"
 	influxDbBenchmarkedIndexTableName = "benchmarkedIndexValues"
 	influxDbVwapFireflyTable          = "vwapFirefly"
 	influxDbSynthSupplyTable          = "synthsupply"
@@ -178,6 +242,38 @@ func queryInfluxDB(clnt clientInfluxdb.Client, cmd string) (res []clientInfluxdb
 	return
 }
 
+// queryInfluxDBParams queries the database with @cmd, where each placeholder $name in @cmd is bound to params[name].
+// Values are sent separately from the query, so they can neither break nor alter its syntax.
+// Only literals can be bound, measurement names and durations of GROUP BY time() must be part of @cmd.
+func queryInfluxDBParams(clnt clientInfluxdb.Client, cmd string, params map[string]interface{}) (res []clientInfluxdb.Result, err error) {
+	q := clientInfluxdb.Query{
+		Command:    cmd,
+		Database:   influxDbName,
+		Parameters: params,
+	}
+	response, err := clnt.Query(q)
+	if err != nil {
+		return
+	}
+	if response.Error() != nil {
+		return res, response.Error()
+	}
+	return response.Results, nil
+}
+
+// queryInflux queries the influx database of @datastore with @cmd and bound @params, see queryInfluxDBParams.
+// Measurements are read from the retention policy they are written to.
+func (datastore *DB) queryInflux(cmd string, params map[string]interface{}) ([]clientInfluxdb.Result, error) {
+	return queryInfluxDBParams(datastore.influxClient, datastore.qualifyMeasurements(cmd), params)
+}
+
+// bindInfluxParam adds @value to @params under a new name and returns its placeholder for the query.
+func bindInfluxParam(params map[string]interface{}, value interface{}) string {
+	name := fmt.Sprintf("p%d", len(params))
+	params[name] = value
+	return "$" + name
+}
+
 // queryInfluxDBName is a wrapper for queryInfluxDB that allows for queries on the database with name @dbName.
 func queryInfluxDBName(clnt clientInfluxdb.Client, dbName string, cmd string) (res []clientInfluxdb.Result, err error) {
 	q := clientInfluxdb.Query{
@@ -217,8 +313,10 @@ func NewDataStoreWithoutRedis() (*DB, error) {
 func NewDataStoreWithOptions(withRedis bool, withInflux bool) (*DB, error) {
 	var (
 		influxClient      clientInfluxdb.Client
+		influxConfig      clientInfluxdb.HTTPConfig
+		influxHTTPClient  *http.Client
 		influxBatchPoints clientInfluxdb.BatchPoints
-		redisClient       *redis.Client
+		redisClient       redis.UniversalClient
 		redisPipe         redis.Pipeliner
 	)
 
@@ -228,6 +326,8 @@ func NewDataStoreWithOptions(withRedis bool, withInflux bool) (*DB, error) {
 	}
 	if withInflux {
 		var err error
+		influxConfig = db.GetInfluxConfig(influxDBDefaultURL)
+		influxHTTPClient = newInfluxHTTPClient(influxConfig)
 		influxClient = db.GetInfluxClient(influxDBDefaultURL)
 		influxBatchPoints = createBatchInflux()
 		_, err = queryInfluxDB(influxClient, fmt.Sprintf("CREATE DATABASE %s", influxDbName))
@@ -235,18 +335,42 @@ func NewDataStoreWithOptions(withRedis bool, withInflux bool) (*DB, error) {
 			log.Errorln("queryInfluxDB CREATE DATABASE", err)
 		}
 	}
-	return &DB{redisClient, redisPipe, influxClient, influxBatchPoints, 0}, nil
+	return &DB{
+		redisClient:       redisClient,
+		redisPipe:         redisPipe,
+		influxClient:      influxClient,
+		influxConfig:      influxConfig,
+		influxHTTPClient:  influxHTTPClient,
+		influxBatchPoints: influxBatchPoints,
+		volumesYesterday:  &volumeYesterdayCache{volumes: make(map[string]volumeYesterdayEntry)},
+	}, nil
+}
+
+// SetRedisClient connects the datastore to the redis setup given by @config, such as
+// sentinels or a redis cluster, instead of the one given by the environment.
+func (datastore *DB) SetRedisClient(config db.RedisConfig) {
+	datastore.redisClient = db.NewRedisClient(config)
+	datastore.redisPipe = datastore.redisClient.TxPipeline()
 }
 
 // SetInfluxClient resets influx's client url to @url.
 func (datastore *DB) SetInfluxClient(url string) {
+	datastore.influxConfig = db.GetInfluxConfig(url)
+	datastore.influxHTTPClient = newInfluxHTTPClient(datastore.influxConfig)
 	datastore.influxClient = db.GetInfluxClient(url)
 }
 
 func createBatchInflux() clientInfluxdb.BatchPoints {
+	return createBatchInfluxRP("")
+}
+
+// createBatchInfluxRP returns a batch which is written to the retention policy @rp.
+// If @rp is empty, the default retention policy of the database is used.
+func createBatchInfluxRP(rp string) clientInfluxdb.BatchPoints {
 	bp, err := clientInfluxdb.NewBatchPoints(clientInfluxdb.BatchPointsConfig{
-		Database:  influxDbName,
-		Precision: "ns",
+		Database:        influxDbName,
+		RetentionPolicy: rp,
+		Precision:       "ns",
 	})
 	if err != nil {
 		log.Errorln("NewBatchPoints", err)
@@ -268,13 +392,21 @@ func (datastore *DB) WriteBatchInflux() (err error) {
 		log.Errorln("WriteBatchInflux", err)
 		return
 	}
+	for rp, bp := range datastore.influxRPBatchPoints {
+		err = datastore.influxClient.Write(bp)
+		if err != nil {
+			log.Errorf("WriteBatchInflux to retention policy %s: %v", rp, err)
+			return
+		}
+		delete(datastore.influxRPBatchPoints, rp)
+	}
 	datastore.influxPointsInBatch = 0
 	datastore.influxBatchPoints = createBatchInflux()
 	return
 }
 
 func (datastore *DB) addPoint(pt *clientInfluxdb.Point) {
-	datastore.influxBatchPoints.AddPoint(pt)
+	datastore.batchForPoint(pt).AddPoint(pt)
 	datastore.influxPointsInBatch++
 
 	if datastore.influxPointsInBatch >= influxMaxPointsInBatch {
@@ -286,8 +418,19 @@ func (datastore *DB) addPoint(pt *clientInfluxdb.Point) {
 }
 
 func (datastore *DB) ExecuteRedisPipe() (err error) {
+	if !cacheAvailable(datastore.redisClient) {
+		skipCacheWrite()
+		if datastore.redisPipe == nil {
+			return nil
+		}
+		return datastore.redisPipe.Discard()
+	}
 	// TO DO: Handle first return value for read requests.
-	_, err = datastore.redisPipe.Exec()
+	_, err = datastore.redisPipe.Exec(datastore.cacheContext())
+	if checkCacheError(err) {
+		skipCacheWrite()
+		return nil
+	}
 	return
 }
 
@@ -298,9 +441,9 @@ func (datastore *DB) FlushRedisPipe() error {
 // CopyInfluxMeasurements copies entries from measurement @tableOrigin in database @dbOrigin into @tableDestination in database @dbDestination.
 // It takes into account all data ranging from @timeInit until @timeFinal.
 func (datastore *DB) CopyInfluxMeasurements(dbOrigin string, dbDestination string, tableOrigin string, tableDestination string, timeInit time.Time, timeFinal time.Time) (numCopiedRows int64, err error) {
-	queryString := "select * into %s..%s from %s..%s where time>%d and time<=%d group by *"
-	query := fmt.Sprintf(queryString, dbDestination, tableDestination, dbOrigin, tableOrigin, timeInit.UnixNano(), timeFinal.UnixNano())
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	queryString := "select * into %s..%s from %s..%s where time>$starttime and time<=$endtime group by *"
+	query := fmt.Sprintf(queryString, quoteInfluxIdentifier(dbDestination), quoteInfluxIdentifier(tableDestination), quoteInfluxIdentifier(dbOrigin), quoteInfluxIdentifier(tableOrigin))
+	res, err := datastore.queryInflux(query, map[string]interface{}{"starttime": timeInit.UnixNano(), "endtime": timeFinal.UnixNano()})
 	if err != nil {
 		return
 	}
@@ -337,9 +480,9 @@ func (datastore *DB) SetVWAPFirefly(foreignName string, value float64, timestamp
 
 func (datastore *DB) GetVWAPFirefly(foreignName string, starttime time.Time, endtime time.Time) (values []float64, timestamps []time.Time, err error) {
 
-	influxQuery := "SELECT value FROM %s WHERE time > %d AND time <= %d AND foreignName = '%s' ORDER BY DESC"
-	q := fmt.Sprintf(influxQuery, influxDbVwapFireflyTable, starttime.UnixNano(), endtime.UnixNano(), foreignName)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	influxQuery := "SELECT value FROM %s WHERE time > $starttime AND time <= $endtime AND foreignName = $foreignName ORDER BY DESC"
+	q := fmt.Sprintf(influxQuery, influxDbVwapFireflyTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"starttime": starttime.UnixNano(), "endtime": endtime.UnixNano(), "foreignName": foreignName})
 	if err != nil {
 		return
 	}
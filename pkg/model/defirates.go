@@ -0,0 +1,115 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+// SetDefiProtocol stores the metadata of a lending protocol. An existing protocol with the same name is updated.
+func (rdb *RelDB) SetDefiProtocol(protocol dia.DefiProtocol) error {
+	if protocol.Name == "" || protocol.Blockchain == "" {
+		return errors.New("defi protocol without name or blockchain")
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (name,blockchain,address,url) VALUES ($1,$2,$3,NULLIF($4,''))
+	ON CONFLICT (name) DO UPDATE SET blockchain=EXCLUDED.blockchain,address=EXCLUDED.address,url=EXCLUDED.url`,
+		defiProtocolTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, protocol.Name, protocol.Blockchain, protocol.Address, protocol.URL)
+	return err
+}
+
+// GetDefiProtocol returns the lending protocol with @name.
+func (rdb *RelDB) GetDefiProtocol(name string) (protocol dia.DefiProtocol, err error) {
+	query := fmt.Sprintf("SELECT name,blockchain,address,COALESCE(url,'') FROM %s WHERE name=$1", defiProtocolTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, name).Scan(
+		&protocol.Name,
+		&protocol.Blockchain,
+		&protocol.Address,
+		&protocol.URL,
+	)
+	return
+}
+
+// GetDefiProtocols returns all lending protocols, ordered by name.
+func (rdb *RelDB) GetDefiProtocols() (protocols []dia.DefiProtocol, err error) {
+	query := fmt.Sprintf("SELECT name,blockchain,address,COALESCE(url,'') FROM %s ORDER BY name", defiProtocolTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var protocol dia.DefiProtocol
+		if err = rows.Scan(&protocol.Name, &protocol.Blockchain, &protocol.Address, &protocol.URL); err != nil {
+			return
+		}
+		protocols = append(protocols, protocol)
+	}
+	err = rows.Err()
+	return
+}
+
+// SaveDefiRateInflux stores the supply and borrow rates @rate of an asset on a lending protocol in influx.
+// Flushed when more than maxPoints in batch.
+func (datastore *DB) SaveDefiRateInflux(rate dia.DefiRate) error {
+	tags := map[string]string{
+		"protocol":   rate.Protocol,
+		"address":    rate.Asset.Address,
+		"blockchain": rate.Asset.Blockchain,
+	}
+	fields := map[string]interface{}{
+		"symbol":    rate.Asset.Symbol,
+		"supplyAPY": rate.SupplyAPY,
+		"borrowAPY": rate.BorrowAPY,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbDefiRateTable, tags, fields, rate.Time)
+	if err != nil {
+		log.Errorln("SaveDefiRateInflux:", err)
+		return err
+	}
+	datastore.addPoint(pt)
+	return nil
+}
+
+// GetDefiRatesInflux returns the rates of @asset on @protocol in the time-range [starttime, endtime), ordered by time.
+func (datastore *DB) GetDefiRatesInflux(protocol string, asset dia.Asset, starttime time.Time, endtime time.Time) ([]dia.DefiRate, error) {
+	rates := []dia.DefiRate{}
+	q := fmt.Sprintf(`SELECT symbol,supplyAPY,borrowAPY FROM %s
+	WHERE protocol=$protocol AND address=$address AND blockchain=$blockchain AND time >= $starttime AND time < $endtime ORDER BY time ASC`,
+		influxDbDefiRateTable)
+	params := map[string]interface{}{
+		"protocol":   protocol,
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	}
+	res, err := datastore.queryInflux(q, params)
+	if err != nil {
+		return rates, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return rates, nil
+	}
+
+	for _, row := range res[0].Series[0].Values {
+		rate := dia.DefiRate{Protocol: protocol, Asset: asset}
+		rate.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return rates, err
+		}
+		if symbol, ok := row[1].(string); ok && rate.Asset.Symbol == "" {
+			rate.Asset.Symbol = symbol
+		}
+		rate.SupplyAPY = influxFloat(row[2])
+		rate.BorrowAPY = influxFloat(row[3])
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
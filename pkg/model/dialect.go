@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/jackc/pgconn"
+)
+
+// Dialect is the database serving the relational layer.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	// DialectCockroach runs the relational layer on CockroachDB. CockroachDB executes all transactions
+	// with serializable isolation and aborts them on contention, so transactions are retried.
+	// The schema in deployments/config/pginit.sql targets postgres: CockroachDB runs neither the plpgsql
+	// changelog triggers nor UNIQUE NULLS NOT DISTINCT. Hence, on CockroachDB the changelog is not
+	// written and the sync methods fail with ErrDialectUnsupported, and exchangelisting must be deployed
+	// with a unique index on (exchange, foreignname, listing_type, COALESCE(effective_time, 'epoch')) instead.
+	DialectCockroach Dialect = "cockroach"
+
+	// serializationFailure is the SQLSTATE of transactions aborted by the database due to contention.
+	serializationFailure = "40001"
+	// maxTxAttempts bounds the attempts of a transaction which is retried after serialization failures.
+	maxTxAttempts = 5
+)
+
+// ErrDialectUnsupported is returned by methods relying on postgres-only features of the schema.
+var ErrDialectUnsupported = errors.New("not supported by database dialect")
+
+// dialectFromEnv returns the dialect set in env var POSTGRES_DIALECT, defaulting to postgres.
+func dialectFromEnv() Dialect {
+	dialect := Dialect(utils.Getenv("POSTGRES_DIALECT", string(DialectPostgres)))
+	switch dialect {
+	case DialectPostgres:
+		return dialect
+	case DialectCockroach:
+		log.Warn("postgres dialect cockroach: changelog and sync of the asset universe are unavailable")
+		return dialect
+	default:
+		log.Warnf("unknown postgres dialect %s, falling back to %s", dialect, DialectPostgres)
+		return DialectPostgres
+	}
+}
+
+// WithDialect sets the @dialect of the database behind the postgres client.
+// It defaults to the value of env var POSTGRES_DIALECT.
+func WithDialect(dialect Dialect) RelDBOption {
+	return func(rdb *RelDB) {
+		rdb.dialect = dialect
+	}
+}
+
+// requirePostgres returns ErrDialectUnsupported if @rdb does not run on postgres.
+func (rdb *RelDB) requirePostgres() error {
+	if rdb.dialect == DialectCockroach {
+		return ErrDialectUnsupported
+	}
+	return nil
+}
+
+// retryTx returns true if a transaction which failed with @err in its @attempt-th attempt should be retried.
+// Only top-level transactions on CockroachDB are retried. A failed nested transaction aborts the
+// outer one, which is retried as a whole.
+func (rdb *RelDB) retryTx(err error, attempt int) bool {
+	if rdb.dialect != DialectCockroach || rdb.inTx || attempt >= maxTxAttempts {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == serializationFailure
+}
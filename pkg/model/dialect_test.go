@@ -0,0 +1,100 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+func TestRetryTx(t *testing.T) {
+	conflict := fmt.Errorf("commit: %w", &pgconn.PgError{Code: serializationFailure})
+	cases := []struct {
+		name    string
+		dialect Dialect
+		inTx    bool
+		err     error
+		attempt int
+		want    bool
+	}{
+		{"cockroach conflict", DialectCockroach, false, conflict, 1, true},
+		{"cockroach nested conflict", DialectCockroach, true, conflict, 1, false},
+		{"cockroach last attempt", DialectCockroach, false, conflict, maxTxAttempts, false},
+		{"cockroach other error", DialectCockroach, false, errors.New("connection refused"), 1, false},
+		{"postgres conflict", DialectPostgres, false, conflict, 1, false},
+	}
+	for _, c := range cases {
+		rdb := &RelDB{dialect: c.dialect, inTx: c.inTx}
+		if got := rdb.retryTx(c.err, c.attempt); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRequirePostgres(t *testing.T) {
+	if err := (&RelDB{dialect: DialectPostgres}).requirePostgres(); err != nil {
+		t.Errorf("postgres: got %v, want nil", err)
+	}
+	if _, err := (&RelDB{dialect: DialectCockroach}).GetSyncChanges(0, 10); !errors.Is(err, ErrDialectUnsupported) {
+		t.Errorf("cockroach: got %v, want %v", err, ErrDialectUnsupported)
+	}
+}
+
+// rollbackTx records whether it was committed or rolled back.
+type rollbackTx struct {
+	pgx.Tx
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *rollbackTx) Commit(ctx context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *rollbackTx) Rollback(ctx context.Context) error {
+	if tx.committed {
+		return pgx.ErrTxClosed
+	}
+	tx.rolledBack = true
+	return nil
+}
+
+// txPgClient begins @tx on each Begin.
+type txPgClient struct {
+	failingPgClient
+	tx *rollbackTx
+}
+
+func (c *txPgClient) Begin(ctx context.Context) (pgx.Tx, error) {
+	return c.tx, nil
+}
+
+func TestRunTxRollbackOnPanic(t *testing.T) {
+	client := &txPgClient{tx: &rollbackTx{}}
+	rdb := &RelDB{postgresClient: client}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic of fn to be propagated")
+			}
+		}()
+		_ = rdb.runTx(context.Background(), pgx.TxOptions{}, func(tx pgx.Tx) error {
+			panic("fn failed")
+		})
+	}()
+	if !client.tx.rolledBack {
+		t.Error("expected the transaction to be rolled back after a panic")
+	}
+
+	client.tx = &rollbackTx{}
+	if err := rdb.runTx(context.Background(), pgx.TxOptions{}, func(tx pgx.Tx) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if !client.tx.committed || client.tx.rolledBack {
+		t.Errorf("expected the transaction to be committed only, got %+v", client.tx)
+	}
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// SetExchangePairPool links the pair @foreignname on @exchange to the pool with @poolAddress on @blockchain,
+// such that trades stored by pair can be joined to the pool's liquidity. A pair can be linked to several pools.
+// Both the pair and the pool must already exist.
+func (rdb *RelDB) SetExchangePairPool(exchange string, foreignname string, blockchain string, poolAddress string) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (exchange,foreignname,pool_id)
+	SELECT $1,$2,pool_id FROM %s WHERE blockchain=$3 AND address=$4
+	ON CONFLICT (exchange,foreignname,pool_id) DO NOTHING`,
+		exchangepairPoolTable, poolTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, exchange, foreignname, blockchain, poolAddress)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 && !rdb.exchangePairPoolExists(exchange, foreignname, blockchain, poolAddress) {
+		return fmt.Errorf("pool %s on %s not found", poolAddress, blockchain)
+	}
+	return nil
+}
+
+// exchangePairPoolExists returns true if the pair @foreignname on @exchange is linked to the given pool.
+func (rdb *RelDB) exchangePairPoolExists(exchange string, foreignname string, blockchain string, poolAddress string) (exists bool) {
+	query := fmt.Sprintf(`
+	SELECT EXISTS (
+		SELECT 1 FROM %s ep INNER JOIN %s p ON ep.pool_id=p.pool_id
+		WHERE ep.exchange=$1 AND ep.foreignname=$2 AND p.blockchain=$3 AND p.address=$4
+	)`,
+		exchangepairPoolTable, poolTable)
+	err := rdb.postgresClient.QueryRow(context.Background(), query, exchange, foreignname, blockchain, poolAddress).Scan(&exists)
+	if err != nil {
+		log.Error("check exchangepair pool: ", err)
+	}
+	return
+}
+
+// DeleteExchangePairPool removes the link between the pair @foreignname on @exchange and the given pool.
+func (rdb *RelDB) DeleteExchangePairPool(exchange string, foreignname string, blockchain string, poolAddress string) error {
+	query := fmt.Sprintf(`
+	DELETE FROM %s WHERE exchange=$1 AND foreignname=$2
+	AND pool_id=(SELECT pool_id FROM %s WHERE blockchain=$3 AND address=$4)`,
+		exchangepairPoolTable, poolTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, exchange, foreignname, blockchain, poolAddress)
+	return err
+}
+
+// GetPoolsForExchangePair returns the pools linked to the pair @foreignname on @exchange.
+// Pools only carry exchange, blockchain and address, liquidity can be fetched with GetPoolByAddress.
+func (rdb *RelDB) GetPoolsForExchangePair(exchange string, foreignname string) (pools []dia.Pool, err error) {
+	query := fmt.Sprintf(`
+	SELECT p.exchange,p.blockchain,p.address
+	FROM %s ep INNER JOIN %s p ON ep.pool_id=p.pool_id
+	WHERE ep.exchange=$1 AND ep.foreignname=$2
+	ORDER BY p.address`,
+		exchangepairPoolTable, poolTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange, foreignname)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pool dia.Pool
+		if err = rows.Scan(&pool.Exchange.Name, &pool.Blockchain.Name, &pool.Address); err != nil {
+			return
+		}
+		pools = append(pools, pool)
+	}
+	err = rows.Err()
+	return
+}
+
+// GetExchangePairsForPool returns the exchangepairs linked to the pool with @poolAddress on @blockchain.
+// The underlying assets are only set for pairs with assigned quote and base tokens.
+func (rdb *RelDB) GetExchangePairsForPool(blockchain string, poolAddress string) (pairs []dia.ExchangePair, err error) {
+	query := fmt.Sprintf(`
+	SELECT e.exchange,e.foreignname,e.symbol,e.verified,
+		a.symbol,a.name,a.address,a.blockchain,a.decimals,
+		b.symbol,b.name,b.address,b.blockchain,b.decimals
+	FROM %s ep
+	INNER JOIN %s p ON ep.pool_id=p.pool_id
+	INNER JOIN %s e ON ep.exchange=e.exchange AND ep.foreignname=e.foreignname
+	LEFT JOIN %s a ON e.id_quotetoken=a.asset_id
+	LEFT JOIN %s b ON e.id_basetoken=b.asset_id
+	WHERE p.blockchain=$1 AND p.address=$2
+	ORDER BY e.exchange,e.foreignname`,
+		exchangepairPoolTable, poolTable, exchangepairTable, assetTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, blockchain, poolAddress)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pair                                                  dia.ExchangePair
+			quoteSymbol, quoteName, quoteAddress, quoteBlockchain sql.NullString
+			baseSymbol, baseName, baseAddress, baseBlockchain     sql.NullString
+			quoteDecimals, baseDecimals                           sql.NullInt64
+		)
+		err = rows.Scan(
+			&pair.Exchange,
+			&pair.ForeignName,
+			&pair.Symbol,
+			&pair.Verified,
+			&quoteSymbol,
+			&quoteName,
+			&quoteAddress,
+			&quoteBlockchain,
+			&quoteDecimals,
+			&baseSymbol,
+			&baseName,
+			&baseAddress,
+			&baseBlockchain,
+			&baseDecimals,
+		)
+		if err != nil {
+			return
+		}
+		if quoteAddress.Valid {
+			pair.UnderlyingPair.QuoteToken = dia.Asset{
+				Symbol:     quoteSymbol.String,
+				Name:       quoteName.String,
+				Address:    quoteAddress.String,
+				Blockchain: quoteBlockchain.String,
+				Decimals:   uint8(quoteDecimals.Int64),
+			}
+		}
+		if baseAddress.Valid {
+			pair.UnderlyingPair.BaseToken = dia.Asset{
+				Symbol:     baseSymbol.String,
+				Name:       baseName.String,
+				Address:    baseAddress.String,
+				Blockchain: baseBlockchain.String,
+				Decimals:   uint8(baseDecimals.Int64),
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+	err = rows.Err()
+	return
+}
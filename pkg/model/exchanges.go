@@ -30,14 +30,19 @@ func (datastore *DB) GetActiveExchangesAndPairs(
 	query := `
 	SELECT count(*)
 	FROM %s 
-	WHERE time>%d AND time<=%d 
-	AND quotetokenaddress='%s' AND quotetokenblockchain='%s'
+	WHERE time>$starttime AND time<=$endtime 
+	AND quotetokenaddress=$address AND quotetokenblockchain=$blockchain
 	AND verified='true'
 	GROUP BY "exchange","pair","basetokenaddress","basetokenblockchain"
 	`
 
-	q := fmt.Sprintf(query, influxDbTradesTable, starttime.UnixNano(), endtime.UnixNano(), address, blockchain)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	q := fmt.Sprintf(query, influxDbTradesTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+		"address":    address,
+		"blockchain": blockchain,
+	})
 	if err != nil {
 		return exchangepairmap, pairCountTradesMap, err
 	}
@@ -71,7 +76,7 @@ func (datastore *DB) GetActiveExchangesAndPairs(
 
 func (rdb *RelDB) GetExchangesForSymbol(symbol string) (exchanges []string, err error) {
 
-	query := fmt.Sprintf("select distinct(exchange) from %s where symbol=$1", exchangesymbolTable)
+	query := fmt.Sprintf("select distinct(exchange) from %s where symbol=$1 and delisted_at is null", exchangesymbolTable)
 	rows, err := rdb.postgresClient.Query(context.Background(), query, symbol)
 	if err != nil {
 		return
@@ -92,14 +97,14 @@ func (rdb *RelDB) GetExchangesForSymbol(symbol string) (exchanges []string, err
 func (datastore *DB) SetAvailablePairs(exchange string, pairs []dia.ExchangePair) error {
 	key := "dia_available_pairs_" + exchange
 	var p dia.Pairs = pairs
-	return datastore.redisClient.Set(key, &p, 0).Err()
+	return datastore.redisClient.Set(datastore.cacheContext(), key, &p, 0).Err()
 }
 
 // GetAvailablePairs a slice of all pairs available in the exchange in the internal redis db
 func (datastore *DB) GetAvailablePairs(exchange string) ([]dia.ExchangePair, error) {
 	key := "dia_available_pairs_" + exchange
 	p := dia.Pairs{}
-	err := datastore.redisClient.Get(key).Scan(&p)
+	err := datastore.redisClient.Get(datastore.cacheContext(), key).Scan(&p)
 	if err != nil {
 		log.Errorf("Error: %v on GetAvailablePairs %v\n", err, exchange)
 		return nil, err
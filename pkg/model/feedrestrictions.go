@@ -0,0 +1,149 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	// feedRestrictionInterval is the time for which feed restrictions are served from memory.
+	feedRestrictionInterval = getCacheTTL("FEED_RESTRICTION_INTERVAL_SECONDS", "60")
+
+	// feedRestrictions holds the restricted regions per feed in memory.
+	feedRestrictions struct {
+		sync.Mutex
+		regions map[string]map[string]struct{}
+		time    time.Time
+	}
+)
+
+// FeedRestriction prohibits the distribution of the data product @Feed in @Region.
+type FeedRestriction struct {
+	Feed   string `json:"Feed"`
+	Region string `json:"Region"`
+}
+
+// normalizeRegion returns @region as upper case code.
+func normalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
+
+// hashAPIKey returns the hex encoded sha256 digest of @apiKey, so that keys are not stored in clear.
+func hashAPIKey(apiKey string) string {
+	digest := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(digest[:])
+}
+
+// SetFeedRestriction prohibits the distribution of @feed in @region.
+func (rdb *RelDB) SetFeedRestriction(feed string, region string) error {
+	region = normalizeRegion(region)
+	if feed == "" || region == "" {
+		return errors.New("empty feed or region")
+	}
+	query := fmt.Sprintf("INSERT INTO %s (feed,region) VALUES ($1,$2) ON CONFLICT (feed,region) DO NOTHING", feedRestrictionTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, feed, region)
+	return err
+}
+
+// DeleteFeedRestriction allows the distribution of @feed in @region again.
+func (rdb *RelDB) DeleteFeedRestriction(feed string, region string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE feed=$1 AND region=$2", feedRestrictionTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, feed, normalizeRegion(region))
+	return err
+}
+
+// GetFeedRestrictions returns all feed restrictions ordered by feed and region.
+func (rdb *RelDB) GetFeedRestrictions() (restrictions []FeedRestriction, err error) {
+	query := fmt.Sprintf("SELECT feed,region FROM %s ORDER BY feed,region", feedRestrictionTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var restriction FeedRestriction
+		err = rows.Scan(&restriction.Feed, &restriction.Region)
+		if err != nil {
+			return
+		}
+		restrictions = append(restrictions, restriction)
+	}
+	err = rows.Err()
+	return
+}
+
+// SetAPIKeyRegion assigns @apiKey to @region. Only a digest of the key is stored.
+func (rdb *RelDB) SetAPIKeyRegion(apiKey string, region string) error {
+	region = normalizeRegion(region)
+	if apiKey == "" || region == "" {
+		return errors.New("empty api key or region")
+	}
+	query := fmt.Sprintf("INSERT INTO %s (api_key_hash,region) VALUES ($1,$2) ON CONFLICT (api_key_hash) DO UPDATE SET region=EXCLUDED.region", apiKeyRegionTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, hashAPIKey(apiKey), region)
+	return err
+}
+
+// GetAPIKeyRegion returns the region of @apiKey. Unknown keys have an empty region.
+func (rdb *RelDB) GetAPIKeyRegion(apiKey string) (region string, err error) {
+	if apiKey == "" {
+		return
+	}
+	query := fmt.Sprintf("SELECT region FROM %s WHERE api_key_hash=$1", apiKeyRegionTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, hashAPIKey(apiKey)).Scan(&region)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return
+}
+
+// IsFeedAvailable returns true if @feed may be distributed in @region. Restricted feeds are not
+// available in an unknown (empty) region. Restrictions are reloaded from postgres once they are
+// older than feedRestrictionInterval.
+func (rdb *RelDB) IsFeedAvailable(feed string, region string) (bool, error) {
+	feedRestrictions.Lock()
+	defer feedRestrictions.Unlock()
+
+	if feedRestrictions.regions == nil || time.Since(feedRestrictions.time) >= feedRestrictionInterval {
+		restrictions, err := rdb.GetFeedRestrictions()
+		if err != nil {
+			return false, err
+		}
+		feedRestrictions.regions = restrictedRegions(restrictions)
+		feedRestrictions.time = time.Now()
+	}
+	return feedAvailable(feedRestrictions.regions, feed, region), nil
+}
+
+// restrictedRegions maps each feed to its restricted regions.
+func restrictedRegions(restrictions []FeedRestriction) map[string]map[string]struct{} {
+	regions := make(map[string]map[string]struct{})
+	for _, restriction := range restrictions {
+		if _, ok := regions[restriction.Feed]; !ok {
+			regions[restriction.Feed] = make(map[string]struct{})
+		}
+		regions[restriction.Feed][normalizeRegion(restriction.Region)] = struct{}{}
+	}
+	return regions
+}
+
+func feedAvailable(regions map[string]map[string]struct{}, feed string, region string) bool {
+	restricted, ok := regions[feed]
+	if !ok {
+		return true
+	}
+	region = normalizeRegion(region)
+	if region == "" {
+		return false
+	}
+	_, ok = restricted[region]
+	return !ok
+}
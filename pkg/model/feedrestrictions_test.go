@@ -0,0 +1,27 @@
+package models
+
+import "testing"
+
+func TestFeedAvailable(t *testing.T) {
+	regions := restrictedRegions([]FeedRestriction{
+		{Feed: "foreignQuotation/CoinMarketCap", Region: "us"},
+		{Feed: "foreignQuotation/CoinMarketCap", Region: "CN"},
+	})
+	cases := []struct {
+		feed      string
+		region    string
+		available bool
+	}{
+		{"foreignQuotation/CoinMarketCap", "DE", true},
+		{"foreignQuotation/CoinMarketCap", "US", false},
+		{"foreignQuotation/CoinMarketCap", " cn ", false},
+		{"foreignQuotation/CoinMarketCap", "", false},
+		{"foreignQuotation/CoinGecko", "", true},
+		{"foreignQuotation/CoinGecko", "US", true},
+	}
+	for _, c := range cases {
+		if got := feedAvailable(regions, c.feed, c.region); got != c.available {
+			t.Errorf("feed %s in region %q: got %v, want %v", c.feed, c.region, got, c.available)
+		}
+	}
+}
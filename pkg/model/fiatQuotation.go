@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"time"
 
 	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
 )
@@ -58,7 +59,7 @@ func (datastore *DB) SetSingleFiatPriceRedis(fiatQuotation *FiatQuotation) error
 	key := getKeyQuotation(fiatQuotation.QuoteCurrency)
 	log.Info("setting ", key, fiatQuotation)
 
-	err = datastore.redisClient.Set(key, fiatQuotation, TimeOutRedis).Err()
+	err = datastore.redisClient.Set(datastore.cacheContext(), key, fiatQuotation, TimeOutRedis).Err()
 	if err != nil {
 		log.Printf("Error: %v on SetQuotation %v\n", err, fiatQuotation.QuoteCurrency)
 	}
@@ -72,3 +73,114 @@ func checkRedisIsAvailable(db *DB) error {
 	}
 	return nil
 }
+
+// fiatPivotCurrencies are the currencies against which fiat rates are published, such as the ECB
+// reference rates against EUR. Rates between other currencies are crossed through them.
+var fiatPivotCurrencies = []string{"EUR", "USD"}
+
+// SetFiatQuotation stores @fiatQuotation in influx. The price is the value of one unit of the quote
+// currency in units of the base currency, e.g. QuoteCurrency EUR, BaseCurrency USD and Price 1.08.
+func (datastore *DB) SetFiatQuotation(fiatQuotation *FiatQuotation) error {
+	return datastore.SetBatchFiatPriceInflux([]*FiatQuotation{fiatQuotation})
+}
+
+// GetFiatQuotation returns the latest rate of @quote in units of @base at or before @timestamp.
+// If no rate of the pair is stored, it is derived from the inverse pair or crossed through one of
+// the pivot currencies. The returned time is the oldest time of all rates used.
+func (datastore *DB) GetFiatQuotation(base string, quote string, timestamp time.Time) (*FiatQuotation, error) {
+	fq, err := resolveFiatQuotation(base, quote, func(base string, quote string) (FiatQuotation, error) {
+		return datastore.getFiatQuotationInflux(base, quote, timestamp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &fq, nil
+}
+
+// ConvertUSDPrice converts the USD price @priceUSD into fiat @currency using the rate at @timestamp.
+func (datastore *DB) ConvertUSDPrice(priceUSD float64, currency string, timestamp time.Time) (float64, error) {
+	fq, err := datastore.GetFiatQuotation("USD", currency, timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return priceUSD / fq.Price, nil
+}
+
+// getFiatQuotationInflux returns the latest stored rate of @quote in units of @base at or before @timestamp.
+func (datastore *DB) getFiatQuotationInflux(base string, quote string, timestamp time.Time) (fq FiatQuotation, err error) {
+	q := fmt.Sprintf("SELECT price,\"source\" FROM %s WHERE base_currency=$base AND quote_currency=$quote AND time<=$timestamp ORDER BY DESC LIMIT 1",
+		influxDbFiatQuotationsTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"base": base, "quote": quote, "timestamp": timestamp.UnixNano()})
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 || len(res[0].Series[0].Values) == 0 {
+		err = fmt.Errorf("no fiat quotation of %s in %s", quote, base)
+		return
+	}
+	row := res[0].Series[0].Values[0]
+	fq.Time, err = time.Parse(time.RFC3339, row[0].(string))
+	if err != nil {
+		return
+	}
+	fq.BaseCurrency = base
+	fq.QuoteCurrency = quote
+	fq.Price = influxFloat(row[1])
+	fq.Source, _ = row[2].(string)
+	if fq.Price <= 0 {
+		err = fmt.Errorf("invalid fiat quotation of %s in %s", quote, base)
+	}
+	return
+}
+
+// resolveFiatQuotation returns the rate of @quote in units of @base from the rates returned by @lookup,
+// using the pair itself, its inverse or a cross rate through a pivot currency, in this order.
+func resolveFiatQuotation(base string, quote string, lookup func(base string, quote string) (FiatQuotation, error)) (FiatQuotation, error) {
+	if base == quote {
+		return FiatQuotation{BaseCurrency: base, QuoteCurrency: quote, Price: 1, Time: time.Now()}, nil
+	}
+	direct := func(base string, quote string) (FiatQuotation, error) {
+		if base == quote {
+			return FiatQuotation{BaseCurrency: base, QuoteCurrency: quote, Price: 1}, nil
+		}
+		if fq, err := lookup(base, quote); err == nil {
+			return fq, nil
+		}
+		inverse, err := lookup(quote, base)
+		if err != nil {
+			return FiatQuotation{}, err
+		}
+		return FiatQuotation{BaseCurrency: base, QuoteCurrency: quote, Price: 1 / inverse.Price, Source: inverse.Source, Time: inverse.Time}, nil
+	}
+
+	fq, err := direct(base, quote)
+	if err == nil {
+		return fq, nil
+	}
+	for _, pivot := range fiatPivotCurrencies {
+		if pivot == base || pivot == quote {
+			continue
+		}
+		// quote in pivot times pivot in base is quote in base.
+		quotePivot, errQuote := direct(pivot, quote)
+		if errQuote != nil {
+			continue
+		}
+		pivotBase, errBase := direct(base, pivot)
+		if errBase != nil {
+			continue
+		}
+		cross := FiatQuotation{
+			BaseCurrency:  base,
+			QuoteCurrency: quote,
+			Price:         quotePivot.Price * pivotBase.Price,
+			Source:        quotePivot.Source,
+			Time:          quotePivot.Time,
+		}
+		if pivotBase.Time.Before(cross.Time) {
+			cross.Time = pivotBase.Time
+		}
+		return cross, nil
+	}
+	return FiatQuotation{}, err
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestResolveFiatQuotation(t *testing.T) {
+	now := time.Now()
+	// ECB reference rates: the value of one EUR in the respective currency.
+	rates := map[string]FiatQuotation{
+		"USD-EUR": {BaseCurrency: "USD", QuoteCurrency: "EUR", Price: 1.08, Time: now},
+		"GBP-EUR": {BaseCurrency: "GBP", QuoteCurrency: "EUR", Price: 0.86, Time: now.Add(-time.Hour)},
+	}
+	lookup := func(base string, quote string) (FiatQuotation, error) {
+		if fq, ok := rates[base+"-"+quote]; ok {
+			return fq, nil
+		}
+		return FiatQuotation{}, errors.New("not found")
+	}
+
+	for _, tc := range []struct {
+		base  string
+		quote string
+		price float64
+	}{
+		{"USD", "USD", 1},
+		{"USD", "EUR", 1.08},
+		{"EUR", "USD", 1 / 1.08},
+		{"USD", "GBP", 1.08 / 0.86},
+		{"GBP", "USD", 0.86 / 1.08},
+	} {
+		fq, err := resolveFiatQuotation(tc.base, tc.quote, lookup)
+		if err != nil {
+			t.Errorf("%s in %s: %v", tc.quote, tc.base, err)
+			continue
+		}
+		if math.Abs(fq.Price-tc.price) > 1e-9 {
+			t.Errorf("%s in %s is %v but should be %v", tc.quote, tc.base, fq.Price, tc.price)
+		}
+	}
+
+	// Cross rates are as old as the oldest rate they are derived from.
+	fq, _ := resolveFiatQuotation("USD", "GBP", lookup)
+	if !fq.Time.Equal(now.Add(-time.Hour)) {
+		t.Errorf("cross rate time is %v but should be %v", fq.Time, now.Add(-time.Hour))
+	}
+
+	if _, err := resolveFiatQuotation("USD", "JPY", lookup); err == nil {
+		t.Error("expected an error for an unknown currency")
+	}
+}
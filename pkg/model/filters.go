@@ -5,10 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
-	"github.com/go-redis/redis"
+	"github.com/go-redis/redis/v8"
 	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
 )
 
@@ -28,13 +29,18 @@ func (datastore *DB) SetFilter(filter string, asset dia.Asset, exchange string,
 
 func (datastore *DB) GetFilterPointsAsset(filter string, exchange string, address string, blockchain string, starttime time.Time, endtime time.Time) (*Points, error) {
 
-	exchangeQuery := "AND exchange='" + exchange + "' "
-
 	q := fmt.Sprintf("SELECT time,address,blockchain,exchange,filter,symbol,value FROM %s"+
-		" WHERE filter='%s' %s AND address='%s' and blockchain='%s' AND time>%d and time<=%d ORDER BY DESC",
-		influxDbFiltersTable, filter, exchangeQuery, address, blockchain, starttime.UnixNano(), endtime.UnixNano())
+		" WHERE filter=$filter AND exchange=$exchange AND address=$address and blockchain=$blockchain AND time>$starttime and time<=$endtime ORDER BY DESC",
+		influxDbFiltersTable)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"filter":     filter,
+		"exchange":   exchange,
+		"address":    address,
+		"blockchain": blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
 	if err != nil {
 		log.Errorln("GetFilterPoints", err)
 	}
@@ -65,25 +71,31 @@ func (datastore *DB) GetFilterPoints(filter string, exchange string, symbol stri
 	}
 	topAsset := sortedAssets[0]
 
-	exchangeQuery := "and exchange='" + exchange + "' "
 	table := ""
 	//	5m 30m 1h 4h 1d 1w
 	if scale != "" {
 		if filter == "VOL120" {
-			table = "a_year.filters_sum_"
+			table = "filters_sum_"
 		} else {
-			table = "a_year.filters_mean_"
+			table = "filters_mean_"
 		}
-		table = table + scale
+		table = "a_year." + quoteInfluxIdentifier(table+scale)
 	} else {
 		table = influxDbFiltersTable
 	}
 
 	q := fmt.Sprintf("SELECT time,exchange,filter,symbol,value FROM %s"+
-		" WHERE filter='%s' %sand address='%s' and blockchain='%s' and time>%d and time<%d ORDER BY DESC",
-		table, filter, exchangeQuery, topAsset.Address, topAsset.Blockchain, starttime.UnixNano(), endtime.UnixNano())
+		" WHERE filter=$filter and exchange=$exchange and address=$address and blockchain=$blockchain and time>$starttime and time<$endtime ORDER BY DESC",
+		table)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"filter":     filter,
+		"exchange":   exchange,
+		"address":    topAsset.Address,
+		"blockchain": topAsset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
 	if err != nil {
 		log.Errorln("GetFilterPoints", err)
 	}
@@ -101,20 +113,26 @@ func (datastore *DB) GetFilter(filter string, topAsset dia.Asset, scale string,
 	//	5m 30m 1h 4h 1d 1w
 	if scale != "" {
 		if filter == "VOL120" {
-			table = "a_year.filters_sum_"
+			table = "filters_sum_"
 		} else {
-			table = "a_year.filters_mean_"
+			table = "filters_mean_"
 		}
-		table = table + scale
+		table = "a_year." + quoteInfluxIdentifier(table+scale)
 	} else {
 		table = influxDbFiltersTable
 	}
 
 	q := fmt.Sprintf("SELECT last(*) FROM %s"+
-		" WHERE filter='%s' and address='%s' and blockchain='%s' and time>%d and time<%d and allExchanges=true group by time(1d) fill(previous) ORDER BY DESC",
-		table, filter, topAsset.Address, topAsset.Blockchain, starttime.UnixNano(), endtime.UnixNano())
+		" WHERE filter=$filter and address=$address and blockchain=$blockchain and time>$starttime and time<$endtime and allExchanges=true group by time(1d) fill(previous) ORDER BY DESC",
+		table)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"filter":     filter,
+		"address":    topAsset.Address,
+		"blockchain": topAsset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
 	if err != nil {
 		log.Errorln("GetFilterPoints", err)
 	}
@@ -155,6 +173,87 @@ func (datastore *DB) GetFilter(filter string, topAsset dia.Asset, scale string,
 	return allFilters, err
 }
 
+// filterAggregation returns the InfluxQL aggregation used when bucketing @filter. Volume filters
+// are summed over a bucket, all other filters keep the latest value.
+func filterAggregation(filter string) string {
+	if strings.HasPrefix(filter, "VOL") {
+		return "SUM"
+	}
+	return "LAST"
+}
+
+// GetFilterPointsRange returns the series of @filter for @asset on @exchange in (@starttime,@endtime], sorted
+// by time ascending. An empty @exchange yields the filter across all exchanges.
+// If @resolution is positive, values are bucketed accordingly and empty buckets are omitted.
+// Otherwise the raw filter points are returned.
+func (datastore *DB) GetFilterPointsRange(
+	filter string,
+	asset dia.Asset,
+	exchange string,
+	starttime time.Time,
+	endtime time.Time,
+	resolution time.Duration,
+) (filterPoints []dia.FilterPoint, err error) {
+	if !endtime.After(starttime) {
+		return nil, errors.New("endtime must be after starttime")
+	}
+	if resolution > 0 && resolution < time.Second {
+		return nil, fmt.Errorf("resolution %v is below one second", resolution)
+	}
+
+	var q string
+	if resolution > 0 {
+		source := influxDbFiltersTable
+		if filter == volumeKey && exchange == "" {
+			source = datastore.downsampledSource(influxDbFiltersTable, volumeFilterWhere, resolution, starttime)
+		}
+		q = fmt.Sprintf(`
+		SELECT %s(value)
+		FROM %s
+		WHERE filter=$filter AND address=$address AND blockchain=$blockchain AND exchange=$exchange
+		AND time>$starttime AND time<=$endtime
+		GROUP BY time(%ds) fill(none)
+		`, filterAggregation(filter), source, int64(resolution.Seconds()))
+	} else {
+		q = fmt.Sprintf(`
+		SELECT value
+		FROM %s
+		WHERE filter=$filter AND address=$address AND blockchain=$blockchain AND exchange=$exchange
+		AND time>$starttime AND time<=$endtime
+		`, influxDbFiltersTable)
+	}
+
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"filter":     filter,
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"exchange":   exchange,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return
+	}
+	for _, row := range res[0].Series[0].Values {
+		if len(row) < 2 || row[1] == nil {
+			continue
+		}
+		var fp dia.FilterPoint
+		fp.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return
+		}
+		fp.Value = influxFloat(row[1])
+		fp.Name = filter
+		fp.Asset = asset
+		filterPoints = append(filterPoints, fp)
+	}
+	return
+}
+
 // GetFilterAllExchanges returns a slice of quotations for each exchange the asset given by
 // @address and @blockchain has a filter value in the given time-range.
 // It returns the most recent filter value in the given time-range for each exchange resp.
@@ -168,18 +267,24 @@ func (datastore *DB) GetFilterAllExchanges(
 	q := fmt.Sprintf(`
 	SELECT time,address,blockchain,symbol,value
 	FROM %s
-	WHERE filter='%s'
+	WHERE filter=$filter
 	AND allExchanges=false
-	AND address='%s'
-	AND blockchain='%s' 
-	AND time>%d
-	AND time<=%d
+	AND address=$address
+	AND blockchain=$blockchain 
+	AND time>$starttime
+	AND time<=$endtime
 	GROUP BY "exchange"
 	ORDER BY DESC
 	LIMIT 1`,
-		influxDbFiltersTable, filter, address, blockchain, starttime.UnixNano(), endtime.UnixNano())
+		influxDbFiltersTable)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"filter":     filter,
+		"address":    address,
+		"blockchain": blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
 	if err != nil {
 		log.Errorln("GetFilterPoints", err)
 		return
@@ -254,7 +359,7 @@ func (datastore *DB) setZSETValue(key string, value float64, unixTime int64, max
 	}
 	member := strconv.FormatFloat(value, 'f', -1, 64) + " " + strconv.FormatInt(unixTime, 10)
 
-	err := datastore.redisPipe.ZAdd(key, redis.Z{
+	err := datastore.redisPipe.ZAdd(datastore.cacheContext(), key, &redis.Z{
 		Score:  float64(unixTime),
 		Member: member,
 	}).Err()
@@ -263,11 +368,11 @@ func (datastore *DB) setZSETValue(key string, value float64, unixTime int64, max
 		log.Errorf("Error: %v on SetZSETValue %v\n", err, key)
 	}
 	// purging old values
-	err = datastore.redisPipe.ZRemRangeByScore(key, "-inf", "("+strconv.FormatInt(unixTime-maxWindow, 10)).Err()
+	err = datastore.redisPipe.ZRemRangeByScore(datastore.cacheContext(), key, "-inf", "("+strconv.FormatInt(unixTime-maxWindow, 10)).Err()
 	if err != nil {
 		log.Errorf("Error: %v on SetZSETValue %v\n", err, key)
 	}
-	if err = datastore.redisPipe.Expire(key, TimeOutRedis).Err(); err != nil {
+	if err = datastore.redisPipe.Expire(datastore.cacheContext(), key, TimeOutRedis).Err(); err != nil {
 		log.Error(err)
 	} //TODO put two commands together ?
 	return err
@@ -277,7 +382,7 @@ func (datastore *DB) getZSETValue(key string, atUnixTime int64) (float64, error)
 
 	result := 0.0
 	max := strconv.FormatInt(atUnixTime, 10)
-	vals, err := datastore.redisClient.ZRangeByScoreWithScores(key, redis.ZRangeBy{
+	vals, err := datastore.redisClient.ZRangeByScoreWithScores(datastore.cacheContext(), key, &redis.ZRangeBy{
 		Min: "-inf",
 		Max: max,
 	}).Result()
@@ -299,7 +404,7 @@ func (datastore *DB) getZSETValue(key string, atUnixTime int64) (float64, error)
 func (datastore *DB) getZSETLastValue(key string) (float64, int64, error) {
 	value := 0.0
 	var unixTime int64
-	vals, err := datastore.redisClient.ZRange(key, -1, -1).Result()
+	vals, err := datastore.redisClient.ZRange(datastore.cacheContext(), key, -1, -1).Result()
 	log.Debug(key, "on getZSETLastValue:", vals)
 	if err == nil {
 		if len(vals) == 1 {
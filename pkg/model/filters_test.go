@@ -0,0 +1,16 @@
+package models
+
+import "testing"
+
+func TestFilterAggregation(t *testing.T) {
+	cases := map[string]string{
+		"VOL120":   "SUM",
+		"MA120":    "LAST",
+		"MEDIR120": "LAST",
+	}
+	for filter, want := range cases {
+		if got := filterAggregation(filter); got != want {
+			t.Errorf("filterAggregation(%s) = %s, want %s", filter, got, want)
+		}
+	}
+}
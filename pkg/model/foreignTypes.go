@@ -35,3 +35,13 @@ func (fq *ForeignQuotation) UnmarshalBinary(data []byte) error {
 	}
 	return nil
 }
+
+// QuotationComparison compares a DIA @Price with the @ForeignPrice from @Source at @Time.
+// @Deviation is the relative deviation of the DIA price from the foreign price.
+type QuotationComparison struct {
+	Time         time.Time `json:"Time"`
+	Price        float64   `json:"Price"`
+	ForeignPrice float64   `json:"ForeignPrice"`
+	Deviation    float64   `json:"Deviation"`
+	Source       string    `json:"Source"`
+}
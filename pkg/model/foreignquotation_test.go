@@ -0,0 +1,34 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCompareQuotations(t *testing.T) {
+	t0 := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	quotations := []AssetQuotation{
+		{Price: 102, Time: t0.Add(2 * time.Minute)},
+		{Price: 100, Time: t0},
+	}
+	foreignQuotations := []ForeignQuotation{
+		// Before any DIA quotation.
+		{Price: 99, Source: "CoinGecko", Time: t0.Add(-time.Minute)},
+		{Price: 100, Source: "CoinGecko", Time: t0.Add(time.Minute)},
+		{Price: 100, Source: "CoinGecko", Time: t0.Add(3 * time.Minute)},
+		// The latest DIA quotation is too old.
+		{Price: 100, Source: "CoinGecko", Time: t0.Add(2*time.Minute + foreignComparisonMaxLag + time.Second)},
+	}
+
+	comparisons := compareQuotations(quotations, foreignQuotations)
+	if len(comparisons) != 2 {
+		t.Fatalf("got %d comparisons but expected 2", len(comparisons))
+	}
+	if comparisons[0].Price != 100 || comparisons[0].Deviation != 0 {
+		t.Errorf("first comparison is %v with deviation %v but should be 100 with deviation 0", comparisons[0].Price, comparisons[0].Deviation)
+	}
+	if comparisons[1].Price != 102 || math.Abs(comparisons[1].Deviation-0.02) > 1e-9 {
+		t.Errorf("second comparison is %v with deviation %v but should be 102 with deviation 0.02", comparisons[1].Price, comparisons[1].Deviation)
+	}
+}
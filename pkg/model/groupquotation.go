@@ -0,0 +1,210 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/go-redis/redis/v8"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+// AssetGroupQuotation is the aggregate price of a logical asset, such as USDC across all chains.
+// @Price is the average of the prices of the linked assets weighted by their 24h volume.
+type AssetGroupQuotation struct {
+	GroupID      string    `json:"GroupID"`
+	Symbol       string    `json:"Symbol"`
+	Price        float64   `json:"Price"`
+	Volume       float64   `json:"Volume"`
+	Constituents int       `json:"Constituents"`
+	Time         time.Time `json:"Time"`
+}
+
+// MarshalBinary for group quotations
+func (gq *AssetGroupQuotation) MarshalBinary() ([]byte, error) {
+	return json.Marshal(gq)
+}
+
+// UnmarshalBinary for group quotations
+func (gq *AssetGroupQuotation) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, gq)
+}
+
+func getKeyAssetGroupQuotation(groupID string) string {
+	return "dia_assetgroupquotation_USD_" + groupID
+}
+
+// AggregateAssetGroupQuotation combines the @quotations of the assets of group @groupID into one quotation,
+// weighting each price by the corresponding entry of @volumes. Quotations without price or volume
+// are left out. The symbol is the one of the asset with the largest volume and the time is the
+// one of the most recent quotation.
+func AggregateAssetGroupQuotation(groupID string, quotations []AssetQuotation, volumes []float64) (AssetGroupQuotation, error) {
+	if len(quotations) != len(volumes) {
+		return AssetGroupQuotation{}, errors.New("number of quotations and volumes differ")
+	}
+	groupQuotation := AssetGroupQuotation{GroupID: groupID}
+	var weightedPrice, maxVolume float64
+	for i, quotation := range quotations {
+		if quotation.Price <= 0 || volumes[i] <= 0 {
+			continue
+		}
+		weightedPrice += quotation.Price * volumes[i]
+		groupQuotation.Volume += volumes[i]
+		groupQuotation.Constituents++
+		if volumes[i] > maxVolume {
+			maxVolume = volumes[i]
+			groupQuotation.Symbol = quotation.Asset.Symbol
+		}
+		if quotation.Time.After(groupQuotation.Time) {
+			groupQuotation.Time = quotation.Time
+		}
+	}
+	if groupQuotation.Constituents == 0 {
+		return AssetGroupQuotation{}, fmt.Errorf("no asset of group %s has price and volume", groupID)
+	}
+	groupQuotation.Price = weightedPrice / groupQuotation.Volume
+	return groupQuotation, nil
+}
+
+// GetAssetGroupID returns the id of the group @asset is linked to.
+func (rdb *RelDB) GetAssetGroupID(asset dia.Asset) (groupID string, err error) {
+	query := fmt.Sprintf(`
+	SELECT g.group_id::text
+	FROM %s g
+	INNER JOIN %s a
+	ON g.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	`, assetGroupTable, assetTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&groupID)
+	return
+}
+
+// GetAssetGroups returns the assets of all asset groups, keyed by group id, together with their last 24h volume.
+func (rdb *RelDB) GetAssetGroups() (groups map[string][]dia.AssetVolume, err error) {
+	query := fmt.Sprintf(`
+	SELECT g.group_id::text,a.symbol,a.name,a.address,a.decimals,a.blockchain,COALESCE(av.volume_usd,0)
+	FROM %s g
+	INNER JOIN %s a
+	ON g.asset_id=a.asset_id
+	LEFT JOIN %s av
+	ON g.asset_id=av.asset_id
+	ORDER BY g.group_id,a.blockchain,a.address
+	`, assetGroupTable, assetTable, assetVolumeTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	groups = make(map[string][]dia.AssetVolume)
+	for rows.Next() {
+		var (
+			groupID     string
+			assetVolume dia.AssetVolume
+			decimals    sql.NullInt64
+		)
+		err = rows.Scan(
+			&groupID,
+			&assetVolume.Asset.Symbol,
+			&assetVolume.Asset.Name,
+			&assetVolume.Asset.Address,
+			&decimals,
+			&assetVolume.Asset.Blockchain,
+			&assetVolume.Volume,
+		)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			assetVolume.Asset.Decimals = uint8(decimals.Int64)
+		}
+		groups[groupID] = append(groups[groupID], assetVolume)
+	}
+	err = rows.Err()
+	return
+}
+
+// SetAssetGroupQuotation stores @quotation in influx and, as latest quotation of its group, in the redis cache.
+func (datastore *DB) SetAssetGroupQuotation(quotation *AssetGroupQuotation) error {
+	tags := map[string]string{
+		"groupid": quotation.GroupID,
+		"symbol":  EscapeReplacer.Replace(quotation.Symbol),
+	}
+	fields := map[string]interface{}{
+		"price":        quotation.Price,
+		"volume":       quotation.Volume,
+		"constituents": quotation.Constituents,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDBAssetGroupQuotationsTable, tags, fields, quotation.Time)
+	if err != nil {
+		log.Errorln("SetAssetGroupQuotation:", err)
+	} else {
+		datastore.addPoint(pt)
+	}
+
+	if !cacheAvailable(datastore.redisClient) {
+		skipCacheWrite()
+		return nil
+	}
+	return datastore.redisPipe.Set(datastore.cacheContext(), getKeyAssetGroupQuotation(quotation.GroupID), quotation, TimeOutAssetQuotation).Err()
+}
+
+// GetAssetGroupQuotationLatest returns the latest quotation of the asset group @groupID.
+// It is read from the redis cache first and from influx on a miss.
+func (datastore *DB) GetAssetGroupQuotationLatest(groupID string) (*AssetGroupQuotation, error) {
+	if cacheAvailable(datastore.redisClient) {
+		quotation := &AssetGroupQuotation{}
+		err := datastore.redisClient.Get(datastore.cacheContext(), getKeyAssetGroupQuotation(groupID)).Scan(quotation)
+		if err == nil {
+			return quotation, nil
+		}
+		if checkCacheError(err) {
+			fallthroughCacheRead()
+		} else if !errors.Is(err, redis.Nil) {
+			return quotation, err
+		}
+	}
+	return datastore.GetAssetGroupQuotation(groupID, time.Now())
+}
+
+// GetAssetGroupQuotation returns the latest quotation of the asset group @groupID before @timestamp.
+func (datastore *DB) GetAssetGroupQuotation(groupID string, timestamp time.Time) (*AssetGroupQuotation, error) {
+	quotation := AssetGroupQuotation{GroupID: groupID}
+	q := fmt.Sprintf(
+		"SELECT price,volume,constituents,symbol FROM %s WHERE groupid=$groupid AND time<=$time ORDER BY DESC LIMIT 1",
+		influxDBAssetGroupQuotationsTable,
+	)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"groupid": groupID, "time": timestamp.UnixNano()})
+	if err != nil {
+		return &quotation, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 || len(res[0].Series[0].Values) == 0 {
+		return &quotation, errors.New("no asset group quotation in DB")
+	}
+	values := res[0].Series[0].Values[0]
+	quotation.Time, err = time.Parse(time.RFC3339, values[0].(string))
+	if err != nil {
+		return &quotation, err
+	}
+	quotation.Price, err = values[1].(json.Number).Float64()
+	if err != nil {
+		return &quotation, err
+	}
+	quotation.Volume, err = values[2].(json.Number).Float64()
+	if err != nil {
+		return &quotation, err
+	}
+	constituents, err := values[3].(json.Number).Int64()
+	if err != nil {
+		return &quotation, err
+	}
+	quotation.Constituents = int(constituents)
+	if symbol, ok := values[4].(string); ok {
+		quotation.Symbol = symbol
+	}
+	return &quotation, nil
+}
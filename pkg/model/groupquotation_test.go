@@ -0,0 +1,38 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestAggregateAssetGroupQuotation(t *testing.T) {
+	now := time.Now()
+	quotations := []AssetQuotation{
+		{Asset: dia.Asset{Symbol: "USDC", Blockchain: "Ethereum"}, Price: 1.00, Time: now.Add(-time.Minute)},
+		{Asset: dia.Asset{Symbol: "USDC.e", Blockchain: "Avalanche"}, Price: 0.98, Time: now},
+		{Asset: dia.Asset{Symbol: "USDC", Blockchain: "Polygon"}, Price: 0, Time: now},
+	}
+	volumes := []float64{3000, 1000, 5000}
+
+	groupQuotation, err := AggregateAssetGroupQuotation("group", quotations, volumes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(groupQuotation.Price-0.995) > 1e-9 {
+		t.Errorf("got price %v, want 0.995", groupQuotation.Price)
+	}
+	if groupQuotation.Volume != 4000 || groupQuotation.Constituents != 2 {
+		t.Errorf("got volume %v of %d constituents, want 4000 of 2", groupQuotation.Volume, groupQuotation.Constituents)
+	}
+	if groupQuotation.Symbol != "USDC" || !groupQuotation.Time.Equal(now) {
+		t.Errorf("got symbol %s at %v, want USDC at %v", groupQuotation.Symbol, groupQuotation.Time, now)
+	}
+
+	_, err = AggregateAssetGroupQuotation("group", quotations[2:], volumes[2:])
+	if err == nil {
+		t.Error("expected error for group without priced constituents")
+	}
+}
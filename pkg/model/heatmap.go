@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SectorAggregate summarizes all active assets tagged with @Sector in their metadata.
+// @AvgChange24h is the average relative price change in percent over all constituents with
+// historical quotations spanning 24 hours.
+type SectorAggregate struct {
+	Sector       string  `json:"Sector"`
+	Volume       float64 `json:"Volume"`
+	AvgChange24h float64 `json:"AvgChange24h"`
+	Constituents int     `json:"Constituents"`
+}
+
+// GetSectorAggregates returns the aggregates of all sectors, sorted by volume in descending order.
+// Sectors are the tags of the asset metadata. An asset with several tags contributes to each of its sectors.
+func (rdb *RelDB) GetSectorAggregates() (aggregates []SectorAggregate, err error) {
+	query := fmt.Sprintf(`
+	WITH sector AS (
+		SELECT DISTINCT am.asset_id,LOWER(t.tag) AS tag
+		FROM %s am
+		INNER JOIN %s a
+		ON am.asset_id=a.asset_id
+		CROSS JOIN LATERAL UNNEST(am.tags) AS t(tag)
+		WHERE a.deprecated_at IS NULL
+	)
+	SELECT s.tag,COUNT(*),COALESCE(SUM(av.volume_usd),0),AVG(CASE WHEN prev.price>0 THEN 100*(cur.price-prev.price)/prev.price END)
+	FROM sector s
+	LEFT JOIN %s av
+	ON s.asset_id=av.asset_id
+	LEFT JOIN LATERAL (
+		SELECT price,quote_time FROM %s WHERE asset_id=s.asset_id ORDER BY quote_time DESC LIMIT 1
+	) cur ON true
+	LEFT JOIN LATERAL (
+		SELECT price FROM %s WHERE asset_id=s.asset_id AND quote_time<=cur.quote_time-interval '24 hours' ORDER BY quote_time DESC LIMIT 1
+	) prev ON true
+	GROUP BY s.tag
+	ORDER BY 3 DESC
+	`, assetMetadataTable, assetTable, assetVolumeTable, historicalQuotationTable, historicalQuotationTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			aggregate SectorAggregate
+			change    sql.NullFloat64
+		)
+		err = rows.Scan(&aggregate.Sector, &aggregate.Constituents, &aggregate.Volume, &change)
+		if err != nil {
+			return
+		}
+		if change.Valid {
+			aggregate.AvgChange24h = change.Float64
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DownsampleRetentionPolicy is the retention policy downsampled series are written to. It keeps data forever,
+// such that raw measurements can be given a short retention period.
+const DownsampleRetentionPolicy = "downsampled"
+
+// DownsampleSeries aggregates the raw measurement @Source into @Measurement in buckets of length @Interval.
+// @Fields is the aggregation of the SELECT clause, @Where an optional condition on @Source and @Tags the tags
+// which are kept in the downsampled series.
+type DownsampleSeries struct {
+	Source          string
+	Measurement     string
+	RetentionPolicy string
+	Interval        time.Duration
+	Fields          string
+	Where           string
+	Tags            []string
+}
+
+var (
+	volumeFilterWhere       = fmt.Sprintf("filter='%s'", volumeKey)
+	volumeFilterDownsampled = []string{"filter", "address", "blockchain", "exchange"}
+)
+
+// DefaultDownsampleSeries are hourly and daily sums of the volume filter.
+// They keep the field and tags of the filters measurement, so volume queries can read them instead.
+var DefaultDownsampleSeries = []DownsampleSeries{
+	{Source: influxDbFiltersTable, Measurement: influxDbFiltersTable + "_vol_1h", RetentionPolicy: DownsampleRetentionPolicy, Interval: time.Hour, Fields: "SUM(value) AS value", Where: volumeFilterWhere, Tags: volumeFilterDownsampled},
+	{Source: influxDbFiltersTable, Measurement: influxDbFiltersTable + "_vol_1d", RetentionPolicy: DownsampleRetentionPolicy, Interval: 24 * time.Hour, Fields: "SUM(value) AS value", Where: volumeFilterWhere, Tags: volumeFilterDownsampled},
+}
+
+// continuousQueryName returns the name of the continuous query which fills @s.
+func (s DownsampleSeries) continuousQueryName() string {
+	return "cq_" + s.Measurement
+}
+
+// target returns the measurement of @s qualified with its retention policy.
+func (s DownsampleSeries) target() string {
+	if s.RetentionPolicy == "" {
+		return quoteInfluxIdentifier(s.Measurement)
+	}
+	return quoteInfluxIdentifier(s.RetentionPolicy) + "." + quoteInfluxIdentifier(s.Measurement)
+}
+
+// selectStatement returns the SELECT INTO statement of @s. @timeCondition restricts the source points and may be empty.
+func (s DownsampleSeries) selectStatement(timeCondition string) string {
+	var conditions []string
+	if s.Where != "" {
+		conditions = append(conditions, s.Where)
+	}
+	if timeCondition != "" {
+		conditions = append(conditions, timeCondition)
+	}
+
+	statement := fmt.Sprintf("SELECT %s INTO %s FROM %s", s.Fields, s.target(), quoteInfluxIdentifier(s.Source))
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	groupBy := []string{fmt.Sprintf("time(%s)", influxDuration(s.Interval))}
+	for _, tag := range s.Tags {
+		groupBy = append(groupBy, quoteInfluxIdentifier(tag))
+	}
+	return statement + " GROUP BY " + strings.Join(groupBy, ",")
+}
+
+// continuousQueryStatement returns the statement which creates the continuous query of @s.
+func (s DownsampleSeries) continuousQueryStatement() string {
+	return fmt.Sprintf("CREATE CONTINUOUS QUERY %s ON %s BEGIN %s END",
+		quoteInfluxIdentifier(s.continuousQueryName()),
+		quoteInfluxIdentifier(influxDbName),
+		s.selectStatement(""),
+	)
+}
+
+// InstallContinuousQueries (re)creates the continuous queries of all @series and creates missing retention policies
+// with infinite duration. Afterwards, range queries read from the installed series where they cover the queried range.
+// Continuous queries only cover data written after their installation, older data is filled by Downsample.
+func (datastore *DB) InstallContinuousQueries(series []DownsampleSeries) error {
+	policies, err := datastore.GetRetentionPolicies()
+	if err != nil {
+		return err
+	}
+	existingPolicies := make(map[string]bool)
+	for _, policy := range policies {
+		existingPolicies[policy.Name] = true
+	}
+	queries, err := datastore.GetContinuousQueries()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range series {
+		if s.RetentionPolicy != "" && !existingPolicies[s.RetentionPolicy] {
+			err = datastore.CreateRetentionPolicy(InfluxRetentionPolicy{Name: s.RetentionPolicy})
+			if err != nil {
+				return err
+			}
+			existingPolicies[s.RetentionPolicy] = true
+		}
+		if _, ok := queries[s.continuousQueryName()]; ok {
+			err = datastore.DropContinuousQuery(s.continuousQueryName())
+			if err != nil {
+				return err
+			}
+		}
+		_, err = queryInfluxDB(datastore.influxClient, s.continuousQueryStatement())
+		if err != nil {
+			return fmt.Errorf("create continuous query %s: %v", s.continuousQueryName(), err)
+		}
+	}
+
+	// The bucket running at installation is computed from the raw points once it is complete.
+	coverage := make(map[string]time.Time)
+	for _, s := range series {
+		coverage[s.Measurement] = time.Now().Truncate(s.Interval)
+		since, ok, err := datastore.downsampledSince(s)
+		if err != nil {
+			return err
+		}
+		if ok && since.Before(coverage[s.Measurement]) {
+			coverage[s.Measurement] = since
+		}
+	}
+	datastore.setDownsampledSeries(series, coverage)
+	return nil
+}
+
+// GetContinuousQueries returns the continuous queries of the influx database, mapped by name.
+func (datastore *DB) GetContinuousQueries() (map[string]string, error) {
+	queries := make(map[string]string)
+	res, err := queryInfluxDB(datastore.influxClient, "SHOW CONTINUOUS QUERIES")
+	if err != nil {
+		return queries, err
+	}
+	for _, result := range res {
+		for _, series := range result.Series {
+			if series.Name != influxDbName {
+				continue
+			}
+			for _, row := range series.Values {
+				if len(row) < 2 {
+					continue
+				}
+				name, _ := row[0].(string)
+				query, _ := row[1].(string)
+				queries[name] = query
+			}
+		}
+	}
+	return queries, nil
+}
+
+// DropContinuousQuery removes the continuous query @name from the influx database.
+func (datastore *DB) DropContinuousQuery(name string) error {
+	_, err := queryInfluxDB(datastore.influxClient, fmt.Sprintf("DROP CONTINUOUS QUERY %s ON %s", quoteInfluxIdentifier(name), quoteInfluxIdentifier(influxDbName)))
+	return err
+}
+
+// Downsample aggregates the points of @series.Source in [@starttime,@endtime) into the downsampled series.
+// It is used to backfill a series and as scheduled downsampling where continuous queries are not available.
+// @starttime and @endtime should be aligned to the interval of @series, as partial buckets are overwritten.
+// A backfill which reaches the covered range of @series extends it to @starttime.
+func (datastore *DB) Downsample(series DownsampleSeries, starttime time.Time, endtime time.Time) error {
+	timeCondition := fmt.Sprintf("time>=%d AND time<%d", starttime.UnixNano(), endtime.UnixNano())
+	_, err := datastore.queryInflux(series.selectStatement(timeCondition), nil)
+	if err != nil {
+		return err
+	}
+	datastore.downsampled.extend(series.Measurement, starttime, endtime)
+	return nil
+}
+
+// SetDownsampledSeries declares @series as filled, such that range queries read from them where possible.
+// It is needed by datastores which did not install the continuous queries themselves. The covered range of
+// each series starts at its earliest point, series without points are not read.
+func (datastore *DB) SetDownsampledSeries(series []DownsampleSeries) error {
+	coverage := make(map[string]time.Time)
+	for _, s := range series {
+		since, ok, err := datastore.downsampledSince(s)
+		if err != nil {
+			return err
+		}
+		if ok {
+			coverage[s.Measurement] = since
+		}
+	}
+	datastore.setDownsampledSeries(series, coverage)
+	return nil
+}
+
+// downsampledSince returns the time of the earliest point of @s. @ok is false if @s has no points.
+func (datastore *DB) downsampledSince(s DownsampleSeries) (since time.Time, ok bool, err error) {
+	res, err := queryInfluxDB(datastore.influxClient, fmt.Sprintf("SELECT * FROM %s ORDER BY time ASC LIMIT 1", s.target()))
+	if err != nil {
+		return since, false, fmt.Errorf("get earliest point of %s: %v", s.Measurement, err)
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 || len(res[0].Series[0].Values) == 0 {
+		return since, false, nil
+	}
+	timestamp, _ := res[0].Series[0].Values[0][0].(string)
+	since, err = time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return since, false, err
+	}
+	return since, true, nil
+}
+
+// downsampledCoverage holds the downsampled series of a datastore and the start of the range each of them covers.
+// It is shared by the copies of a datastore made by WithContext.
+type downsampledCoverage struct {
+	series []DownsampleSeries
+	since  map[string]time.Time
+	lock   sync.RWMutex
+}
+
+// setDownsampledSeries replaces the downsampled series of @datastore with @series covering the ranges since @coverage.
+func (datastore *DB) setDownsampledSeries(series []DownsampleSeries, coverage map[string]time.Time) {
+	if datastore.downsampled == nil {
+		datastore.downsampled = &downsampledCoverage{}
+	}
+	c := datastore.downsampled
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.series = series
+	c.since = coverage
+}
+
+// extend lets the covered range of @measurement start at @starttime if [@starttime,@endtime) reaches it.
+func (c *downsampledCoverage) extend(measurement string, starttime time.Time, endtime time.Time) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	since, ok := c.since[measurement]
+	if ok && starttime.Before(since) && !endtime.Before(since) {
+		c.since[measurement] = starttime
+	}
+}
+
+// downsampledSource returns the measurement from which a query on @source with condition @where aggregating
+// in buckets of @resolution since @starttime should read. This is the coarsest downsampled series whose interval
+// divides @resolution and which covers @starttime, or @source itself if there is none. Ranges reaching before
+// the earliest downsampled point are read from @source, as empty buckets of the downsampled series are no data.
+func (datastore *DB) downsampledSource(source string, where string, resolution time.Duration, starttime time.Time) string {
+	c := datastore.downsampled
+	if c == nil {
+		return source
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	var (
+		best  DownsampleSeries
+		found bool
+	)
+	for _, s := range c.series {
+		if s.Source != source || s.Where != where || s.Interval <= 0 || resolution%s.Interval != 0 {
+			continue
+		}
+		since, ok := c.since[s.Measurement]
+		if !ok || starttime.Before(since) {
+			continue
+		}
+		if !found || s.Interval > best.Interval {
+			best, found = s, true
+		}
+	}
+	if !found {
+		return source
+	}
+	return best.target()
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDownsampleStatements(t *testing.T) {
+	s := DownsampleSeries{
+		Source:          "filters",
+		Measurement:     "filters_vol_1h",
+		RetentionPolicy: DownsampleRetentionPolicy,
+		Interval:        time.Hour,
+		Fields:          "SUM(value) AS value",
+		Where:           "filter='VOL120'",
+		Tags:            []string{"address", "blockchain"},
+	}
+
+	want := `CREATE CONTINUOUS QUERY "cq_filters_vol_1h" ON "dia" BEGIN SELECT SUM(value) AS value INTO "downsampled"."filters_vol_1h" FROM "filters" WHERE filter='VOL120' GROUP BY time(3600s),"address","blockchain" END`
+	if got := s.continuousQueryStatement(); got != want {
+		t.Errorf("continuousQueryStatement() = %s, want %s", got, want)
+	}
+
+	want = `SELECT SUM(value) AS value INTO "downsampled"."filters_vol_1h" FROM "filters" WHERE filter='VOL120' AND time>=0 AND time<10 GROUP BY time(3600s),"address","blockchain"`
+	if got := s.selectStatement("time>=0 AND time<10"); got != want {
+		t.Errorf("selectStatement() = %s, want %s", got, want)
+	}
+}
+
+func TestDownsampledSource(t *testing.T) {
+	now := time.Now()
+	datastore := &DB{}
+	if got := datastore.downsampledSource(influxDbFiltersTable, volumeFilterWhere, 24*time.Hour, now); got != influxDbFiltersTable {
+		t.Errorf("without downsampled series got %s, want %s", got, influxDbFiltersTable)
+	}
+
+	datastore.setDownsampledSeries(DefaultDownsampleSeries, map[string]time.Time{
+		influxDbFiltersTable + "_vol_1h": now.AddDate(0, 0, -30),
+		influxDbFiltersTable + "_vol_1d": now.AddDate(0, 0, -7),
+	})
+	cases := []struct {
+		resolution time.Duration
+		starttime  time.Time
+		want       string
+	}{
+		{2 * time.Minute, now.AddDate(0, 0, -1), influxDbFiltersTable},
+		{time.Hour, now.AddDate(0, 0, -1), `"downsampled"."filters_vol_1h"`},
+		{6 * time.Hour, now.AddDate(0, 0, -1), `"downsampled"."filters_vol_1h"`},
+		{48 * time.Hour, now.AddDate(0, 0, -1), `"downsampled"."filters_vol_1d"`},
+		{48 * time.Hour, now.AddDate(0, 0, -14), `"downsampled"."filters_vol_1h"`},
+		{48 * time.Hour, now.AddDate(0, 0, -60), influxDbFiltersTable},
+	}
+	for _, c := range cases {
+		if got := datastore.downsampledSource(influxDbFiltersTable, volumeFilterWhere, c.resolution, c.starttime); got != c.want {
+			t.Errorf("downsampledSource for resolution %v since %v = %s, want %s", c.resolution, c.starttime, got, c.want)
+		}
+	}
+
+	// A backfill reaching the covered range extends it, a disjoint one does not.
+	datastore.downsampled.extend(influxDbFiltersTable+"_vol_1d", now.AddDate(0, 0, -90), now.AddDate(0, 0, -60))
+	if got := datastore.downsampledSource(influxDbFiltersTable, volumeFilterWhere, 48*time.Hour, now.AddDate(0, 0, -14)); got != `"downsampled"."filters_vol_1h"` {
+		t.Errorf("after disjoint backfill got %s", got)
+	}
+	datastore.downsampled.extend(influxDbFiltersTable+"_vol_1d", now.AddDate(0, 0, -90), now.AddDate(0, 0, -7))
+	if got := datastore.downsampledSource(influxDbFiltersTable, volumeFilterWhere, 48*time.Hour, now.AddDate(0, 0, -60)); got != `"downsampled"."filters_vol_1d"` {
+		t.Errorf("after backfill got %s", got)
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+func TestQueryInfluxBindsParams(t *testing.T) {
+	var query, params string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		query, params = r.FormValue("q"), r.FormValue("params")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"statement_id":0}]}`))
+	}))
+	defer server.Close()
+
+	client, err := clientInfluxdb.NewHTTPClient(clientInfluxdb.HTTPConfig{Addr: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	datastore := &DB{influxClient: client}
+
+	asset := dia.Asset{Address: "0x1' OR '1'='1", Blockchain: dia.ETHEREUM}
+	if _, err = datastore.GetVolumeInflux(asset, "", time.Unix(0, 0), time.Unix(60, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(query, asset.Address) {
+		t.Errorf("address is part of the query: %s", query)
+	}
+	var bound map[string]interface{}
+	if err = json.Unmarshal([]byte(params), &bound); err != nil {
+		t.Fatalf("decode params %q: %v", params, err)
+	}
+	if bound["address"] != asset.Address {
+		t.Errorf("bound address %v, want %s", bound["address"], asset.Address)
+	}
+}
+
+func TestTradeConditions(t *testing.T) {
+	params := map[string]interface{}{"address": "0x0"}
+	exchanges := exchangesCondition([]string{dia.BinanceExchange, dia.KrakenExchange}, params)
+	if exchanges != "(exchange=$p1 OR exchange=$p2)" {
+		t.Errorf("unexpected exchanges condition %s", exchanges)
+	}
+	assets := assetsCondition("basetoken", []dia.Asset{{Address: "0xa", Blockchain: dia.ETHEREUM}}, params)
+	if assets != "((basetokenaddress=$p3 AND basetokenblockchain=$p4)) " {
+		t.Errorf("unexpected assets condition %s", assets)
+	}
+	want := map[string]interface{}{
+		"address": "0x0",
+		"p1":      dia.BinanceExchange,
+		"p2":      dia.KrakenExchange,
+		"p3":      "0xa",
+		"p4":      dia.ETHEREUM,
+	}
+	for name, value := range want {
+		if params[name] != value {
+			t.Errorf("param %s = %v, want %v", name, params[name], value)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+// InfluxRetentionPolicy is a retention policy of the influx database.
+// A zero @Duration keeps data forever, a zero @ShardGroupDuration lets influx choose the shard duration.
+type InfluxRetentionPolicy struct {
+	Name               string        `json:"Name"`
+	Duration           time.Duration `json:"Duration"`
+	ShardGroupDuration time.Duration `json:"ShardGroupDuration"`
+	Replication        int           `json:"Replication"`
+	Default            bool          `json:"Default"`
+}
+
+// CreateRetentionPolicy creates @rp on the influx database.
+func (datastore *DB) CreateRetentionPolicy(rp InfluxRetentionPolicy) error {
+	statement, err := retentionPolicyStatement("CREATE", rp)
+	if err != nil {
+		return err
+	}
+	_, err = queryInfluxDB(datastore.influxClient, statement)
+	return err
+}
+
+// AlterRetentionPolicy sets duration, shard duration, replication and default flag of the existing policy @rp.Name.
+// Shortening the duration deletes all data older than the new duration at the next retention check.
+func (datastore *DB) AlterRetentionPolicy(rp InfluxRetentionPolicy) error {
+	statement, err := retentionPolicyStatement("ALTER", rp)
+	if err != nil {
+		return err
+	}
+	_, err = queryInfluxDB(datastore.influxClient, statement)
+	return err
+}
+
+// EnsureRetentionPolicy creates @rp if it does not exist yet and alters it otherwise.
+func (datastore *DB) EnsureRetentionPolicy(rp InfluxRetentionPolicy) error {
+	policies, err := datastore.GetRetentionPolicies()
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if policy.Name == rp.Name {
+			return datastore.AlterRetentionPolicy(rp)
+		}
+	}
+	return datastore.CreateRetentionPolicy(rp)
+}
+
+// GetRetentionPolicies returns all retention policies of the influx database.
+func (datastore *DB) GetRetentionPolicies() (policies []InfluxRetentionPolicy, err error) {
+	res, err := queryInfluxDB(datastore.influxClient, fmt.Sprintf("SHOW RETENTION POLICIES ON %s", quoteInfluxIdentifier(influxDbName)))
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return
+	}
+	series := res[0].Series[0]
+	for _, row := range series.Values {
+		var policy InfluxRetentionPolicy
+		policy, err = parseRetentionPolicy(series.Columns, row)
+		if err != nil {
+			return
+		}
+		policies = append(policies, policy)
+	}
+	return
+}
+
+// SetWriteRetentionPolicy makes all subsequent writes and reads of @measurement target the retention policy @rp.
+// Reads through the datastore are qualified with @rp, so data of @measurement in other policies is not read.
+// An empty @rp resets the measurement to the default retention policy of the database.
+func (datastore *DB) SetWriteRetentionPolicy(measurement string, rp string) {
+	if datastore.writeRetentionPolicies == nil {
+		datastore.writeRetentionPolicies = make(map[string]string)
+	}
+	if rp == "" {
+		delete(datastore.writeRetentionPolicies, measurement)
+		return
+	}
+	datastore.writeRetentionPolicies[measurement] = rp
+}
+
+// retentionPolicyOf returns the retention policy @measurement is written to and read from.
+// It is empty for measurements in the default retention policy.
+func (datastore *DB) retentionPolicyOf(measurement string) string {
+	return datastore.writeRetentionPolicies[measurement]
+}
+
+// qualifyMeasurements qualifies the measurements read by @cmd with their retention policy, see
+// SetWriteRetentionPolicy. Measurements in the default retention policy are left unqualified.
+func (datastore *DB) qualifyMeasurements(cmd string) string {
+	for measurement := range datastore.writeRetentionPolicies {
+		pattern := regexp.MustCompile(`(?i)(\bFROM\s+)(?:"` + regexp.QuoteMeta(measurement) + `"|` + regexp.QuoteMeta(measurement) + `\b)`)
+		qualified := quoteInfluxIdentifier(datastore.retentionPolicyOf(measurement)) + "." + quoteInfluxIdentifier(measurement)
+		cmd = pattern.ReplaceAllString(cmd, "${1}"+strings.ReplaceAll(qualified, "$", "$$"))
+	}
+	return cmd
+}
+
+// batchForPoint returns the batch @pt is added to, which depends on the retention policy of its measurement.
+func (datastore *DB) batchForPoint(pt *clientInfluxdb.Point) clientInfluxdb.BatchPoints {
+	rp := datastore.retentionPolicyOf(pt.Name())
+	if rp == "" {
+		return datastore.influxBatchPoints
+	}
+	if datastore.influxRPBatchPoints == nil {
+		datastore.influxRPBatchPoints = make(map[string]clientInfluxdb.BatchPoints)
+	}
+	bp, ok := datastore.influxRPBatchPoints[rp]
+	if !ok {
+		bp = createBatchInfluxRP(rp)
+		datastore.influxRPBatchPoints[rp] = bp
+	}
+	return bp
+}
+
+// retentionPolicyStatement returns the InfluxQL statement which creates or alters @rp, depending on @action.
+func retentionPolicyStatement(action string, rp InfluxRetentionPolicy) (string, error) {
+	if rp.Name == "" {
+		return "", errors.New("retention policy without name")
+	}
+	if rp.Duration < 0 || rp.ShardGroupDuration < 0 {
+		return "", fmt.Errorf("negative duration in retention policy %s", rp.Name)
+	}
+	replication := rp.Replication
+	if replication == 0 {
+		replication = 1
+	}
+
+	statement := fmt.Sprintf("%s RETENTION POLICY %s ON %s DURATION %s REPLICATION %d",
+		action,
+		quoteInfluxIdentifier(rp.Name),
+		quoteInfluxIdentifier(influxDbName),
+		influxDuration(rp.Duration),
+		replication,
+	)
+	if rp.ShardGroupDuration > 0 {
+		statement += " SHARD DURATION " + influxDuration(rp.ShardGroupDuration)
+	}
+	if rp.Default {
+		statement += " DEFAULT"
+	}
+	return statement, nil
+}
+
+// parseRetentionPolicy parses a row of SHOW RETENTION POLICIES with the given @columns.
+func parseRetentionPolicy(columns []string, row []interface{}) (rp InfluxRetentionPolicy, err error) {
+	for i, column := range columns {
+		if i >= len(row) {
+			break
+		}
+		switch column {
+		case "name":
+			rp.Name, _ = row[i].(string)
+		case "duration":
+			rp.Duration, err = time.ParseDuration(fmt.Sprint(row[i]))
+		case "shardGroupDuration":
+			rp.ShardGroupDuration, err = time.ParseDuration(fmt.Sprint(row[i]))
+		case "replicaN":
+			if n, ok := row[i].(json.Number); ok {
+				var replication int64
+				replication, err = n.Int64()
+				rp.Replication = int(replication)
+			}
+		case "default":
+			rp.Default, _ = row[i].(bool)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// influxDurationPattern matches InfluxQL duration literals such as 30s, 4h or 1w.
+var influxDurationPattern = regexp.MustCompile(`^[0-9]+(ns|u|µ|ms|s|m|h|d|w)$`)
+
+// influxDuration formats @d as an InfluxQL duration literal. A zero duration is infinite.
+func influxDuration(d time.Duration) string {
+	if d == 0 {
+		return "INF"
+	}
+	return fmt.Sprintf("%ds", int64(d.Seconds()))
+}
+
+// quoteInfluxIdentifier returns @identifier as a double quoted InfluxQL identifier.
+func quoteInfluxIdentifier(identifier string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(identifier) + `"`
+}
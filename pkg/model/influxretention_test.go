@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicyStatement(t *testing.T) {
+	cases := []struct {
+		action string
+		rp     InfluxRetentionPolicy
+		want   string
+	}{
+		{
+			action: "CREATE",
+			rp:     InfluxRetentionPolicy{Name: "raw", Duration: 30 * 24 * time.Hour},
+			want:   `CREATE RETENTION POLICY "raw" ON "dia" DURATION 2592000s REPLICATION 1`,
+		},
+		{
+			action: "ALTER",
+			rp:     InfluxRetentionPolicy{Name: "down\"sampled", ShardGroupDuration: 7 * 24 * time.Hour, Replication: 2, Default: true},
+			want:   `ALTER RETENTION POLICY "down\"sampled" ON "dia" DURATION INF REPLICATION 2 SHARD DURATION 604800s DEFAULT`,
+		},
+	}
+	for _, c := range cases {
+		got, err := retentionPolicyStatement(c.action, c.rp)
+		if err != nil {
+			t.Fatalf("retentionPolicyStatement(%s, %v): %v", c.action, c.rp, err)
+		}
+		if got != c.want {
+			t.Errorf("retentionPolicyStatement(%s, %v) = %s, want %s", c.action, c.rp, got, c.want)
+		}
+	}
+
+	if _, err := retentionPolicyStatement("CREATE", InfluxRetentionPolicy{}); err == nil {
+		t.Error("expected error for retention policy without name")
+	}
+}
+
+func TestParseRetentionPolicy(t *testing.T) {
+	columns := []string{"name", "duration", "shardGroupDuration", "replicaN", "default"}
+	row := []interface{}{"raw", "720h0m0s", "24h0m0s", json.Number("1"), true}
+
+	rp, err := parseRetentionPolicy(columns, row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := InfluxRetentionPolicy{Name: "raw", Duration: 720 * time.Hour, ShardGroupDuration: 24 * time.Hour, Replication: 1, Default: true}
+	if rp != want {
+		t.Errorf("parseRetentionPolicy = %v, want %v", rp, want)
+	}
+}
+
+func TestQualifyMeasurements(t *testing.T) {
+	datastore := &DB{}
+	datastore.SetWriteRetentionPolicy(influxDbTradesTable, "raw30d")
+
+	cases := []struct {
+		cmd      string
+		expected string
+	}{
+		{`SELECT price FROM trades WHERE time>now()-1h`, `SELECT price FROM "raw30d"."trades" WHERE time>now()-1h`},
+		{`SELECT price FROM "trades" WHERE time>now()-1h`, `SELECT price FROM "raw30d"."trades" WHERE time>now()-1h`},
+		{`SELECT MEAN(price) FROM (SELECT price from trades)`, `SELECT MEAN(price) FROM (SELECT price from "raw30d"."trades")`},
+		{`SELECT value FROM tradesDownsampled`, `SELECT value FROM tradesDownsampled`},
+		{`SELECT value FROM filters`, `SELECT value FROM filters`},
+	}
+	for _, c := range cases {
+		if cmd := datastore.qualifyMeasurements(c.cmd); cmd != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, cmd)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+// SetExchangeListing stores a listing or delisting announcement in postgres.
+// The announced asset is referenced if it can be found in the asset table. Repeated announcements
+// of a market with the same listing type and effective time are ignored, such that the first announcement
+// is kept. A zero effective time is stored as unknown.
+func (rdb *RelDB) SetExchangeListing(listing dia.ExchangeListing) error {
+	var effectiveTime sql.NullTime
+	if !listing.EffectiveTime.IsZero() {
+		effectiveTime = sql.NullTime{Time: listing.EffectiveTime, Valid: true}
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (exchange,foreignname,symbol,asset_id,listing_type,announced_time,effective_time,source)
+	VALUES ($1,$2,$3,(SELECT asset_id FROM %s WHERE address=$4 AND blockchain=$5),$6,$7,$8,$9)
+	ON CONFLICT (exchange,foreignname,listing_type,effective_time) DO NOTHING
+	`, exchangelistingTable, assetTable)
+	_, err := rdb.postgresClient.Exec(
+		context.Background(),
+		query,
+		listing.Exchange,
+		listing.ForeignName,
+		listing.Symbol,
+		listing.Asset.Address,
+		listing.Asset.Blockchain,
+		listing.Type,
+		listing.AnnouncedTime,
+		effectiveTime,
+		listing.Source,
+	)
+	return err
+}
+
+// GetExchangeListings returns all announcements with effective time in [@starttime,@endtime), most recent first.
+// Announcements without effective time are selected by their announcement time.
+// If @exchange is the empty string, announcements of all exchanges are returned.
+func (rdb *RelDB) GetExchangeListings(exchange string, starttime time.Time, endtime time.Time) ([]dia.ExchangeListing, error) {
+	query := fmt.Sprintf(`
+	SELECT el.exchange,el.foreignname,el.symbol,el.listing_type,el.announced_time,el.effective_time,el.source,a.symbol,a.name,a.address,a.blockchain,a.decimals
+	FROM %s el
+	LEFT JOIN %s a
+	ON el.asset_id=a.asset_id
+	WHERE COALESCE(el.effective_time,el.announced_time)>=$1 AND COALESCE(el.effective_time,el.announced_time)<$2
+	AND ($3='' OR el.exchange=$3)
+	ORDER BY COALESCE(el.effective_time,el.announced_time) DESC
+	`, exchangelistingTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, starttime, endtime, exchange)
+	if err != nil {
+		return []dia.ExchangeListing{}, err
+	}
+	defer rows.Close()
+	return scanExchangeListings(rows)
+}
+
+// GetUpcomingListings returns all listing announcements on @exchange whose market is not yet
+// in the exchangepair table. This allows the pair discovery to anticipate markets before trades appear.
+func (rdb *RelDB) GetUpcomingListings(exchange string) ([]dia.ExchangeListing, error) {
+	query := fmt.Sprintf(`
+	SELECT el.exchange,el.foreignname,el.symbol,el.listing_type,el.announced_time,el.effective_time,el.source,a.symbol,a.name,a.address,a.blockchain,a.decimals
+	FROM %s el
+	LEFT JOIN %s a
+	ON el.asset_id=a.asset_id
+	WHERE el.exchange=$1
+	AND el.listing_type=$2
+	AND NOT EXISTS (SELECT 1 FROM %s ep WHERE ep.exchange=el.exchange AND ep.foreignname=el.foreignname)
+	ORDER BY COALESCE(el.effective_time,el.announced_time) ASC
+	`, exchangelistingTable, assetTable, exchangepairTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange, dia.LISTING)
+	if err != nil {
+		return []dia.ExchangeListing{}, err
+	}
+	defer rows.Close()
+	return scanExchangeListings(rows)
+}
+
+func scanExchangeListings(rows pgx.Rows) (listings []dia.ExchangeListing, err error) {
+	for rows.Next() {
+		var (
+			listing       dia.ExchangeListing
+			symbol        sql.NullString
+			announcedTime sql.NullTime
+			effectiveTime sql.NullTime
+			source        sql.NullString
+			assetSymbol   sql.NullString
+			assetName     sql.NullString
+			address       sql.NullString
+			blockchain    sql.NullString
+			decimals      sql.NullInt64
+		)
+		err = rows.Scan(
+			&listing.Exchange,
+			&listing.ForeignName,
+			&symbol,
+			&listing.Type,
+			&announcedTime,
+			&effectiveTime,
+			&source,
+			&assetSymbol,
+			&assetName,
+			&address,
+			&blockchain,
+			&decimals,
+		)
+		if err != nil {
+			return
+		}
+		if symbol.Valid {
+			listing.Symbol = symbol.String
+		}
+		if announcedTime.Valid {
+			listing.AnnouncedTime = announcedTime.Time
+		}
+		if effectiveTime.Valid {
+			listing.EffectiveTime = effectiveTime.Time
+		}
+		if source.Valid {
+			listing.Source = source.String
+		}
+		if address.Valid {
+			listing.Asset = dia.Asset{
+				Symbol:     assetSymbol.String,
+				Name:       assetName.String,
+				Address:    address.String,
+				Blockchain: blockchain.String,
+			}
+			if decimals.Valid {
+				listing.Asset.Decimals = uint8(decimals.Int64)
+			}
+		}
+		listings = append(listings, listing)
+	}
+	return
+}
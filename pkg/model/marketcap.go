@@ -0,0 +1,207 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	keyMarketCap     = "dia_marketcap_"
+	keyTopMarketCaps = "dia_topmarketcaps_"
+)
+
+// MarketCap is the market cap in USD of @Asset, i.e. its latest @Price times its @CirculatingSupply.
+// @Time is the time of the price.
+type MarketCap struct {
+	Asset             dia.Asset `json:"Asset"`
+	Price             float64   `json:"Price"`
+	CirculatingSupply float64   `json:"CirculatingSupply"`
+	MarketCap         float64   `json:"MarketCap"`
+	Time              time.Time `json:"Time"`
+}
+
+// GetLatestSupplies returns the latest supply with positive circulating supply of all assets on
+// @blockchain, or on all blockchains if @blockchain is empty.
+func (rdb *RelDB) GetLatestSupplies(blockchain string) (supplies []dia.Supply, err error) {
+	qb := queryBuilder{clauses: []string{"s.circulating>0"}}
+	if blockchain != "" {
+		qb.where("a.blockchain=%s", blockchain)
+	}
+	query := fmt.Sprintf(`
+	SELECT DISTINCT ON (s.asset_id) a.symbol,a.name,a.address,a.blockchain,a.decimals,s.circulating,s.total,s.source,s.time_stamp
+	FROM %s s
+	INNER JOIN %s a
+	ON s.asset_id=a.asset_id
+	WHERE %s
+	ORDER BY s.asset_id,s.time_stamp DESC`,
+		supplyTable, assetTable, qb.conditions())
+	rows, err := rdb.postgresClient.Query(context.Background(), query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			supply   dia.Supply
+			decimals sql.NullInt64
+			total    sql.NullFloat64
+			source   sql.NullString
+		)
+		err = rows.Scan(
+			&supply.Asset.Symbol,
+			&supply.Asset.Name,
+			&supply.Asset.Address,
+			&supply.Asset.Blockchain,
+			&decimals,
+			&supply.CirculatingSupply,
+			&total,
+			&source,
+			&supply.Time,
+		)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			supply.Asset.Decimals = uint8(decimals.Int64)
+		}
+		supply.Supply = total.Float64
+		supply.Source = source.String
+		supplies = append(supplies, supply)
+	}
+	err = rows.Err()
+	return
+}
+
+// GetMarketCap returns the market cap of @asset from its latest price and the latest circulating supply
+// in postgres, falling back to the supply in redis. Results are cached for REDIS_TTL_MARKETCAP_SECONDS.
+func (datastore *DB) GetMarketCap(asset dia.Asset, relDB *RelDB) (marketCap MarketCap, err error) {
+	key := keyMarketCap + asset.Identifier()
+	if datastore.getMarketCapCache(key, &marketCap) {
+		return
+	}
+
+	supply, err := relDB.GetLatestSupply(asset)
+	if errors.Is(err, pgx.ErrNoRows) {
+		supply, err = datastore.GetSupplyCache(asset)
+	}
+	if err != nil {
+		return
+	}
+	quotation, err := datastore.GetAssetQuotationLatest(asset)
+	if err != nil {
+		return
+	}
+
+	marketCap = newMarketCap(*quotation, supply.CirculatingSupply)
+	datastore.setMarketCapCache(key, marketCap)
+	return
+}
+
+// GetTopAssetsByMarketCap returns the @limit assets on @blockchain, or on all blockchains if @blockchain is
+// empty, with the highest market caps. Only assets with a circulating supply in postgres and a cached
+// quotation are ranked. Results are cached for REDIS_TTL_MARKETCAP_SECONDS.
+func (datastore *DB) GetTopAssetsByMarketCap(limit int, blockchain string, relDB *RelDB) (marketCaps []MarketCap, err error) {
+	if limit <= 0 {
+		err = fmt.Errorf("invalid limit %d", limit)
+		return
+	}
+	key := fmt.Sprintf("%s%s_%d", keyTopMarketCaps, blockchain, limit)
+	if datastore.getMarketCapCache(key, &marketCaps) {
+		return
+	}
+
+	supplies, err := relDB.GetLatestSupplies(blockchain)
+	if err != nil {
+		return
+	}
+	circulating := make(map[string]float64)
+	var assets []dia.Asset
+	for _, supply := range supplies {
+		circulating[supply.Asset.Identifier()] = supply.CirculatingSupply
+		assets = append(assets, supply.Asset)
+	}
+	quotations, err := datastore.GetAssetQuotationsCache(assets)
+	if err != nil {
+		return
+	}
+
+	marketCaps = rankMarketCaps(quotations, circulating, limit)
+	datastore.setMarketCapCache(key, marketCaps)
+	return
+}
+
+// newMarketCap returns the market cap given by @quotation and @circulatingSupply.
+func newMarketCap(quotation AssetQuotation, circulatingSupply float64) MarketCap {
+	return MarketCap{
+		Asset:             quotation.Asset,
+		Price:             quotation.Price,
+		CirculatingSupply: circulatingSupply,
+		MarketCap:         quotation.Price * circulatingSupply,
+		Time:              quotation.Time,
+	}
+}
+
+// rankMarketCaps returns the @limit highest market caps of the assets quoted in @quotations, whose
+// circulating supplies are given in @circulating by asset identifier.
+func rankMarketCaps(quotations []AssetQuotation, circulating map[string]float64, limit int) []MarketCap {
+	marketCaps := []MarketCap{}
+	for _, quotation := range quotations {
+		supply, ok := circulating[quotation.Asset.Identifier()]
+		if !ok || quotation.Price <= 0 {
+			continue
+		}
+		marketCaps = append(marketCaps, newMarketCap(quotation, supply))
+	}
+	sort.SliceStable(marketCaps, func(i, j int) bool {
+		return marketCaps[i].MarketCap > marketCaps[j].MarketCap
+	})
+	if len(marketCaps) > limit {
+		marketCaps = marketCaps[:limit]
+	}
+	return marketCaps
+}
+
+// getMarketCapCache unmarshals the cached market caps under @key into @value and returns true on a hit.
+func (datastore *DB) getMarketCapCache(key string, value interface{}) bool {
+	if marketCapCacheTTL <= 0 || !cacheAvailable(datastore.redisClient) {
+		return false
+	}
+	data, err := datastore.redisClient.Get(datastore.cacheContext(), key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) && !checkCacheError(err) {
+			log.Warn("get market cap cache: ", err)
+		}
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+// setMarketCapCache stores @value under @key for marketCapCacheTTL.
+func (datastore *DB) setMarketCapCache(key string, value interface{}) {
+	if marketCapCacheTTL <= 0 {
+		return
+	}
+	if !cacheAvailable(datastore.redisClient) {
+		skipCacheWrite()
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Error("marshal market cap: ", err)
+		return
+	}
+	err = datastore.redisClient.Set(datastore.cacheContext(), key, data, marketCapCacheTTL).Err()
+	if err != nil && !checkCacheError(err) {
+		log.Warn("set market cap cache: ", err)
+	}
+}
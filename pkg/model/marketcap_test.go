@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestRankMarketCaps(t *testing.T) {
+	btc := dia.Asset{Symbol: "BTC", Address: "0x0000000000000000000000000000000000000000", Blockchain: dia.BITCOIN}
+	eth := dia.Asset{Symbol: "ETH", Address: "0x0000000000000000000000000000000000000000", Blockchain: dia.ETHEREUM}
+	usdc := dia.Asset{Symbol: "USDC", Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Blockchain: dia.ETHEREUM}
+	dai := dia.Asset{Symbol: "DAI", Address: "0x6B175474E89094C44Da98b954EedeAC495271d0F", Blockchain: dia.ETHEREUM}
+
+	quotations := []AssetQuotation{
+		{Asset: usdc, Price: 1},
+		{Asset: btc, Price: 20000},
+		{Asset: eth, Price: 1500},
+		// DAI has no supply and must be skipped.
+		{Asset: dai, Price: 1},
+	}
+	circulating := map[string]float64{
+		btc.Identifier():  19e6,
+		eth.Identifier():  120e6,
+		usdc.Identifier(): 40e9,
+	}
+
+	marketCaps := rankMarketCaps(quotations, circulating, 2)
+	if len(marketCaps) != 2 {
+		t.Fatalf("got %d market caps but expected 2", len(marketCaps))
+	}
+	if marketCaps[0].Asset.Symbol != "BTC" || marketCaps[1].Asset.Symbol != "ETH" {
+		t.Errorf("ranking is %s, %s but should be BTC, ETH", marketCaps[0].Asset.Symbol, marketCaps[1].Asset.Symbol)
+	}
+	if marketCaps[0].MarketCap != 20000*19e6 {
+		t.Errorf("market cap of BTC is %v but should be %v", marketCaps[0].MarketCap, 20000*19e6)
+	}
+
+	if marketCaps = rankMarketCaps(quotations, circulating, 10); len(marketCaps) != 3 {
+		t.Errorf("got %d market caps but expected 3", len(marketCaps))
+	}
+}
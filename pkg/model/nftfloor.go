@@ -0,0 +1,267 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// nftFloorQuantile is the quantile of the sale prices in a window which is taken as floor price.
+	nftFloorQuantile = 0.1
+	// nftFloorOutlierIQR is the multiple of the interquartile range beyond which sale prices are outliers.
+	nftFloorOutlierIQR = 1.5
+	// nftFloorMinOutlierSample is the minimal number of sales from which on outliers are filtered.
+	nftFloorMinOutlierSample = 4
+	// NFTFloorMaxAge is the age after which a stored floor price is stale and recomputed on read.
+	NFTFloorMaxAge = time.Hour
+)
+
+// NFTFloorWindows are the windows for which robust floor prices are computed and stored.
+var NFTFloorWindows = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// ValidNFTFloorWindow returns true if @window is one of the NFTFloorWindows.
+func ValidNFTFloorWindow(window time.Duration) bool {
+	for _, w := range NFTFloorWindows {
+		if w == window {
+			return true
+		}
+	}
+	return false
+}
+
+// NFTFloorPrice is a robust floor price of an NFT collection, computed from the sales in the window
+// [@Time-@Window, @Time]. @Floor is denominated in the payment currency, @FloorUSD in US dollars.
+type NFTFloorPrice struct {
+	NFTClass  dia.NFTClass  `json:"NFTClass"`
+	Floor     float64       `json:"Floor"`
+	FloorUSD  float64       `json:"FloorUSD"`
+	NumTrades int           `json:"NumTrades"`
+	Window    time.Duration `json:"Window"`
+	Time      time.Time     `json:"Time"`
+}
+
+// nftPaymentCurrencies returns the currencies whose sales count towards the floor price of collections
+// on @blockchain, i.e. the native asset and its wrapped version. It is nil on all other blockchains.
+func nftPaymentCurrencies(blockchain string) []dia.Asset {
+	switch blockchain {
+	case dia.ETHEREUM:
+		return []dia.Asset{
+			{Blockchain: dia.ETHEREUM, Address: dia.NATIVE_ASSET_ADDRESS},
+			{Blockchain: dia.ETHEREUM, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"},
+		}
+	case dia.ASTAR:
+		return []dia.Asset{
+			{Blockchain: dia.ASTAR, Address: dia.NATIVE_ASSET_ADDRESS},
+			{Blockchain: dia.ASTAR, Address: "0x9dA4A3a345bf6371f8e47c63Cad2293e532022dE"},
+		}
+	case dia.BINANCESMARTCHAIN:
+		return []dia.Asset{
+			{Blockchain: dia.BINANCESMARTCHAIN, Address: dia.NATIVE_ASSET_ADDRESS},
+			{Blockchain: dia.BINANCESMARTCHAIN, Address: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"},
+		}
+	}
+	return nil
+}
+
+// GetNFTFloorPrice computes the robust floor price of @nftclass from the non-bundle sales in the last @window,
+// which must be one of the NFTFloorWindows. Outliers are removed from the sale prices, and the 10th percentile
+// of the remaining prices is taken as floor, such that single sales far below market do not determine the floor.
+func (rdb *RelDB) GetNFTFloorPrice(nftclass dia.NFTClass, window time.Duration) (floorPrice NFTFloorPrice, err error) {
+	if !ValidNFTFloorWindow(window) {
+		err = fmt.Errorf("invalid floor window %v", window)
+		return
+	}
+	endtime := time.Now()
+	prices, pricesUSD, err := rdb.getNFTSalePrices(nftclass, endtime.Add(-window), endtime)
+	if err != nil {
+		return
+	}
+
+	floor, ok := robustFloor(prices, nftFloorQuantile)
+	if !ok {
+		err = fmt.Errorf("no sales of %s on %s in the last %v", nftclass.Address, nftclass.Blockchain, window)
+		return
+	}
+	floorUSD, _ := robustFloor(pricesUSD, nftFloorQuantile)
+
+	floorPrice = NFTFloorPrice{
+		NFTClass:  nftclass,
+		Floor:     floor,
+		FloorUSD:  floorUSD,
+		NumTrades: len(prices),
+		Window:    window,
+		Time:      endtime,
+	}
+	return
+}
+
+// GetRecentNFTFloorPrice returns the latest stored floor price of @nftclass computed with @window.
+// If it is missing or older than NFTFloorMaxAge, the floor price is computed instead, but not stored.
+func (rdb *RelDB) GetRecentNFTFloorPrice(nftclass dia.NFTClass, window time.Duration) (NFTFloorPrice, error) {
+	floorPrice, err := rdb.GetLastNFTFloorPrice(nftclass, window)
+	if err == nil && time.Since(floorPrice.Time) <= NFTFloorMaxAge {
+		return floorPrice, nil
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return floorPrice, err
+	}
+	return rdb.GetNFTFloorPrice(nftclass, window)
+}
+
+// UpdateNFTFloorPrices computes and stores the floor prices of all collections traded in the largest
+// of the NFTFloorWindows for each of the windows. It returns the number of stored floor prices.
+func (rdb *RelDB) UpdateNFTFloorPrices() (updated int, err error) {
+	maxWindow := NFTFloorWindows[len(NFTFloorWindows)-1]
+	nftClasses, err := rdb.GetTradedNFTClasses(time.Now().Add(-maxWindow))
+	if err != nil {
+		return
+	}
+	for _, nftclass := range nftClasses {
+		for _, window := range NFTFloorWindows {
+			floorPrice, err := rdb.GetNFTFloorPrice(nftclass, window)
+			if err != nil {
+				log.Warnf("compute floor price of %s on %s for window %v: %v", nftclass.Address, nftclass.Blockchain, window, err)
+				continue
+			}
+			if err = rdb.SetNFTFloorPrice(floorPrice); err != nil {
+				log.Errorf("store floor price of %s on %s: %v", nftclass.Address, nftclass.Blockchain, err)
+				continue
+			}
+			updated++
+		}
+	}
+	return
+}
+
+// SetNFTFloorPrice stores @floorPrice in postgres.
+func (rdb *RelDB) SetNFTFloorPrice(floorPrice NFTFloorPrice) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (nftclass_id,floor,floor_usd,num_trades,window_seconds,floor_time)
+	SELECT nftclass_id,$3,$4,$5,$6,$7 FROM %s WHERE address=$1 AND blockchain=$2
+	ON CONFLICT (nftclass_id,window_seconds,floor_time) DO UPDATE SET floor=EXCLUDED.floor,floor_usd=EXCLUDED.floor_usd,num_trades=EXCLUDED.num_trades`,
+		nftFloorTable, nftclassTable)
+	tag, err := rdb.postgresClient.Exec(
+		context.Background(),
+		query,
+		floorPrice.NFTClass.Address,
+		floorPrice.NFTClass.Blockchain,
+		floorPrice.Floor,
+		floorPrice.FloorUSD,
+		floorPrice.NumTrades,
+		int64(floorPrice.Window.Seconds()),
+		floorPrice.Time,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("nft class %s on %s not found", floorPrice.NFTClass.Address, floorPrice.NFTClass.Blockchain)
+	}
+	return nil
+}
+
+// GetLastNFTFloorPrice returns the latest stored floor price of @nftclass computed with @window.
+func (rdb *RelDB) GetLastNFTFloorPrice(nftclass dia.NFTClass, window time.Duration) (floorPrice NFTFloorPrice, err error) {
+	query := fmt.Sprintf(`
+	SELECT nc.symbol,nc.name,f.floor,f.floor_usd,f.num_trades,f.floor_time
+	FROM %s f INNER JOIN %s nc ON f.nftclass_id=nc.nftclass_id
+	WHERE nc.address=$1 AND nc.blockchain=$2 AND f.window_seconds=$3
+	ORDER BY f.floor_time DESC LIMIT 1`,
+		nftFloorTable, nftclassTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, nftclass.Address, nftclass.Blockchain, int64(window.Seconds())).Scan(
+		&floorPrice.NFTClass.Symbol,
+		&floorPrice.NFTClass.Name,
+		&floorPrice.Floor,
+		&floorPrice.FloorUSD,
+		&floorPrice.NumTrades,
+		&floorPrice.Time,
+	)
+	if err != nil {
+		return
+	}
+	floorPrice.NFTClass.Address = nftclass.Address
+	floorPrice.NFTClass.Blockchain = nftclass.Blockchain
+	floorPrice.Window = window
+	return
+}
+
+// getNFTSalePrices returns the prices in payment currency and in USD of all non-bundle sales of @nftclass
+// in (@starttime,@endtime]. On blockchains with payment currencies, only sales in these are returned.
+func (rdb *RelDB) getNFTSalePrices(nftclass dia.NFTClass, starttime time.Time, endtime time.Time) (prices []float64, pricesUSD []float64, err error) {
+	var qb queryBuilder
+	qb.where("nc.address=%s", nftclass.Address)
+	qb.where("nc.blockchain=%s", nftclass.Blockchain)
+	qb.where("tr.trade_time>%s", starttime)
+	qb.where("tr.trade_time<=%s", endtime)
+	if currencies := nftPaymentCurrencies(nftclass.Blockchain); currencies != nil {
+		var addresses []string
+		for _, currency := range currencies {
+			addresses = append(addresses, currency.Address)
+		}
+		qb.where("a.address=ANY(%s)", addresses)
+	}
+	query := fmt.Sprintf(`
+	SELECT tr.price::numeric/power(10,COALESCE(NULLIF(a.decimals,'')::int,18)),COALESCE(tr.price_usd,0)
+	FROM %s tr
+	INNER JOIN %s nc ON tr.nftclass_id=nc.nftclass_id
+	LEFT JOIN %s a ON tr.currency_id=a.asset_id
+	WHERE tr.bundle_sale=false AND %s`,
+		NfttradeCurrTable, nftclassTable, assetTable, qb.conditions())
+	rows, err := rdb.postgresClient.Query(context.Background(), query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var price, priceUSD float64
+		if err = rows.Scan(&price, &priceUSD); err != nil {
+			return
+		}
+		prices = append(prices, price)
+		if priceUSD > 0 {
+			pricesUSD = append(pricesUSD, priceUSD)
+		}
+	}
+	err = rows.Err()
+	return
+}
+
+// robustFloor returns the @quantile of @prices after removing outliers, which are prices further than
+// nftFloorOutlierIQR interquartile ranges away from the quartiles. It returns false if @prices is empty.
+func robustFloor(prices []float64, quantile float64) (float64, bool) {
+	if len(prices) == 0 {
+		return 0, false
+	}
+	sorted := make([]float64, len(prices))
+	copy(sorted, prices)
+	sort.Float64s(sorted)
+
+	if len(sorted) >= nftFloorMinOutlierSample {
+		q1, q3 := sortedQuantile(sorted, 0.25), sortedQuantile(sorted, 0.75)
+		lower, upper := q1-nftFloorOutlierIQR*(q3-q1), q3+nftFloorOutlierIQR*(q3-q1)
+		var filtered []float64
+		for _, price := range sorted {
+			if price >= lower && price <= upper {
+				filtered = append(filtered, price)
+			}
+		}
+		sorted = filtered
+	}
+	return sortedQuantile(sorted, quantile), true
+}
+
+// sortedQuantile returns the @quantile of the ascending @values, interpolating linearly between ranks.
+func sortedQuantile(values []float64, quantile float64) float64 {
+	rank := quantile * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return values[lower] + (rank-float64(lower))*(values[upper]-values[lower])
+}
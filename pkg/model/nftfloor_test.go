@@ -0,0 +1,44 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRobustFloor(t *testing.T) {
+	if _, ok := robustFloor(nil, nftFloorQuantile); ok {
+		t.Error("expected no floor without sales")
+	}
+
+	floor, ok := robustFloor([]float64{2}, nftFloorQuantile)
+	if !ok || floor != 2 {
+		t.Errorf("floor of a single sale is %v but should be 2", floor)
+	}
+
+	// The sale at 0.01 is far below market and must not determine the floor.
+	prices := []float64{10, 11, 12, 0.01, 13, 14, 15, 16, 17, 18, 19}
+	floor, ok = robustFloor(prices, nftFloorQuantile)
+	if !ok || math.Abs(floor-10.9) > 1e-9 {
+		t.Errorf("floor is %v but should be 10.9", floor)
+	}
+	if prices[3] != 0.01 {
+		t.Error("robustFloor must not reorder its input")
+	}
+}
+
+func TestSortedQuantile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+	for _, tc := range []struct {
+		quantile float64
+		expected float64
+	}{
+		{0, 1},
+		{0.5, 3},
+		{0.1, 1.4},
+		{1, 5},
+	} {
+		if q := sortedQuantile(values, tc.quantile); math.Abs(q-tc.expected) > 1e-9 {
+			t.Errorf("quantile %v is %v but should be %v", tc.quantile, q, tc.expected)
+		}
+	}
+}
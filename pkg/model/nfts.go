@@ -16,8 +16,6 @@ import (
 	"github.com/jackc/pgx/v4"
 )
 
-var currencyCache = make(map[string]dia.Asset)
-
 // SetNFTClass stores @nftClass in postgres.
 func (rdb *RelDB) SetNFTClass(nftClass dia.NFTClass) error {
 	query := fmt.Sprintf("INSERT INTO %s (address,symbol,name,blockchain,contract_type,category) VALUES ($1,$2,$3,$4,$5,NULLIF($6,''))", nftclassTable)
@@ -28,6 +26,7 @@ func (rdb *RelDB) SetNFTClass(nftClass dia.NFTClass) error {
 	return nil
 }
 
+// GetNFTClass returns the NFT class with contract @address on @blockchain.
 func (rdb *RelDB) GetNFTClass(address string, blockchain string) (nftclass dia.NFTClass, err error) {
 	query := fmt.Sprintf("SELECT symbol,name,contract_type,category FROM %s WHERE address=$1 AND blockchain=$2", nftclassTable)
 	var category sql.NullString
@@ -43,6 +42,7 @@ func (rdb *RelDB) GetNFTClass(address string, blockchain string) (nftclass dia.N
 	return
 }
 
+// GetNFTClassID returns the nftclass_id of the NFT class with contract @address on @blockchain.
 func (rdb *RelDB) GetNFTClassID(address string, blockchain string) (ID string, err error) {
 	query := fmt.Sprintf("SELECT nftclass_id FROM %s WHERE address=$1 AND blockchain=$2", nftclassTable)
 	err = rdb.postgresClient.QueryRow(context.Background(), query, address, blockchain).Scan(&ID)
@@ -52,15 +52,16 @@ func (rdb *RelDB) GetNFTClassID(address string, blockchain string) (ID string, e
 	return ID, nil
 }
 
+// GetNFTClassByID returns the NFT class with nftclass_id @id.
 func (rdb *RelDB) GetNFTClassByID(id string) (nftclass dia.NFTClass, err error) {
 	query := fmt.Sprintf("SELECT address,symbol,name,blockchain,contract_type,category FROM %s WHERE nftclass_id=$1", nftclassTable)
-	var category interface{}
+	var category sql.NullString
 	err = rdb.postgresClient.QueryRow(context.Background(), query, id).Scan(&nftclass.Address, &nftclass.Symbol, &nftclass.Name, &nftclass.Blockchain, &nftclass.ContractType, &category)
 	if err != nil {
 		return
 	}
-	if category != nil {
-		nftclass.Category = category.(string)
+	if category.Valid {
+		nftclass.Category = category.String
 	}
 	return
 }
@@ -148,13 +149,7 @@ func (rdb *RelDB) UpdateNFTClassCategory(nftclassID string, category string) (bo
 	if err != nil {
 		return false, err
 	}
-	var success bool
-	respSlice := strings.Split(string(resp), " ")
-	numUpdates := respSlice[1]
-	if numUpdates != "0" {
-		success = true
-	}
-	return success, nil
+	return resp.RowsAffected() > 0, nil
 }
 
 // GetNFTCategories returns all available NFT categories.
@@ -239,6 +234,65 @@ func (rdb *RelDB) GetNFTID(address string, blockchain string, tokenID string) (I
 	return
 }
 
+// GetNFTsByAttributes returns up to @limit NFTs of the class with contract @address on @blockchain, skipping the
+// first @offset, whose attributes contain all of @attributes, such as {"Background": "Blue"}.
+// NFTs are sorted by token id.
+func (rdb *RelDB) GetNFTsByAttributes(address string, blockchain string, attributes dia.NFTAttributes, limit int, offset int) (nfts []dia.NFT, err error) {
+	if blockchain == dia.ETHEREUM {
+		address = common.HexToAddress(address).Hex()
+	}
+	if attributes == nil {
+		attributes = dia.NFTAttributes{}
+	}
+	query := fmt.Sprintf(`
+	SELECT c.address,c.symbol,c.name,c.blockchain,c.contract_type,c.category,n.token_id,n.creation_time,n.creator_address,n.uri,n.attributes
+	FROM %s n
+	INNER JOIN %s c ON c.nftclass_id=n.nftclass_id
+	WHERE c.address=$1 AND c.blockchain=$2 AND n.attributes @> $3::jsonb
+	ORDER BY n.token_id
+	LIMIT $4 OFFSET $5`, nftTable, nftclassTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, address, blockchain, attributes, limit, offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			nft            dia.NFT
+			contractType   sql.NullString
+			category       sql.NullString
+			creationTime   sql.NullTime
+			creatorAddress sql.NullString
+			uri            sql.NullString
+		)
+		err = rows.Scan(
+			&nft.NFTClass.Address,
+			&nft.NFTClass.Symbol,
+			&nft.NFTClass.Name,
+			&nft.NFTClass.Blockchain,
+			&contractType,
+			&category,
+			&nft.TokenID,
+			&creationTime,
+			&creatorAddress,
+			&uri,
+			&nft.Attributes,
+		)
+		if err != nil {
+			return
+		}
+		nft.NFTClass.ContractType = contractType.String
+		nft.NFTClass.Category = category.String
+		nft.CreationTime = creationTime.Time
+		nft.CreatorAddress = creatorAddress.String
+		nft.URI = uri.String
+		nfts = append(nfts, nft)
+	}
+	err = rows.Err()
+	return
+}
+
 // GetLastBlockheightTopshot returns the last block number before timestamp given by @upperBound.
 func (rdb *RelDB) GetLastBlockheightTopshot(upperBound time.Time) (uint64, error) {
 	query := fmt.Sprintf("SELECT attributes FROM %s WHERE nftclass_id=(select nftclass_id FROM %s WHERE address='0x0b2a3299cc857e29' AND blockchain='Flow') ORDER BY creation_time DESC LIMIT 1;", nftTable, nftclassTable)
@@ -350,7 +404,7 @@ func (rdb *RelDB) GetNFTTradesCollection(address string, blockchain string, star
 		trade.Price = n
 
 		if currencyID.Valid {
-			if asset, ok := currencyCache[currencyID.String]; ok {
+			if asset, ok := rdb.assetCache.Get(currencyID.String); ok {
 				trade.Currency = asset
 			} else {
 				asset, err := rdb.GetAssetByID(currencyID.String)
@@ -358,7 +412,7 @@ func (rdb *RelDB) GetNFTTradesCollection(address string, blockchain string, star
 					log.Errorf("cannot fetch asset with postgres id %s", currencyID.String)
 				}
 				trade.Currency = asset
-				currencyCache[currencyID.String] = asset
+				rdb.assetCache.Add(currencyID.String, asset)
 			}
 		}
 		if tokenID.Valid {
@@ -421,7 +475,7 @@ func (rdb *RelDB) GetNFTTrades(address string, blockchain string, tokenID string
 		trade.Price = n
 
 		if currencyID.Valid {
-			if asset, ok := currencyCache[currencyID.String]; ok {
+			if asset, ok := rdb.assetCache.Get(currencyID.String); ok {
 				trade.Currency = asset
 			} else {
 				asset, err := rdb.GetAssetByID(currencyID.String)
@@ -429,7 +483,7 @@ func (rdb *RelDB) GetNFTTrades(address string, blockchain string, tokenID string
 					log.Errorf("cannot fetch asset with postgres id %s", currencyID.String)
 				}
 				trade.Currency = asset
-				currencyCache[currencyID.String] = asset
+				rdb.assetCache.Add(currencyID.String, asset)
 			}
 		}
 
@@ -603,19 +657,7 @@ func (rdb *RelDB) GetNFTFloor(
 	noBundles bool,
 	exchange string,
 ) (floor float64, err error) {
-	var paymentCurrencies []dia.Asset
-	switch nftclass.Blockchain {
-	case dia.ETHEREUM:
-		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.ETHEREUM, Address: "0x0000000000000000000000000000000000000000"})
-		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.ETHEREUM, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"})
-	case dia.ASTAR:
-		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.ASTAR, Address: "0x0000000000000000000000000000000000000000"})
-		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.ASTAR, Address: "0x9dA4A3a345bf6371f8e47c63Cad2293e532022dE"})
-	case dia.BINANCESMARTCHAIN:
-		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.BINANCESMARTCHAIN, Address: "0x0000000000000000000000000000000000000000"})
-		paymentCurrencies = append(paymentCurrencies, dia.Asset{Blockchain: dia.BINANCESMARTCHAIN, Address: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"})
-	}
-	return rdb.GetNFTFloorLevel(nftclass, timestamp, floorWindowSeconds, paymentCurrencies, float64(0), noBundles, exchange)
+	return rdb.GetNFTFloorLevel(nftclass, timestamp, floorWindowSeconds, nftPaymentCurrencies(nftclass.Blockchain), float64(0), noBundles, exchange)
 }
 
 // GetNFTFloorRecursive returns the floor price of @nftclass. If necessary, it iterates back in time until it finds a floor price.
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// PairReconciliation summarizes the reconciliation of the pairs reported by an exchange scraper with
+// the exchangepair table. @Added holds the foreign names of new and relisted pairs, @Delisted the
+// foreign names of listed pairs which were not reported anymore.
+type PairReconciliation struct {
+	Exchange  string    `json:"Exchange"`
+	Added     []string  `json:"Added"`
+	Delisted  []string  `json:"Delisted"`
+	Unchanged int       `json:"Unchanged"`
+	Time      time.Time `json:"Time"`
+}
+
+// ReconcileExchangePairs makes the listed pairs of @exchange match the @pairs currently reported by
+// its scraper. Unknown and delisted pairs are inserted and listed, listed pairs missing in @pairs are
+// marked as delisted. Pairs which are listed already are left unchanged, so their verification is kept.
+// As an empty scraper result usually means a failed request, @pairs must not be empty.
+func (rdb *RelDB) ReconcileExchangePairs(exchange string, pairs []dia.ExchangePair) (report PairReconciliation, err error) {
+	if len(pairs) == 0 {
+		return report, errors.New("no pairs reported")
+	}
+	pairs = uniqueExchangePairs(pairs)
+	report.Exchange = exchange
+
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		listed, err := txRDB.getExchangePairListing(exchange)
+		if err != nil {
+			return err
+		}
+
+		newPairs, unchanged := diffExchangePairs(listed, pairs)
+		err = txRDB.SetExchangePairs(exchange, newPairs)
+		if err != nil {
+			return err
+		}
+
+		foreignNames := make([]string, len(pairs))
+		for i, pair := range pairs {
+			foreignNames[i] = pair.ForeignName
+		}
+		query := fmt.Sprintf(`
+		UPDATE %s SET delisted_at=NOW()
+		WHERE exchange=$1 AND delisted_at IS NULL AND NOT (foreignname=ANY($2))
+		RETURNING foreignname
+		`, exchangepairTable)
+		rows, err := txRDB.postgresClient.Query(context.Background(), query, exchange, foreignNames)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		var delisted []string
+		for rows.Next() {
+			var foreignName string
+			if err = rows.Scan(&foreignName); err != nil {
+				return err
+			}
+			delisted = append(delisted, foreignName)
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		// The transaction may be retried, so the report is only filled by the final attempt.
+		report.Added = []string{}
+		for _, pair := range newPairs {
+			report.Added = append(report.Added, pair.ForeignName)
+		}
+		report.Delisted = append([]string{}, delisted...)
+		report.Unchanged = unchanged
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	report.Time = time.Now()
+
+	for _, foreignName := range report.Delisted {
+		if errCache := rdb.deleteExchangePairCache(exchange, foreignName); errCache != nil {
+			log.Errorf("delete delisted pair %s on %s from cache: %v", foreignName, exchange, errCache)
+		}
+	}
+	return
+}
+
+// getExchangePairListing returns all foreign names of pairs on @exchange in postgres, mapped to
+// true if the pair is listed and to false if it is delisted.
+func (rdb *RelDB) getExchangePairListing(exchange string) (map[string]bool, error) {
+	query := fmt.Sprintf("SELECT foreignname,delisted_at IS NULL FROM %s WHERE exchange=$1", exchangepairTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	listed := make(map[string]bool)
+	for rows.Next() {
+		var (
+			foreignName string
+			isListed    bool
+		)
+		if err = rows.Scan(&foreignName, &isListed); err != nil {
+			return nil, err
+		}
+		listed[foreignName] = isListed
+	}
+	return listed, rows.Err()
+}
+
+// diffExchangePairs returns the pairs in @pairs which are not listed according to @listed, and the
+// number of pairs which are listed already.
+func diffExchangePairs(listed map[string]bool, pairs []dia.ExchangePair) (newPairs []dia.ExchangePair, unchanged int) {
+	for _, pair := range pairs {
+		if listed[pair.ForeignName] {
+			unchanged++
+			continue
+		}
+		newPairs = append(newPairs, pair)
+	}
+	return
+}
@@ -82,13 +82,146 @@ func (rdb *RelDB) GetExchangePair(exchange string, foreignname string, caseSensi
 }
 
 // SetExchangePair adds @pair to exchangepair table.
+// The pair and its token references are written atomically.
 // If cache==true, it is also cached into redis
 func (rdb *RelDB) SetExchangePair(exchange string, pair dia.ExchangePair, cache bool) error {
+	var excluded bool
+	err := rdb.withTx(context.Background(), func(txRDB *RelDB) (err error) {
+		excluded, err = txRDB.setExchangePair(exchange, pair)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	if excluded {
+		pair.Verified = false
+	}
+	if cache {
+		err = rdb.SetExchangePairCache(exchange, pair)
+		if err != nil {
+			log.Errorf("setting pair %s to redis for exchange %s: %v", pair.ForeignName, exchange, err)
+		}
+	}
+	return nil
+}
+
+// SetExchangePairs adds @pairs to exchangepair table in a single transaction. Asset IDs of all
+// underlying tokens are resolved in one query and all rows are upserted in one statement, such
+// that a full pair sync of an exchange needs two round trips. Existing pairs are updated as in
+// SetExchangePair. If a foreign name appears more than once in @pairs, the last pair is written.
+func (rdb *RelDB) SetExchangePairs(exchange string, pairs []dia.ExchangePair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	pairs = uniqueExchangePairs(pairs)
+	return rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		var tokens []dia.Asset
+		for _, pair := range pairs {
+			tokens = append(tokens, pair.UnderlyingPair.QuoteToken, pair.UnderlyingPair.BaseToken)
+		}
+		assetIDs, err := txRDB.getAssetIDs(tokens)
+		if err != nil {
+			return err
+		}
+
+		var (
+			symbols       = make([]string, len(pairs))
+			foreignNames  = make([]string, len(pairs))
+			verified      = make([]bool, len(pairs))
+			excluded      = make([]bool, len(pairs))
+			quotetokenIDs = make([]*string, len(pairs))
+			basetokenIDs  = make([]*string, len(pairs))
+		)
+		for i, pair := range pairs {
+			symbols[i] = pair.Symbol
+			foreignNames[i] = pair.ForeignName
+			excluded[i] = dia.IsExcludedMarket(exchange, pair.ForeignName)
+			verified[i] = pair.Verified && !excluded[i]
+			if id, ok := assetIDs[pair.UnderlyingPair.QuoteToken.Identifier()]; ok {
+				quotetokenIDs[i] = &id
+			}
+			if id, ok := assetIDs[pair.UnderlyingPair.BaseToken.Identifier()]; ok {
+				basetokenIDs[i] = &id
+			}
+		}
+
+		query := fmt.Sprintf(`
+		INSERT INTO %[1]s (symbol,foreignname,exchange,verified,excluded,id_quotetoken,id_basetoken)
+		SELECT p.symbol,p.foreignname,$1,p.verified,p.excluded,p.id_quotetoken::uuid,p.id_basetoken::uuid
+		FROM unnest($2::text[],$3::text[],$4::boolean[],$5::boolean[],$6::text[],$7::text[]) AS p(symbol,foreignname,verified,excluded,id_quotetoken,id_basetoken)
+		ON CONFLICT (foreignname,exchange) DO UPDATE SET
+		verified=EXCLUDED.verified AND NOT %[1]s.excluded,
+		excluded=EXCLUDED.excluded OR %[1]s.excluded,
+		id_quotetoken=COALESCE(EXCLUDED.id_quotetoken,%[1]s.id_quotetoken),
+		id_basetoken=COALESCE(EXCLUDED.id_basetoken,%[1]s.id_basetoken),
+		delisted_at=NULL
+		`, exchangepairTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, exchange, symbols, foreignNames, verified, excluded, quotetokenIDs, basetokenIDs)
+		return err
+	})
+}
+
+// uniqueExchangePairs returns @pairs without duplicate foreign names, keeping the last occurrence
+// at the position of the first one.
+func uniqueExchangePairs(pairs []dia.ExchangePair) []dia.ExchangePair {
+	index := make(map[string]int, len(pairs))
+	var unique []dia.ExchangePair
+	for _, pair := range pairs {
+		if i, ok := index[pair.ForeignName]; ok {
+			unique[i] = pair
+			continue
+		}
+		index[pair.ForeignName] = len(unique)
+		unique = append(unique, pair)
+	}
+	return unique
+}
+
+// getAssetIDs returns the asset IDs of all @assets found in postgres, keyed by asset identifier.
+func (rdb *RelDB) getAssetIDs(assets []dia.Asset) (map[string]string, error) {
+	var addresses, blockchains []string
+	for _, asset := range assets {
+		if asset.Address == "" && asset.Blockchain == "" {
+			continue
+		}
+		addresses = append(addresses, asset.Address)
+		blockchains = append(blockchains, asset.Blockchain)
+	}
+	assetIDs := make(map[string]string)
+	if len(addresses) == 0 {
+		return assetIDs, nil
+	}
+
+	query := fmt.Sprintf(`
+	SELECT DISTINCT a.asset_id::text,a.address,a.blockchain
+	FROM %s a
+	INNER JOIN unnest($1::text[],$2::text[]) AS k(address,blockchain)
+	ON a.address=k.address AND a.blockchain=k.blockchain
+	`, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, addresses, blockchains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var asset dia.Asset
+		err = rows.Scan(&id, &asset.Address, &asset.Blockchain)
+		if err != nil {
+			return nil, err
+		}
+		assetIDs[asset.Identifier()] = id
+	}
+	return assetIDs, rows.Err()
+}
+
+// setExchangePair writes @pair to the exchangepair table and returns whether the pair is excluded.
+func (rdb *RelDB) setExchangePair(exchange string, pair dia.ExchangePair) (excluded bool, err error) {
 	var query string
 	query = fmt.Sprintf("INSERT INTO %s (symbol,foreignname,exchange) SELECT $1,$2,$3 WHERE NOT EXISTS (SELECT 1 FROM %s WHERE symbol=$1 AND foreignname=$2 AND exchange=$3)", exchangepairTable, exchangepairTable)
-	_, err := rdb.postgresClient.Exec(context.Background(), query, pair.Symbol, pair.ForeignName, exchange)
+	_, err = rdb.postgresClient.Exec(context.Background(), query, pair.Symbol, pair.ForeignName, exchange)
 	if err != nil {
-		return err
+		return
 	}
 	basetokenID, err := rdb.GetAssetID(pair.UnderlyingPair.BaseToken)
 	if err != nil {
@@ -102,28 +235,61 @@ func (rdb *RelDB) SetExchangePair(exchange string, pair dia.ExchangePair, cache
 		query = fmt.Sprintf("UPDATE %s SET id_basetoken='%s' WHERE foreignname='%s' AND exchange='%s'", exchangepairTable, basetokenID, pair.ForeignName, exchange)
 		_, err = rdb.postgresClient.Exec(context.Background(), query)
 		if err != nil {
-			return err
+			return
 		}
 	}
 	if quotetokenID != "" {
 		query = fmt.Sprintf("UPDATE %s SET id_quotetoken='%s' WHERE foreignname='%s' AND exchange='%s'", exchangepairTable, quotetokenID, pair.ForeignName, exchange)
 		_, err = rdb.postgresClient.Exec(context.Background(), query)
 		if err != nil {
-			return err
+			return
 		}
 	}
-	query = fmt.Sprintf("UPDATE %s SET verified='%v' WHERE foreignname='%s' AND exchange='%s'", exchangepairTable, pair.Verified, pair.ForeignName, exchange)
-	_, err = rdb.postgresClient.Exec(context.Background(), query)
+	// A pair which is set again is listed on the exchange, so a previous delisting is reverted.
+	// Excluded pairs are never verified.
+	query = fmt.Sprintf(`
+	UPDATE %s SET excluded=excluded OR $1,verified=$2 AND NOT (excluded OR $1),delisted_at=NULL
+	WHERE foreignname=$3 AND exchange=$4
+	RETURNING excluded
+	`, exchangepairTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, dia.IsExcludedMarket(exchange, pair.ForeignName), pair.Verified, pair.ForeignName, exchange).Scan(&excluded)
+	return
+}
+
+// DeleteExchangePair removes the pair with @foreignname on @exchange from postgres and from the cache.
+// If @markDelisted is true, the pair is kept and only marked as delisted. Delisted pairs are no
+// longer returned for scraping, but setting the pair again lists it anew.
+func (rdb *RelDB) DeleteExchangePair(exchange string, foreignname string, markDelisted bool) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE exchange=$1 AND foreignname=$2", exchangepairTable)
+	if markDelisted {
+		query = fmt.Sprintf("UPDATE %s SET delisted_at=COALESCE(delisted_at,NOW()) WHERE exchange=$1 AND foreignname=$2", exchangepairTable)
+	}
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, exchange, foreignname)
 	if err != nil {
 		return err
 	}
-	if cache {
-		err = rdb.SetExchangePairCache(exchange, pair)
-		if err != nil {
-			log.Errorf("setting pair %s to redis for exchange %s: %v", pair.ForeignName, exchange, err)
-		}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pair %s on %s not found", foreignname, exchange)
 	}
-	return nil
+	return rdb.deleteExchangePairCache(exchange, foreignname)
+}
+
+// SetExchangePairExcluded marks the pair with @foreignname on @exchange as excluded resp. not
+// excluded. Excluded pairs are unverified, so their trades do not contribute to prices. Pairs
+// classified as sandbox, testnet or demo markets by dia.ClassifyMarket cannot be included.
+func (rdb *RelDB) SetExchangePairExcluded(exchange string, foreignname string, excluded bool) error {
+	if !excluded && dia.IsExcludedMarket(exchange, foreignname) {
+		return fmt.Errorf("pair %s on %s is a %s market", foreignname, exchange, dia.ClassifyMarket(exchange, foreignname))
+	}
+	query := fmt.Sprintf("UPDATE %s SET excluded=$1,verified=verified AND NOT $1 WHERE exchange=$2 AND foreignname=$3", exchangepairTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, excluded, exchange, foreignname)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pair %s on %s not found", foreignname, exchange)
+	}
+	return rdb.deleteExchangePairCache(exchange, foreignname)
 }
 
 // GetExchangePairSeparator returns the separator that is used as notation for an exchange pair.
@@ -158,9 +324,10 @@ func (rdb *RelDB) GetExchangePairSeparator(exchange string) (string, error) {
 	return separator, nil
 }
 
-// GetExchangePairSymbols returns all foreign names on @exchange from exchangepair table.
+// GetExchangePairSymbols returns all foreign names of listed pairs on @exchange from exchangepair table.
+// Excluded pairs are omitted.
 func (rdb *RelDB) GetExchangePairSymbols(exchange string) (pairs []dia.ExchangePair, err error) {
-	query := fmt.Sprintf("SELECT symbol,foreignname FROM %s WHERE exchange=$1", exchangepairTable)
+	query := fmt.Sprintf("SELECT symbol,foreignname FROM %s WHERE exchange=$1 AND delisted_at IS NULL AND NOT excluded", exchangepairTable)
 	var rows pgx.Rows
 	rows, err = rdb.postgresClient.Query(context.Background(), query, exchange)
 	if err != nil {
@@ -179,6 +346,171 @@ func (rdb *RelDB) GetExchangePairSymbols(exchange string) (pairs []dia.ExchangeP
 	return
 }
 
+// GetExcludedExchangePairs returns all pairs which are marked as excluded in the exchangepair table,
+// either by the static exclusion list or manually through SetExchangePairExcluded.
+func (rdb *RelDB) GetExcludedExchangePairs() (pairs []dia.ExchangePair, err error) {
+	query := fmt.Sprintf("SELECT exchange,symbol,foreignname FROM %s WHERE excluded", exchangepairTable)
+	var rows pgx.Rows
+	rows, err = rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pair dia.ExchangePair
+		err = rows.Scan(&pair.Exchange, &pair.Symbol, &pair.ForeignName)
+		if err != nil {
+			return
+		}
+		pairs = append(pairs, pair)
+	}
+	return
+}
+
+// GetAllExchangePairs returns up to @limit pairs on @exchange ordered by foreign name, starting at @offset.
+// Verified flag and underlying tokens are resolved in a single query. Tokens which are not yet
+// assigned to a pair are left empty.
+func (rdb *RelDB) GetAllExchangePairs(exchange string, limit int, offset int) (pairs []dia.ExchangePair, err error) {
+	query := fmt.Sprintf(`
+		SELECT ep.symbol,ep.foreignname,ep.verified,a.symbol,a.name,a.address,a.blockchain,a.decimals,b.symbol,b.name,b.address,b.blockchain,b.decimals
+		FROM %s ep
+		LEFT JOIN %s a
+		ON ep.id_quotetoken=a.asset_id
+		LEFT JOIN %s b
+		ON ep.id_basetoken=b.asset_id
+		WHERE ep.exchange=$1
+		ORDER BY ep.foreignname ASC
+		LIMIT $2 OFFSET $3`,
+		exchangepairTable,
+		assetTable,
+		assetTable,
+	)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange, limit, offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pair       = dia.ExchangePair{Exchange: exchange}
+			verified   sql.NullBool
+			quoteToken nullAsset
+			baseToken  nullAsset
+		)
+		err = rows.Scan(
+			&pair.Symbol,
+			&pair.ForeignName,
+			&verified,
+			&quoteToken.symbol,
+			&quoteToken.name,
+			&quoteToken.address,
+			&quoteToken.blockchain,
+			&quoteToken.decimals,
+			&baseToken.symbol,
+			&baseToken.name,
+			&baseToken.address,
+			&baseToken.blockchain,
+			&baseToken.decimals,
+		)
+		if err != nil {
+			return
+		}
+		pair.Verified = verified.Bool
+		pair.UnderlyingPair.QuoteToken = quoteToken.asset()
+		pair.UnderlyingPair.BaseToken = baseToken.asset()
+		pairs = append(pairs, pair)
+	}
+	err = rows.Err()
+	return
+}
+
+// GetUnverifiedExchangePairs returns all listed pairs which are not verified or whose underlying tokens
+// are not assigned yet, ordered by exchange and foreign name. If @exchange is empty, pairs on all
+// exchanges are returned. Assigned tokens are resolved as in GetAllExchangePairs.
+func (rdb *RelDB) GetUnverifiedExchangePairs(exchange string) (pairs []dia.ExchangePair, err error) {
+	qb := queryBuilder{clauses: []string{
+		"ep.delisted_at IS NULL",
+		"(ep.verified IS NOT TRUE OR ep.id_quotetoken IS NULL OR ep.id_basetoken IS NULL)",
+	}}
+	if exchange != "" {
+		qb.where("ep.exchange=%s", exchange)
+	}
+	query := fmt.Sprintf(`
+		SELECT ep.exchange,ep.symbol,ep.foreignname,ep.verified,a.symbol,a.name,a.address,a.blockchain,a.decimals,b.symbol,b.name,b.address,b.blockchain,b.decimals
+		FROM %s ep
+		LEFT JOIN %s a
+		ON ep.id_quotetoken=a.asset_id
+		LEFT JOIN %s b
+		ON ep.id_basetoken=b.asset_id
+		WHERE %s
+		ORDER BY ep.exchange,ep.foreignname ASC`,
+		exchangepairTable,
+		assetTable,
+		assetTable,
+		qb.conditions(),
+	)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pair       dia.ExchangePair
+			verified   sql.NullBool
+			quoteToken nullAsset
+			baseToken  nullAsset
+		)
+		err = rows.Scan(
+			&pair.Exchange,
+			&pair.Symbol,
+			&pair.ForeignName,
+			&verified,
+			&quoteToken.symbol,
+			&quoteToken.name,
+			&quoteToken.address,
+			&quoteToken.blockchain,
+			&quoteToken.decimals,
+			&baseToken.symbol,
+			&baseToken.name,
+			&baseToken.address,
+			&baseToken.blockchain,
+			&baseToken.decimals,
+		)
+		if err != nil {
+			return
+		}
+		pair.Verified = verified.Bool
+		pair.UnderlyingPair.QuoteToken = quoteToken.asset()
+		pair.UnderlyingPair.BaseToken = baseToken.asset()
+		pairs = append(pairs, pair)
+	}
+	err = rows.Err()
+	return
+}
+
+// nullAsset scans the columns of an asset from an outer join.
+type nullAsset struct {
+	symbol     sql.NullString
+	name       sql.NullString
+	address    sql.NullString
+	blockchain sql.NullString
+	decimals   sql.NullInt64
+}
+
+func (na nullAsset) asset() dia.Asset {
+	return dia.Asset{
+		Symbol:     na.symbol.String,
+		Name:       na.name.String,
+		Address:    na.address.String,
+		Blockchain: na.blockchain.String,
+		Decimals:   uint8(na.decimals.Int64),
+	}
+}
+
 // GetExchangePairs returns all pairs on a (centralized) @exchange.
 func (rdb *RelDB) GetPairsForExchange(exchange dia.Exchange, filterVerified bool, verified bool) ([]dia.ExchangePair, error) {
 	var pairs []dia.ExchangePair
@@ -195,7 +527,7 @@ func (rdb *RelDB) GetPairsForExchange(exchange dia.Exchange, filterVerified bool
 		ON e.id_quotetoken=a.asset_id 
 		INNER JOIN %s b 
 		ON e.id_basetoken=b.asset_id 
-		WHERE e.exchange='%s'`,
+		WHERE e.exchange='%s' AND e.delisted_at IS NULL`,
 		exchangepairTable,
 		assetTable,
 		assetTable,
@@ -250,7 +582,13 @@ func (rdb *RelDB) GetPairsForExchange(exchange dia.Exchange, filterVerified bool
 	return pairs, nil
 }
 
+// GetPairsForAsset returns all exchangepairs with @asset as quote or base token.
+// If @filterVerified is true, only pairs with verified status @verified are returned.
 func (rdb *RelDB) GetPairsForAsset(asset dia.Asset, filterVerified bool, verified bool) ([]dia.ExchangePair, error) {
+	return getPairsForAsset(rdb.postgresClient, asset, filterVerified, verified)
+}
+
+func getPairsForAsset(q pgQuerier, asset dia.Asset, filterVerified bool, verified bool) ([]dia.ExchangePair, error) {
 	var pairs []dia.ExchangePair
 
 	query := fmt.Sprintf(`
@@ -273,7 +611,7 @@ func (rdb *RelDB) GetPairsForAsset(asset dia.Asset, filterVerified bool, verifie
 		query += fmt.Sprintf(" AND e.verified='%v'", verified)
 	}
 
-	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	rows, err := q.Query(context.Background(), query)
 	if err != nil {
 		return pairs, err
 	}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestUniqueExchangePairs(t *testing.T) {
+	pairs := []dia.ExchangePair{
+		{ForeignName: "BTC-USDT", Verified: false},
+		{ForeignName: "ETH-USDT"},
+		{ForeignName: "BTC-USDT", Verified: true},
+	}
+	unique := uniqueExchangePairs(pairs)
+	if len(unique) != 2 || unique[0].ForeignName != "BTC-USDT" || !unique[0].Verified || unique[1].ForeignName != "ETH-USDT" {
+		t.Errorf("unexpected pairs %+v", unique)
+	}
+}
+
+func TestDiffExchangePairs(t *testing.T) {
+	listed := map[string]bool{"BTC-USDT": true, "ETH-USDT": false}
+	pairs := []dia.ExchangePair{
+		{ForeignName: "BTC-USDT"},
+		{ForeignName: "ETH-USDT"},
+		{ForeignName: "SOL-USDT"},
+	}
+	newPairs, unchanged := diffExchangePairs(listed, pairs)
+	if unchanged != 1 || len(newPairs) != 2 || newPairs[0].ForeignName != "ETH-USDT" || newPairs[1].ForeignName != "SOL-USDT" {
+		t.Errorf("unexpected diff %+v, %d unchanged", newPairs, unchanged)
+	}
+}
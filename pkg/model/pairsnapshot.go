@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+// SnapshotExchangePairs records the set of verified exchangepairs at @timestamp.
+// Snapshots are stored as validity intervals: a pair's interval is opened once it is verified with a
+// given pair of tokens and closed once it is falsified or its tokens change. Hence unchanged pairs do not
+// take additional space. It returns the number of opened and closed intervals.
+func (rdb *RelDB) SnapshotExchangePairs(timestamp time.Time) (opened int64, closed int64, err error) {
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		query := fmt.Sprintf(`
+		UPDATE %s h SET valid_to=$1
+		WHERE h.valid_to IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM %s ep
+			WHERE ep.verified=true AND ep.delisted_at IS NULL
+			AND ep.exchange=h.exchange AND ep.foreignname=h.foreignname
+			AND ep.id_quotetoken IS NOT DISTINCT FROM h.id_quotetoken
+			AND ep.id_basetoken IS NOT DISTINCT FROM h.id_basetoken
+		)
+		`, pairHistoryTable, exchangepairTable)
+		tag, errSnapshot := txRDB.postgresClient.Exec(context.Background(), query, timestamp)
+		if errSnapshot != nil {
+			return errSnapshot
+		}
+		closed = tag.RowsAffected()
+
+		query = fmt.Sprintf(`
+		INSERT INTO %s (exchange,foreignname,symbol,id_quotetoken,id_basetoken,valid_from)
+		SELECT ep.exchange,ep.foreignname,ep.symbol,ep.id_quotetoken,ep.id_basetoken,$1
+		FROM %s ep
+		WHERE ep.verified=true AND ep.delisted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM %s h
+			WHERE h.valid_to IS NULL
+			AND ep.exchange=h.exchange AND ep.foreignname=h.foreignname
+			AND ep.id_quotetoken IS NOT DISTINCT FROM h.id_quotetoken
+			AND ep.id_basetoken IS NOT DISTINCT FROM h.id_basetoken
+		)
+		`, pairHistoryTable, exchangepairTable, pairHistoryTable)
+		tag, errSnapshot = txRDB.postgresClient.Exec(context.Background(), query, timestamp)
+		if errSnapshot != nil {
+			return errSnapshot
+		}
+		opened = tag.RowsAffected()
+		return nil
+	})
+	return
+}
+
+// GetExchangePairsAt returns all verified exchangepairs with @asset as quote or base token
+// as recorded by the latest snapshot at or before @timestamp.
+func (rdb *RelDB) GetExchangePairsAt(asset dia.Asset, timestamp time.Time) (pairs []dia.ExchangePair, err error) {
+	query := fmt.Sprintf(`
+	SELECT h.exchange,h.foreignname,h.symbol,a.symbol,a.name,a.address,a.blockchain,a.decimals,b.symbol,b.name,b.address,b.blockchain,b.decimals
+	FROM %s h
+	INNER JOIN %s a
+	ON h.id_quotetoken=a.asset_id
+	INNER JOIN %s b
+	ON h.id_basetoken=b.asset_id
+	WHERE ((a.address=$1 AND a.blockchain=$2) OR (b.address=$1 AND b.blockchain=$2))
+	AND h.valid_from<=$3
+	AND (h.valid_to IS NULL OR h.valid_to>$3)
+	ORDER BY h.exchange,h.foreignname
+	`, pairHistoryTable, assetTable, assetTable)
+	var rows pgx.Rows
+	rows, err = rdb.postgresClient.Query(context.Background(), query, asset.Address, asset.Blockchain, timestamp)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pair          dia.ExchangePair
+			symbol        sql.NullString
+			quoteDecimals sql.NullInt64
+			baseDecimals  sql.NullInt64
+		)
+		err = rows.Scan(
+			&pair.Exchange,
+			&pair.ForeignName,
+			&symbol,
+			&pair.UnderlyingPair.QuoteToken.Symbol,
+			&pair.UnderlyingPair.QuoteToken.Name,
+			&pair.UnderlyingPair.QuoteToken.Address,
+			&pair.UnderlyingPair.QuoteToken.Blockchain,
+			&quoteDecimals,
+			&pair.UnderlyingPair.BaseToken.Symbol,
+			&pair.UnderlyingPair.BaseToken.Name,
+			&pair.UnderlyingPair.BaseToken.Address,
+			&pair.UnderlyingPair.BaseToken.Blockchain,
+			&baseDecimals,
+		)
+		if err != nil {
+			return
+		}
+		pair.Symbol = symbol.String
+		pair.Verified = true
+		if quoteDecimals.Valid {
+			pair.UnderlyingPair.QuoteToken.Decimals = uint8(quoteDecimals.Int64)
+		}
+		if baseDecimals.Valid {
+			pair.UnderlyingPair.BaseToken.Decimals = uint8(baseDecimals.Int64)
+		}
+		pairs = append(pairs, pair)
+	}
+	err = rows.Err()
+	return
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+// PegDeviation measures how far the price of a stablecoin @Asset moved away from its @Peg in USD in the
+// window [@Time-@Window, @Time]. Deviations are relative to the peg, i.e. 0.01 is a deviation of 1%.
+// @Deviation is signed and refers to the latest @Price, @MaxDeviation and @MeanDeviation are absolute.
+type PegDeviation struct {
+	Asset         dia.Asset     `json:"Asset"`
+	Peg           float64       `json:"Peg"`
+	Price         float64       `json:"Price"`
+	Deviation     float64       `json:"Deviation"`
+	MaxDeviation  float64       `json:"MaxDeviation"`
+	MeanDeviation float64       `json:"MeanDeviation"`
+	NumQuotations int           `json:"NumQuotations"`
+	Window        time.Duration `json:"Window"`
+	Time          time.Time     `json:"Time"`
+}
+
+// PeggedAsset is a stablecoin @Asset whose USD price is pegged to @Peg.
+type PeggedAsset struct {
+	Asset dia.Asset
+	Peg   float64
+}
+
+var (
+	// PeggedAssets are the stablecoins whose peg deviations are computed and stored.
+	PeggedAssets = []PeggedAsset{
+		{Asset: dia.Asset{Symbol: "USDT", Blockchain: dia.ETHEREUM, Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7"}, Peg: 1},
+		{Asset: dia.Asset{Symbol: "USDC", Blockchain: dia.ETHEREUM, Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"}, Peg: 1},
+		{Asset: dia.Asset{Symbol: "DAI", Blockchain: dia.ETHEREUM, Address: "0x6B175474E89094C44Da98b954EedeAC495271d0F"}, Peg: 1},
+		{Asset: dia.Asset{Symbol: "BUSD", Blockchain: dia.ETHEREUM, Address: "0x4Fabb145d64652a948d72533023f6E7A623C7C53"}, Peg: 1},
+		{Asset: dia.Asset{Symbol: "TUSD", Blockchain: dia.ETHEREUM, Address: "0x0000000000085d4780B73119b644AE5ecd22b376"}, Peg: 1},
+	}
+	// PegDeviationWindows are the windows for which peg deviations are computed and stored.
+	PegDeviationWindows = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+)
+
+// ValidPegDeviationWindow returns true if @window is one of the PegDeviationWindows.
+func ValidPegDeviationWindow(window time.Duration) bool {
+	for _, w := range PegDeviationWindows {
+		if w == window {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputePegDeviation computes the deviation of @asset from @peg from its quotations in the last @window,
+// which must be one of the PegDeviationWindows.
+func (datastore *DB) ComputePegDeviation(asset dia.Asset, peg float64, window time.Duration) (deviation PegDeviation, err error) {
+	if peg <= 0 {
+		err = fmt.Errorf("invalid peg %v", peg)
+		return
+	}
+	if !ValidPegDeviationWindow(window) {
+		err = fmt.Errorf("invalid peg deviation window %v", window)
+		return
+	}
+	endtime := time.Now()
+	quotations, err := datastore.GetAssetQuotations(asset, endtime.Add(-window), endtime)
+	if err != nil {
+		return
+	}
+	if len(quotations) == 0 {
+		err = fmt.Errorf("no quotations of %s on %s in the last %v", asset.Address, asset.Blockchain, window)
+		return
+	}
+
+	deviation = pegDeviation(quotations, peg)
+	deviation.Asset = asset
+	deviation.Window = window
+	deviation.Time = endtime
+	return
+}
+
+// UpdatePegDeviations computes and stores the deviations of all PeggedAssets for each of the PegDeviationWindows.
+// It returns the number of stored deviations.
+func (datastore *DB) UpdatePegDeviations() (updated int, err error) {
+	for _, pegged := range PeggedAssets {
+		for _, window := range PegDeviationWindows {
+			deviation, err := datastore.ComputePegDeviation(pegged.Asset, pegged.Peg, window)
+			if err != nil {
+				log.Warnf("compute peg deviation of %s for window %v: %v", pegged.Asset.Symbol, window, err)
+				continue
+			}
+			if err = datastore.SavePegDeviationInflux(deviation); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+	return
+}
+
+// pegDeviation returns the deviation statistics of @quotations from @peg. The latest quotation determines
+// the current price, irrespective of the order of @quotations.
+func pegDeviation(quotations []AssetQuotation, peg float64) (deviation PegDeviation) {
+	deviation.Peg = peg
+	deviation.NumQuotations = len(quotations)
+	var latest time.Time
+	for _, q := range quotations {
+		d := q.Price/peg - 1
+		deviation.MaxDeviation = math.Max(deviation.MaxDeviation, math.Abs(d))
+		deviation.MeanDeviation += math.Abs(d)
+		if !q.Time.Before(latest) {
+			latest = q.Time
+			deviation.Price = q.Price
+			deviation.Deviation = d
+		}
+	}
+	if len(quotations) > 0 {
+		deviation.MeanDeviation /= float64(len(quotations))
+	}
+	return
+}
+
+// SavePegDeviationInflux stores @deviation in influx and flushes the batch.
+func (datastore *DB) SavePegDeviationInflux(deviation PegDeviation) error {
+	tags := map[string]string{
+		"address":    deviation.Asset.Address,
+		"blockchain": deviation.Asset.Blockchain,
+		"window":     strconv.FormatInt(int64(deviation.Window.Seconds()), 10),
+	}
+	fields := map[string]interface{}{
+		"symbol":        deviation.Asset.Symbol,
+		"peg":           deviation.Peg,
+		"price":         deviation.Price,
+		"deviation":     deviation.Deviation,
+		"maxDeviation":  deviation.MaxDeviation,
+		"meanDeviation": deviation.MeanDeviation,
+		"numQuotations": int64(deviation.NumQuotations),
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDbPegDeviationTable, tags, fields, deviation.Time)
+	if err != nil {
+		log.Errorln("SavePegDeviationInflux:", err)
+		return err
+	}
+	datastore.addPoint(pt)
+	return datastore.WriteBatchInflux()
+}
+
+// GetPegDeviation returns the latest stored deviation of @asset from its peg computed with @window.
+func (datastore *DB) GetPegDeviation(asset dia.Asset, window time.Duration) (deviation PegDeviation, err error) {
+	q := fmt.Sprintf(`SELECT symbol,peg,price,deviation,maxDeviation,meanDeviation,numQuotations FROM %s
+	WHERE address=$address AND blockchain=$blockchain AND "window"=$window ORDER BY time DESC LIMIT 1`,
+		influxDbPegDeviationTable)
+	params := map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"window":     strconv.FormatInt(int64(window.Seconds()), 10),
+	}
+	res, err := datastore.queryInflux(q, params)
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 || len(res[0].Series[0].Values) == 0 {
+		err = errors.New("no peg deviation found")
+		return
+	}
+
+	row := res[0].Series[0].Values[0]
+	deviation.Time, err = time.Parse(time.RFC3339, row[0].(string))
+	if err != nil {
+		return
+	}
+	deviation.Asset = asset
+	if symbol, ok := row[1].(string); ok && deviation.Asset.Symbol == "" {
+		deviation.Asset.Symbol = symbol
+	}
+	deviation.Peg = influxFloat(row[2])
+	deviation.Price = influxFloat(row[3])
+	deviation.Deviation = influxFloat(row[4])
+	deviation.MaxDeviation = influxFloat(row[5])
+	deviation.MeanDeviation = influxFloat(row[6])
+	deviation.NumQuotations = int(influxFloat(row[7]))
+	deviation.Window = window
+	return
+}
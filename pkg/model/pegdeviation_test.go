@@ -0,0 +1,37 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPegDeviation(t *testing.T) {
+	now := time.Now()
+	quotations := []AssetQuotation{
+		{Price: 0.98, Time: now.Add(-3 * time.Minute)},
+		{Price: 1.005, Time: now},
+		{Price: 1.01, Time: now.Add(-2 * time.Minute)},
+		{Price: 1, Time: now.Add(-time.Minute)},
+	}
+	deviation := pegDeviation(quotations, 1)
+
+	if deviation.Price != 1.005 || math.Abs(deviation.Deviation-0.005) > 1e-9 {
+		t.Errorf("latest price is %v with deviation %v but should be 1.005 with deviation 0.005", deviation.Price, deviation.Deviation)
+	}
+	if math.Abs(deviation.MaxDeviation-0.02) > 1e-9 {
+		t.Errorf("max deviation is %v but should be 0.02", deviation.MaxDeviation)
+	}
+	if math.Abs(deviation.MeanDeviation-0.00875) > 1e-9 {
+		t.Errorf("mean deviation is %v but should be 0.00875", deviation.MeanDeviation)
+	}
+	if deviation.NumQuotations != 4 {
+		t.Errorf("number of quotations is %d but should be 4", deviation.NumQuotations)
+	}
+
+	// Deviations are relative to the peg.
+	deviation = pegDeviation([]AssetQuotation{{Price: 0.9, Time: now}}, 0.5)
+	if math.Abs(deviation.Deviation-0.8) > 1e-9 {
+		t.Errorf("deviation is %v but should be 0.8", deviation.Deviation)
+	}
+}
@@ -0,0 +1,333 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// PendingAssetStatus is the review status of an asset in the staging area.
+type PendingAssetStatus string
+
+const (
+	PendingAssetPending  PendingAssetStatus = "pending"
+	PendingAssetApproved PendingAssetStatus = "approved"
+	PendingAssetRejected PendingAssetStatus = "rejected"
+
+	pendingAssetMaxSymbol   = 32
+	pendingAssetMaxName     = 128
+	pendingAssetMaxDecimals = 36
+	// autoReviewer is recorded as reviewer of automatic decisions.
+	autoReviewer = "auto"
+)
+
+// PendingAsset is an asset discovered by @Source which awaits review before promotion to the asset table.
+type PendingAsset struct {
+	ID             string             `json:"ID"`
+	Asset          dia.Asset          `json:"Asset"`
+	Source         string             `json:"Source"`
+	DiscoveredTime time.Time          `json:"DiscoveredTime"`
+	Status         PendingAssetStatus `json:"Status"`
+	Reviewer       string             `json:"Reviewer"`
+	ReviewTime     time.Time          `json:"ReviewTime"`
+	Note           string             `json:"Note"`
+}
+
+// AutoApprovalRule promotes a newly discovered asset without manual review if Approve returns true.
+type AutoApprovalRule struct {
+	Name    string
+	Approve func(asset dia.Asset, source string) bool
+}
+
+// DefaultAutoApprovalRules returns the rules applied by the asset collection.
+// Assets from the sources in env var ASSET_TRUSTED_SOURCES are approved automatically.
+func DefaultAutoApprovalRules() []AutoApprovalRule {
+	return []AutoApprovalRule{
+		TrustedSourceRule(strings.Split(utils.Getenv("ASSET_TRUSTED_SOURCES", "assetlists"), ",")),
+	}
+}
+
+// TrustedSourceRule approves all assets discovered by one of @sources.
+func TrustedSourceRule(sources []string) AutoApprovalRule {
+	return AutoApprovalRule{
+		Name: "trustedsource",
+		Approve: func(asset dia.Asset, source string) bool {
+			return utils.Contains(&sources, source)
+		},
+	}
+}
+
+// ValidPendingAsset returns an error if @asset is malformed. Malformed assets are rejected without review.
+func ValidPendingAsset(asset dia.Asset) error {
+	if asset.Blockchain == "" || asset.Address == "" {
+		return errors.New("missing blockchain or address")
+	}
+	if asset.Symbol == "" || len(asset.Symbol) > pendingAssetMaxSymbol || strings.IndexFunc(asset.Symbol, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("invalid symbol %q", asset.Symbol)
+	}
+	if asset.Name == "" || len(asset.Name) > pendingAssetMaxName {
+		return fmt.Errorf("invalid name %q", asset.Name)
+	}
+	if strings.IndexFunc(asset.Symbol+asset.Name, unicode.IsControl) >= 0 {
+		return errors.New("symbol or name contains control characters")
+	}
+	if asset.Decimals > pendingAssetMaxDecimals {
+		return fmt.Errorf("invalid decimals %d", asset.Decimals)
+	}
+	if err := dia.ValidNativeAddress(asset.Blockchain, asset.Address); err != nil {
+		return err
+	}
+	if strings.HasPrefix(asset.Address, "0x") && !common.IsHexAddress(asset.Address) {
+		return fmt.Errorf("invalid address %s", asset.Address)
+	}
+	return nil
+}
+
+// SubmitPendingAsset stages @asset discovered by @source for review and returns its review status.
+// Assets which are already in the asset table are not staged. Malformed assets are rejected and
+// assets matching one of @rules are approved immediately. Previous review decisions are kept.
+func (rdb *RelDB) SubmitPendingAsset(asset dia.Asset, source string, rules []AutoApprovalRule) (status PendingAssetStatus, err error) {
+	_, err = getAsset(rdb.postgresClient, asset.Address, asset.Blockchain)
+	if err == nil {
+		return PendingAssetApproved, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return
+	}
+
+	status = PendingAssetPending
+	var note string
+	if errValid := ValidPendingAsset(asset); errValid != nil {
+		status = PendingAssetRejected
+		note = errValid.Error()
+	}
+
+	var id string
+	query := fmt.Sprintf(`
+	INSERT INTO %s (symbol,name,address,decimals,blockchain,source,status,reviewer,review_time,note)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+	ON CONFLICT (address,blockchain) DO NOTHING
+	RETURNING pending_id
+	`, pendingAssetTable)
+	var (
+		reviewer   sql.NullString
+		reviewTime sql.NullTime
+	)
+	if status == PendingAssetRejected {
+		reviewer = sql.NullString{String: autoReviewer, Valid: true}
+		reviewTime = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	err = rdb.postgresClient.QueryRow(
+		context.Background(),
+		query,
+		asset.Symbol,
+		asset.Name,
+		asset.Address,
+		strconv.Itoa(int(asset.Decimals)),
+		asset.Blockchain,
+		source,
+		status,
+		reviewer,
+		reviewTime,
+		note,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The asset was staged before.
+		query = fmt.Sprintf("SELECT status FROM %s WHERE address=$1 AND blockchain=$2", pendingAssetTable)
+		err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&status)
+		return
+	}
+	if err != nil || status == PendingAssetRejected {
+		return
+	}
+
+	for _, rule := range rules {
+		if rule.Approve(asset, source) {
+			err = rdb.ApprovePendingAsset(id, autoReviewer+":"+rule.Name)
+			if err != nil {
+				return
+			}
+			return PendingAssetApproved, nil
+		}
+	}
+	return
+}
+
+// GetPendingAssets returns up to @limit staged assets with @status, oldest first.
+func (rdb *RelDB) GetPendingAssets(status PendingAssetStatus, limit int, offset int) (pendingAssets []PendingAsset, err error) {
+	query := fmt.Sprintf(`
+	SELECT pending_id,symbol,name,address,decimals,blockchain,source,discovered_time,status,reviewer,review_time,note
+	FROM %s
+	WHERE status=$1
+	ORDER BY discovered_time ASC
+	LIMIT $2 OFFSET $3
+	`, pendingAssetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, status, limit, offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pendingAsset PendingAsset
+			decimals     sql.NullString
+			source       sql.NullString
+			reviewer     sql.NullString
+			reviewTime   sql.NullTime
+			note         sql.NullString
+		)
+		err = rows.Scan(
+			&pendingAsset.ID,
+			&pendingAsset.Asset.Symbol,
+			&pendingAsset.Asset.Name,
+			&pendingAsset.Asset.Address,
+			&decimals,
+			&pendingAsset.Asset.Blockchain,
+			&source,
+			&pendingAsset.DiscoveredTime,
+			&pendingAsset.Status,
+			&reviewer,
+			&reviewTime,
+			&note,
+		)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			var d uint64
+			d, err = strconv.ParseUint(decimals.String, 10, 8)
+			if err != nil {
+				return
+			}
+			pendingAsset.Asset.Decimals = uint8(d)
+		}
+		pendingAsset.Source = source.String
+		pendingAsset.Reviewer = reviewer.String
+		if reviewTime.Valid {
+			pendingAsset.ReviewTime = reviewTime.Time
+		}
+		pendingAsset.Note = note.String
+		pendingAssets = append(pendingAssets, pendingAsset)
+	}
+	err = rows.Err()
+	return
+}
+
+// EditPendingAsset corrects symbol, name and decimals of the pending asset with @id.
+func (rdb *RelDB) EditPendingAsset(id string, asset dia.Asset) error {
+	query := fmt.Sprintf("UPDATE %s SET symbol=$1,name=$2,decimals=$3 WHERE pending_id=$4 AND status=$5", pendingAssetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, asset.Symbol, asset.Name, strconv.Itoa(int(asset.Decimals)), id, PendingAssetPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pending asset %s not found", id)
+	}
+	return nil
+}
+
+// ApprovePendingAsset promotes the pending asset with @id to the asset table. If the asset exists
+// already, its symbol, name and decimals are updated to the ones of the pending asset.
+// @reviewer is recorded both in the staging area and in the asset history.
+func (rdb *RelDB) ApprovePendingAsset(id string, reviewer string) error {
+	var asset dia.Asset
+	err := rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		var decimals sql.NullString
+		txRDB.actor = reviewer
+		query := fmt.Sprintf("SELECT symbol,name,address,decimals,blockchain FROM %s WHERE pending_id=$1 AND status=$2 FOR UPDATE", pendingAssetTable)
+		err := txRDB.postgresClient.QueryRow(context.Background(), query, id, PendingAssetPending).Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("pending asset %s not found", id)
+			}
+			return err
+		}
+		if decimals.Valid {
+			d, err := strconv.ParseUint(decimals.String, 10, 8)
+			if err != nil {
+				return err
+			}
+			asset.Decimals = uint8(d)
+		}
+
+		_, err = getAsset(txRDB.postgresClient, asset.Address, asset.Blockchain)
+		switch {
+		case err == nil:
+			// The pending asset is a change of an existing asset, see stageAssetChange.
+			err = txRDB.UpdateAsset(asset)
+		case errors.Is(err, pgx.ErrNoRows):
+			err = setAsset(txRDB.postgresClient, asset, reviewer)
+		}
+		if err != nil {
+			return err
+		}
+		query = fmt.Sprintf("UPDATE %s SET status=$1,reviewer=$2,review_time=$3 WHERE pending_id=$4", pendingAssetTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, PendingAssetApproved, reviewer, time.Now(), id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	rdb.clearAssetMissing(asset)
+	return nil
+}
+
+// stageAssetChange stages a change of symbol, name or decimals of the existing @asset proposed by @source
+// for review. A pending change of the asset is replaced. A change which was rejected before is not staged again.
+func stageAssetChange(q pgQuerier, asset dia.Asset, source string) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (symbol,name,address,decimals,blockchain,source,status,note)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	ON CONFLICT (address,blockchain)
+	DO UPDATE SET
+		symbol=EXCLUDED.symbol,
+		name=EXCLUDED.name,
+		decimals=EXCLUDED.decimals,
+		source=EXCLUDED.source,
+		discovered_time=NOW(),
+		status=EXCLUDED.status,
+		reviewer=NULL,
+		review_time=NULL,
+		note=EXCLUDED.note
+	WHERE %s.status<>$9
+	OR (%s.symbol,%s.name,%s.decimals) IS DISTINCT FROM (EXCLUDED.symbol,EXCLUDED.name,EXCLUDED.decimals)
+	`, pendingAssetTable, pendingAssetTable, pendingAssetTable, pendingAssetTable, pendingAssetTable)
+	_, err := q.Exec(
+		context.Background(),
+		query,
+		asset.Symbol,
+		asset.Name,
+		asset.Address,
+		strconv.Itoa(int(asset.Decimals)),
+		asset.Blockchain,
+		source,
+		PendingAssetPending,
+		"change of existing asset",
+		PendingAssetRejected,
+	)
+	return err
+}
+
+// RejectPendingAsset rejects the pending asset with @id. Rejected assets are not staged again.
+func (rdb *RelDB) RejectPendingAsset(id string, reviewer string, note string) error {
+	query := fmt.Sprintf("UPDATE %s SET status=$1,reviewer=$2,review_time=$3,note=$4 WHERE pending_id=$5 AND status=$6", pendingAssetTable)
+	tag, err := rdb.postgresClient.Exec(context.Background(), query, PendingAssetRejected, reviewer, time.Now(), note, id, PendingAssetPending)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pending asset %s not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestValidPendingAsset(t *testing.T) {
+	valid := dia.Asset{Symbol: "USDC", Name: "USD Coin", Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Blockchain: dia.ETHEREUM, Decimals: 6}
+	if err := ValidPendingAsset(valid); err != nil {
+		t.Errorf("expected valid asset, got %v", err)
+	}
+
+	invalid := map[string]func(asset *dia.Asset){
+		"empty symbol":       func(asset *dia.Asset) { asset.Symbol = "" },
+		"whitespace symbol":  func(asset *dia.Asset) { asset.Symbol = "US DC" },
+		"control characters": func(asset *dia.Asset) { asset.Name = "USD\x00Coin" },
+		"decimals":           func(asset *dia.Asset) { asset.Decimals = 255 },
+		"hex address":        func(asset *dia.Asset) { asset.Address = "0x1234" },
+		"missing blockchain": func(asset *dia.Asset) { asset.Blockchain = "" },
+	}
+	for name, modify := range invalid {
+		asset := valid
+		modify(&asset)
+		if err := ValidPendingAsset(asset); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestTrustedSourceRule(t *testing.T) {
+	rule := TrustedSourceRule([]string{"assetlists"})
+	if !rule.Approve(dia.Asset{}, "assetlists") {
+		t.Error("expected approval of trusted source")
+	}
+	if rule.Approve(dia.Asset{}, "Uniswap") {
+		t.Error("expected no approval of untrusted source")
+	}
+}
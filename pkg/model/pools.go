@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -51,10 +52,10 @@ func (datastore *DB) SavePoolInflux(p dia.Pool) error {
 func (datastore *DB) GetPoolInflux(poolAddress string, starttime time.Time, endtime time.Time) ([]dia.Pool, error) {
 
 	pools := []dia.Pool{}
-	queryString := "SELECT \"exchange\",\"blockchain\",volumes FROM %s WHERE address='%s' AND time >= %d AND time < %d ORDER BY DESC"
-	q := fmt.Sprintf(queryString, influxDbDEXPoolTable, poolAddress, starttime.UnixNano(), endtime.UnixNano())
+	queryString := "SELECT \"exchange\",\"blockchain\",volumes FROM %s WHERE address=$address AND time >= $starttime AND time < $endtime ORDER BY DESC"
+	q := fmt.Sprintf(queryString, influxDbDEXPoolTable)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"address": poolAddress, "starttime": starttime.UnixNano(), "endtime": endtime.UnixNano()})
 	if err != nil {
 		return pools, err
 	}
@@ -83,6 +84,100 @@ func (datastore *DB) GetPoolInflux(poolAddress string, starttime time.Time, endt
 	return pools, nil
 }
 
+// SavePoolLiquidityInflux stores the reserves @assetvolumes of @pool at time @t in influx, one point per asset.
+// Together with GetPoolLiquidity it gives the history of a pool's liquidity, and hence its TVL.
+func (datastore *DB) SavePoolLiquidityInflux(pool dia.Pool, assetvolumes []dia.AssetVolume, t time.Time) error {
+	for _, av := range assetvolumes {
+		tags := map[string]string{
+			"pool":            pool.Address,
+			"exchange":        pool.Exchange.Name,
+			"blockchain":      pool.Blockchain.Name,
+			"assetAddress":    av.Asset.Address,
+			"assetBlockchain": av.Asset.Blockchain,
+		}
+		fields := map[string]interface{}{
+			"symbol":       av.Asset.Symbol,
+			"decimals":     int64(av.Asset.Decimals),
+			"liquidity":    av.Volume,
+			"liquidityUSD": av.VolumeUSD,
+			"tokenIndex":   int64(av.Index),
+			"weight":       av.Weight,
+		}
+		pt, err := clientInfluxdb.NewPoint(influxDbPoolLiquidityTable, tags, fields, t)
+		if err != nil {
+			log.Errorln("SavePoolLiquidityInflux:", err)
+			continue
+		}
+		datastore.addPoint(pt)
+	}
+
+	err := datastore.WriteBatchInflux()
+	if err != nil {
+		log.Errorln("Write influx batch: ", err)
+	}
+	return err
+}
+
+// GetPoolLiquidity returns the liquidity of @pool in the time-range [starttime, endtime), ordered by time.
+// Each element holds the reserves of all pool assets at one point in time.
+func (datastore *DB) GetPoolLiquidity(pool dia.Pool, starttime time.Time, endtime time.Time) ([]dia.Pool, error) {
+	pools := []dia.Pool{}
+	q := fmt.Sprintf(`SELECT "exchange","assetAddress","assetBlockchain",symbol,decimals,liquidity,liquidityUSD,tokenIndex,weight
+	FROM %s WHERE pool=$pool AND blockchain=$blockchain AND time >= $starttime AND time < $endtime ORDER BY time ASC`,
+		influxDbPoolLiquidityTable)
+	params := map[string]interface{}{
+		"pool":       pool.Address,
+		"blockchain": pool.Blockchain.Name,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	}
+	res, err := datastore.queryInflux(q, params)
+	if err != nil {
+		return pools, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return pools, nil
+	}
+
+	for _, row := range res[0].Series[0].Values {
+		t, err := time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return pools, err
+		}
+		// Points of all assets of the pool share the same timestamp.
+		if len(pools) == 0 || !pools[len(pools)-1].Time.Equal(t) {
+			snapshot := dia.Pool{Address: pool.Address, Blockchain: pool.Blockchain, Time: t}
+			snapshot.Exchange.Name, _ = row[1].(string)
+			pools = append(pools, snapshot)
+		}
+
+		var av dia.AssetVolume
+		av.Asset.Address, _ = row[2].(string)
+		av.Asset.Blockchain, _ = row[3].(string)
+		av.Asset.Symbol, _ = row[4].(string)
+		av.Asset.Decimals = uint8(influxFloat(row[5]))
+		av.Volume = influxFloat(row[6])
+		av.VolumeUSD = influxFloat(row[7])
+		av.Index = uint8(influxFloat(row[8]))
+		av.Weight = influxFloat(row[9])
+		pools[len(pools)-1].Assetvolumes = append(pools[len(pools)-1].Assetvolumes, av)
+	}
+	return pools, nil
+}
+
+// influxFloat returns the numeric influx value @v as float64. Missing values yield zero.
+func influxFloat(v interface{}) float64 {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
 // SetPool writes pool data into pool table and the underlying asset and liquidity data into the poolasset table.
 func (rdb *RelDB) SetPool(pool dia.Pool) error {
 	if len(pool.Assetvolumes) < 2 {
@@ -112,10 +207,10 @@ func (rdb *RelDB) SetPool(pool dia.Pool) error {
 	var query1 string
 	for i := 0; i < len(pool.Assetvolumes); i++ {
 		query1 = fmt.Sprintf(
-			`INSERT INTO %s (pool_id,asset_id,liquidity,liquidity_usd,time_stamp,token_index)
-				VALUES ((SELECT pool_id from %s where address=$1 and blockchain=$2),(SELECT asset_id from %s where address=$3 and blockchain=$4),$5,$6,$7,$8)
+			`INSERT INTO %s (pool_id,asset_id,liquidity,liquidity_usd,time_stamp,token_index,weight)
+				VALUES ((SELECT pool_id from %s where address=$1 and blockchain=$2),(SELECT asset_id from %s where address=$3 and blockchain=$4),$5,$6,$7,$8,NULLIF($9,0::numeric))
 				ON CONFLICT (pool_id,asset_id) 
-				DO UPDATE SET liquidity=EXCLUDED.liquidity, liquidity_usd=EXCLUDED.liquidity_usd, time_stamp=EXCLUDED.time_stamp, token_index=EXCLUDED.token_index`,
+				DO UPDATE SET liquidity=EXCLUDED.liquidity, liquidity_usd=EXCLUDED.liquidity_usd, time_stamp=EXCLUDED.time_stamp, token_index=EXCLUDED.token_index, weight=EXCLUDED.weight`,
 			poolassetTable,
 			poolTable,
 			assetTable,
@@ -132,6 +227,7 @@ func (rdb *RelDB) SetPool(pool dia.Pool) error {
 			pool.Assetvolumes[i].VolumeUSD,
 			pool.Time,
 			pool.Assetvolumes[i].Index,
+			pool.Assetvolumes[i].Weight,
 		)
 		if err != nil {
 			return err
@@ -145,22 +241,20 @@ func (rdb *RelDB) GetPoolByAddress(blockchain string, address string) (pool dia.
 
 	var rows pgx.Rows
 	query := fmt.Sprintf(`
-		SELECT pa.liquidity,pa.liquidity_usd,a.symbol,a.name,a.address,a.decimals,p.exchange,pa.time_stamp,pa.token_index 
+		SELECT pa.liquidity,pa.liquidity_usd,a.symbol,a.name,a.address,a.decimals,p.exchange,pa.time_stamp,pa.token_index,pa.weight
 		FROM %s pa 
 		INNER JOIN %s p 
 		ON p.pool_id=pa.pool_id 
 		INNER JOIN %s a
 		ON pa.asset_id=a.asset_id 
-		WHERE p.blockchain='%s'
-		AND p.address='%s'`,
+		WHERE p.blockchain=$1
+		AND p.address=$2`,
 		poolassetTable,
 		poolTable,
 		assetTable,
-		blockchain,
-		address,
 	)
 
-	rows, err = rdb.postgresClient.Query(context.Background(), query)
+	rows, err = rdb.postgresClient.Query(context.Background(), query, blockchain, address)
 	if err != nil {
 		return
 	}
@@ -173,6 +267,7 @@ func (rdb *RelDB) GetPoolByAddress(blockchain string, address string) (pool dia.
 			timestamp    sql.NullTime
 			liquidity    sql.NullFloat64
 			liquidityUSD sql.NullFloat64
+			weight       sql.NullFloat64
 			assetvolume  dia.AssetVolume
 		)
 		err = rows.Scan(
@@ -185,6 +280,7 @@ func (rdb *RelDB) GetPoolByAddress(blockchain string, address string) (pool dia.
 			&pool.Exchange.Name,
 			&timestamp,
 			&index,
+			&weight,
 		)
 		if err != nil {
 			return
@@ -204,6 +300,7 @@ func (rdb *RelDB) GetPoolByAddress(blockchain string, address string) (pool dia.
 		if liquidityUSD.Valid {
 			assetvolume.VolumeUSD = liquidityUSD.Float64
 		}
+		assetvolume.Weight = weight.Float64
 		assetvolume.Asset.Blockchain = blockchain
 		pool.Assetvolumes = append(pool.Assetvolumes, assetvolume)
 	}
@@ -259,7 +356,7 @@ func (rdb *RelDB) GetAllPoolsExchange(exchange string, liquiThreshold float64) (
 	)
 
 	query = fmt.Sprintf(`
-		SELECT exch_pools.address,a.address,a.blockchain,a.decimals,a.symbol,a.name,pa.token_index,pa.liquidity,pa.liquidity_usd
+		SELECT exch_pools.address,a.address,a.blockchain,a.decimals,a.symbol,a.name,pa.token_index,pa.liquidity,pa.liquidity_usd,pa.weight
 		FROM (
 			SELECT p.pool_id,p.address, SUM(CASE WHEN pa.liquidity<%v THEN 1 ELSE 0 END) AS no_liqui 
 			FROM %s p 
@@ -290,6 +387,7 @@ func (rdb *RelDB) GetAllPoolsExchange(exchange string, liquiThreshold float64) (
 			index        sql.NullInt64
 			liquidity    sql.NullFloat64
 			liquidityUSD sql.NullFloat64
+			weight       sql.NullFloat64
 		)
 		err := rows.Scan(
 			&poolAddress,
@@ -301,9 +399,10 @@ func (rdb *RelDB) GetAllPoolsExchange(exchange string, liquiThreshold float64) (
 			&index,
 			&liquidity,
 			&liquidityUSD,
+			&weight,
 		)
 		if err != nil {
-			log.Error(err)
+			return pools, err
 		}
 		if decimals.Valid {
 			av.Asset.Decimals = uint8(decimals.Int64)
@@ -317,6 +416,7 @@ func (rdb *RelDB) GetAllPoolsExchange(exchange string, liquiThreshold float64) (
 		if liquidityUSD.Valid {
 			av.VolumeUSD = liquidityUSD.Float64
 		}
+		av.Weight = weight.Float64
 
 		// map poolasset to pool if pool address already exists.
 		if _, ok := poolIndexMap[poolAddress]; !ok {
@@ -335,6 +435,8 @@ func (rdb *RelDB) GetAllPoolsExchange(exchange string, liquiThreshold float64) (
 }
 
 // GetPoolsByAsset returns all pools with @asset as a pool asset and both assets have liquidity above @liquiThreshold.
+// Each pool contains all its assets, i.e. @asset and its counter-assets, with their latest liquidity.
+// Pools are ordered by total USD liquidity descending, such that the deepest pool comes first.
 // If @liquidityThresholdUSD>0 AND @liquiThreshold=0, only pools where total liquidity is available
 // AND above @liquidityThresholdUSD are returned.
 func (rdb *RelDB) GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, liquidityThresholdUSD float64) ([]dia.Pool, error) {
@@ -344,15 +446,15 @@ func (rdb *RelDB) GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, l
 	)
 
 	query = fmt.Sprintf(`
-		SELECT exch_pools.exchange,exch_pools.address,a.address,a.blockchain,a.decimals,a.symbol,a.name,pa.token_index,pa.liquidity,pa.liquidity_usd,pa.time_stamp
+		SELECT exch_pools.exchange,exch_pools.address,a.address,a.blockchain,a.decimals,a.symbol,a.name,pa.token_index,pa.liquidity,pa.liquidity_usd,pa.time_stamp,pa.weight
 		FROM (
-			SELECT p.exchange,p.pool_id,p.address, SUM(CASE WHEN pa.liquidity>=%v THEN 0 ELSE 1 END) AS no_liqui, SUM(CASE WHEN a.address='%s' THEN 1 ELSE 0 END) AS correct_asset 
+			SELECT p.exchange,p.pool_id,p.address, SUM(CASE WHEN pa.liquidity>=$1 THEN 0 ELSE 1 END) AS no_liqui, SUM(CASE WHEN a.address=$2 THEN 1 ELSE 0 END) AS correct_asset 
 			FROM %s p 
 			INNER JOIN %s pa 
 			ON p.pool_id=pa.pool_id 
 			INNER JOIN %s a 
 			ON pa.asset_id=a.asset_id 
-			WHERE p.blockchain='%s' 
+			WHERE p.blockchain=$3 
 			GROUP BY p.exchange,p.pool_id,p.address
 			) exch_pools 
 		INNER JOIN %s pa 
@@ -360,9 +462,10 @@ func (rdb *RelDB) GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, l
 		INNER JOIN %s a ON pa.asset_id=a.asset_id 
 		WHERE exch_pools.no_liqui=0 
 		AND exch_pools.correct_asset=1
-		AND pa.time_stamp IS NOT NULL;
-	`, liquidityThreshold, asset.Address, poolTable, poolassetTable, assetTable, asset.Blockchain, poolassetTable, assetTable)
-	rows, err := rdb.postgresClient.Query(context.Background(), query)
+		AND pa.time_stamp IS NOT NULL
+		ORDER BY exch_pools.address,pa.token_index;
+	`, poolTable, poolassetTable, assetTable, poolassetTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, liquidityThreshold, asset.Address, asset.Blockchain)
 	if err != nil {
 		return pools, err
 	}
@@ -380,6 +483,7 @@ func (rdb *RelDB) GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, l
 			liquidity    sql.NullFloat64
 			liquidityUSD sql.NullFloat64
 			timestamp    sql.NullTime
+			weight       sql.NullFloat64
 		)
 		err := rows.Scan(
 			&exchange,
@@ -393,6 +497,7 @@ func (rdb *RelDB) GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, l
 			&liquidity,
 			&liquidityUSD,
 			&timestamp,
+			&weight,
 		)
 		if err != nil {
 			log.Error(err)
@@ -409,6 +514,7 @@ func (rdb *RelDB) GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, l
 		if liquidityUSD.Valid {
 			av.VolumeUSD = liquidityUSD.Float64
 		}
+		av.Weight = weight.Float64
 
 		// map poolasset to pool if pool address already exists.
 		if _, ok := poolIndexMap[poolAddress]; !ok {
@@ -426,6 +532,15 @@ func (rdb *RelDB) GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, l
 		}
 
 	}
+	if err := rows.Err(); err != nil {
+		return pools, err
+	}
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		liquidityI, _ := pools[i].GetPoolLiquidityUSD()
+		liquidityJ, _ := pools[j].GetPoolLiquidityUSD()
+		return liquidityI > liquidityJ
+	})
 
 	if liquidityThresholdUSD > 0 {
 		var filteredPools []dia.Pool
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PurgeMode determines whether the data of a customer is deleted or anonymized.
+type PurgeMode string
+
+const (
+	// PurgeDelete removes all rows associated with a customer.
+	PurgeDelete PurgeMode = "delete"
+	// PurgeAnonymize removes keys and resources of a customer, but keeps its feeds and their
+	// on-chain update history with the owner replaced by a random pseudonym.
+	PurgeAnonymize PurgeMode = "anonymize"
+)
+
+// PurgeReport lists the number of affected rows per table.
+// If DryRun is true, the changes were rolled back and no data was modified.
+type PurgeReport struct {
+	Subject string           `json:"Subject"`
+	DryRun  bool             `json:"DryRun"`
+	Rows    map[string]int64 `json:"Rows"`
+	Time    time.Time        `json:"Time"`
+}
+
+// RetentionPolicy declares that rows in @Table are pruned once @TimeColumn is older than @MaxAge.
+// @TimeColumn may be an expression over the columns of @Table.
+type RetentionPolicy struct {
+	Table      string
+	TimeColumn string
+	MaxAge     time.Duration
+}
+
+// DefaultRetentionPolicies are the retention periods of the data classes stored in postgres.
+var DefaultRetentionPolicies = []RetentionPolicy{
+	{Table: feederupdatesTable, TimeColumn: "update_time", MaxAge: 2 * 365 * 24 * time.Hour},
+	// Announcements without scheduled time are pruned by their announcement time.
+	{Table: exchangelistingTable, TimeColumn: "COALESCE(effective_time,announced_time)", MaxAge: 365 * 24 * time.Hour},
+}
+
+type purgeStatement struct {
+	table string
+	query string
+}
+
+// PurgeCustomer deletes or anonymizes all data associated with the API customer identified by @owner.
+// This comprises feeder keys, feeder resources and access, custom feeds and their update history.
+// Alerts are not persisted and hence need not be purged.
+// For @dryRun true, all statements are executed in a transaction which is rolled back afterwards.
+func (rdb *RelDB) PurgeCustomer(owner string, mode PurgeMode, dryRun bool) (PurgeReport, error) {
+	statements := []purgeStatement{
+		{
+			table: keypairTable,
+			query: fmt.Sprintf("DELETE FROM %s WHERE publickey IN (SELECT feeder_address FROM %s WHERE owner=$1)", keypairTable, oracleconfigTable),
+		},
+		{
+			table: feederaccessTable,
+			query: fmt.Sprintf("DELETE FROM %s WHERE owner=$1", feederaccessTable),
+		},
+		{
+			table: feederResourceTable,
+			query: fmt.Sprintf("DELETE FROM %s WHERE owner=$1", feederResourceTable),
+		},
+	}
+
+	switch mode {
+	case PurgeDelete:
+		statements = append(statements,
+			purgeStatement{
+				table: feederupdatesTable,
+				query: fmt.Sprintf("DELETE FROM %s WHERE oracle_address IN (SELECT address FROM %s WHERE owner=$1)", feederupdatesTable, oracleconfigTable),
+			},
+			purgeStatement{
+				table: oracleconfigTable,
+				query: fmt.Sprintf("DELETE FROM %s WHERE owner=$1", oracleconfigTable),
+			},
+		)
+	case PurgeAnonymize:
+		statements = append(statements, purgeStatement{
+			table: oracleconfigTable,
+			// The pseudonym is random, so it cannot be linked to the owner by hashing known addresses.
+			query: fmt.Sprintf(`
+			WITH pseudonym AS (SELECT 'anonymized-'||gen_random_uuid() AS owner)
+			UPDATE %s SET owner=(SELECT owner FROM pseudonym),active=false,deleted=true,lastupdate=NOW() WHERE owner=$1
+			`, oracleconfigTable),
+		})
+	default:
+		return PurgeReport{}, fmt.Errorf("unknown purge mode %s", mode)
+	}
+
+	return rdb.executePurge(owner, statements, dryRun, owner)
+}
+
+// PruneRetention deletes all rows that are older than the retention period given in @policies.
+// For @dryRun true, the deletions are rolled back and only reported.
+func (rdb *RelDB) PruneRetention(policies []RetentionPolicy, dryRun bool) (PurgeReport, error) {
+	var statements []purgeStatement
+	for _, policy := range policies {
+		statements = append(statements, purgeStatement{
+			table: policy.Table,
+			query: fmt.Sprintf("DELETE FROM %s WHERE %s<NOW()-make_interval(secs => %d)", policy.Table, policy.TimeColumn, int64(policy.MaxAge.Seconds())),
+		})
+	}
+	return rdb.executePurge("retention", statements, dryRun)
+}
+
+// errPurgeDryRun rolls back the transaction of a dry run.
+var errPurgeDryRun = errors.New("purge dry run")
+
+// executePurge runs @statements in a single transaction and collects the affected rows per table.
+func (rdb *RelDB) executePurge(subject string, statements []purgeStatement, dryRun bool, args ...interface{}) (report PurgeReport, err error) {
+	report = PurgeReport{
+		Subject: subject,
+		DryRun:  dryRun,
+		Rows:    make(map[string]int64),
+		Time:    time.Now(),
+	}
+
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		for _, statement := range statements {
+			tag, errExec := txRDB.postgresClient.Exec(context.Background(), statement.query, args...)
+			if errExec != nil {
+				return fmt.Errorf("purge %s: %v", statement.table, errExec)
+			}
+			report.Rows[statement.table] += tag.RowsAffected()
+		}
+		if dryRun {
+			return errPurgeDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errPurgeDryRun) {
+		log.Infof("dry run of purge for %s: %v", subject, report.Rows)
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// likeEscaper escapes the wildcards of LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// assetOrderClauses maps the allowed asset orders to their ORDER BY clauses.
+// The asset_id is appended in order to obtain a deterministic order for pagination.
+var assetOrderClauses = map[AssetOrder]string{
+	AssetOrderSymbol:  "a.symbol ASC, a.asset_id ASC",
+	AssetOrderName:    "a.name ASC, a.asset_id ASC",
+	AssetOrderAddress: "a.address ASC, a.asset_id ASC",
+	AssetOrderVolume:  "av.volume_usd DESC NULLS LAST, a.asset_id ASC",
+}
+
+// queryBuilder collects the conditions of a WHERE clause together with their arguments,
+// so that dynamic queries never contain user input.
+type queryBuilder struct {
+	clauses []string
+	args    []interface{}
+}
+
+// where adds @condition with @value as its argument. The verb %s in @condition is replaced by
+// the placeholder of the argument.
+func (qb *queryBuilder) where(condition string, value interface{}) {
+	qb.args = append(qb.args, value)
+	qb.clauses = append(qb.clauses, fmt.Sprintf(condition, fmt.Sprintf("$%d", len(qb.args))))
+}
+
+// conditions returns all conditions joined by AND.
+func (qb *queryBuilder) conditions() string {
+	return strings.Join(qb.clauses, " AND ")
+}
+
+// set adds the assignment @assignment of a SET clause with @value as its argument.
+func (qb *queryBuilder) set(assignment string, value interface{}) {
+	qb.where(assignment, value)
+}
+
+// assignments returns all assignments separated by commas.
+func (qb *queryBuilder) assignments() string {
+	return strings.Join(qb.clauses, ",")
+}
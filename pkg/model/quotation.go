@@ -11,7 +11,7 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/utils"
-	"github.com/go-redis/redis"
+	"github.com/go-redis/redis/v8"
 	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
 	"github.com/jackc/pgx/v4"
 )
@@ -34,7 +34,7 @@ func getKeyQuotation(value string) string {
 }
 
 func getKeyAssetQuotation(blockchain, address string) string {
-	return "dia_assetquotation_USD_" + blockchain + "_" + address
+	return "dia_assetquotation_USD_" + blockchain + "_" + dia.NormalizeNativeAddress(blockchain, address)
 }
 
 // ------------------------------------------------------------------------------
@@ -79,13 +79,10 @@ func (datastore *DB) AddAssetQuotationsToBatch(quotations []*AssetQuotation) err
 		tags := map[string]string{
 			"symbol":     EscapeReplacer.Replace(quotation.Asset.Symbol),
 			"name":       EscapeReplacer.Replace(quotation.Asset.Name),
-			"address":    quotation.Asset.Address,
+			"address":    dia.NormalizeNativeAddress(quotation.Asset.Blockchain, quotation.Asset.Address),
 			"blockchain": quotation.Asset.Blockchain,
 		}
-		fields := map[string]interface{}{
-			"price": quotation.Price,
-		}
-		pt, err := clientInfluxdb.NewPoint(influxDBAssetQuotationsTable, tags, fields, quotation.Time)
+		pt, err := clientInfluxdb.NewPoint(influxDBAssetQuotationsTable, tags, assetQuotationFields(quotation), quotation.Time)
 		if err != nil {
 			log.Errorln("addAssetQuotationsToBatch:", err)
 			return err
@@ -95,20 +92,55 @@ func (datastore *DB) AddAssetQuotationsToBatch(quotations []*AssetQuotation) err
 	return nil
 }
 
+// assetQuotationColumns are the fields of asset quotations in influx, in the order read by parseAssetQuotationRow.
+const assetQuotationColumns = "price,carriedforward,age"
+
+// assetQuotationFields returns the influx fields of @quotation.
+func assetQuotationFields(quotation *AssetQuotation) map[string]interface{} {
+	fields := map[string]interface{}{
+		"price": quotation.Price,
+	}
+	if quotation.CarriedForward {
+		fields["carriedforward"] = true
+		fields["age"] = quotation.Age
+	}
+	return fields
+}
+
+// parseAssetQuotationRow parses a row of time and assetQuotationColumns from influx.
+// Quotations which were not carried forward have empty carriedforward and age fields.
+func parseAssetQuotationRow(row []interface{}) (quotation AssetQuotation, err error) {
+	if len(row) < 4 {
+		err = fmt.Errorf("unexpected asset quotation row %v", row)
+		return
+	}
+	quotation.Time, err = time.Parse(time.RFC3339, row[0].(string))
+	if err != nil {
+		return
+	}
+	quotation.Price, err = row[1].(json.Number).Float64()
+	if err != nil {
+		return
+	}
+	if carried, ok := row[2].(bool); ok {
+		quotation.CarriedForward = carried
+	}
+	if age, ok := row[3].(json.Number); ok {
+		quotation.Age, err = age.Int64()
+	}
+	return
+}
+
 // SetAssetQuotation stores the full quotation of @asset into influx and cache.
 func (datastore *DB) SetAssetQuotation(quotation *AssetQuotation) error {
 	// Write to influx
 	tags := map[string]string{
 		"symbol":     EscapeReplacer.Replace(quotation.Asset.Symbol),
 		"name":       EscapeReplacer.Replace(quotation.Asset.Name),
-		"address":    quotation.Asset.Address,
+		"address":    dia.NormalizeNativeAddress(quotation.Asset.Blockchain, quotation.Asset.Address),
 		"blockchain": quotation.Asset.Blockchain,
 	}
-	fields := map[string]interface{}{
-		"price": quotation.Price,
-	}
-
-	pt, err := clientInfluxdb.NewPoint(influxDBAssetQuotationsTable, tags, fields, quotation.Time)
+	pt, err := clientInfluxdb.NewPoint(influxDBAssetQuotationsTable, tags, assetQuotationFields(quotation), quotation.Time)
 	if err != nil {
 		log.Errorln("SetAssetQuotation:", err)
 	} else {
@@ -117,44 +149,61 @@ func (datastore *DB) SetAssetQuotation(quotation *AssetQuotation) error {
 
 	// Write latest point to redis cache
 	// log.Printf("write to cache: %s", quotation.Asset.Symbol)
+	if quotation.VolumeYesterdayUSD == 0 {
+		quotation.VolumeYesterdayUSD = datastore.volumeYesterdayUSD(quotation.Asset)
+	}
 	_, err = datastore.SetAssetQuotationCache(quotation, false)
 	return err
 
 }
 
-// GetAssetQuotation returns the latest full quotation for @asset.
-func (datastore *DB) GetAssetQuotationLatest(asset dia.Asset) (*AssetQuotation, error) {
+// GetAssetQuotationLatest returns the latest full quotation for @asset.
+// By default, the quotation is read from the redis cache first, see ReadConsistency for other semantics.
+func (datastore *DB) GetAssetQuotationLatest(asset dia.Asset, opts ...ReadOption) (*AssetQuotation, error) {
+	o := newReadOptions(opts)
 
 	// First attempt to get latest quotation from redis cache
-	quotation, err := datastore.GetAssetQuotationCache(asset)
-	if err == nil {
-		log.Infof("got asset quotation for %s from cache: %v", asset.Symbol, quotation)
-		return quotation, nil
+	if o.readCache() {
+		quotation, err := datastore.GetAssetQuotationCache(asset)
+		if err == nil {
+			log.Infof("got asset quotation for %s from cache: %v", asset.Symbol, quotation)
+			return quotation, nil
+		}
+		// if not in cache, get quotation from influx
+		if errors.Is(err, ErrCacheUnavailable) {
+			fallthroughCacheRead()
+		}
+		log.Infof("asset %s not in cache. Query influx...", asset.Symbol)
 	}
 
-	// if not in cache, get quotation from influx
-	log.Infof("asset %s not in cache. Query influx...", asset.Symbol)
-	return datastore.GetAssetQuotation(asset, time.Now())
-
+	quotation, err := datastore.GetAssetQuotation(asset, time.Now())
+	if err == nil && o.consistency == CacheRefresh {
+		if _, errCache := datastore.SetAssetQuotationCache(quotation, true); errCache != nil {
+			log.Warn("refresh quotation cache: ", errCache)
+		}
+	} else if err == nil && o.writeCache() {
+		datastore.fillAssetQuotationCache(quotation)
+	}
+	return quotation, err
 }
 
 // GetAssetQuotation returns the latest full quotation for @asset before @timestamp.
 func (datastore *DB) GetAssetQuotation(asset dia.Asset, timestamp time.Time) (*AssetQuotation, error) {
 
 	quotation := AssetQuotation{}
-	q := fmt.Sprintf("SELECT price FROM %s WHERE address='%s' AND blockchain='%s' AND time<=%d ORDER BY DESC LIMIT 1", influxDBAssetQuotationsTable, asset.Address, asset.Blockchain, timestamp.UnixNano())
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	q := fmt.Sprintf("SELECT %s FROM %s WHERE address=$address AND blockchain=$blockchain AND time<=$time ORDER BY DESC LIMIT 1", assetQuotationColumns, influxDBAssetQuotationsTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"address":    dia.NormalizeNativeAddress(asset.Blockchain, asset.Address),
+		"blockchain": asset.Blockchain,
+		"time":       timestamp.UnixNano(),
+	})
 	if err != nil {
 		return &quotation, err
 	}
 
 	if len(res) > 0 && len(res[0].Series) > 0 {
 		if len(res[0].Series[0].Values) > 0 {
-			quotation.Time, err = time.Parse(time.RFC3339, res[0].Series[0].Values[0][0].(string))
-			if err != nil {
-				return &quotation, err
-			}
-			quotation.Price, err = res[0].Series[0].Values[0][1].(json.Number).Float64()
+			quotation, err = parseAssetQuotationRow(res[0].Series[0].Values[0])
 			if err != nil {
 				return &quotation, err
 			}
@@ -175,27 +224,24 @@ func (datastore *DB) GetAssetQuotations(asset dia.Asset, starttime time.Time, en
 
 	quotations := []AssetQuotation{}
 	q := fmt.Sprintf(
-		"SELECT price FROM %s WHERE address='%s' AND blockchain='%s' AND time>%d AND time<=%d ORDER BY DESC",
+		"SELECT %s FROM %s WHERE address=$address AND blockchain=$blockchain AND time>$starttime AND time<=$endtime ORDER BY DESC",
+		assetQuotationColumns,
 		influxDBAssetQuotationsTable,
-		asset.Address,
-		asset.Blockchain,
-		starttime.UnixNano(),
-		endtime.UnixNano(),
 	)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
 	if err != nil {
 		return quotations, err
 	}
 
 	if len(res) > 0 && len(res[0].Series) > 0 {
 		for i := range res[0].Series[0].Values {
-			var quotation AssetQuotation
-			quotation.Time, err = time.Parse(time.RFC3339, res[0].Series[0].Values[i][0].(string))
-			if err != nil {
-				return quotations, err
-			}
-			quotation.Price, err = res[0].Series[0].Values[i][1].(json.Number).Float64()
+			quotation, err := parseAssetQuotationRow(res[0].Series[0].Values[i])
 			if err != nil {
 				return quotations, err
 			}
@@ -210,12 +256,21 @@ func (datastore *DB) GetAssetQuotations(asset dia.Asset, starttime time.Time, en
 	return quotations, nil
 }
 
-// SetAssetQuotationCache stores @quotation in redis cache.
+// SetAssetQuotationCache stores @quotation in redis cache for REDIS_TTL_ASSET_QUOTATION_SECONDS.
 // If @check is true, it checks for a more recent quotation first.
+// If redis is unavailable, the write is skipped.
 func (datastore *DB) SetAssetQuotationCache(quotation *AssetQuotation, check bool) (bool, error) {
+	if !cacheAvailable(datastore.redisClient) {
+		skipCacheWrite()
+		return false, nil
+	}
 	if check {
 		// fetch current state of cache
 		cachestate, err := datastore.GetAssetQuotationCache(quotation.Asset)
+		if errors.Is(err, ErrCacheUnavailable) {
+			skipCacheWrite()
+			return false, nil
+		}
 		if err != nil && !errors.Is(err, redis.Nil) {
 			return false, err
 		}
@@ -226,7 +281,25 @@ func (datastore *DB) SetAssetQuotationCache(quotation *AssetQuotation, check boo
 	}
 	// Otherwise write to cache
 	key := getKeyAssetQuotation(quotation.Asset.Blockchain, quotation.Asset.Address)
-	return true, datastore.redisPipe.Set(key, quotation, TimeOutAssetQuotation).Err()
+	return true, datastore.redisPipe.Set(datastore.cacheContext(), key, quotation, assetQuotationCacheTTL).Err()
+}
+
+// fillAssetQuotationCache writes @quotation read from influx to the cache unless the cache holds a quotation
+// of the asset already, such that subsequent reads do not query influx. It never overwrites fresher
+// quotations written by the price computation in the meantime.
+func (datastore *DB) fillAssetQuotationCache(quotation *AssetQuotation) {
+	if !cacheAvailable(datastore.redisClient) {
+		skipCacheWrite()
+		return
+	}
+	if quotation.VolumeYesterdayUSD == 0 {
+		quotation.VolumeYesterdayUSD = datastore.volumeYesterdayUSD(quotation.Asset)
+	}
+	key := getKeyAssetQuotation(quotation.Asset.Blockchain, quotation.Asset.Address)
+	err := datastore.redisClient.SetNX(datastore.cacheContext(), key, quotation, assetQuotationCacheTTL).Err()
+	if err != nil && !checkCacheError(err) {
+		log.Warn("fill asset quotation cache: ", err)
+	}
 }
 
 // GetAssetQuotationCache returns the latest quotation for @asset from the redis cache.
@@ -235,8 +308,14 @@ func (datastore *DB) GetAssetQuotationCache(asset dia.Asset) (*AssetQuotation, e
 	// log.Infof("get asset quotation from cache for asset %s with address %s using key as %s ", asset.Symbol, asset.Address, key)
 
 	quotation := &AssetQuotation{}
+	if !cacheAvailable(datastore.redisClient) {
+		return quotation, ErrCacheUnavailable
+	}
 
-	err := datastore.redisClient.Get(key).Scan(quotation)
+	err := datastore.redisClient.Get(datastore.cacheContext(), key).Scan(quotation)
+	if checkCacheError(err) {
+		return quotation, ErrCacheUnavailable
+	}
 	if err != nil {
 		if !errors.Is(err, redis.Nil) {
 			log.Errorf("GetAssetQuotationCache on %s: %v\n", asset.Name, err)
@@ -246,6 +325,42 @@ func (datastore *DB) GetAssetQuotationCache(asset dia.Asset) (*AssetQuotation, e
 	return quotation, nil
 }
 
+// GetAssetQuotationsCache returns the latest quotations of all @assets which are in the redis cache
+// using a single round trip. Assets without a cached quotation are omitted.
+func (datastore *DB) GetAssetQuotationsCache(assets []dia.Asset) ([]AssetQuotation, error) {
+	quotations := []AssetQuotation{}
+	if len(assets) == 0 {
+		return quotations, nil
+	}
+	if !cacheAvailable(datastore.redisClient) {
+		return quotations, ErrCacheUnavailable
+	}
+	var keys []string
+	for _, asset := range assets {
+		keys = append(keys, getKeyAssetQuotation(asset.Blockchain, asset.Address))
+	}
+	result, err := cacheMGet(datastore.cacheContext(), datastore.redisClient, keys)
+	if err != nil {
+		if checkCacheError(err) {
+			return quotations, ErrCacheUnavailable
+		}
+		return quotations, err
+	}
+	for _, val := range result {
+		if val == nil {
+			continue
+		}
+		var quotation AssetQuotation
+		err = json.Unmarshal([]byte(fmt.Sprint(val)), &quotation)
+		if err != nil {
+			log.Error("unmarshal asset quotation: ", err)
+			continue
+		}
+		quotations = append(quotations, quotation)
+	}
+	return quotations, nil
+}
+
 // GetAssetPriceUSDCache returns the latest price of @asset from the cache.
 func (datastore *DB) GetAssetPriceUSDCache(asset dia.Asset) (price float64, err error) {
 	quotation, err := datastore.GetAssetQuotationCache(asset)
@@ -295,23 +410,18 @@ func (datastore *DB) GetSortedAssetQuotations(assets []dia.Asset) ([]AssetQuotat
 func (datastore *DB) GetOldestQuotation(asset dia.Asset) (quotation AssetQuotation, err error) {
 
 	q := fmt.Sprintf(`
-	SELECT price FROM %s WHERE address='%s' AND blockchain='%s' ORDER BY ASC LIMIT 1`,
+	SELECT %s FROM %s WHERE address=$address AND blockchain=$blockchain ORDER BY ASC LIMIT 1`,
+		assetQuotationColumns,
 		influxDBAssetQuotationsTable,
-		asset.Address,
-		asset.Blockchain,
 	)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"address": asset.Address, "blockchain": asset.Blockchain})
 	if err != nil {
 		return
 	}
 
 	if len(res) > 0 && len(res[0].Series) > 0 {
 		if len(res[0].Series[0].Values) > 0 {
-			quotation.Time, err = time.Parse(time.RFC3339, res[0].Series[0].Values[0][0].(string))
-			if err != nil {
-				return quotation, err
-			}
-			quotation.Price, err = res[0].Series[0].Values[0][1].(json.Number).Float64()
+			quotation, err = parseAssetQuotationRow(res[0].Series[0].Values[0])
 			if err != nil {
 				return
 			}
@@ -358,7 +468,27 @@ func (rdb *RelDB) SetHistoricalQuotation(quotation AssetQuotation) error {
 }
 
 // GetHistoricalQuotations returns all historical quotations of @asset in the given time range.
-func (rdb *RelDB) GetHistoricalQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) (quotations []AssetQuotation, err error) {
+// Quotations from the archive are merged with those from the historicalquotation table.
+func (rdb *RelDB) GetHistoricalQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) ([]AssetQuotation, error) {
+	quotations, err := rdb.getHotHistoricalQuotations(asset, starttime, endtime)
+	if err != nil {
+		return quotations, err
+	}
+	archived, err := rdb.getArchivedQuotations(asset, starttime, endtime)
+	if err != nil {
+		return quotations, err
+	}
+	if len(archived) == 0 {
+		return quotations, nil
+	}
+	quotations = append(archived, quotations...)
+	sort.SliceStable(quotations, func(i, j int) bool { return quotations[i].Time.Before(quotations[j].Time) })
+	return quotations, nil
+}
+
+// getHotHistoricalQuotations returns the historical quotations of @asset in the given time range
+// which are not archived yet.
+func (rdb *RelDB) getHotHistoricalQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) (quotations []AssetQuotation, err error) {
 	query := fmt.Sprintf(`
 	SELECT hq.price,hq.quote_time,hq.source,a.decimals 
 	FROM %s hq
@@ -430,6 +560,10 @@ func (rdb *RelDB) GetLastHistoricalQuotationTimestamp(asset dia.Asset) (timestam
 	)
 	var t sql.NullTime
 	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// All quotations of the asset may be archived.
+		return rdb.getLastArchivedQuotationTimestamp(asset)
+	}
 	if err != nil {
 		return
 	}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+func TestAssetQuotationRoundTrip(t *testing.T) {
+	asset := dia.Asset{Symbol: "WETH", Blockchain: dia.ETHEREUM, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"}
+	for _, written := range []AssetQuotation{
+		{Asset: asset, Price: 1800.5, Time: time.Unix(1700000000, 0).UTC(), Source: dia.Diadata},
+		{Asset: asset, Price: 1800.5, Time: time.Unix(1700000000, 0).UTC(), Source: dia.Diadata, CarriedForward: true, Age: 240},
+	} {
+		// Serve the fields as written by SetAssetQuotation in the response format of influx.
+		fields := assetQuotationFields(&written)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response := map[string]interface{}{
+				"results": []interface{}{map[string]interface{}{
+					"series": []interface{}{map[string]interface{}{
+						"name":    influxDBAssetQuotationsTable,
+						"columns": []string{"time", "price", "carriedforward", "age"},
+						"values":  [][]interface{}{{written.Time.Format(time.RFC3339), fields["price"], fields["carriedforward"], fields["age"]}},
+					}},
+				}},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(response); err != nil {
+				t.Error(err)
+			}
+		}))
+		client, err := clientInfluxdb.NewHTTPClient(clientInfluxdb.HTTPConfig{Addr: server.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		datastore := &DB{influxClient: client}
+
+		read, err := datastore.GetAssetQuotation(asset, written.Time)
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read.Price != written.Price || !read.Time.Equal(written.Time) || read.CarriedForward != written.CarriedForward || read.Age != written.Age {
+			t.Errorf("expected %v, got %v", written, *read)
+		}
+	}
+}
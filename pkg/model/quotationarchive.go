@@ -0,0 +1,259 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+// archivedQuotation is the compact representation of a historical quotation in the archive.
+type archivedQuotation struct {
+	Time   int64   `json:"t"`
+	Price  float64 `json:"p"`
+	Source string  `json:"s"`
+}
+
+// encodeArchivedQuotations returns the gzip compressed json encoding of @quotations.
+func encodeArchivedQuotations(quotations []archivedQuotation) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	err := json.NewEncoder(zw).Encode(quotations)
+	if err != nil {
+		return nil, err
+	}
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeArchivedQuotations(data []byte) (quotations []archivedQuotation, err error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	defer zr.Close()
+	raw, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(raw, &quotations)
+	return
+}
+
+// mergeArchivedQuotations returns the union of @a and @b ordered by time. Quotations with equal time
+// and source are kept once.
+func mergeArchivedQuotations(a []archivedQuotation, b []archivedQuotation) []archivedQuotation {
+	type key struct {
+		time   int64
+		source string
+	}
+	seen := make(map[key]struct{})
+	var merged []archivedQuotation
+	for _, quotation := range append(append([]archivedQuotation{}, a...), b...) {
+		k := key{quotation.Time, quotation.Source}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		merged = append(merged, quotation)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time < merged[j].Time })
+	return merged
+}
+
+// ArchiveHistoricalQuotations moves all historical quotations of months before the month of @before
+// from the historicalquotation table into the compressed archive. Each asset and month is moved in a
+// transaction of its own and merged with quotations archived before. It returns the number of moved quotations.
+func (rdb *RelDB) ArchiveHistoricalQuotations(before time.Time) (archived int, err error) {
+	before = time.Date(before.Year(), before.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	query := fmt.Sprintf(`
+	SELECT asset_id,date_trunc('month',quote_time)::date AS month
+	FROM %s
+	WHERE quote_time<$1
+	GROUP BY asset_id,month
+	ORDER BY month,asset_id
+	`, historicalQuotationTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, before)
+	if err != nil {
+		return
+	}
+	type assetMonth struct {
+		assetID string
+		month   time.Time
+	}
+	var months []assetMonth
+	for rows.Next() {
+		var am assetMonth
+		err = rows.Scan(&am.assetID, &am.month)
+		if err != nil {
+			rows.Close()
+			return
+		}
+		months = append(months, am)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for _, am := range months {
+		var n int
+		n, err = rdb.archiveMonth(am.assetID, am.month)
+		if err != nil {
+			return archived, fmt.Errorf("archive %s of asset %s: %v", am.month.Format("2006-01"), am.assetID, err)
+		}
+		archived += n
+	}
+	return
+}
+
+// archiveMonth moves the historical quotations of the asset @assetID in @month into the archive.
+func (rdb *RelDB) archiveMonth(assetID string, month time.Time) (archived int, err error) {
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE asset_id=$1 AND quote_time>=$2 AND quote_time<$3
+		RETURNING quote_time,price,source
+		`, historicalQuotationTable)
+		rows, err := txRDB.postgresClient.Query(context.Background(), query, assetID, month, month.AddDate(0, 1, 0))
+		if err != nil {
+			return err
+		}
+		var quotations []archivedQuotation
+		for rows.Next() {
+			var (
+				quoteTime time.Time
+				price     sql.NullFloat64
+				source    sql.NullString
+			)
+			err = rows.Scan(&quoteTime, &price, &source)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			quotations = append(quotations, archivedQuotation{Time: quoteTime.Unix(), Price: price.Float64, Source: source.String})
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		var data []byte
+		query = fmt.Sprintf("SELECT data FROM %s WHERE asset_id=$1 AND month=$2 FOR UPDATE", quotationArchiveTable)
+		err = txRDB.postgresClient.QueryRow(context.Background(), query, assetID, month).Scan(&data)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		var existing []archivedQuotation
+		if len(data) > 0 {
+			existing, err = decodeArchivedQuotations(data)
+			if err != nil {
+				return err
+			}
+		}
+		quotations = mergeArchivedQuotations(existing, quotations)
+
+		data, err = encodeArchivedQuotations(quotations)
+		if err != nil {
+			return err
+		}
+		query = fmt.Sprintf(`
+		INSERT INTO %s (asset_id,month,num_quotations,data) VALUES ($1,$2,$3,$4)
+		ON CONFLICT (asset_id,month) DO UPDATE SET num_quotations=EXCLUDED.num_quotations,data=EXCLUDED.data
+		`, quotationArchiveTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, assetID, month, len(quotations), data)
+		if err != nil {
+			return err
+		}
+		archived = len(quotations)
+		return nil
+	})
+	return
+}
+
+// getArchivedQuotations returns the archived quotations of @asset in the open time range (@starttime,@endtime).
+func (rdb *RelDB) getArchivedQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) (quotations []AssetQuotation, err error) {
+	query := fmt.Sprintf(`
+	SELECT hqa.data,a.decimals
+	FROM %s hqa
+	INNER JOIN %s a
+	ON hqa.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	AND hqa.month>=date_trunc('month',to_timestamp($3))::date
+	AND hqa.month<to_timestamp($4)
+	ORDER BY hqa.month ASC
+	`, quotationArchiveTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, asset.Address, asset.Blockchain, starttime.Unix(), endtime.Unix())
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			data     []byte
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&data, &decimals)
+		if err != nil {
+			return
+		}
+		var archived []archivedQuotation
+		archived, err = decodeArchivedQuotations(data)
+		if err != nil {
+			return
+		}
+		for _, aq := range archived {
+			if aq.Time <= starttime.Unix() || aq.Time >= endtime.Unix() {
+				continue
+			}
+			quotation := AssetQuotation{
+				Asset:  asset,
+				Price:  aq.Price,
+				Source: aq.Source,
+				Time:   time.Unix(aq.Time, 0).UTC(),
+			}
+			quotation.Asset.Decimals = uint8(decimals.Int64)
+			quotations = append(quotations, quotation)
+		}
+	}
+	err = rows.Err()
+	return
+}
+
+// getLastArchivedQuotationTimestamp returns the time of the last archived quotation of @asset.
+func (rdb *RelDB) getLastArchivedQuotationTimestamp(asset dia.Asset) (timestamp time.Time, err error) {
+	query := fmt.Sprintf(`
+	SELECT hqa.data
+	FROM %s hqa
+	INNER JOIN %s a
+	ON hqa.asset_id=a.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	ORDER BY hqa.month DESC
+	LIMIT 1
+	`, quotationArchiveTable, assetTable)
+	var data []byte
+	err = rdb.postgresClient.QueryRow(context.Background(), query, asset.Address, asset.Blockchain).Scan(&data)
+	if err != nil {
+		return
+	}
+	archived, err := decodeArchivedQuotations(data)
+	if err != nil || len(archived) == 0 {
+		return
+	}
+	timestamp = time.Unix(archived[len(archived)-1].Time, 0).UTC()
+	return
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArchivedQuotations(t *testing.T) {
+	archived := []archivedQuotation{
+		{Time: 1700000000, Price: 1.5, Source: "CoinGecko"},
+		{Time: 1700086400, Price: 1.6, Source: "CoinGecko"},
+	}
+	moved := []archivedQuotation{
+		{Time: 1700043200, Price: 1.55, Source: "CoinGecko"},
+		{Time: 1700086400, Price: 1.6, Source: "CoinGecko"},
+	}
+	merged := mergeArchivedQuotations(archived, moved)
+	expected := []archivedQuotation{archived[0], moved[0], archived[1]}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("expected %v, got %v", expected, merged)
+	}
+
+	data, err := encodeArchivedQuotations(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeArchivedQuotations(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, merged) {
+		t.Errorf("expected %v after round trip, got %v", merged, decoded)
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// QuotationQuality classifies how far a quotation can be relied on.
+type QuotationQuality string
+
+const (
+	// QualityFresh is a price computed from recent trades.
+	QualityFresh QuotationQuality = "fresh"
+	// QualityCarried is a recent price carried forward by the heartbeat in absence of trades.
+	QualityCarried QuotationQuality = "carried"
+	// QualityStale is a price older than the maximal age.
+	QualityStale QuotationQuality = "stale"
+	// QualityUnavailable means that there is no price at all.
+	QualityUnavailable QuotationQuality = "unavailable"
+)
+
+// QuotationEnvelope is the uniform response of quotation getters, so that consumers need not
+// interpret zero values. @StalenessSeconds is the age of the underlying price at the time of the request.
+type QuotationEnvelope struct {
+	Asset            dia.Asset        `json:"Asset"`
+	Price            float64          `json:"Price"`
+	Time             time.Time        `json:"Time"`
+	SourceCount      int              `json:"SourceCount"`
+	StalenessSeconds int64            `json:"StalenessSeconds"`
+	Quality          QuotationQuality `json:"Quality"`
+	Error            string           `json:"Error,omitempty"`
+}
+
+// NewQuotationEnvelope returns the envelope of @quotation at time @now. Prices older than @maxAge
+// are stale. Carried forward prices are dated back to the time of the underlying price.
+func NewQuotationEnvelope(quotation AssetQuotation, sourceCount int, now time.Time, maxAge time.Duration) QuotationEnvelope {
+	if quotation.Price <= 0 || quotation.Time.IsZero() {
+		return UnavailableQuotationEnvelope(quotation.Asset, "no price")
+	}
+	priceTime := quotation.Time
+	if quotation.CarriedForward {
+		priceTime = priceTime.Add(-time.Duration(quotation.Age) * time.Second)
+	}
+	staleness := now.Sub(priceTime)
+	if staleness < 0 {
+		staleness = 0
+	}
+
+	envelope := QuotationEnvelope{
+		Asset:            quotation.Asset,
+		Price:            quotation.Price,
+		Time:             quotation.Time,
+		SourceCount:      sourceCount,
+		StalenessSeconds: int64(staleness.Seconds()),
+	}
+	switch {
+	case maxAge > 0 && staleness > maxAge:
+		envelope.Quality = QualityStale
+	case quotation.CarriedForward:
+		envelope.Quality = QualityCarried
+	default:
+		envelope.Quality = QualityFresh
+	}
+	return envelope
+}
+
+// UnavailableQuotationEnvelope returns the envelope of @asset without price together with the reason @reason.
+func UnavailableQuotationEnvelope(asset dia.Asset, reason string) QuotationEnvelope {
+	return QuotationEnvelope{
+		Asset:            asset,
+		StalenessSeconds: -1,
+		Quality:          QualityUnavailable,
+		Error:            reason,
+	}
+}
+
+// GetQuotationEnvelope returns the envelope of the quotation of @asset at @timestamp. The source count is
+// the number of exchanges with a price of @asset within @maxAge before the quotation.
+func (datastore *DB) GetQuotationEnvelope(asset dia.Asset, timestamp time.Time, maxAge time.Duration) QuotationEnvelope {
+	quotation, err := datastore.GetAssetQuotation(asset, timestamp)
+	if err != nil {
+		return UnavailableQuotationEnvelope(asset, err.Error())
+	}
+	quotation.Asset = asset
+	sources, err := datastore.GetFilterAllExchanges(dia.FilterKing, asset.Address, asset.Blockchain, quotation.Time.Add(-maxAge), quotation.Time)
+	if err != nil {
+		log.Warnf("get sources of %s: %v", asset.Identifier(), err)
+	}
+	return NewQuotationEnvelope(*quotation, len(sources), timestamp, maxAge)
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewQuotationEnvelope(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name      string
+		quotation AssetQuotation
+		quality   QuotationQuality
+		staleness int64
+	}{
+		{"fresh", AssetQuotation{Price: 1, Time: now.Add(-time.Minute)}, QualityFresh, 60},
+		{"carried", AssetQuotation{Price: 1, Time: now.Add(-time.Minute), CarriedForward: true, Age: 240}, QualityCarried, 300},
+		{"carried beyond max age", AssetQuotation{Price: 1, Time: now, CarriedForward: true, Age: 7200}, QualityStale, 7200},
+		{"stale", AssetQuotation{Price: 1, Time: now.Add(-2 * time.Hour)}, QualityStale, 7200},
+		{"unavailable", AssetQuotation{Time: now}, QualityUnavailable, -1},
+	}
+	for _, tt := range tests {
+		envelope := NewQuotationEnvelope(tt.quotation, 3, now, time.Hour)
+		if envelope.Quality != tt.quality || envelope.StalenessSeconds != tt.staleness {
+			t.Errorf("%s: expected %s with staleness %d, got %s with staleness %d", tt.name, tt.quality, tt.staleness, envelope.Quality, envelope.StalenessSeconds)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// SetQuoteAssetConstraint restricts the quote assets accepted for price computation of @asset to @quoteAssets.
+// In a trade, the quote asset is the basetoken the traded asset is priced in.
+// Existing constraints of @asset are replaced. An empty @quoteAssets removes all constraints.
+func (rdb *RelDB) SetQuoteAssetConstraint(asset dia.Asset, quoteAssets []dia.Asset) error {
+	assetID, err := rdb.GetAssetID(asset)
+	if err != nil {
+		return err
+	}
+
+	return rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		_, err := txRDB.postgresClient.Exec(context.Background(), fmt.Sprintf("DELETE FROM %s WHERE asset_id=$1", quoteConstraintTable), assetID)
+		if err != nil {
+			return err
+		}
+		query := fmt.Sprintf(`
+		INSERT INTO %s (asset_id,quoteasset_id)
+		VALUES ($1,(SELECT asset_id FROM %s WHERE address=$2 AND blockchain=$3))
+		ON CONFLICT (asset_id,quoteasset_id) DO NOTHING
+		`, quoteConstraintTable, assetTable)
+		for _, quoteAsset := range quoteAssets {
+			_, err = txRDB.postgresClient.Exec(context.Background(), query, assetID, quoteAsset.Address, quoteAsset.Blockchain)
+			if err != nil {
+				return fmt.Errorf("quote asset %s: %v", quoteAsset.Identifier(), err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetQuoteAssetConstraint returns the quote assets accepted for price computation of @asset.
+// An empty slice means that all quote assets are accepted.
+func (rdb *RelDB) GetQuoteAssetConstraint(asset dia.Asset) (quoteAssets []dia.Asset, err error) {
+	query := fmt.Sprintf(`
+	SELECT q.symbol,q.name,q.address,q.blockchain,q.decimals
+	FROM %s qc
+	INNER JOIN %s a
+	ON qc.asset_id=a.asset_id
+	INNER JOIN %s q
+	ON qc.quoteasset_id=q.asset_id
+	WHERE a.address=$1 AND a.blockchain=$2
+	`, quoteConstraintTable, assetTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, asset.Address, asset.Blockchain)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			quoteAsset dia.Asset
+			decimals   sql.NullInt64
+		)
+		err = rows.Scan(&quoteAsset.Symbol, &quoteAsset.Name, &quoteAsset.Address, &quoteAsset.Blockchain, &decimals)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			quoteAsset.Decimals = uint8(decimals.Int64)
+		}
+		quoteAssets = append(quoteAssets, quoteAsset)
+	}
+	return
+}
+
+// GetAllQuoteAssetConstraints returns the accepted quote assets for all constrained assets.
+// Map keys and values only contain address and blockchain of the assets.
+func (rdb *RelDB) GetAllQuoteAssetConstraints() (map[dia.Asset][]dia.Asset, error) {
+	constraints := make(map[dia.Asset][]dia.Asset)
+	query := fmt.Sprintf(`
+	SELECT a.address,a.blockchain,q.address,q.blockchain
+	FROM %s qc
+	INNER JOIN %s a
+	ON qc.asset_id=a.asset_id
+	INNER JOIN %s q
+	ON qc.quoteasset_id=q.asset_id
+	`, quoteConstraintTable, assetTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return constraints, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var asset, quoteAsset dia.Asset
+		err = rows.Scan(&asset.Address, &asset.Blockchain, &quoteAsset.Address, &quoteAsset.Blockchain)
+		if err != nil {
+			return constraints, err
+		}
+		constraints[asset] = append(constraints[asset], quoteAsset)
+	}
+	return constraints, nil
+}
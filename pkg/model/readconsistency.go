@@ -0,0 +1,52 @@
+package models
+
+// ReadConsistency determines how a read method uses the redis and in-memory caches.
+type ReadConsistency int
+
+const (
+	// CacheFirst reads from the caches and falls back to the database on a miss.
+	// This is the default of read methods which do not document another one.
+	CacheFirst ReadConsistency = iota
+	// DatabaseOnly bypasses the caches and neither reads nor writes them.
+	DatabaseOnly
+	// CacheRefresh reads from the database and updates the caches with the result.
+	CacheRefresh
+)
+
+// ReadOption configures a single call of a read method.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	consistency ReadConsistency
+}
+
+// WithReadConsistency sets the @consistency of a read. Correctness-critical consumers such as
+// oracle feeders read with DatabaseOnly, latency-sensitive consumers keep the default CacheFirst.
+func WithReadConsistency(consistency ReadConsistency) ReadOption {
+	return func(o *readOptions) {
+		o.consistency = consistency
+	}
+}
+
+func newReadOptions(opts []ReadOption) readOptions {
+	return newReadOptionsDefault(CacheFirst, opts)
+}
+
+// newReadOptionsDefault returns the read options given by @opts, with @consistency unless they set another one.
+func newReadOptionsDefault(consistency ReadConsistency, opts []ReadOption) readOptions {
+	o := readOptions{consistency: consistency}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// readCache returns true if the caches may serve the read.
+func (o readOptions) readCache() bool {
+	return o.consistency == CacheFirst
+}
+
+// writeCache returns true if the result of a database read is written to the caches.
+func (o readOptions) writeCache() bool {
+	return o.consistency != DatabaseOnly
+}
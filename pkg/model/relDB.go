@@ -2,15 +2,21 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers/db"
 
-	"github.com/go-redis/redis"
+	"github.com/go-redis/redis/v8"
 )
 
 // RelDatastore is a (persistent) relational database with an additional redis caching layer
@@ -18,20 +24,85 @@ type RelDatastore interface {
 
 	// --- Assets methods ---
 	// --------- Persistent ---------
+	WithTx(ctx context.Context, fn func(tx RelStore) error) error
 	SetAsset(asset dia.Asset) error
-	GetAsset(address, blockchain string) (dia.Asset, error)
-	GetAssetByID(ID string) (dia.Asset, error)
+	UpdateAsset(asset dia.Asset) error
+	DeleteAsset(asset dia.Asset) error
+	GetAssetHistory(assetID string) ([]AssetHistoryEntry, error)
+	SubmitPendingAsset(asset dia.Asset, source string, rules []AutoApprovalRule) (PendingAssetStatus, error)
+	GetPendingAssets(status PendingAssetStatus, limit int, offset int) ([]PendingAsset, error)
+	EditPendingAsset(id string, asset dia.Asset) error
+	ApprovePendingAsset(id string, reviewer string) error
+	RejectPendingAsset(id string, reviewer string, note string) error
+	DeprecateAsset(asset dia.Asset, timestamp time.Time) error
+	ReactivateAsset(asset dia.Asset) error
+	GetActiveAssetsOnly(blockchain string) ([]dia.Asset, error)
+	GetDeprecatedAssets(blockchain string) ([]dia.Asset, error)
+	GetAsset(address, blockchain string, opts ...ReadOption) (dia.Asset, error)
+	GetAssetByID(ID string, opts ...ReadOption) (dia.Asset, error)
+	GetAssetsByIDs(assetIDs []string, opts ...ReadOption) (map[string]dia.Asset, error)
 	GetAssetsBySymbolName(symbol, name string) ([]dia.Asset, error)
+	AddAssetTicker(asset dia.Asset, symbol string, validFrom time.Time, validTo time.Time) error
+	GetAssetTickers(asset dia.Asset) ([]AssetTicker, error)
+	GetAssetsByTicker(symbol string) ([]AssetTickerMatch, error)
+	SearchAssets(query string, limit int) ([]dia.Asset, error)
 	GetAllAssets(blockchain string) ([]dia.Asset, error)
+	GetAssetsByBlockchain(blockchain string, symbolPrefix string, order AssetOrder, limit int, offset int) ([]dia.Asset, error)
 	GetFiatAssetBySymbol(symbol string) (asset dia.Asset, err error)
 	IdentifyAsset(asset dia.Asset) ([]dia.Asset, error)
+	IdentifyAssetMatches(asset dia.Asset) ([]AssetMatch, error)
+	SetAssetAlias(asset dia.Asset, alias string) error
+	GetAssetByAlias(alias string) ([]dia.Asset, error)
+	LinkAssets(assets []dia.Asset) (string, error)
+	UnlinkAsset(asset dia.Asset) error
+	GetAssetGroup(address string, blockchain string) ([]dia.Asset, error)
+	GetAssetGroupID(asset dia.Asset) (string, error)
+	GetAssetGroups() (map[string][]dia.AssetVolume, error)
 	GetAssetID(asset dia.Asset) (string, error)
 	GetPage(pageNumber uint32) ([]dia.Asset, bool, error)
+	GetAssetsPage(cursor string, pagesize int) ([]dia.Asset, string, error)
 	Count() (uint32, error)
-	SetAssetVolume24H(asset dia.Asset, volume float64, timestamp time.Time) error
-	GetLastAssetVolume24H(asset dia.Asset) (float64, error)
+	GetSummaryCounts(opts ...ReadOption) (SummaryCounts, error)
+	GetExchangeSummaryCounts(exchange string, opts ...ReadOption) (ExchangeCounts, error)
+	RefreshSummaryCounts() (SummaryCounts, error)
+	SetAssetVolume24H(asset dia.Asset, volume AssetVolume24H) error
+	GetLastAssetVolume24H(asset dia.Asset) (AssetVolume24H, error)
+	SetAssetVolumeAt(asset dia.Asset, volume float64, timestamp time.Time) error
+	GetAssetVolumeSeries(asset dia.Asset, starttime time.Time, endtime time.Time) ([]AssetVolumeDay, error)
+	SetAssetVolumeByExchange(asset dia.Asset, exchange string, volume float64) error
+	GetAssetVolumeBreakdown(asset dia.Asset) (dia.ExchangeVolumesList, error)
+	SetSupply(supply dia.Supply) error
+	GetLatestSupply(asset dia.Asset) (dia.Supply, error)
+	GetLatestSupplies(blockchain string) ([]dia.Supply, error)
+	SnapshotAssetRanks(timestamp time.Time, marketCaps []MarketCap) (int64, error)
+	GetAssetRankHistory(asset dia.Asset) ([]AssetRank, error)
+	GetSectorAggregates() ([]SectorAggregate, error)
+	GetTopAssetsByVolume(starttime time.Time, limit int, offset int, blockchain string) ([]dia.AssetVolume, error)
 	GetAssetsWithVOL(starttime time.Time, numAssets int64, skip int64, onlycex bool, substring string) ([]dia.AssetVolume, error)
 	GetAssetSource(asset dia.Asset, onlycex bool) ([]string, error)
+	GetAssetSources(asset dia.Asset) ([]string, []string, error)
+	GetAssetSnapshot(asset dia.Asset) (AssetSnapshot, error)
+	SetAssetMetadata(metadata dia.AssetMetadata) error
+	GetAssetMetadata(asset dia.Asset) (dia.AssetMetadata, error)
+	GetAssetDisplayFormat(asset dia.Asset) (dia.DisplayFormat, error)
+	SetBenchmarkAsset(benchmark dia.BenchmarkAsset) error
+	GetBenchmarkAssets() ([]dia.BenchmarkAsset, error)
+	DeleteBenchmarkAsset(asset dia.Asset) error
+	SetServiceConfig(service string, key string, value string) error
+	GetServiceConfig(service string) (map[string]string, error)
+	SetFeedRestriction(feed string, region string) error
+	DeleteFeedRestriction(feed string, region string) error
+	GetFeedRestrictions() ([]FeedRestriction, error)
+	SetAPIKeyRegion(apiKey string, region string) error
+	GetAPIKeyRegion(apiKey string) (string, error)
+	IsFeedAvailable(feed string, region string) (bool, error)
+	ImportTokenList(r io.Reader) (int, error)
+	ImportTokenListFromURL(url string) (int, error)
+	ExportAssetsCSV(w io.Writer, blockchain string) error
+	ImportAssetsCSV(r io.Reader, dryRun bool) (AssetImportReport, error)
+	SetQuoteAssetConstraint(asset dia.Asset, quoteAssets []dia.Asset) error
+	GetQuoteAssetConstraint(asset dia.Asset) ([]dia.Asset, error)
+	GetAllQuoteAssetConstraints() (map[dia.Asset][]dia.Asset, error)
 	GetAssetsWithVolByBlockchain(starttime time.Time, endtime time.Time, blockchain string) ([]dia.AssetVolume, error)
 
 	// --------------- asset methods for exchanges ---------------
@@ -40,20 +111,42 @@ type RelDatastore interface {
 	GetExchangePairSeparator(exchange string) (string, error)
 	GetPairsForExchange(exchange dia.Exchange, filterVerified bool, verified bool) ([]dia.ExchangePair, error)
 	GetPairsForAsset(asset dia.Asset, filterVerified bool, verified bool) ([]dia.ExchangePair, error)
+	SnapshotExchangePairs(timestamp time.Time) (int64, int64, error)
+	GetExchangePairsAt(asset dia.Asset, timestamp time.Time) ([]dia.ExchangePair, error)
 	GetExchangePairSymbols(exchange string) ([]dia.ExchangePair, error)
+	GetAllExchangePairs(exchange string, limit int, offset int) ([]dia.ExchangePair, error)
+	GetUnverifiedExchangePairs(exchange string) ([]dia.ExchangePair, error)
+	SetExchangePairs(exchange string, pairs []dia.ExchangePair) error
+	ReconcileExchangePairs(exchange string, pairs []dia.ExchangePair) (PairReconciliation, error)
+	DeleteExchangePair(exchange string, foreignname string, markDelisted bool) error
+	SetExchangePairExcluded(exchange string, foreignname string, excluded bool) error
+	GetExcludedExchangePairs() ([]dia.ExchangePair, error)
 	GetNumPairs(exchange dia.Exchange) (int, error)
 	SetExchangeSymbol(exchange string, symbol string) error
 	GetExchangeSymbol(exchange string, symbol string) (dia.Asset, error)
-	GetExchangeSymbols(exchange string, substring string) ([]string, error)
+	GetExchangeSymbols(filter ExchangeSymbolFilter) ([]ExchangeSymbol, error)
+	GetExchangeSymbolsPage(filter ExchangeSymbolFilter) (ExchangeSymbolPage, error)
 	GetUnverifiedExchangeSymbols(exchange string) ([]string, error)
-	VerifyExchangeSymbol(exchange string, symbol string, assetID string) (bool, error)
+	GetStaleExchangeSymbols(exchange string, olderThan time.Duration) ([]string, error)
+	VerifyExchangeSymbol(exchange string, symbol string, assetID string, source string) (bool, error)
+	UnverifyExchangeSymbol(exchange string, symbol string, reviewer string) (bool, error)
+	GetVerificationHistory(exchange string, symbol string) ([]SymbolVerificationEntry, error)
+	DeleteExchangeSymbol(exchange string, symbol string, markDelisted bool) error
+	SetSymbolLabel(label SymbolLabel) error
+	GetSymbolLabels(exchange string) ([]SymbolLabel, error)
+	GetSymbolScorer() (*SymbolScorer, error)
+	GetSymbolSuggestions(exchange string, symbol string, scorer *SymbolScorer) ([]SymbolSuggestion, error)
 	GetExchangeSymbolAssetID(exchange string, symbol string) (string, bool, error)
+	SetSymbolCasing(canonical string) error
+	GetSymbolCasings() (map[string]string, error)
+	CanonicalSymbol(symbol string) string
 	GetAllExchangeAssets(verified bool) ([]dia.Asset, error)
 
 	// ----------------- Historical quotations methods -------------------
 	SetHistoricalQuotation(quotation AssetQuotation) error
 	GetHistoricalQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) ([]AssetQuotation, error)
 	GetLastHistoricalQuotationTimestamp(asset dia.Asset) (time.Time, error)
+	ArchiveHistoricalQuotations(before time.Time) (int, error)
 
 	// ----------------- exchange methods -------------------
 	SetExchange(exchange dia.Exchange) error
@@ -61,25 +154,60 @@ type RelDatastore interface {
 	GetAllExchanges() ([]dia.Exchange, error)
 	GetExchangeNames() ([]string, error)
 
+	// ----------------- exchange listing methods -------------------
+	SetExchangeListing(listing dia.ExchangeListing) error
+	GetExchangeListings(exchange string, starttime time.Time, endtime time.Time) ([]dia.ExchangeListing, error)
+	GetUpcomingListings(exchange string) ([]dia.ExchangeListing, error)
+
 	// ----------------- pool methods -------------------
 	SetPool(pool dia.Pool) error
 	GetPoolByAddress(blockchain string, address string) (pool dia.Pool, err error)
 	GetAllPoolAddrsExchange(exchange string, liquiThreshold float64) ([]string, error)
 	GetAllPoolsExchange(exchange string, liquiThreshold float64) ([]dia.Pool, error)
 	GetPoolsByAsset(asset dia.Asset, liquidityThreshold float64, liquidityThresholdUSD float64) ([]dia.Pool, error)
+	SetExchangePairPool(exchange string, foreignname string, blockchain string, poolAddress string) error
+	DeleteExchangePairPool(exchange string, foreignname string, blockchain string, poolAddress string) error
+	GetPoolsForExchangePair(exchange string, foreignname string) ([]dia.Pool, error)
+	GetExchangePairsForPool(blockchain string, poolAddress string) ([]dia.ExchangePair, error)
+
+	// ----------------- DeFi methods -------------------
+	SetTVL(tvl dia.ProtocolTVL) error
+	GetTVL(protocol string, blockchain string, timestamp time.Time) (dia.ProtocolTVL, error)
+	GetTVLByChain(timestamp time.Time) ([]dia.ChainTVL, error)
+	SetDefiProtocol(protocol dia.DefiProtocol) error
+	GetDefiProtocol(name string) (dia.DefiProtocol, error)
+	GetDefiProtocols() ([]dia.DefiProtocol, error)
 
 	// ----------------- blockchain methods -------------------
 	SetBlockchain(blockchain dia.BlockChain) error
 	GetBlockchain(name string) (dia.BlockChain, error)
+	UpdateBlockchain(name string, update BlockchainUpdate) error
+	DeleteBlockchain(name string) error
+	SetBlockchainRPC(rpc BlockchainRPC) error
+	DeleteBlockchainRPC(blockchain string, restURL string) error
+	GetBlockchainRPCs(blockchain string) ([]BlockchainRPC, error)
+	SetBlockchainRPCHealth(blockchain string, restURL string, healthy bool) error
+	GetHealthyRPC(blockchain string) (BlockchainRPC, error)
 	GetAllAssetsBlockchains() ([]string, error)
 	GetAllBlockchains(fullAsset bool) ([]dia.BlockChain, error)
+	GetBlockchainsByType(chainType dia.ChainType, includeTestnets bool) ([]dia.BlockChain, error)
 
 	// ------ Caching ------
 	SetAssetCache(asset dia.Asset) error
 	GetAssetCache(blockchain string, address string) (dia.Asset, error)
+	SetAssetCacheContext(ctx context.Context, asset dia.Asset) error
+	GetAssetCacheContext(ctx context.Context, blockchain string, address string) (dia.Asset, error)
+	SetAssetCacheBatch(assets []dia.Asset) error
+	GetAssetCacheBatch(assets []dia.Asset) ([]dia.Asset, error)
+	InvalidateAssetCache(asset dia.Asset) error
 	SetExchangePairCache(exchange string, pair dia.ExchangePair) error
 	GetExchangePairCache(exchange string, foreignName string) (dia.ExchangePair, error)
+	SetExchangePairCacheContext(ctx context.Context, exchange string, pair dia.ExchangePair) error
+	GetExchangePairCacheContext(ctx context.Context, exchange string, foreignName string) (dia.ExchangePair, error)
 	CountCache() (uint32, error)
+	CountCacheByClass() (map[string]uint32, error)
+	CheckCacheIntegrity(repair bool) (CacheIntegrityReport, error)
+	WarmAssetCache(ctx context.Context, blockchain string, opts ...WarmupOption) (AssetCacheWarmup, error)
 
 	// ---------------- NFT methods -------------------
 	// NFT class methods
@@ -98,6 +226,7 @@ type RelDatastore interface {
 	SetNFT(nft dia.NFT) error
 	GetNFT(address string, blockchain string, tokenID string) (dia.NFT, error)
 	GetNFTID(address string, blockchain string, tokenID string) (string, error)
+	GetNFTsByAttributes(address string, blockchain string, attributes dia.NFTAttributes, limit int, offset int) ([]dia.NFT, error)
 
 	// NFT trading and bidding methods
 	SetNFTTrade(trade dia.NFTTrade) error
@@ -111,6 +240,11 @@ type RelDatastore interface {
 	GetNFTFloorLevel(nftclass dia.NFTClass, timestamp time.Time, floorWindowSeconds time.Duration, currencies []dia.Asset, level float64, noBundles bool, exchange string) (float64, error)
 	GetNFTFloorRecursive(nftClass dia.NFTClass, timestamp time.Time, floorWindowSeconds time.Duration, stepBackLimit int, noBundles bool, exchange string) (float64, error)
 	GetNFTFloorRange(nftClass dia.NFTClass, starttime time.Time, endtime time.Time, floorWindowSeconds time.Duration, stepBackLimit int, noBundles bool, exchange string) ([]float64, error)
+	GetNFTFloorPrice(nftclass dia.NFTClass, window time.Duration) (NFTFloorPrice, error)
+	SetNFTFloorPrice(floorPrice NFTFloorPrice) error
+	GetLastNFTFloorPrice(nftclass dia.NFTClass, window time.Duration) (NFTFloorPrice, error)
+	GetRecentNFTFloorPrice(nftclass dia.NFTClass, window time.Duration) (NFTFloorPrice, error)
+	UpdateNFTFloorPrices() (int, error)
 	GetLastBlockheightTopshot(upperBound time.Time) (uint64, error)
 	SetNFTBid(bid dia.NFTBid) error
 	GetLastNFTBid(address string, blockchain string, tokenID string, blockNumber uint64, blockPosition uint) (dia.NFTBid, error)
@@ -143,6 +277,8 @@ type RelDatastore interface {
 	SetBlockData(dia.BlockData) error
 	GetBlockData(blockchain string, blocknumber int64) (dia.BlockData, error)
 	GetLastBlockBlockscraper(blockchain string) (int64, error)
+	SetScraperBlock(scraper string, blockchain string, blockNumber uint64) error
+	GetScraperBlocks() ([]ScraperBlock, error)
 
 	//NFT exchange methods
 
@@ -171,6 +307,16 @@ type RelDatastore interface {
 	GetFeederResources() (addresses []string, err error)
 	GetOracleUpdates(address string, chainid string, offset int) ([]dia.OracleUpdate, error)
 	GetOracleUpdateCount(address string, chainid string) (int64, error)
+
+	// Edge node synchronization
+	GetSyncVersion() (int64, error)
+	GetSyncAssetsPage(after string, limit int) (SyncSnapshotPage, error)
+	GetSyncExchangePairsPage(after string, limit int) (SyncSnapshotPage, error)
+	GetSyncChanges(version int64, limit int) ([]SyncChange, error)
+
+	// Data protection
+	PurgeCustomer(owner string, mode PurgeMode, dryRun bool) (PurgeReport, error)
+	PruneRetention(policies []RetentionPolicy, dryRun bool) (PurgeReport, error)
 }
 
 const (
@@ -180,20 +326,50 @@ const (
 	assetIdent               = "assetIdent"
 	exchangepairTable        = "exchangepair"
 	exchangesymbolTable      = "exchangesymbol"
+	symbolcasingTable        = "symbolcasing"
+	tokenListVersionTable    = "tokenlistversion"
 	poolTable                = "pool"
 	poolassetTable           = "poolasset"
+	exchangepairPoolTable    = "exchangepairpool"
+	tvlTable                 = "tvl"
+	defiProtocolTable        = "defiprotocol"
+	supplyTable              = "supply"
+	assetRankTable           = "assetrank"
 	exchangeTable            = "exchange"
+	exchangelistingTable     = "exchangelisting"
 	nftExchangeTable         = "nftexchange"
 	chainconfigTable         = "chainconfig"
 	blockchainTable          = "blockchain"
 	assetVolumeTable         = "assetvolume"
+	assetMetadataTable       = "assetmetadata"
+	quoteConstraintTable     = "quoteassetconstraint"
 	historicalQuotationTable = "historicalquotation"
+	changelogTable           = "changelog"
+	assetHistoryTable        = "asset_history"
+	pendingAssetTable        = "pendingasset"
+	symbolLabelTable         = "symbolverificationlabel"
+	symbolHistoryTable       = "symbolverificationhistory"
+	pairHistoryTable         = "exchangepairhistory"
+	assetAliasTable          = "assetalias"
+	assetTickerTable         = "assetticker"
+	feedRestrictionTable     = "feedrestriction"
+	apiKeyRegionTable        = "apikeyregion"
+	benchmarkAssetTable      = "benchmarkasset"
+	benchmarkComponentTable  = "benchmarkcomponent"
+	serviceConfigTable       = "serviceconfig"
+	blockchainRPCTable       = "blockchain_rpc"
+	quotationArchiveTable    = "historicalquotationarchive"
+	assetVolumeHistoryTable  = "assetvolume_history"
+	assetVolumeExchangeTable = "assetvolume_exchange"
+	assetGroupTable          = "assetgroup"
 
 	// cache keys
 	keyAssetCache        = "dia_asset_"
 	keyExchangePairCache = "dia_exchangepair_"
+	keyMissingAssetCache = "dia_missingasset_"
 
 	blockdataTable       = "blockdata"
+	scraperblockTable    = "scraperblock"
 	nftcategoryTable     = "nftcategory"
 	nftclassTable        = "nftclass"
 	nftTable             = "nft"
@@ -201,6 +377,7 @@ const (
 	NfttradeSumeriaTable = "nfttradesumeria"
 	nftbidTable          = "nftbid"
 	nftofferTable        = "nftoffer"
+	nftFloorTable        = "nftfloor"
 	scrapersTable        = "scrapers"
 	keypairTable         = "keypair"
 	oracleconfigTable    = "oracleconfig"
@@ -213,13 +390,82 @@ const (
 	// timeFormatBlockchain = "2006-01-02"
 )
 
+// pgQuerier is implemented by both the postgres pool and a postgres transaction,
+// so that queries can be shared between plain and transactional reads.
+type pgQuerier interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// pgClient is the postgres connection of a RelDB. It is either the pool or, for a RelDB
+// bound to a transaction by WithTx, the transaction. All queries of the relational layer go
+// through this interface, so that any database speaking the postgres wire protocol can serve
+// them, see Dialect.
+type pgClient interface {
+	pgQuerier
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// txBeginner is implemented by pgClients which start transactions with options, such as the pool.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+}
+
+// RelStore is the relational datastore passed to the function executed by WithTx.
+// All postgres reads and writes on it are part of the transaction.
+type RelStore interface {
+	RelDatastore
+}
+
 // RelDB is a relative database with redis caching layer.
 type RelDB struct {
 	URI            string
-	postgresClient *pgxpool.Pool
-	redisClient    *redis.Client
+	postgresClient pgClient
+	redisClient    redis.UniversalClient
 	redisPipe      redis.Pipeliner
 	pagesize       uint32
+	assetCache     *assetLRU
+	// actor is recorded in the asset history for all changes made through this RelDB.
+	actor string
+	// dialect is the database behind postgresClient.
+	dialect Dialect
+	// inTx is true if postgresClient is a transaction started by withTx.
+	inTx bool
+	// ctx is the context cache operations are bound to by WithContext.
+	ctx context.Context
+	// redisConfig overrides the redis configuration given by the environment.
+	redisConfig *db.RedisConfig
+	// missingAssets remembers assets which are not in postgres for missingAssetTTL, see WithNegativeAssetCache.
+	missingAssets   *assetLRU
+	missingAssetTTL time.Duration
+}
+
+// RelDBOption configures optional settings of a RelDB.
+type RelDBOption func(*RelDB)
+
+// WithAssetCache sets @capacity and @expiry of the in-memory asset cache.
+// A capacity of zero disables the cache and a zero expiry keeps entries until they are evicted.
+func WithAssetCache(capacity int, expiry time.Duration) RelDBOption {
+	return func(rdb *RelDB) {
+		rdb.assetCache = newAssetLRU(capacity, expiry)
+	}
+}
+
+// WithActor sets the @actor which is recorded in the asset history, such as a service or operator name.
+// It defaults to the name of the executable.
+func WithActor(actor string) RelDBOption {
+	return func(rdb *RelDB) {
+		rdb.actor = actor
+	}
+}
+
+// WithRedisConfig connects the caching layer to the redis setup given by @config, such as
+// sentinels or a redis cluster, instead of the one given by the environment.
+func WithRedisConfig(config db.RedisConfig) RelDBOption {
+	return func(rdb *RelDB) {
+		rdb.redisConfig = &config
+	}
 }
 
 // NewRelDataStore returns a datastore with postgres client and redis cache.
@@ -239,11 +485,10 @@ func NewCachingLayer() (*RelDB, error) {
 }
 
 // NewRelDataStoreWithOptions returns a postgres datastore and/or redis caching layer.
-func NewRelDataStoreWithOptions(withPostgres bool, withRedis bool) (*RelDB, error) {
+// @opts are applied on top of the default settings.
+func NewRelDataStoreWithOptions(withPostgres bool, withRedis bool, opts ...RelDBOption) (*RelDB, error) {
 	var (
 		postgresClient *pgxpool.Pool
-		redisClient    *redis.Client
-		redisPipe      redis.Pipeliner
 		url            string
 	)
 
@@ -251,11 +496,27 @@ func NewRelDataStoreWithOptions(withPostgres bool, withRedis bool) (*RelDB, erro
 		url = db.GetPostgresURL()
 		postgresClient = db.PostgresDatabase()
 	}
+	rdb := &RelDB{
+		URI:            url,
+		postgresClient: postgresClient,
+		pagesize:       32,
+		assetCache:     newAssetLRU(defaultAssetCacheCapacity, defaultAssetCacheExpiry),
+		actor:          filepath.Base(os.Args[0]),
+		dialect:        dialectFromEnv(),
+	}
+	WithNegativeAssetCache(missingAssetCacheTTL)(rdb)
+	for _, opt := range opts {
+		opt(rdb)
+	}
 	if withRedis {
-		redisClient = db.GetRedisClient()
-		redisPipe = redisClient.TxPipeline()
+		redisConfig := db.GetRedisConfig()
+		if rdb.redisConfig != nil {
+			redisConfig = *rdb.redisConfig
+		}
+		rdb.redisClient = db.NewRedisClient(redisConfig)
+		rdb.redisPipe = rdb.redisClient.TxPipeline()
 	}
-	return &RelDB{url, postgresClient, redisClient, redisPipe, 32}, nil
+	return rdb, nil
 }
 
 // GetKeys returns a slice of strings holding the names of the keys of @table in postgres
@@ -276,3 +537,66 @@ func (rdb *RelDB) GetKeys(table string) (keys []string, err error) {
 	}
 	return
 }
+
+// beginTx starts a transaction with @opts. On a RelDB which is bound to a transaction, a nested
+// transaction (i.e. a savepoint) is started instead and @opts are those of the outer transaction.
+func (rdb *RelDB) beginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	if beginner, ok := rdb.postgresClient.(txBeginner); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return rdb.postgresClient.Begin(ctx)
+}
+
+// WithTx executes @fn in a transaction. All postgres writes made through @tx are committed
+// if @fn returns nil and rolled back otherwise. Writes to the caches are not transactional.
+func (rdb *RelDB) WithTx(ctx context.Context, fn func(tx RelStore) error) error {
+	return rdb.withTx(ctx, func(txRDB *RelDB) error {
+		return fn(txRDB)
+	})
+}
+
+func (rdb *RelDB) withTx(ctx context.Context, fn func(txRDB *RelDB) error) error {
+	return rdb.runTx(ctx, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		txRDB := *rdb
+		txRDB.postgresClient = tx
+		txRDB.inTx = true
+		return fn(&txRDB)
+	})
+}
+
+// ReadSnapshot executes @fn in a read-only transaction with isolation level repeatable read.
+// All queries executed on @tx see the same consistent snapshot of the database.
+func (rdb *RelDB) ReadSnapshot(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return rdb.runTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, fn)
+}
+
+// runTx executes @fn in a transaction with @opts and commits it if @fn returns nil.
+// Transactions which the database aborted due to contention are retried, see retryTx.
+func (rdb *RelDB) runTx(ctx context.Context, opts pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := rdb.runTxOnce(ctx, opts, fn)
+		if err == nil || !rdb.retryTx(err, attempt) {
+			return err
+		}
+		log.Warnf("retry transaction after attempt %d: %v", attempt, err)
+	}
+}
+
+// runTxOnce executes @fn in a transaction with @opts. The transaction is rolled back unless it is committed,
+// including when @fn panics.
+func (rdb *RelDB) runTxOnce(ctx context.Context, opts pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	tx, err := rdb.beginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errRollback := tx.Rollback(ctx); errRollback != nil && !errors.Is(errRollback, pgx.ErrTxClosed) {
+			log.Error("rollback transaction: ", errRollback)
+		}
+	}()
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// bindContext returns @ctx if it can be cancelled and @bound otherwise. Most queries are run
+// with context.Background(), which is replaced by the context the datastore is bound to.
+func bindContext(bound context.Context, ctx context.Context) context.Context {
+	if ctx.Done() == nil {
+		return bound
+	}
+	return ctx
+}
+
+// WithContext returns a copy of @rdb whose postgres queries and cache operations are cancelled
+// when @ctx is done, for instance when the client of an http request disconnects.
+func (rdb *RelDB) WithContext(ctx context.Context) *RelDB {
+	ctxRDB := *rdb
+	ctxRDB.postgresClient = &ctxPgClient{pgClient: rdb.postgresClient, ctx: ctx}
+	ctxRDB.ctx = ctx
+	return &ctxRDB
+}
+
+// cacheContext returns the context redis commands of @rdb are run with.
+func (rdb *RelDB) cacheContext() context.Context {
+	if rdb.ctx == nil {
+		return context.Background()
+	}
+	return rdb.ctx
+}
+
+// ctxPgClient binds all queries of a pgClient to a context.
+type ctxPgClient struct {
+	pgClient
+	ctx context.Context
+}
+
+func (c *ctxPgClient) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return c.pgClient.Exec(bindContext(c.ctx, ctx), sql, args...)
+}
+
+func (c *ctxPgClient) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return c.pgClient.Query(bindContext(c.ctx, ctx), sql, args...)
+}
+
+func (c *ctxPgClient) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return c.pgClient.QueryRow(bindContext(c.ctx, ctx), sql, args...)
+}
+
+func (c *ctxPgClient) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := c.pgClient.Begin(bindContext(c.ctx, ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &ctxTx{Tx: tx, ctx: c.ctx}, nil
+}
+
+func (c *ctxPgClient) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	beginner, ok := c.pgClient.(txBeginner)
+	if !ok {
+		return c.Begin(ctx)
+	}
+	tx, err := beginner.BeginTx(bindContext(c.ctx, ctx), opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ctxTx{Tx: tx, ctx: c.ctx}, nil
+}
+
+// ctxTx binds all queries of a transaction to a context.
+type ctxTx struct {
+	pgx.Tx
+	ctx context.Context
+}
+
+func (tx *ctxTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return tx.Tx.Exec(bindContext(tx.ctx, ctx), sql, args...)
+}
+
+func (tx *ctxTx) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return tx.Tx.Query(bindContext(tx.ctx, ctx), sql, args...)
+}
+
+func (tx *ctxTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return tx.Tx.QueryRow(bindContext(tx.ctx, ctx), sql, args...)
+}
+
+func (tx *ctxTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	nested, err := tx.Tx.Begin(bindContext(tx.ctx, ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &ctxTx{Tx: nested, ctx: tx.ctx}, nil
+}
+
+// WithContext returns a copy of @datastore whose influx queries and cache operations are cancelled
+// when @ctx is done. Influx aborts a query as soon as its http request is closed.
+func (datastore *DB) WithContext(ctx context.Context) Datastore {
+	ctxDatastore := *datastore
+	ctxDatastore.ctx = ctx
+	if datastore.influxClient != nil {
+		httpClient := datastore.influxHTTPClient
+		if httpClient == nil {
+			httpClient = newInfluxHTTPClient(datastore.influxConfig)
+		}
+		ctxDatastore.influxClient = &ctxInfluxClient{Client: datastore.influxClient, config: datastore.influxConfig, httpClient: httpClient, ctx: ctx}
+	}
+	return &ctxDatastore
+}
+
+// cacheContext returns the context redis commands of @datastore are run with.
+func (datastore *DB) cacheContext() context.Context {
+	if datastore.ctx == nil {
+		return context.Background()
+	}
+	return datastore.ctx
+}
+
+// ctxInfluxClient runs the queries of an influx client in http requests bound to a context,
+// which the influx client does not support itself. All other calls are passed through.
+type ctxInfluxClient struct {
+	clientInfluxdb.Client
+	config     clientInfluxdb.HTTPConfig
+	httpClient *http.Client
+	ctx        context.Context
+}
+
+// newInfluxHTTPClient returns an http client for queries to influx which is configured like the
+// one of the influx client given by @config, i.e. with its timeout, proxy and TLS settings.
+func newInfluxHTTPClient(config clientInfluxdb.HTTPConfig) *http.Client {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: config.InsecureSkipVerify,
+		},
+		Proxy: config.Proxy,
+	}
+	if config.TLSConfig != nil {
+		transport.TLSClientConfig = config.TLSConfig
+	}
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+	}
+}
+
+func (c *ctxInfluxClient) Query(q clientInfluxdb.Query) (*clientInfluxdb.Response, error) {
+	if q.Chunked {
+		return c.Client.Query(q)
+	}
+	u, err := url.Parse(c.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "query")
+	params := url.Values{}
+	params.Set("q", q.Command)
+	params.Set("db", q.Database)
+	if q.RetentionPolicy != "" {
+		params.Set("rp", q.RetentionPolicy)
+	}
+	if q.Precision != "" {
+		params.Set("epoch", q.Precision)
+	}
+	if len(q.Parameters) > 0 {
+		jsonParameters, err := json.Marshal(q.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		params.Set("params", string(jsonParameters))
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	userAgent := c.config.UserAgent
+	if userAgent == "" {
+		userAgent = "InfluxDBClient"
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response clientInfluxdb.Response
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	err = decoder.Decode(&response)
+	if err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("decode influx response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK && response.Error() == nil {
+		return &response, fmt.Errorf("received status code %d from influx", resp.StatusCode)
+	}
+	return &response, nil
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+func TestCtxInfluxClientCancel(t *testing.T) {
+	aborted := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a long running query which is aborted once the client disconnects.
+		select {
+		case <-r.Context().Done():
+			close(aborted)
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	config := clientInfluxdb.HTTPConfig{Addr: server.URL}
+	client := &ctxInfluxClient{config: config, httpClient: newInfluxHTTPClient(config), ctx: ctx}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	_, err := queryInfluxDB(client, "SELECT * FROM trades")
+	if err == nil {
+		t.Fatal("expected error on cancelled query")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("query was not cancelled")
+	}
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Error("server did not observe the disconnect")
+	}
+}
+
+func TestCtxInfluxClientConfig(t *testing.T) {
+	userAgents := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents <- r.UserAgent()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	config := clientInfluxdb.HTTPConfig{Addr: server.URL, UserAgent: "dia-api", Timeout: 50 * time.Millisecond}
+	client := &ctxInfluxClient{config: config, httpClient: newInfluxHTTPClient(config), ctx: context.Background()}
+
+	start := time.Now()
+	_, err := queryInfluxDB(client, "SELECT * FROM trades")
+	if err == nil {
+		t.Fatal("expected error on timed out query")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("query did not time out")
+	}
+	if userAgent := <-userAgents; userAgent != "dia-api" {
+		t.Errorf("got user agent %s, want dia-api", userAgent)
+	}
+}
+
+func TestBindContext(t *testing.T) {
+	bound, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if bindContext(bound, context.Background()) != bound {
+		t.Error("expected background context to be replaced")
+	}
+	own, cancelOwn := context.WithTimeout(context.Background(), time.Second)
+	defer cancelOwn()
+	if bindContext(bound, own) != own {
+		t.Error("expected cancellable context to be kept")
+	}
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
+)
+
+// ScraperBlock is the latest block processed by an on-chain scraper.
+type ScraperBlock struct {
+	Scraper     string    `json:"Scraper"`
+	Blockchain  string    `json:"Blockchain"`
+	BlockNumber uint64    `json:"BlockNumber"`
+	UpdateTime  time.Time `json:"UpdateTime"`
+}
+
+// ScraperLag is the distance of an on-chain scraper to the head of its blockchain at @Time.
+// @LagSeconds is the difference of the timestamps of the head block and the processed block.
+type ScraperLag struct {
+	Scraper        string    `json:"Scraper"`
+	Blockchain     string    `json:"Blockchain"`
+	ProcessedBlock uint64    `json:"ProcessedBlock"`
+	HeadBlock      uint64    `json:"HeadBlock"`
+	LagBlocks      int64     `json:"LagBlocks"`
+	LagSeconds     float64   `json:"LagSeconds"`
+	Alert          bool      `json:"Alert"`
+	Time           time.Time `json:"Time"`
+}
+
+// SetScraperBlock stores @blockNumber as the latest block processed by @scraper on @blockchain.
+// Block numbers lower than the stored one are ignored.
+func (rdb *RelDB) SetScraperBlock(scraper string, blockchain string, blockNumber uint64) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %[1]s (scraper,blockchain,block_number,update_time)
+	VALUES ($1,$2,$3,NOW())
+	ON CONFLICT (scraper,blockchain) DO UPDATE SET
+	block_number=GREATEST(%[1]s.block_number,EXCLUDED.block_number),
+	update_time=EXCLUDED.update_time
+	`, scraperblockTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, scraper, blockchain, strconv.FormatUint(blockNumber, 10))
+	return err
+}
+
+// GetScraperBlocks returns the latest processed blocks of all on-chain scrapers.
+func (rdb *RelDB) GetScraperBlocks() (blocks []ScraperBlock, err error) {
+	query := fmt.Sprintf("SELECT scraper,blockchain,block_number::text,update_time FROM %s ORDER BY blockchain,scraper", scraperblockTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			block       ScraperBlock
+			blockNumber string
+		)
+		err = rows.Scan(&block.Scraper, &block.Blockchain, &blockNumber, &block.UpdateTime)
+		if err != nil {
+			return
+		}
+		block.BlockNumber, err = strconv.ParseUint(blockNumber, 10, 64)
+		if err != nil {
+			return
+		}
+		blocks = append(blocks, block)
+	}
+	err = rows.Err()
+	return
+}
+
+// ScraperBlockReporter reports the latest block processed by a scraper to postgres at most once per
+// interval, so that scrapers can report each processed event without a write per event.
+// Scrapers should report the chain heads they receive, not only blocks with events, so that
+// scrapers of quiet markets are not reported as lagging. Only the UniswapV2 scraper reports so far.
+type ScraperBlockReporter struct {
+	rdb        *RelDB
+	scraper    string
+	blockchain string
+	interval   time.Duration
+
+	mu           sync.Mutex
+	latest       uint64
+	lastReported time.Time
+}
+
+// NewScraperBlockReporter returns a reporter for the blocks processed by @scraper on @blockchain.
+func (rdb *RelDB) NewScraperBlockReporter(scraper string, blockchain string, interval time.Duration) *ScraperBlockReporter {
+	return &ScraperBlockReporter{
+		rdb:        rdb,
+		scraper:    scraper,
+		blockchain: blockchain,
+		interval:   interval,
+	}
+}
+
+// Report records @blockNumber as processed. Events may be processed out of order, so only the
+// highest block number is kept.
+func (r *ScraperBlockReporter) Report(blockNumber uint64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if blockNumber > r.latest {
+		r.latest = blockNumber
+	}
+	if time.Since(r.lastReported) < r.interval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastReported = time.Now()
+	latest := r.latest
+	r.mu.Unlock()
+
+	if err := r.rdb.SetScraperBlock(r.scraper, r.blockchain, latest); err != nil {
+		log.Errorf("set processed block of %s on %s: %v", r.scraper, r.blockchain, err)
+	}
+}
+
+// SetScraperLag stores @lag in influx.
+func (datastore *DB) SetScraperLag(lag ScraperLag) error {
+	tags := map[string]string{
+		"scraper":    lag.Scraper,
+		"blockchain": lag.Blockchain,
+	}
+	fields := map[string]interface{}{
+		"processedblock": int64(lag.ProcessedBlock),
+		"headblock":      int64(lag.HeadBlock),
+		"lagblocks":      lag.LagBlocks,
+		"lagseconds":     lag.LagSeconds,
+		"alert":          lag.Alert,
+	}
+	pt, err := clientInfluxdb.NewPoint(influxDBScraperLagTable, tags, fields, lag.Time)
+	if err != nil {
+		return err
+	}
+	datastore.addPoint(pt)
+	return nil
+}
+
+// GetScraperLags returns the lags of @scraper on @blockchain in the time range (@starttime,@endtime].
+func (datastore *DB) GetScraperLags(scraper string, blockchain string, starttime time.Time, endtime time.Time) (lags []ScraperLag, err error) {
+	q := fmt.Sprintf(
+		"SELECT processedblock,headblock,lagblocks,lagseconds,alert FROM %s WHERE scraper=$scraper AND blockchain=$blockchain AND time>$starttime AND time<=$endtime ORDER BY ASC",
+		influxDBScraperLagTable,
+	)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"scraper":    scraper,
+		"blockchain": blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return
+	}
+	for _, values := range res[0].Series[0].Values {
+		lag := ScraperLag{Scraper: scraper, Blockchain: blockchain}
+		lag.Time, err = time.Parse(time.RFC3339, values[0].(string))
+		if err != nil {
+			return
+		}
+		var processedBlock, headBlock int64
+		processedBlock, err = values[1].(json.Number).Int64()
+		if err != nil {
+			return
+		}
+		headBlock, err = values[2].(json.Number).Int64()
+		if err != nil {
+			return
+		}
+		lag.ProcessedBlock, lag.HeadBlock = uint64(processedBlock), uint64(headBlock)
+		lag.LagBlocks, err = values[3].(json.Number).Int64()
+		if err != nil {
+			return
+		}
+		lag.LagSeconds, err = values[4].(json.Number).Float64()
+		if err != nil {
+			return
+		}
+		lag.Alert, _ = values[5].(bool)
+		lags = append(lags, lag)
+	}
+	return
+}
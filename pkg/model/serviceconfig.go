@@ -0,0 +1,39 @@
+package models
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetServiceConfig sets the configuration value of @key for @service.
+func (rdb *RelDB) SetServiceConfig(service string, key string, value string) error {
+	query := fmt.Sprintf(`
+	INSERT INTO %s (service,key,value) VALUES ($1,$2,$3)
+	ON CONFLICT (service,key) DO UPDATE SET value=EXCLUDED.value
+	`, serviceConfigTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, service, key, value)
+	return err
+}
+
+// GetServiceConfig returns all configuration values of @service, keyed by their name.
+// The keys each service accepts are listed with the serviceconfig table in pginit.sql.
+func (rdb *RelDB) GetServiceConfig(service string) (config map[string]string, err error) {
+	config = make(map[string]string)
+	query := fmt.Sprintf("SELECT key,value FROM %s WHERE service=$1", serviceConfigTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, service)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		err = rows.Scan(&key, &value)
+		if err != nil {
+			return
+		}
+		config[key] = value
+	}
+	err = rows.Err()
+	return
+}
@@ -40,12 +40,14 @@ func (datastore *DB) SetStockQuotation(sq StockQuotation) error {
 func (db *DB) GetStockQuotation(source string, symbol string, timeInit time.Time, timeFinal time.Time) ([]StockQuotation, error) {
 	stockQuotations := []StockQuotation{}
 
-	unixtimeInit := timeInit.UnixNano()
-	unixtimeFinal := timeFinal.UnixNano()
-
-	query := "SELECT priceAsk,priceBid,sizeAsk,sizeBid,source,\"isin\",\"name\" FROM %s WHERE source='%s' and \"symbol\"='%s' and time>%d and time<=%d order by time desc"
-	q := fmt.Sprintf(query, influxDbStockQuotationsTable, source, symbol, unixtimeInit, unixtimeFinal)
-	res, err := queryInfluxDB(db.influxClient, q)
+	query := "SELECT priceAsk,priceBid,sizeAsk,sizeBid,source,\"isin\",\"name\" FROM %s WHERE source=$source and \"symbol\"=$symbol and time>$starttime and time<=$endtime order by time desc"
+	q := fmt.Sprintf(query, influxDbStockQuotationsTable)
+	res, err := db.queryInflux(q, map[string]interface{}{
+		"source":    source,
+		"symbol":    symbol,
+		"starttime": timeInit.UnixNano(),
+		"endtime":   timeFinal.UnixNano(),
+	})
 	if err != nil {
 		fmt.Println("Error querying influx")
 		return stockQuotations, err
@@ -95,7 +97,7 @@ func (db *DB) GetStockSymbols() (map[Stock]string, error) {
 	allStocks := make(map[Stock]string)
 
 	q := fmt.Sprintf("SELECT \"symbol\",\"name\",\"isin\",source FROM %s WHERE time>now()-7d", influxDbStockQuotationsTable)
-	res, err := queryInfluxDB(db.influxClient, q)
+	res, err := db.queryInflux(q, nil)
 	if err != nil {
 		log.Error("query stock symbols from influx: ", err)
 		return allStocks, err
@@ -127,7 +129,7 @@ func (db *DB) GetStockSymbols() (map[Stock]string, error) {
 // 	influxQuery := "SELECT \"asset\",borrowRate,lendingRate,\"protocol\" FROM %s WHERE time > %d and time < %d and asset = '%s' and protocol = '%s'"
 // 	q := fmt.Sprintf(influxQuery, influxDbDefiRateTable, starttime.UnixNano(), endtime.UnixNano(), asset, protocol)
 // 	fmt.Println("influx query: ", q)
-// 	res, err := queryInfluxDB(db.influxClient, q)
+// 	res, err := db.queryInflux(q, nil)
 // 	fmt.Println("res, err: ", res, err)
 // 	if err != nil {
 // 		return retval, err
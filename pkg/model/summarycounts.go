@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const keySummaryCounts = "dia_summarycounts"
+
+var (
+	// summaryCountInterval is the time for which summary counters are served from the caches.
+	summaryCountInterval = getCacheTTL("SUMMARY_COUNT_INTERVAL_SECONDS", "600")
+
+	// summaryCounts holds the latest summary counters in memory.
+	summaryCounts struct {
+		sync.Mutex
+		counts *SummaryCounts
+	}
+
+	// summaryCountGroup lets concurrent requests of expired counters share a single recount.
+	summaryCountGroup singleflight.Group
+)
+
+// ExchangeCounts holds the number of listed pairs and of assets traded in these pairs on an exchange.
+type ExchangeCounts struct {
+	Pairs         int64 `json:"Pairs"`
+	VerifiedPairs int64 `json:"VerifiedPairs"`
+	Assets        int64 `json:"Assets"`
+}
+
+// SummaryCounts holds the number of active assets, listed pairs and the counts per exchange at @Time.
+type SummaryCounts struct {
+	Assets        int64                     `json:"Assets"`
+	Pairs         int64                     `json:"Pairs"`
+	VerifiedPairs int64                     `json:"VerifiedPairs"`
+	Exchanges     map[string]ExchangeCounts `json:"Exchanges"`
+	Time          time.Time                 `json:"Time"`
+}
+
+// MarshalBinary for summary counts
+func (sc *SummaryCounts) MarshalBinary() ([]byte, error) {
+	return json.Marshal(sc)
+}
+
+// UnmarshalBinary for summary counts
+func (sc *SummaryCounts) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, sc)
+}
+
+func (sc *SummaryCounts) fresh() bool {
+	return sc != nil && time.Since(sc.Time) < summaryCountInterval
+}
+
+// GetSummaryCounts returns the summary counters. By default, they are served from memory or redis and
+// are recounted once they are older than summaryCountInterval.
+func (rdb *RelDB) GetSummaryCounts(opts ...ReadOption) (SummaryCounts, error) {
+	o := newReadOptions(opts)
+	if !o.readCache() {
+		if !o.writeCache() {
+			return rdb.countSummary()
+		}
+		return rdb.RefreshSummaryCounts()
+	}
+
+	summaryCounts.Lock()
+	counts := summaryCounts.counts
+	summaryCounts.Unlock()
+	if counts.fresh() {
+		return *counts, nil
+	}
+
+	if cacheAvailable(rdb.redisClient) {
+		counts = &SummaryCounts{}
+		err := rdb.redisClient.Get(rdb.cacheContext(), keySummaryCounts).Scan(counts)
+		if err == nil && counts.fresh() {
+			summaryCounts.Lock()
+			summaryCounts.counts = counts
+			summaryCounts.Unlock()
+			return *counts, nil
+		}
+		if checkCacheError(err) {
+			fallthroughCacheRead()
+		}
+	}
+	return rdb.RefreshSummaryCounts()
+}
+
+// GetExchangeSummaryCounts returns the summary counters of @exchange.
+func (rdb *RelDB) GetExchangeSummaryCounts(exchange string, opts ...ReadOption) (ExchangeCounts, error) {
+	counts, err := rdb.GetSummaryCounts(opts...)
+	if err != nil {
+		return ExchangeCounts{}, err
+	}
+	return counts.Exchanges[exchange], nil
+}
+
+// RefreshSummaryCounts recounts the summary counters in postgres and stores them in memory and redis.
+// Concurrent calls wait for the running recount and share its result.
+func (rdb *RelDB) RefreshSummaryCounts() (SummaryCounts, error) {
+	counts, err, _ := summaryCountGroup.Do(keySummaryCounts, func() (interface{}, error) {
+		return rdb.refreshSummaryCounts()
+	})
+	return counts.(SummaryCounts), err
+}
+
+func (rdb *RelDB) refreshSummaryCounts() (SummaryCounts, error) {
+	counts, err := rdb.countSummary()
+	if err != nil {
+		return counts, err
+	}
+
+	summaryCounts.Lock()
+	summaryCounts.counts = &counts
+	summaryCounts.Unlock()
+
+	if !cacheAvailable(rdb.redisClient) {
+		skipCacheWrite()
+		return counts, nil
+	}
+	err = rdb.redisClient.Set(rdb.cacheContext(), keySummaryCounts, &counts, 2*summaryCountInterval).Err()
+	if checkCacheError(err) {
+		skipCacheWrite()
+		return counts, nil
+	}
+	return counts, err
+}
+
+// countSummary counts active assets and listed pairs in postgres.
+func (rdb *RelDB) countSummary() (counts SummaryCounts, err error) {
+	counts.Exchanges = make(map[string]ExchangeCounts)
+	counts.Time = time.Now()
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE deprecated_at IS NULL", assetTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query).Scan(&counts.Assets)
+	if err != nil {
+		return
+	}
+
+	query = fmt.Sprintf(`
+	SELECT exchange,COUNT(*),COUNT(*) FILTER (WHERE verified)
+	FROM %s
+	WHERE delisted_at IS NULL
+	GROUP BY exchange
+	`, exchangepairTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			exchange       string
+			exchangeCounts ExchangeCounts
+		)
+		err = rows.Scan(&exchange, &exchangeCounts.Pairs, &exchangeCounts.VerifiedPairs)
+		if err != nil {
+			return
+		}
+		counts.Exchanges[exchange] = exchangeCounts
+		counts.Pairs += exchangeCounts.Pairs
+		counts.VerifiedPairs += exchangeCounts.VerifiedPairs
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	rows.Close()
+
+	query = fmt.Sprintf(`
+	SELECT exchange,COUNT(DISTINCT asset_id)
+	FROM (
+		SELECT exchange,id_quotetoken AS asset_id FROM %[1]s WHERE delisted_at IS NULL
+		UNION
+		SELECT exchange,id_basetoken AS asset_id FROM %[1]s WHERE delisted_at IS NULL
+	) p
+	WHERE asset_id IS NOT NULL
+	GROUP BY exchange
+	`, exchangepairTable)
+	rows, err = rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			exchange string
+			assets   int64
+		)
+		err = rows.Scan(&exchange, &assets)
+		if err != nil {
+			return
+		}
+		exchangeCounts := counts.Exchanges[exchange]
+		exchangeCounts.Assets = assets
+		counts.Exchanges[exchange] = exchangeCounts
+	}
+	err = rows.Err()
+	return
+}
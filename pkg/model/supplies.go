@@ -9,7 +9,7 @@ import (
 
 	"github.com/diadata-org/diadata/pkg/dia"
 	"github.com/diadata-org/diadata/pkg/dia/helpers"
-	"github.com/go-redis/redis"
+	"github.com/go-redis/redis/v8"
 	clientInfluxdb "github.com/influxdata/influxdb1-client/v2"
 )
 
@@ -57,14 +57,17 @@ func (datastore *DB) SaveSupplyInflux(supply *dia.Supply) error {
 func (datastore *DB) GetSupplyInflux(asset dia.Asset, starttime time.Time, endtime time.Time) ([]dia.Supply, error) {
 	retval := []dia.Supply{}
 	var q string
+	params := map[string]interface{}{"address": asset.Address, "blockchain": asset.Blockchain}
 	if starttime.IsZero() || endtime.IsZero() {
-		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\" FROM %s WHERE \"address\" = '%s' AND \"blockchain\"='%s' AND time<now() ORDER BY DESC LIMIT 1"
-		q = fmt.Sprintf(queryString, influxDbSupplyTable, asset.Address, asset.Blockchain)
+		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\" FROM %s WHERE \"address\" = $address AND \"blockchain\"=$blockchain AND time<now() ORDER BY DESC LIMIT 1"
+		q = fmt.Sprintf(queryString, influxDbSupplyTable)
 	} else {
-		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\" FROM %s WHERE time > %d AND time < %d AND \"address\" = '%s' AND \"blockchain\"='%s' ORDER BY DESC"
-		q = fmt.Sprintf(queryString, influxDbSupplyTable, starttime.UnixNano(), endtime.UnixNano(), asset.Address, asset.Blockchain)
+		queryString := "SELECT supply,circulatingsupply,source,\"name\",\"symbol\" FROM %s WHERE time > $starttime AND time < $endtime AND \"address\" = $address AND \"blockchain\"=$blockchain ORDER BY DESC"
+		q = fmt.Sprintf(queryString, influxDbSupplyTable)
+		params["starttime"] = starttime.UnixNano()
+		params["endtime"] = endtime.UnixNano()
 	}
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, params)
 	if err != nil {
 		return retval, err
 	}
@@ -151,7 +154,7 @@ func (datastore *DB) GetSupply(symbol string, starttime, endtime time.Time, relD
 }
 
 func (datastore *DB) GetSupplyCache(asset dia.Asset) (supply dia.Supply, err error) {
-	err = datastore.redisClient.Get(getKeySupply(asset)).Scan(&supply)
+	err = datastore.redisClient.Get(datastore.cacheContext(), getKeySupply(asset)).Scan(&supply)
 	if err != nil {
 		return
 	}
@@ -161,7 +164,7 @@ func (datastore *DB) GetSupplyCache(asset dia.Asset) (supply dia.Supply, err err
 func (datastore *DB) SetSupply(supply *dia.Supply) error {
 	key := getKeySupply(supply.Asset)
 	log.Debug("setting ", key, supply)
-	err := datastore.redisClient.Set(key, supply, 0).Err()
+	err := datastore.redisClient.Set(datastore.cacheContext(), key, supply, 0).Err()
 	if err != nil {
 		log.Errorf("Error: %v on SetSupply (redis) %v\n", err, supply.Asset.Symbol)
 	}
@@ -176,7 +179,7 @@ func (db *DB) SetDiaTotalSupply(totalSupply float64) error {
 	key := getKeyDiaTotalSupply()
 	log.Debug("setting ", key, totalSupply)
 
-	err := db.redisClient.Set(key, totalSupply, 0).Err()
+	err := db.redisClient.Set(db.cacheContext(), key, totalSupply, 0).Err()
 	if err != nil {
 		log.Errorf("Error: %v on SetDiaTotalSupply (redis) %v\n", err, totalSupply)
 	}
@@ -185,7 +188,7 @@ func (db *DB) SetDiaTotalSupply(totalSupply float64) error {
 
 func (db *DB) GetDiaTotalSupply() (float64, error) {
 	key := getKeyDiaTotalSupply()
-	value, err := db.redisClient.Get(key).Result()
+	value, err := db.redisClient.Get(db.cacheContext(), key).Result()
 	if err != nil {
 		if err != redis.Nil {
 			log.Errorf("Error: %v on GetDiaTotalSupply\n", err)
@@ -204,7 +207,7 @@ func (db *DB) SetDiaCirculatingSupply(circulatingSupply float64) error {
 	key := getKeyDiaCirculatingSupply()
 	log.Debug("setting ", key, circulatingSupply)
 
-	err := db.redisClient.Set(key, circulatingSupply, 0).Err()
+	err := db.redisClient.Set(db.cacheContext(), key, circulatingSupply, 0).Err()
 	if err != nil {
 		log.Errorf("Error: %v on SetDiaCirculatingSupply (redis) %v\n", err, circulatingSupply)
 	}
@@ -213,7 +216,7 @@ func (db *DB) SetDiaCirculatingSupply(circulatingSupply float64) error {
 
 func (db *DB) GetDiaCirculatingSupply() (float64, error) {
 	key := getKeyDiaCirculatingSupply()
-	value, err := db.redisClient.Get(key).Result()
+	value, err := db.redisClient.Get(db.cacheContext(), key).Result()
 	if err != nil {
 		if err != redis.Nil {
 			log.Errorf("Error: %v on GetDiaCirculatingSupply\n", err)
@@ -227,3 +230,74 @@ func (db *DB) GetDiaCirculatingSupply() (float64, error) {
 	}
 	return retval, nil
 }
+
+// GetSupplyInfluxRange returns the supplies of @asset in the time-range [starttime, endtime), ordered by time.
+// If @interval is positive, the series is downsampled to the latest supply in each interval, which is
+// timestamped with the start of the interval.
+func (datastore *DB) GetSupplyInfluxRange(asset dia.Asset, starttime time.Time, endtime time.Time, interval time.Duration) ([]dia.Supply, error) {
+	supplies := []dia.Supply{}
+	if interval < 0 {
+		return supplies, fmt.Errorf("invalid interval %v", interval)
+	}
+	params := map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	}
+	where := "WHERE \"address\"=$address AND \"blockchain\"=$blockchain AND time>=$starttime AND time<$endtime"
+	var q string
+	if interval > 0 {
+		q = fmt.Sprintf("SELECT LAST(supply),LAST(circulatingsupply),LAST(source) FROM %s %s GROUP BY time(%s) fill(none) ORDER BY ASC",
+			influxDbSupplyTable, where, influxDuration(interval))
+	} else {
+		q = fmt.Sprintf("SELECT supply,circulatingsupply,source FROM %s %s ORDER BY ASC", influxDbSupplyTable, where)
+	}
+	res, err := datastore.queryInflux(q, params)
+	if err != nil {
+		return supplies, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return supplies, nil
+	}
+	for _, row := range res[0].Series[0].Values {
+		supply, err := parseSupplyRow(asset, row)
+		if err != nil {
+			return supplies, err
+		}
+		supplies = append(supplies, supply)
+	}
+	return supplies, nil
+}
+
+// GetSupplyAt returns the latest supply of @asset at or before @timestamp.
+func (datastore *DB) GetSupplyAt(asset dia.Asset, timestamp time.Time) (dia.Supply, error) {
+	q := fmt.Sprintf("SELECT supply,circulatingsupply,source FROM %s WHERE \"address\"=$address AND \"blockchain\"=$blockchain AND time<=$timestamp ORDER BY DESC LIMIT 1",
+		influxDbSupplyTable)
+	params := map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"timestamp":  timestamp.UnixNano(),
+	}
+	res, err := datastore.queryInflux(q, params)
+	if err != nil {
+		return dia.Supply{}, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 || len(res[0].Series[0].Values) == 0 {
+		return dia.Supply{}, fmt.Errorf("no supply of %s on %s before %v", asset.Address, asset.Blockchain, timestamp)
+	}
+	return parseSupplyRow(asset, res[0].Series[0].Values[0])
+}
+
+// parseSupplyRow returns the supply of @asset in the influx result @row of time,supply,circulatingsupply,source.
+func parseSupplyRow(asset dia.Asset, row []interface{}) (supply dia.Supply, err error) {
+	supply.Asset = asset
+	supply.Time, err = time.Parse(time.RFC3339, row[0].(string))
+	if err != nil {
+		return
+	}
+	supply.Supply = influxFloat(row[1])
+	supply.CirculatingSupply = influxFloat(row[2])
+	supply.Source, _ = row[3].(string)
+	return
+}
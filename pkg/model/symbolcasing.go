@@ -0,0 +1,102 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// symbolCasingRetry is the time after which a failed load of the casing overrides is retried.
+const symbolCasingRetry = time.Minute
+
+// symbolCasings caches the casing overrides from postgres, keyed by the lower case symbol.
+// @lastAttempt is the time of the latest failed load.
+var symbolCasings = struct {
+	sync.RWMutex
+	loaded      bool
+	lastAttempt time.Time
+	overrides   map[string]string
+}{overrides: make(map[string]string)}
+
+// SetSymbolCasing stores @canonical as the canonical casing of all case variations of the symbol.
+// Exchange symbols stored in another casing of the symbol are renamed to @canonical.
+func (rdb *RelDB) SetSymbolCasing(canonical string) error {
+	err := rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		query := fmt.Sprintf(`
+		INSERT INTO %s (symbol_lower,canonical) VALUES ($1,$2)
+		ON CONFLICT (symbol_lower) DO UPDATE SET canonical=EXCLUDED.canonical
+		`, symbolcasingTable)
+		_, err := txRDB.postgresClient.Exec(context.Background(), query, strings.ToLower(canonical), canonical)
+		if err != nil {
+			return err
+		}
+		// Exchanges which store the symbol in @canonical casing already keep their other variations.
+		query = fmt.Sprintf(`
+		UPDATE %[1]s SET symbol=$1
+		WHERE LOWER(symbol)=LOWER($1) AND symbol<>$1
+		AND NOT EXISTS (SELECT 1 FROM %[1]s es WHERE es.exchange=%[1]s.exchange AND es.symbol=$1)
+		`, exchangesymbolTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query, canonical)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	symbolCasings.Lock()
+	symbolCasings.overrides[strings.ToLower(canonical)] = canonical
+	symbolCasings.Unlock()
+	return nil
+}
+
+// GetSymbolCasings returns all casing overrides, keyed by the lower case symbol.
+func (rdb *RelDB) GetSymbolCasings() (map[string]string, error) {
+	casings := make(map[string]string)
+	query := fmt.Sprintf("SELECT symbol_lower,canonical FROM %s", symbolcasingTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query)
+	if err != nil {
+		return casings, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var symbolLower, canonical string
+		err = rows.Scan(&symbolLower, &canonical)
+		if err != nil {
+			return casings, err
+		}
+		casings[symbolLower] = canonical
+	}
+	return casings, nil
+}
+
+// CanonicalSymbol returns the canonical casing of @symbol. This is the override from the
+// symbolcasing table if existent and the upper case symbol otherwise. If the overrides cannot be
+// loaded, the upper case symbol is returned and loading is retried after symbolCasingRetry.
+func (rdb *RelDB) CanonicalSymbol(symbol string) string {
+	symbolCasings.RLock()
+	load := !symbolCasings.loaded && time.Since(symbolCasings.lastAttempt) >= symbolCasingRetry
+	symbolCasings.RUnlock()
+	if load {
+		casings, err := rdb.GetSymbolCasings()
+		symbolCasings.Lock()
+		if err != nil {
+			log.Errorf("load symbol casings, retry in %v: %v", symbolCasingRetry, err)
+			symbolCasings.lastAttempt = time.Now()
+		} else {
+			for symbolLower, canonical := range casings {
+				symbolCasings.overrides[symbolLower] = canonical
+			}
+			symbolCasings.loaded = true
+		}
+		symbolCasings.Unlock()
+	}
+
+	symbolCasings.RLock()
+	defer symbolCasings.RUnlock()
+	if canonical, ok := symbolCasings.overrides[strings.ToLower(symbol)]; ok {
+		return canonical
+	}
+	return strings.ToUpper(symbol)
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// failingPgClient fails all queries and counts them.
+type failingPgClient struct {
+	queries int
+}
+
+func (c *failingPgClient) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	c.queries++
+	return nil, errors.New("connection refused")
+}
+
+func (c *failingPgClient) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	c.queries++
+	return nil, errors.New("connection refused")
+}
+
+func (c *failingPgClient) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	c.queries++
+	return nil
+}
+
+func (c *failingPgClient) Begin(ctx context.Context) (pgx.Tx, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestCanonicalSymbolRetry(t *testing.T) {
+	client := &failingPgClient{}
+	rdb := &RelDB{postgresClient: client}
+	t.Cleanup(func() {
+		symbolCasings.Lock()
+		symbolCasings.lastAttempt = time.Time{}
+		symbolCasings.Unlock()
+	})
+
+	for _, symbol := range []string{"weth", "Weth"} {
+		if canonical := rdb.CanonicalSymbol(symbol); canonical != "WETH" {
+			t.Errorf("expected WETH, got %s", canonical)
+		}
+	}
+	if client.queries != 1 {
+		t.Errorf("expected a single load attempt within the retry interval, got %d", client.queries)
+	}
+}
+
+// recordingPgClient accepts all statements and records the arguments of each Exec.
+type recordingPgClient struct {
+	failingPgClient
+	execArgs [][]interface{}
+}
+
+func (c *recordingPgClient) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	c.execArgs = append(c.execArgs, args)
+	return pgconn.CommandTag("INSERT 0 1"), nil
+}
+
+func TestSetExchangeSymbolCasing(t *testing.T) {
+	symbolCasings.Lock()
+	loaded, overrides := symbolCasings.loaded, symbolCasings.overrides
+	symbolCasings.loaded = true
+	symbolCasings.overrides = map[string]string{"steth": "stETH"}
+	symbolCasings.Unlock()
+	t.Cleanup(func() {
+		symbolCasings.Lock()
+		symbolCasings.loaded, symbolCasings.overrides = loaded, overrides
+		symbolCasings.Unlock()
+	})
+
+	cases := []struct {
+		symbol   string
+		expected string
+	}{
+		{"weth", "WETH"},
+		{"Weth", "WETH"},
+		{"STETH", "stETH"},
+	}
+	for _, c := range cases {
+		client := &recordingPgClient{}
+		rdb := &RelDB{postgresClient: client}
+		if err := rdb.SetExchangeSymbol("Uniswap", c.symbol); err != nil {
+			t.Fatal(err)
+		}
+		if len(client.execArgs) != 1 || client.execArgs[0][0] != c.expected {
+			t.Errorf("%s: expected %s to be stored, got %v", c.symbol, c.expected, client.execArgs)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+// symbolScorerTTL bounds the age of the cached symbol scorer, such that labels recorded by other
+// processes are taken into account.
+const symbolScorerTTL = 10 * time.Minute
+
+// symbolScorer caches the symbol scorer trained on the labels of all exchanges.
+// @trainTime of an invalidated scorer is the time of invalidation.
+var symbolScorer = struct {
+	sync.Mutex
+	scorer    *SymbolScorer
+	trainTime time.Time
+}{}
+
+// GetSymbolScorer returns the symbol scorer trained on the labels of all exchanges, such that the statistics
+// over all exchanges serve as prior for exchanges with few labels. The scorer is cached for symbolScorerTTL
+// and retrained once a label is recorded by SetSymbolLabel.
+func (rdb *RelDB) GetSymbolScorer() (*SymbolScorer, error) {
+	symbolScorer.Lock()
+	cached, cachedTime := symbolScorer.scorer, symbolScorer.trainTime
+	symbolScorer.Unlock()
+	if cached != nil && time.Since(cachedTime) < symbolScorerTTL {
+		return cached, nil
+	}
+
+	trainTime := time.Now()
+	labels, err := rdb.GetSymbolLabels("")
+	if err != nil {
+		return nil, err
+	}
+	scorer := NewSymbolScorer(labels)
+	symbolScorer.Lock()
+	// Do not cache the scorer if it was invalidated or retrained in the meantime.
+	if symbolScorer.trainTime.Before(trainTime) {
+		symbolScorer.scorer, symbolScorer.trainTime = scorer, trainTime
+	}
+	symbolScorer.Unlock()
+	return scorer, nil
+}
+
+// invalidateSymbolScorer removes the cached scorer. Scorers trained before are not cached anymore.
+func invalidateSymbolScorer() {
+	symbolScorer.Lock()
+	symbolScorer.scorer, symbolScorer.trainTime = nil, time.Now()
+	symbolScorer.Unlock()
+}
+
+// SetSymbolLabel records a manual verification decision as labeled example for the symbol scorer.
+// If @label is accepted, @label.Symbol on @label.Exchange is verified and mapped to @label.Asset
+// with the reviewer as actor of the verification.
+func (rdb *RelDB) SetSymbolLabel(label SymbolLabel) (err error) {
+	assetID, err := rdb.GetAssetID(label.Asset)
+	if err != nil {
+		return
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (exchange,symbol,asset_id,accepted,reviewer)
+	VALUES ($1,$2,$3,$4,$5)
+	`, symbolLabelTable)
+	_, err = rdb.postgresClient.Exec(context.Background(), query, label.Exchange, label.Symbol, assetID, label.Accepted, label.Reviewer)
+	if err != nil {
+		return
+	}
+	invalidateSymbolScorer()
+	if !label.Accepted {
+		return
+	}
+	var success bool
+	actor := label.Reviewer
+	if actor == "" {
+		actor = rdb.actor
+	}
+	success, err = rdb.verifyExchangeSymbol(label.Exchange, label.Symbol, assetID, "label", actor)
+	if err == nil && !success {
+		err = fmt.Errorf("symbol %s not found on %s", label.Symbol, label.Exchange)
+	}
+	return
+}
+
+// GetSymbolLabels returns all labeled verification decisions on @exchange.
+// If @exchange is the empty string, the labels of all exchanges are returned.
+func (rdb *RelDB) GetSymbolLabels(exchange string) (labels []SymbolLabel, err error) {
+	query := fmt.Sprintf(`
+	SELECT sl.exchange,sl.symbol,sl.accepted,sl.reviewer,a.symbol,a.name,a.address,a.blockchain,a.decimals
+	FROM %s sl
+	INNER JOIN %s a
+	ON sl.asset_id=a.asset_id
+	WHERE ($1='' OR sl.exchange=$1)
+	ORDER BY sl.label_id ASC
+	`, symbolLabelTable, assetTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			label    SymbolLabel
+			reviewer sql.NullString
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(
+			&label.Exchange,
+			&label.Symbol,
+			&label.Accepted,
+			&reviewer,
+			&label.Asset.Symbol,
+			&label.Asset.Name,
+			&label.Asset.Address,
+			&label.Asset.Blockchain,
+			&decimals,
+		)
+		if err != nil {
+			return
+		}
+		label.Reviewer = reviewer.String
+		if decimals.Valid {
+			label.Asset.Decimals = uint8(decimals.Int64)
+		}
+		labels = append(labels, label)
+	}
+	err = rows.Err()
+	return
+}
+
+// GetSymbolSuggestions returns candidate assets for @symbol on @exchange together with the confidence
+// assigned by @scorer, by descending confidence. Deprecated assets are not suggested.
+func (rdb *RelDB) GetSymbolSuggestions(exchange string, symbol string, scorer *SymbolScorer) (suggestions []SymbolSuggestion, err error) {
+	candidates := scorer.CandidateSymbols(exchange, symbol)
+	query := fmt.Sprintf(`
+	SELECT symbol,name,address,decimals,blockchain
+	FROM %s
+	WHERE UPPER(symbol)=ANY($1)
+	AND deprecated_at IS NULL
+	`, assetTable)
+	var rows pgx.Rows
+	rows, err = rdb.postgresClient.Query(context.Background(), query, candidates)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var assets []dia.Asset
+	for rows.Next() {
+		var (
+			asset    dia.Asset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.Symbol, &asset.Name, &asset.Address, &decimals, &asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+	return scorer.Suggest(exchange, strings.ToUpper(symbol), assets), nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetSymbolScorerCache(t *testing.T) {
+	client := &failingPgClient{}
+	rdb := &RelDB{postgresClient: client}
+	cached := NewSymbolScorer(nil)
+	symbolScorer.Lock()
+	symbolScorer.scorer, symbolScorer.trainTime = cached, time.Now()
+	symbolScorer.Unlock()
+	t.Cleanup(invalidateSymbolScorer)
+
+	scorer, err := rdb.GetSymbolScorer()
+	if err != nil || scorer != cached || client.queries != 0 {
+		t.Fatalf("expected cached scorer without query, got %v, %v after %d queries", scorer, err, client.queries)
+	}
+	invalidateSymbolScorer()
+	_, err = rdb.GetSymbolScorer()
+	if err == nil || client.queries != 1 {
+		t.Errorf("expected labels to be queried after invalidation, got %v after %d queries", err, client.queries)
+	}
+}
@@ -13,7 +13,7 @@ func (datastore *DB) GetSymbols(exchange string) ([]string, error) {
 	for {
 		var keys []string
 		var err error
-		keys, cursor, err = datastore.redisClient.Scan(cursor, key+"*", 10).Result()
+		keys, cursor, err = datastore.redisClient.Scan(datastore.cacheContext(), cursor, key+"*", 10).Result()
 		if err != nil {
 			log.Error("GetPairs err", err)
 			return result, err
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	symbolRuleIdentity = "identity"
+	symbolRuleAlias    = "alias"
+	symbolRulePrefix   = "prefix"
+	symbolRuleSuffix   = "suffix"
+	// symbolRuleMaxAffix bounds the length of prefixes and suffixes which are learned as naming quirk,
+	// such as the X prefix in Kraken's XXBT or the .E suffix of wrapped tokens.
+	symbolRuleMaxAffix = 3
+)
+
+// SymbolLabel is a manual verification decision on mapping @Symbol on @Exchange to @Asset.
+// Rejected suggestions are recorded with @Accepted=false.
+type SymbolLabel struct {
+	Exchange string    `json:"Exchange"`
+	Symbol   string    `json:"Symbol"`
+	Asset    dia.Asset `json:"Asset"`
+	Accepted bool      `json:"Accepted"`
+	Reviewer string    `json:"Reviewer"`
+}
+
+// SymbolSuggestion is a candidate asset for an unverified exchange symbol.
+// @Confidence is the estimated probability that the mapping is correct.
+type SymbolSuggestion struct {
+	Asset      dia.Asset `json:"Asset"`
+	Rule       string    `json:"Rule"`
+	Confidence float64   `json:"Confidence"`
+}
+
+// symbolRule is a transformation from an exchange symbol to the symbol of the underlying asset.
+type symbolRule struct {
+	kind  string
+	value string
+}
+
+type symbolRuleStats struct {
+	accepted int
+	rejected int
+}
+
+// SymbolScorer learns exchange specific naming quirks from labeled verification decisions
+// and scores mappings of exchange symbols to assets.
+type SymbolScorer struct {
+	// rules holds per exchange the number of accepted and rejected labels of each rule.
+	rules map[string]map[symbolRule]*symbolRuleStats
+	// global holds the statistics of identity and affix rules over all exchanges. They serve as prior
+	// for exchanges without labels.
+	global map[symbolRule]*symbolRuleStats
+}
+
+// NewSymbolScorer returns a scorer trained on @labels.
+func NewSymbolScorer(labels []SymbolLabel) *SymbolScorer {
+	scorer := &SymbolScorer{
+		rules:  make(map[string]map[symbolRule]*symbolRuleStats),
+		global: make(map[symbolRule]*symbolRuleStats),
+	}
+	for _, label := range labels {
+		scorer.Add(label)
+	}
+	return scorer
+}
+
+// Add updates the scorer with @label.
+func (s *SymbolScorer) Add(label SymbolLabel) {
+	rule := deriveSymbolRule(label.Symbol, label.Asset.Symbol)
+	if _, ok := s.rules[label.Exchange]; !ok {
+		s.rules[label.Exchange] = make(map[symbolRule]*symbolRuleStats)
+	}
+	updateSymbolRuleStats(s.rules[label.Exchange], rule, label.Accepted)
+	if rule.kind != symbolRuleAlias {
+		updateSymbolRuleStats(s.global, rule, label.Accepted)
+	}
+}
+
+func updateSymbolRuleStats(stats map[symbolRule]*symbolRuleStats, rule symbolRule, accepted bool) {
+	if _, ok := stats[rule]; !ok {
+		stats[rule] = &symbolRuleStats{}
+	}
+	if accepted {
+		stats[rule].accepted++
+	} else {
+		stats[rule].rejected++
+	}
+}
+
+// CandidateSymbols returns all asset symbols which @symbol on @exchange may refer to according to the learned rules.
+func (s *SymbolScorer) CandidateSymbols(exchange string, symbol string) (candidates []string) {
+	symbol = strings.ToUpper(symbol)
+	seen := map[string]struct{}{symbol: {}}
+	candidates = append(candidates, symbol)
+	for rule, stats := range s.rules[exchange] {
+		if stats.accepted == 0 {
+			continue
+		}
+		candidate, ok := applySymbolRule(rule, symbol)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[candidate]; !ok {
+			seen[candidate] = struct{}{}
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.Strings(candidates[1:])
+	return
+}
+
+// Score returns the confidence that @symbol on @exchange refers to @asset together with the applied rule.
+// The confidence is the smoothed share of accepted labels of the rule on @exchange, falling back to
+// the statistics over all exchanges. Mappings which are not explained by any rule have zero confidence.
+func (s *SymbolScorer) Score(exchange string, symbol string, asset dia.Asset) (string, float64) {
+	rule := deriveSymbolRule(symbol, asset.Symbol)
+	if rule.kind == symbolRuleAlias {
+		stats, ok := s.rules[exchange][rule]
+		if !ok {
+			return rule.kind, 0
+		}
+		return rule.kind, smoothedConfidence(stats, 0)
+	}
+	// Identity mappings are plausible a priori, learned affixes are not.
+	prior := 0.0
+	if rule.kind == symbolRuleIdentity {
+		prior = 0.5
+	}
+	if globalStats, ok := s.global[rule]; ok {
+		prior = smoothedConfidence(globalStats, prior)
+	}
+	stats, ok := s.rules[exchange][rule]
+	if !ok {
+		if rule.kind == symbolRuleIdentity {
+			return rule.kind, prior
+		}
+		return rule.kind, 0
+	}
+	return rule.kind, smoothedConfidence(stats, prior)
+}
+
+// Suggest scores all @candidates as mappings of @symbol on @exchange and returns them by descending confidence.
+func (s *SymbolScorer) Suggest(exchange string, symbol string, candidates []dia.Asset) (suggestions []SymbolSuggestion) {
+	for _, asset := range candidates {
+		rule, confidence := s.Score(exchange, symbol, asset)
+		if confidence == 0 {
+			continue
+		}
+		suggestions = append(suggestions, SymbolSuggestion{Asset: asset, Rule: rule, Confidence: confidence})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+	return
+}
+
+// smoothedConfidence returns the share of accepted labels, smoothed towards @prior with weight of two labels.
+func smoothedConfidence(stats *symbolRuleStats, prior float64) float64 {
+	return (float64(stats.accepted) + 2*prior) / (float64(stats.accepted+stats.rejected) + 2)
+}
+
+// deriveSymbolRule returns the rule which transforms @exchangeSymbol into @assetSymbol.
+func deriveSymbolRule(exchangeSymbol string, assetSymbol string) symbolRule {
+	exchangeSymbol = strings.ToUpper(exchangeSymbol)
+	assetSymbol = strings.ToUpper(assetSymbol)
+	switch {
+	case exchangeSymbol == assetSymbol:
+		return symbolRule{kind: symbolRuleIdentity}
+	case assetSymbol != "" && strings.HasSuffix(exchangeSymbol, assetSymbol) && len(exchangeSymbol)-len(assetSymbol) <= symbolRuleMaxAffix:
+		return symbolRule{kind: symbolRulePrefix, value: strings.TrimSuffix(exchangeSymbol, assetSymbol)}
+	case assetSymbol != "" && strings.HasPrefix(exchangeSymbol, assetSymbol) && len(exchangeSymbol)-len(assetSymbol) <= symbolRuleMaxAffix:
+		return symbolRule{kind: symbolRuleSuffix, value: strings.TrimPrefix(exchangeSymbol, assetSymbol)}
+	default:
+		return symbolRule{kind: symbolRuleAlias, value: exchangeSymbol + ">" + assetSymbol}
+	}
+}
+
+// applySymbolRule transforms @exchangeSymbol according to @rule. It returns false if the rule does not apply.
+func applySymbolRule(rule symbolRule, exchangeSymbol string) (string, bool) {
+	switch rule.kind {
+	case symbolRuleIdentity:
+		return exchangeSymbol, true
+	case symbolRulePrefix:
+		if strings.HasPrefix(exchangeSymbol, rule.value) && len(exchangeSymbol) > len(rule.value) {
+			return strings.TrimPrefix(exchangeSymbol, rule.value), true
+		}
+	case symbolRuleSuffix:
+		if strings.HasSuffix(exchangeSymbol, rule.value) && len(exchangeSymbol) > len(rule.value) {
+			return strings.TrimSuffix(exchangeSymbol, rule.value), true
+		}
+	case symbolRuleAlias:
+		parts := strings.SplitN(rule.value, ">", 2)
+		if parts[0] == exchangeSymbol {
+			return parts[1], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestSymbolScorer(t *testing.T) {
+	eth := dia.Asset{Symbol: "ETH", Blockchain: dia.ETHEREUM, Address: "0x0000000000000000000000000000000000000000"}
+	btc := dia.Asset{Symbol: "BTC", Blockchain: dia.BITCOIN, Address: "0x0000000000000000000000000000000000000000"}
+	usdt := dia.Asset{Symbol: "USDT", Blockchain: dia.ETHEREUM, Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7"}
+
+	scorer := NewSymbolScorer([]SymbolLabel{
+		{Exchange: dia.KrakenExchange, Symbol: "XETH", Asset: eth, Accepted: true},
+		{Exchange: dia.KrakenExchange, Symbol: "XLTC", Asset: dia.Asset{Symbol: "LTC"}, Accepted: true},
+		{Exchange: dia.KrakenExchange, Symbol: "XXBT", Asset: btc, Accepted: true},
+		{Exchange: dia.BitfinexExchange, Symbol: "UST", Asset: usdt, Accepted: true},
+		{Exchange: dia.BitfinexExchange, Symbol: "UST", Asset: dia.Asset{Symbol: "UST"}, Accepted: false},
+	})
+
+	candidates := scorer.CandidateSymbols(dia.KrakenExchange, "XXBT")
+	if candidates[0] != "XXBT" || !containsSymbol(candidates, "BTC") || !containsSymbol(candidates, "XBT") {
+		t.Errorf("unexpected candidates %v", candidates)
+	}
+
+	// Learned prefix quirk on Kraken.
+	rule, confidence := scorer.Score(dia.KrakenExchange, "XDOT", dia.Asset{Symbol: "DOT"})
+	if rule != symbolRulePrefix || confidence <= 0.5 {
+		t.Errorf("prefix: got %s with confidence %v", rule, confidence)
+	}
+	// The prefix is not learned for other exchanges.
+	if _, confidence := scorer.Score(dia.BinanceExchange, "XDOT", dia.Asset{Symbol: "DOT"}); confidence != 0 {
+		t.Errorf("prefix on other exchange: got confidence %v", confidence)
+	}
+
+	suggestions := scorer.Suggest(dia.BitfinexExchange, "UST", []dia.Asset{{Symbol: "UST"}, usdt})
+	if len(suggestions) != 2 || suggestions[0].Asset != usdt || suggestions[0].Confidence <= suggestions[1].Confidence {
+		t.Errorf("unexpected suggestions %v", suggestions)
+	}
+}
+
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	symbolOperationVerify   = "VERIFY"
+	symbolOperationUnverify = "UNVERIFY"
+)
+
+// SymbolVerificationEntry is a recorded verification or unverification of an exchange symbol.
+// @Source names the candidate list the asset was chosen from, such as gitcoin or suggestions.
+type SymbolVerificationEntry struct {
+	Exchange   string    `json:"Exchange"`
+	Symbol     string    `json:"Symbol"`
+	Operation  string    `json:"Operation"`
+	OldAssetID string    `json:"OldAssetID"`
+	NewAssetID string    `json:"NewAssetID"`
+	Actor      string    `json:"Actor"`
+	Source     string    `json:"Source"`
+	Time       time.Time `json:"Time"`
+}
+
+// VerifyExchangeSymbol verifies @symbol on @exchange and maps it uniquely to @assetID in asset table.
+// It returns true if symbol,exchange is present.
+// All case variations of @symbol are verified. The previous mapping, the actor of the RelDB and
+// @source are recorded in the verification history, so that verified symbols can be re-verified.
+func (rdb *RelDB) VerifyExchangeSymbol(exchange string, symbol string, assetID string, source string) (bool, error) {
+	return rdb.verifyExchangeSymbol(exchange, symbol, assetID, source, rdb.actor)
+}
+
+func (rdb *RelDB) verifyExchangeSymbol(exchange string, symbol string, assetID string, source string, actor string) (bool, error) {
+	return rdb.setSymbolVerification(symbolOperationVerify, exchange, symbol, sql.NullString{String: assetID, Valid: true}, source, actor)
+}
+
+// UnverifyExchangeSymbol removes the mapping of @symbol on @exchange to an asset, ignoring case.
+// It returns true if symbol,exchange is present. @reviewer is required and recorded as the actor
+// in the verification history.
+func (rdb *RelDB) UnverifyExchangeSymbol(exchange string, symbol string, reviewer string) (bool, error) {
+	if reviewer == "" {
+		return false, errors.New("reviewer is required to unverify a symbol")
+	}
+	return rdb.setSymbolVerification(symbolOperationUnverify, exchange, symbol, sql.NullString{}, "", reviewer)
+}
+
+// setSymbolVerification maps all case variations of @symbol on @exchange to @assetID, or unmaps them
+// for a null @assetID, and records the change in the verification history in the same statement.
+// Symbols which already have the requested mapping are left unchanged and not recorded.
+func (rdb *RelDB) setSymbolVerification(operation string, exchange string, symbol string, assetID sql.NullString, source string, actor string) (bool, error) {
+	query := fmt.Sprintf(`
+	WITH old AS (
+		SELECT symbol,asset_id,verified FROM %[1]s WHERE LOWER(symbol)=LOWER($2) AND exchange=$3
+	), updated AS (
+		UPDATE %[1]s es SET verified=($1::uuid IS NOT NULL),asset_id=$1::uuid
+		FROM old
+		WHERE es.symbol=old.symbol AND es.exchange=$3
+		AND (old.asset_id IS DISTINCT FROM $1::uuid OR old.verified IS DISTINCT FROM ($1::uuid IS NOT NULL))
+		RETURNING es.symbol,old.asset_id AS old_asset_id
+	), recorded AS (
+		INSERT INTO %[2]s (exchange,symbol,operation,old_asset_id,new_asset_id,actor,source)
+		SELECT $3,symbol,$4,old_asset_id,$1::uuid,$5,$6 FROM updated
+	)
+	SELECT COUNT(*) FROM old
+	`, exchangesymbolTable, symbolHistoryTable)
+	var count int64
+	err := rdb.postgresClient.QueryRow(context.Background(), query, assetID, symbol, exchange, operation, actor, source).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetVerificationHistory returns all recorded verifications of @symbol on @exchange, ignoring case,
+// in chronological order.
+func (rdb *RelDB) GetVerificationHistory(exchange string, symbol string) (history []SymbolVerificationEntry, err error) {
+	query := fmt.Sprintf(`
+	SELECT exchange,symbol,operation,old_asset_id::text,new_asset_id::text,actor,source,change_time
+	FROM %s
+	WHERE exchange=$1 AND LOWER(symbol)=LOWER($2)
+	ORDER BY history_id ASC
+	`, symbolHistoryTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, exchange, symbol)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry      SymbolVerificationEntry
+			oldAssetID sql.NullString
+			newAssetID sql.NullString
+			actor      sql.NullString
+			source     sql.NullString
+		)
+		err = rows.Scan(
+			&entry.Exchange,
+			&entry.Symbol,
+			&entry.Operation,
+			&oldAssetID,
+			&newAssetID,
+			&actor,
+			&source,
+			&entry.Time,
+		)
+		if err != nil {
+			return
+		}
+		entry.OldAssetID = oldAssetID.String
+		entry.NewAssetID = newAssetID.String
+		entry.Actor = actor.String
+		entry.Source = source.String
+		history = append(history, entry)
+	}
+	err = rows.Err()
+	return
+}
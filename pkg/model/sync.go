@@ -0,0 +1,278 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+// SyncAsset is an asset together with its primary key in postgres. The key is needed by
+// edge nodes in order to resolve the token references of exchangepair changes.
+type SyncAsset struct {
+	AssetID string    `json:"AssetID"`
+	Asset   dia.Asset `json:"Asset"`
+}
+
+// SyncSnapshotPage is a page of the asset or exchangepair table read at @Version. @Next is the cursor
+// of the following page and empty on the last page. Pages are read at different versions, so edge nodes
+// apply all changes after the version of their first page once they read all pages. As changes hold
+// entire rows, reapplying changes which are contained in later pages already is harmless.
+type SyncSnapshotPage struct {
+	Version       int64              `json:"Version"`
+	Assets        []SyncAsset        `json:"Assets,omitempty"`
+	ExchangePairs []dia.ExchangePair `json:"ExchangePairs,omitempty"`
+	Next          string             `json:"Next"`
+}
+
+// SyncChange is a row level change of the asset or exchangepair table.
+// @Payload holds the row after an INSERT or UPDATE and the deleted row for a DELETE.
+type SyncChange struct {
+	Version   int64           `json:"Version"`
+	Entity    string          `json:"Entity"`
+	Operation string          `json:"Operation"`
+	Payload   json.RawMessage `json:"Payload"`
+	Time      time.Time       `json:"Time"`
+}
+
+// GetSyncVersion returns the current version of the asset universe.
+func (rdb *RelDB) GetSyncVersion() (int64, error) {
+	if err := rdb.requirePostgres(); err != nil {
+		return 0, err
+	}
+	return getSyncVersion(rdb.postgresClient)
+}
+
+func getSyncVersion(q pgQuerier) (version int64, err error) {
+	query := fmt.Sprintf("SELECT COALESCE(MAX(version),0) FROM %s", changelogTable)
+	err = q.QueryRow(context.Background(), query).Scan(&version)
+	return
+}
+
+// GetSyncAssetsPage returns up to @limit assets after the cursor @after together with the current version.
+// An empty @after returns the first page.
+func (rdb *RelDB) GetSyncAssetsPage(after string, limit int) (page SyncSnapshotPage, err error) {
+	if err = rdb.requirePostgres(); err != nil {
+		return
+	}
+	if err = rdb.sequenceChanges(); err != nil {
+		return
+	}
+	err = rdb.ReadSnapshot(context.Background(), func(tx pgx.Tx) error {
+		var errPage error
+		page.Version, errPage = getSyncVersion(tx)
+		if errPage != nil {
+			return errPage
+		}
+		page.Assets, page.Next, errPage = getSyncAssets(tx, after, limit)
+		return errPage
+	})
+	return
+}
+
+// GetSyncExchangePairsPage returns up to @limit exchangepairs after the cursor @after together with
+// the current version. An empty @after returns the first page.
+func (rdb *RelDB) GetSyncExchangePairsPage(after string, limit int) (page SyncSnapshotPage, err error) {
+	if err = rdb.requirePostgres(); err != nil {
+		return
+	}
+	if err = rdb.sequenceChanges(); err != nil {
+		return
+	}
+	err = rdb.ReadSnapshot(context.Background(), func(tx pgx.Tx) error {
+		var errPage error
+		page.Version, errPage = getSyncVersion(tx)
+		if errPage != nil {
+			return errPage
+		}
+		page.ExchangePairs, page.Next, errPage = getSyncExchangePairs(tx, after, limit)
+		return errPage
+	})
+	return
+}
+
+// GetSyncChanges returns up to @limit changes after @version in ascending order. Versions are assigned
+// in commit order by sequenceChanges, so edge nodes resuming from the highest version seen miss no change.
+func (rdb *RelDB) GetSyncChanges(version int64, limit int) (changes []SyncChange, err error) {
+	if err = rdb.requirePostgres(); err != nil {
+		return
+	}
+	if err = rdb.sequenceChanges(); err != nil {
+		return
+	}
+	query := fmt.Sprintf(`
+	SELECT version,entity,operation,payload,change_time
+	FROM %s
+	WHERE version>$1
+	ORDER BY version ASC
+	LIMIT $2
+	`, changelogTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, version, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			change  SyncChange
+			payload []byte
+		)
+		err = rows.Scan(&change.Version, &change.Entity, &change.Operation, &payload, &change.Time)
+		if err != nil {
+			return
+		}
+		change.Payload = payload
+		changes = append(changes, change)
+	}
+	return
+}
+
+// sequenceChanges assigns versions to the changes of all finished transactions in the order of their txid.
+// Transactions with a txid below the xmin of the current snapshot have finished and all transactions
+// with larger txid receive versions later, so versions grow in commit order without serializing the
+// writers of the changelog. Sequencers are serialized by an advisory lock. If another sequencer holds it,
+// the changes are sequenced by that one.
+func (rdb *RelDB) sequenceChanges() error {
+	return rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		var locked bool
+		err := txRDB.postgresClient.QueryRow(context.Background(), "SELECT pg_try_advisory_xact_lock(hashtext('changelog_sequencer'))").Scan(&locked)
+		if err != nil || !locked {
+			return err
+		}
+		query := fmt.Sprintf(`
+		UPDATE %[1]s c SET version=s.version
+		FROM (
+			SELECT change_id,nextval('changelog_version_seq') AS version
+			FROM (
+				SELECT change_id FROM %[1]s
+				WHERE version IS NULL AND txid<pg_snapshot_xmin(pg_current_snapshot())
+				ORDER BY txid,change_id
+			) unsequenced
+		) s
+		WHERE c.change_id=s.change_id
+		`, changelogTable)
+		_, err = txRDB.postgresClient.Exec(context.Background(), query)
+		return err
+	})
+}
+
+// getSyncAssets returns up to @limit assets with asset_id after @after in ascending order of asset_id,
+// together with the cursor of the next page.
+func getSyncAssets(q pgQuerier, after string, limit int) (assets []SyncAsset, next string, err error) {
+	qb := queryBuilder{clauses: []string{"true"}}
+	if after != "" {
+		qb.where("asset_id>%s::uuid", after)
+	}
+	query := fmt.Sprintf("SELECT asset_id,symbol,name,address,decimals,blockchain FROM %s WHERE %s ORDER BY asset_id LIMIT %d",
+		assetTable, qb.conditions(), limit)
+	rows, err := q.Query(context.Background(), query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			asset    SyncAsset
+			decimals sql.NullInt64
+		)
+		err = rows.Scan(&asset.AssetID, &asset.Asset.Symbol, &asset.Asset.Name, &asset.Asset.Address, &decimals, &asset.Asset.Blockchain)
+		if err != nil {
+			return
+		}
+		if decimals.Valid {
+			asset.Asset.Decimals = uint8(decimals.Int64)
+		}
+		assets = append(assets, asset)
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	if len(assets) == limit {
+		next = assets[len(assets)-1].AssetID
+	}
+	return
+}
+
+// getSyncExchangePairs returns up to @limit exchangepairs with exchangepair_id after @after in ascending
+// order of exchangepair_id, together with the cursor of the next page.
+func getSyncExchangePairs(q pgQuerier, after string, limit int) (pairs []dia.ExchangePair, next string, err error) {
+	qb := queryBuilder{clauses: []string{"true"}}
+	if after != "" {
+		qb.where("ep.exchangepair_id>%s::uuid", after)
+	}
+	query := fmt.Sprintf(`
+	SELECT ep.exchangepair_id,ep.exchange,ep.symbol,ep.foreignname,ep.verified,
+		qa.symbol,qa.name,qa.address,qa.blockchain,qa.decimals,
+		ba.symbol,ba.name,ba.address,ba.blockchain,ba.decimals
+	FROM %s ep
+	LEFT JOIN %s qa ON ep.id_quotetoken=qa.asset_id
+	LEFT JOIN %s ba ON ep.id_basetoken=ba.asset_id
+	WHERE %s
+	ORDER BY ep.exchangepair_id
+	LIMIT %d
+	`, exchangepairTable, assetTable, assetTable, qb.conditions(), limit)
+	rows, err := q.Query(context.Background(), query, qb.args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pairID   string
+			pair     dia.ExchangePair
+			verified sql.NullBool
+			quote    nullableAsset
+			base     nullableAsset
+		)
+		err = rows.Scan(
+			&pairID,
+			&pair.Exchange,
+			&pair.Symbol,
+			&pair.ForeignName,
+			&verified,
+			&quote.symbol, &quote.name, &quote.address, &quote.blockchain, &quote.decimals,
+			&base.symbol, &base.name, &base.address, &base.blockchain, &base.decimals,
+		)
+		if err != nil {
+			return
+		}
+		pair.Verified = verified.Bool
+		pair.UnderlyingPair.QuoteToken = quote.asset()
+		pair.UnderlyingPair.BaseToken = base.asset()
+		pairs = append(pairs, pair)
+		next = pairID
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	if len(pairs) < limit {
+		next = ""
+	}
+	return
+}
+
+// nullableAsset scans the columns of an asset obtained by an outer join.
+type nullableAsset struct {
+	symbol     sql.NullString
+	name       sql.NullString
+	address    sql.NullString
+	blockchain sql.NullString
+	decimals   sql.NullInt64
+}
+
+func (na nullableAsset) asset() dia.Asset {
+	return dia.Asset{
+		Symbol:     na.symbol.String,
+		Name:       na.name.String,
+		Address:    na.address.String,
+		Blockchain: na.blockchain.String,
+		Decimals:   uint8(na.decimals.Int64),
+	}
+}
@@ -55,13 +55,13 @@ func (datastore *DB) GetSynthAssets(blockchain, protocol string) (r []string, er
 	queryString := ` 
 	SHOW TAG VALUES FROM %s 
 	WITH KEY="synthtokenaddress" 
-	WHERE blockchain='%s'
-	and protocol='%s'`
+	WHERE blockchain=$blockchain
+	and protocol=$protocol`
 
-	q := fmt.Sprintf(queryString, influxDbSynthSupplyTable, blockchain, protocol)
+	q := fmt.Sprintf(queryString, influxDbSynthSupplyTable)
 
 	log.Info("query: ", q)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"blockchain": blockchain, "protocol": protocol})
 	if err != nil {
 		log.Errorln("GetSynthAssets", err)
 		return r, err
@@ -95,19 +95,26 @@ func (datastore *DB) GetSynthSupplyInflux(blockchain, protocol, address string,
 	protocol, supply,   synthassetsymbol, synthtokenaddress,
 	totaldebt, underlyingassetsymbol, underlyinglocked, underlyingtokenaddress  
 	FROM %s 
-	WHERE blockchain='%s'
+	WHERE blockchain=$blockchain
 	`
+	params := map[string]interface{}{
+		"blockchain": blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	}
 
 	if protocol != "" {
-		queryString = queryString + `AND protocol='` + protocol + `'`
+		queryString = queryString + `AND protocol=$protocol`
+		params["protocol"] = protocol
 	}
 	if address != "" && address != "0x0000000000000000000000000000000000000000" {
-		queryString = queryString + `AND underlyingtokenaddress='` + address + `'`
-		queryString = queryString + ` OR synthtokenaddress='` + address + `'`
+		queryString = queryString + `AND underlyingtokenaddress=$address`
+		queryString = queryString + ` OR synthtokenaddress=$address`
+		params["address"] = address
 
 	}
 
-	queryString = queryString + ` AND time > %d AND time<= %d  `
+	queryString = queryString + ` AND time > $starttime AND time<= $endtime  `
 
 	queryString = queryString + " ORDER BY time DESC"
 
@@ -115,10 +122,10 @@ func (datastore *DB) GetSynthSupplyInflux(blockchain, protocol, address string,
 		queryString = queryString + " LIMIT 1"
 	}
 	queryString = queryString + " ;"
-	q := fmt.Sprintf(queryString, influxDbSynthSupplyTable, blockchain, starttime.UnixNano(), endtime.UnixNano())
+	q := fmt.Sprintf(queryString, influxDbSynthSupplyTable)
 
 	log.Info("query: ", q)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, params)
 	if err != nil {
 		log.Errorln("GetSynthSupplyInflux", err)
 		return r, err
@@ -0,0 +1,265 @@
+package testsupport
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// ErrNoQuotation is returned if no quotation of an asset exists at the requested time.
+var ErrNoQuotation = errors.New("no assetQuotation in DB")
+
+var _ models.Datastore = (*Datastore)(nil)
+
+// Datastore is an in-memory fake of models.Datastore. Quotations are kept per asset sorted by time,
+// the quotation cache and the price filters read from them. Batches and pipes are no-ops.
+type Datastore struct {
+	lock       sync.RWMutex
+	quotations map[string][]models.AssetQuotation
+	trades     []dia.Trade
+	claimed    map[string]bool
+	filters    map[string]float64
+}
+
+// NewDatastore returns a Datastore seeded with the quotations and trades in @fixtures.
+func NewDatastore(fixtures Fixtures) *Datastore {
+	d := &Datastore{
+		quotations: make(map[string][]models.AssetQuotation),
+		claimed:    make(map[string]bool),
+		filters:    make(map[string]float64),
+	}
+	for _, quotation := range fixtures.Quotations {
+		identifier := quotation.Asset.Identifier()
+		d.quotations[identifier] = insertQuotation(d.quotations[identifier], quotation)
+	}
+	for i := range fixtures.Trades {
+		d.saveTrade(fixtures.Trades[i])
+	}
+	return d
+}
+
+func (d *Datastore) SetInfluxClient(url string) {}
+
+func (d *Datastore) WithContext(ctx context.Context) models.Datastore {
+	return d
+}
+
+func (d *Datastore) Flush() error {
+	return nil
+}
+
+func (d *Datastore) ExecuteRedisPipe() error {
+	return nil
+}
+
+func (d *Datastore) FlushRedisPipe() error {
+	return nil
+}
+
+// ------------------------------------------------------------------------------
+// Quotations
+// ------------------------------------------------------------------------------
+
+func (d *Datastore) SetAssetQuotation(quotation *models.AssetQuotation) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	identifier := quotation.Asset.Identifier()
+	d.quotations[identifier] = insertQuotation(d.quotations[identifier], *quotation)
+	return nil
+}
+
+func (d *Datastore) AddAssetQuotationsToBatch(quotations []*models.AssetQuotation) error {
+	for _, quotation := range quotations {
+		if err := d.SetAssetQuotation(quotation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetAssetQuotationCache stores @quotation. If @check is true, it is not stored if a more recent
+// quotation exists.
+func (d *Datastore) SetAssetQuotationCache(quotation *models.AssetQuotation, check bool) (bool, error) {
+	if check {
+		latest, err := d.GetAssetQuotationCache(quotation.Asset)
+		if err == nil && quotation.Time.Before(latest.Time) {
+			return false, nil
+		}
+	}
+	return true, d.SetAssetQuotation(quotation)
+}
+
+// GetAssetQuotation returns the latest quotation of @asset at or before @timestamp.
+func (d *Datastore) GetAssetQuotation(asset dia.Asset, timestamp time.Time) (*models.AssetQuotation, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	quotations := d.quotations[asset.Identifier()]
+	i := sort.Search(len(quotations), func(i int) bool { return quotations[i].Time.After(timestamp) })
+	if i == 0 {
+		return &models.AssetQuotation{}, ErrNoQuotation
+	}
+	quotation := quotations[i-1]
+	return &quotation, nil
+}
+
+// GetAssetQuotationLatest returns the most recent quotation of @asset.
+func (d *Datastore) GetAssetQuotationLatest(asset dia.Asset, opts ...models.ReadOption) (*models.AssetQuotation, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	quotations := d.quotations[asset.Identifier()]
+	if len(quotations) == 0 {
+		return &models.AssetQuotation{}, ErrNoQuotation
+	}
+	quotation := quotations[len(quotations)-1]
+	return &quotation, nil
+}
+
+func (d *Datastore) GetAssetQuotationCache(asset dia.Asset) (*models.AssetQuotation, error) {
+	return d.GetAssetQuotationLatest(asset)
+}
+
+// GetAssetQuotationsCache returns the latest quotations of all @assets. Assets without quotation are omitted.
+func (d *Datastore) GetAssetQuotationsCache(assets []dia.Asset) (quotations []models.AssetQuotation, err error) {
+	quotations = []models.AssetQuotation{}
+	for _, asset := range assets {
+		quotation, err := d.GetAssetQuotationLatest(asset)
+		if err == nil {
+			quotations = append(quotations, *quotation)
+		}
+	}
+	return
+}
+
+// GetAssetQuotations returns all quotations of @asset in (@starttime,@endtime] in descending order by time.
+func (d *Datastore) GetAssetQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) (quotations []models.AssetQuotation, err error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	quotations = []models.AssetQuotation{}
+	all := d.quotations[asset.Identifier()]
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].Time.After(starttime) && !all[i].Time.After(endtime) {
+			quotations = append(quotations, all[i])
+		}
+	}
+	return
+}
+
+// GetQuotationEnvelope returns the quotation of @asset at @timestamp in an envelope. The fake
+// keeps no filter points, so the number of sources is always one.
+func (d *Datastore) GetQuotationEnvelope(asset dia.Asset, timestamp time.Time, maxAge time.Duration) models.QuotationEnvelope {
+	quotation, err := d.GetAssetQuotation(asset, timestamp)
+	if err != nil {
+		return models.UnavailableQuotationEnvelope(asset, err.Error())
+	}
+	quotation.Asset = asset
+	return models.NewQuotationEnvelope(*quotation, 1, timestamp, maxAge)
+}
+
+func (d *Datastore) SetAssetPriceUSD(asset dia.Asset, price float64, timestamp time.Time) error {
+	return d.SetAssetQuotation(&models.AssetQuotation{Asset: asset, Price: price, Source: dia.Diadata, Time: timestamp})
+}
+
+func (d *Datastore) GetAssetPriceUSD(asset dia.Asset, timestamp time.Time) (float64, error) {
+	quotation, err := d.GetAssetQuotation(asset, timestamp)
+	return quotation.Price, err
+}
+
+func (d *Datastore) GetAssetPriceUSDLatest(asset dia.Asset) (float64, error) {
+	quotation, err := d.GetAssetQuotationLatest(asset)
+	return quotation.Price, err
+}
+
+func (d *Datastore) GetAssetPriceUSDCache(asset dia.Asset) (float64, error) {
+	return d.GetAssetPriceUSDLatest(asset)
+}
+
+// SetFilter stores the latest @value of the filter @filterName for @asset on @exchange.
+func (d *Datastore) SetFilter(filterName string, asset dia.Asset, exchange string, value float64, t time.Time) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.filters[filterName+"_"+exchange+"_"+asset.Identifier()] = value
+	return nil
+}
+
+// ------------------------------------------------------------------------------
+// Trades
+// ------------------------------------------------------------------------------
+
+// saveTrade inserts @trade into the trades sorted by time.
+func (d *Datastore) saveTrade(trade dia.Trade) {
+	i := sort.Search(len(d.trades), func(i int) bool { return d.trades[i].Time.After(trade.Time) })
+	d.trades = append(d.trades, dia.Trade{})
+	copy(d.trades[i+1:], d.trades[i:])
+	d.trades[i] = trade
+}
+
+func (d *Datastore) SaveTradeInflux(t *dia.Trade) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.saveTrade(*t)
+	return nil
+}
+
+func (d *Datastore) SaveTradeInfluxToTable(t *dia.Trade, table string) error {
+	return d.SaveTradeInflux(t)
+}
+
+// TradesClaimed returns for each of @keys whether it was claimed before.
+func (d *Datastore) TradesClaimed(keys []string) ([]bool, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	claimed := make([]bool, len(keys))
+	for i, key := range keys {
+		claimed[i] = d.claimed[key]
+	}
+	return claimed, nil
+}
+
+// ClaimTrades records @keys as claimed. Claims do not expire.
+func (d *Datastore) ClaimTrades(keys []string, ttl time.Duration) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, key := range keys {
+		d.claimed[key] = true
+	}
+	return nil
+}
+
+// GetLastTrades returns the last @maxTrades trades of @asset on @exchange before @timestamp in
+// descending order by time. If @exchange is empty, trades from all exchanges are returned.
+func (d *Datastore) GetLastTrades(asset dia.Asset, exchange string, timestamp time.Time, maxTrades int, fullAsset bool) (trades []dia.Trade, err error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	for i := len(d.trades) - 1; i >= 0 && len(trades) < maxTrades; i-- {
+		trade := d.trades[i]
+		if !trade.Time.Before(timestamp) || trade.QuoteToken.Identifier() != asset.Identifier() {
+			continue
+		}
+		if exchange == "" || trade.Source == exchange {
+			trades = append(trades, trade)
+		}
+	}
+	return
+}
+
+// GetVolumeInflux returns the USD volume of all trades of @asset on @exchange in (@starttime,@endtime].
+func (d *Datastore) GetVolumeInflux(asset dia.Asset, exchange string, starttime time.Time, endtime time.Time) (*float64, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	var volume float64
+	for _, trade := range d.trades {
+		if !trade.Time.After(starttime) || trade.Time.After(endtime) {
+			continue
+		}
+		if (asset == dia.Asset{} || trade.QuoteToken.Identifier() == asset.Identifier()) && (exchange == "" || trade.Source == exchange) {
+			volume += trade.EstimatedUSDPrice * math.Abs(trade.Volume)
+		}
+	}
+	return &volume, nil
+}
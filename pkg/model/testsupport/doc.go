@@ -0,0 +1,12 @@
+// Package testsupport provides in-memory fakes of the datastores in pkg/model, so that code written against
+// models.RelDatastore and models.Datastore, such as scrapers and services, can be built and tested without
+// postgres, redis and influx. The API handlers in pkg/http/restServer/diaApi hold a concrete models.RelDB
+// and cannot be tested with these fakes.
+//
+// RelDatastore implements models.RelDatastore and Datastore implements models.Datastore. Both are
+// seeded with Fixtures and behave deterministically: lists are sorted and no method depends on the
+// wall clock. Assets, exchanges, pairs, blockchains, volumes, quotations and trades are kept in memory.
+// All other methods return ErrNotImplemented. They are generated into notimplemented.go by gen.go.
+package testsupport
+
+//go:generate go run gen.go
@@ -0,0 +1,33 @@
+package testsupport
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+// ErrNotImplemented is returned by all methods of the fakes which are not kept in memory.
+var ErrNotImplemented = errors.New("not implemented in testsupport")
+
+// Fixtures hold the initial content of the fakes.
+type Fixtures struct {
+	Assets        []dia.Asset             `json:"Assets"`
+	Exchanges     []dia.Exchange          `json:"Exchanges"`
+	ExchangePairs []dia.ExchangePair      `json:"ExchangePairs"`
+	Blockchains   []dia.BlockChain        `json:"Blockchains"`
+	Quotations    []models.AssetQuotation `json:"Quotations"`
+	Trades        []dia.Trade             `json:"Trades"`
+}
+
+// LoadFixtures reads fixtures from the json file at @path.
+func LoadFixtures(path string) (fixtures Fixtures, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &fixtures)
+	return
+}
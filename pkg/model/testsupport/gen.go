@@ -0,0 +1,247 @@
+//go:build ignore
+// +build ignore
+
+// gen.go writes notimplemented.go, which completes the fakes RelDatastore and Datastore with all
+// methods of the corresponding interfaces in pkg/model that are not implemented by hand.
+// Run it with go generate after the interfaces changed.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	modelDir   = ".."
+	outputFile = "notimplemented.go"
+)
+
+// imports are the packages which may be referenced by the method signatures.
+var imports = []struct {
+	name string
+	spec string
+}{
+	{"context", `"context"`},
+	{"io", `"io"`},
+	{"time", `"time"`},
+	{"dia", `"github.com/diadata-org/diadata/pkg/dia"`},
+	{"models", `models "github.com/diadata-org/diadata/pkg/model"`},
+}
+
+// fakes maps the interfaces in pkg/model to the fakes implementing them.
+var fakes = []struct {
+	iface    string
+	fake     string
+	receiver string
+}{
+	{"RelDatastore", "RelDatastore", "r"},
+	{"Datastore", "Datastore", "d"},
+}
+
+func main() {
+	fset := token.NewFileSet()
+	interfaces := parseInterfaces(fset)
+	implemented := parseImplemented(fset)
+
+	var buf bytes.Buffer
+
+	for _, f := range fakes {
+		iface, ok := interfaces[f.iface]
+		if !ok {
+			log.Fatalf("interface %s not found", f.iface)
+		}
+		for _, method := range iface.Methods.List {
+			funcType, ok := method.Type.(*ast.FuncType)
+			if !ok || len(method.Names) == 0 {
+				continue
+			}
+			name := method.Names[0].Name
+			if implemented[f.fake][name] {
+				continue
+			}
+			writeStub(&buf, fset, f.fake, f.receiver, name, funcType)
+		}
+	}
+
+	var header bytes.Buffer
+	header.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\n")
+	header.WriteString("package testsupport\n\nimport (\n")
+	for _, imp := range imports {
+		if strings.Contains(buf.String(), imp.name+".") {
+			if imp.name == "dia" {
+				header.WriteString("\n")
+			}
+			fmt.Fprintf(&header, "%s\n", imp.spec)
+		}
+	}
+	header.WriteString(")\n")
+
+	src, err := format.Source(append(header.Bytes(), buf.Bytes()...))
+	if err != nil {
+		log.Fatal("format generated source: ", err)
+	}
+	if err = ioutil.WriteFile(outputFile, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseInterfaces returns all interface types declared in pkg/model.
+func parseInterfaces(fset *token.FileSet) map[string]*ast.InterfaceType {
+	pkgs, err := parser.ParseDir(fset, modelDir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	interfaces := make(map[string]*ast.InterfaceType)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if spec, ok := n.(*ast.TypeSpec); ok {
+					if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+						interfaces[spec.Name.Name] = iface
+					}
+				}
+				return true
+			})
+		}
+	}
+	return interfaces
+}
+
+// parseImplemented returns the names of the methods implemented by hand, keyed by receiver type.
+func parseImplemented(fset *token.FileSet) map[string]map[string]bool {
+	implemented := make(map[string]map[string]bool)
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, filename := range files {
+		if filename == outputFile || filename == "gen.go" || strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+				continue
+			}
+			recv := fn.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				if implemented[ident.Name] == nil {
+					implemented[ident.Name] = make(map[string]bool)
+				}
+				implemented[ident.Name][fn.Name.Name] = true
+			}
+		}
+	}
+	return implemented
+}
+
+// writeStub writes a method @name of @fake with signature @funcType, which returns ErrNotImplemented.
+func writeStub(buf *bytes.Buffer, fset *token.FileSet, fake string, receiver string, name string, funcType *ast.FuncType) {
+	var params, results []string
+	if funcType.Params != nil {
+		for _, field := range funcType.Params.List {
+			typ := render(fset, qualify(field.Type))
+			for i := 0; i < max(1, len(field.Names)); i++ {
+				params = append(params, "_ "+typ)
+			}
+		}
+	}
+	returnsError := false
+	if funcType.Results != nil {
+		for _, field := range funcType.Results.List {
+			typ := render(fset, qualify(field.Type))
+			for i := 0; i < max(1, len(field.Names)); i++ {
+				results = append(results, "_ "+typ)
+			}
+		}
+		if n := len(results); n > 0 && results[n-1] == "_ error" {
+			results[n-1] = "err error"
+			returnsError = true
+		}
+	}
+
+	fmt.Fprintf(buf, "\nfunc (%s *%s) %s(%s)", receiver, fake, name, strings.Join(params, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(buf, " (%s)", strings.Join(results, ", "))
+	}
+	buf.WriteString(" {\n")
+	if returnsError {
+		buf.WriteString("err = ErrNotImplemented\n")
+	}
+	if len(results) > 0 {
+		buf.WriteString("return\n")
+	}
+	buf.WriteString("}\n")
+}
+
+// qualify prefixes all types declared in pkg/model in @expr with the package name.
+func qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(e.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("models"), Sel: ast.NewIdent(e.Name)}
+		}
+	case *ast.StarExpr:
+		e.X = qualify(e.X)
+	case *ast.ArrayType:
+		e.Elt = qualify(e.Elt)
+	case *ast.Ellipsis:
+		e.Elt = qualify(e.Elt)
+	case *ast.MapType:
+		e.Key = qualify(e.Key)
+		e.Value = qualify(e.Value)
+	case *ast.ChanType:
+		e.Value = qualify(e.Value)
+	case *ast.StructType:
+		qualifyFields(e.Fields)
+	case *ast.FuncType:
+		qualifyFields(e.Params)
+		qualifyFields(e.Results)
+	}
+	return expr
+}
+
+func qualifyFields(fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		field.Type = qualify(field.Type)
+	}
+}
+
+func render(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		log.Fatal(err)
+	}
+	return buf.String()
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,1631 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package testsupport
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+)
+
+func (r *RelDatastore) UpdateAsset(_ dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteAsset(_ dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetHistory(_ string) (_ []models.AssetHistoryEntry, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SubmitPendingAsset(_ dia.Asset, _ string, _ []models.AutoApprovalRule) (_ models.PendingAssetStatus, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetPendingAssets(_ models.PendingAssetStatus, _ int, _ int) (_ []models.PendingAsset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) EditPendingAsset(_ string, _ dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ApprovePendingAsset(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) RejectPendingAsset(_ string, _ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeprecateAsset(_ dia.Asset, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ReactivateAsset(_ dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetActiveAssetsOnly(_ string) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetDeprecatedAssets(_ string) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetsByIDs(_ []string, _ ...models.ReadOption) (_ map[string]dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) AddAssetTicker(_ dia.Asset, _ string, _ time.Time, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetTickers(_ dia.Asset) (_ []models.AssetTicker, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetsByTicker(_ string) (_ []models.AssetTickerMatch, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SearchAssets(_ string, _ int) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetsByBlockchain(_ string, _ string, _ models.AssetOrder, _ int, _ int) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetFiatAssetBySymbol(_ string) (_ dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) IdentifyAsset(_ dia.Asset) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) IdentifyAssetMatches(_ dia.Asset) (_ []models.AssetMatch, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetAssetAlias(_ dia.Asset, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetByAlias(_ string) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) LinkAssets(_ []dia.Asset) (_ string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) UnlinkAsset(_ dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetGroup(_ string, _ string) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetGroupID(_ dia.Asset) (_ string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetGroups() (_ map[string][]dia.AssetVolume, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetPage(_ uint32) (_ []dia.Asset, _ bool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetsPage(_ string, _ int) (_ []dia.Asset, _ string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) Count() (_ uint32, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSummaryCounts(_ ...models.ReadOption) (_ models.SummaryCounts, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangeSummaryCounts(_ string, _ ...models.ReadOption) (_ models.ExchangeCounts, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) RefreshSummaryCounts() (_ models.SummaryCounts, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetAssetVolumeAt(_ dia.Asset, _ float64, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetVolumeSeries(_ dia.Asset, _ time.Time, _ time.Time) (_ []models.AssetVolumeDay, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetAssetVolumeByExchange(_ dia.Asset, _ string, _ float64) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetVolumeBreakdown(_ dia.Asset) (_ dia.ExchangeVolumesList, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetSupply(_ dia.Supply) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLatestSupply(_ dia.Asset) (_ dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLatestSupplies(_ string) (_ []dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SnapshotAssetRanks(_ time.Time, _ []models.MarketCap) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetRankHistory(_ dia.Asset) (_ []models.AssetRank, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSectorAggregates() (_ []models.SectorAggregate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetTopAssetsByVolume(_ time.Time, _ int, _ int, _ string) (_ []dia.AssetVolume, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetsWithVOL(_ time.Time, _ int64, _ int64, _ bool, _ string) (_ []dia.AssetVolume, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetSource(_ dia.Asset, _ bool) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetSources(_ dia.Asset) (_ []string, _ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetSnapshot(_ dia.Asset) (_ models.AssetSnapshot, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetAssetMetadata(_ dia.AssetMetadata) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetMetadata(_ dia.Asset) (_ dia.AssetMetadata, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetDisplayFormat(_ dia.Asset) (_ dia.DisplayFormat, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetBenchmarkAsset(_ dia.BenchmarkAsset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetBenchmarkAssets() (_ []dia.BenchmarkAsset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteBenchmarkAsset(_ dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetServiceConfig(_ string, _ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetServiceConfig(_ string) (_ map[string]string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetFeedRestriction(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteFeedRestriction(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetFeedRestrictions() (_ []models.FeedRestriction, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetAPIKeyRegion(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAPIKeyRegion(_ string) (_ string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) IsFeedAvailable(_ string, _ string) (_ bool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ImportTokenList(_ io.Reader) (_ int, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ImportTokenListFromURL(_ string) (_ int, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ExportAssetsCSV(_ io.Writer, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ImportAssetsCSV(_ io.Reader, _ bool) (_ models.AssetImportReport, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetQuoteAssetConstraint(_ dia.Asset, _ []dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetQuoteAssetConstraint(_ dia.Asset) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllQuoteAssetConstraints() (_ map[dia.Asset][]dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAssetsWithVolByBlockchain(_ time.Time, _ time.Time, _ string) (_ []dia.AssetVolume, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangePairSeparator(_ string) (_ string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetPairsForAsset(_ dia.Asset, _ bool, _ bool) (_ []dia.ExchangePair, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SnapshotExchangePairs(_ time.Time) (_ int64, _ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangePairsAt(_ dia.Asset, _ time.Time) (_ []dia.ExchangePair, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllExchangePairs(_ string, _ int, _ int) (_ []dia.ExchangePair, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetUnverifiedExchangePairs(_ string) (_ []dia.ExchangePair, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ReconcileExchangePairs(_ string, _ []dia.ExchangePair) (_ models.PairReconciliation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteExchangePair(_ string, _ string, _ bool) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetExchangePairExcluded(_ string, _ string, _ bool) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExcludedExchangePairs() (_ []dia.ExchangePair, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNumPairs(_ dia.Exchange) (_ int, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetExchangeSymbol(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangeSymbol(_ string, _ string) (_ dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangeSymbols(_ models.ExchangeSymbolFilter) (_ []models.ExchangeSymbol, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangeSymbolsPage(_ models.ExchangeSymbolFilter) (_ models.ExchangeSymbolPage, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetUnverifiedExchangeSymbols(_ string) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetStaleExchangeSymbols(_ string, _ time.Duration) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) VerifyExchangeSymbol(_ string, _ string, _ string, _ string) (_ bool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) UnverifyExchangeSymbol(_ string, _ string, _ string) (_ bool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetVerificationHistory(_ string, _ string) (_ []models.SymbolVerificationEntry, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteExchangeSymbol(_ string, _ string, _ bool) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetSymbolLabel(_ models.SymbolLabel) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSymbolLabels(_ string) (_ []models.SymbolLabel, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSymbolScorer() (_ *models.SymbolScorer, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSymbolSuggestions(_ string, _ string, _ *models.SymbolScorer) (_ []models.SymbolSuggestion, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangeSymbolAssetID(_ string, _ string) (_ string, _ bool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetSymbolCasing(_ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSymbolCasings() (_ map[string]string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) CanonicalSymbol(_ string) (_ string) {
+	return
+}
+
+func (r *RelDatastore) GetAllExchangeAssets(_ bool) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ArchiveHistoricalQuotations(_ time.Time) (_ int, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetExchangeListing(_ dia.ExchangeListing) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangeListings(_ string, _ time.Time, _ time.Time) (_ []dia.ExchangeListing, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetUpcomingListings(_ string) (_ []dia.ExchangeListing, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetPool(_ dia.Pool) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetPoolByAddress(_ string, _ string) (_ dia.Pool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllPoolAddrsExchange(_ string, _ float64) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllPoolsExchange(_ string, _ float64) (_ []dia.Pool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetPoolsByAsset(_ dia.Asset, _ float64, _ float64) (_ []dia.Pool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetExchangePairPool(_ string, _ string, _ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteExchangePairPool(_ string, _ string, _ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetPoolsForExchangePair(_ string, _ string) (_ []dia.Pool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetExchangePairsForPool(_ string, _ string) (_ []dia.ExchangePair, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetTVL(_ dia.ProtocolTVL) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetTVL(_ string, _ string, _ time.Time) (_ dia.ProtocolTVL, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetTVLByChain(_ time.Time) (_ []dia.ChainTVL, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetDefiProtocol(_ dia.DefiProtocol) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetDefiProtocol(_ string) (_ dia.DefiProtocol, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetDefiProtocols() (_ []dia.DefiProtocol, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) UpdateBlockchain(_ string, _ models.BlockchainUpdate) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteBlockchain(_ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetBlockchainRPC(_ models.BlockchainRPC) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteBlockchainRPC(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetBlockchainRPCs(_ string) (_ []models.BlockchainRPC, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetBlockchainRPCHealth(_ string, _ string, _ bool) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetHealthyRPC(_ string) (_ models.BlockchainRPC, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllAssetsBlockchains() (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) InvalidateAssetCache(_ dia.Asset) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) CountCache() (_ uint32, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) CountCacheByClass() (_ map[string]uint32, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) CheckCacheIntegrity(_ bool) (_ models.CacheIntegrityReport, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) WarmAssetCache(_ context.Context, _ string, _ ...models.WarmupOption) (_ models.AssetCacheWarmup, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFTClass(_ dia.NFTClass) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllNFTClasses(_ string) (_ []dia.NFTClass, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetTradedNFTClasses(_ time.Time) (_ []dia.NFTClass, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTClasses(_ uint64, _ uint64) (_ []dia.NFTClass, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTClass(_ string, _ string) (_ dia.NFTClass, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTClassID(_ string, _ string) (_ string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTClassByID(_ string) (_ dia.NFTClass, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTClassesByNameSymbol(_ string) (_ []dia.NFTClass, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) UpdateNFTClassCategory(_ string, _ string) (_ bool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTCategories() (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFT(_ dia.NFT) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFT(_ string, _ string, _ string) (_ dia.NFT, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTID(_ string, _ string, _ string) (_ string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTsByAttributes(_ string, _ string, _ dia.NFTAttributes, _ int, _ int) (_ []dia.NFT, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFTTrade(_ dia.NFTTrade) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFTTradeToTable(_ dia.NFTTrade, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTTrades(_ string, _ string, _ string, _ time.Time, _ time.Time) (_ []dia.NFTTrade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTTradesCollection(_ string, _ string, _ time.Time, _ time.Time) (_ []dia.NFTTrade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllLastTrades(_ dia.NFTClass) (_ []dia.NFTTrade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTOffers(_ string, _ string, _ string) (_ []dia.NFTOffer, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTBids(_ string, _ string, _ string) (_ []dia.NFTBid, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTFloor(_ dia.NFTClass, _ time.Time, _ time.Duration, _ bool, _ string) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTFloorLevel(_ dia.NFTClass, _ time.Time, _ time.Duration, _ []dia.Asset, _ float64, _ bool, _ string) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTFloorRecursive(_ dia.NFTClass, _ time.Time, _ time.Duration, _ int, _ bool, _ string) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTFloorRange(_ dia.NFTClass, _ time.Time, _ time.Time, _ time.Duration, _ int, _ bool, _ string) (_ []float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTFloorPrice(_ dia.NFTClass, _ time.Duration) (_ models.NFTFloorPrice, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFTFloorPrice(_ models.NFTFloorPrice) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastNFTFloorPrice(_ dia.NFTClass, _ time.Duration) (_ models.NFTFloorPrice, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetRecentNFTFloorPrice(_ dia.NFTClass, _ time.Duration) (_ models.NFTFloorPrice, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) UpdateNFTFloorPrices() (_ int, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastBlockheightTopshot(_ time.Time) (_ uint64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFTBid(_ dia.NFTBid) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastNFTBid(_ string, _ string, _ string, _ uint64, _ uint) (_ dia.NFTBid, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastBlockNFTBid(_ dia.NFTClass) (_ uint64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastBlockNFTOffer(_ dia.NFTClass) (_ uint64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastBlockNFTTrade(_ dia.NFTClass) (_ uint64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFTOffer(_ dia.NFTOffer) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastNFTOffer(_ string, _ string, _ string, _ uint64, _ uint) (_ dia.NFTOffer, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetTopNFTsEth(_ int, _ int64, _ []string, _ time.Time, _ time.Time) (_ []struct {
+	Name       string
+	Address    string
+	Blockchain string
+	Volume     float64
+}, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNumNFTTrades(_ string, _ string, _ string, _ time.Time, _ time.Time) (_ int, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTVolume(_ string, _ string, _ string, _ time.Time, _ time.Time) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetKeys(_ string) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetScraperState(_ context.Context, _ string, _ models.ScraperState) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetScraperState(_ context.Context, _ string, _ models.ScraperState) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetScraperConfig(_ context.Context, _ string, _ models.ScraperConfig) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetScraperConfig(_ context.Context, _ string, _ models.ScraperConfig) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetBlockData(_ dia.BlockData) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetBlockData(_ string, _ int64) (_ dia.BlockData, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetLastBlockBlockscraper(_ string) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetScraperBlock(_ string, _ string, _ uint64) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetScraperBlocks() (_ []models.ScraperBlock, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllNFTExchanges() (_ []dia.NFTExchange, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetNFTExchange(_ string) (_ dia.Exchange, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetNFTExchange(_ dia.NFTExchange) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetCollectionCountByExchange(_ string) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) Get24HoursNFTExchangeVolume(_ dia.NFTExchange) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) Get24HoursNFTExchangeTrades(_ dia.NFTExchange) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetKeyPair(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetKeyPairID(_ string) (_ string) {
+	return
+}
+
+func (r *RelDatastore) GetFeederAccessByID(_ string) (_ string) {
+	return
+}
+
+func (r *RelDatastore) GetFeederByID(_ string) (_ string) {
+	return
+}
+
+func (r *RelDatastore) SetOracleConfig(_ string, _ string, _ string, _ string, _ string, _ string, _ string, _ string, _ string, _ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) SetFeederConfig(_ string, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetFeederID(_ string) (_ string) {
+	return
+}
+
+func (r *RelDatastore) GetFeederLimit(_ string) (_ int) {
+	return
+}
+
+func (r *RelDatastore) GetTotalFeeder(_ string) (_ int) {
+	return
+}
+
+func (r *RelDatastore) GetOracleConfig(_ string) (_ dia.OracleConfig, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) ChangeOracleState(_ string, _ bool) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) DeleteOracle(_ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetOraclesByOwner(_ string) (_ []dia.OracleConfig, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetAllFeeders() (_ []dia.OracleConfig, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetFeederResources() (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetOracleUpdates(_ string, _ string, _ int) (_ []dia.OracleUpdate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetOracleUpdateCount(_ string, _ string) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSyncVersion() (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSyncAssetsPage(_ string, _ int) (_ models.SyncSnapshotPage, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSyncExchangePairsPage(_ string, _ int) (_ models.SyncSnapshotPage, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) GetSyncChanges(_ int64, _ int) (_ []models.SyncChange, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) PurgeCustomer(_ string, _ models.PurgeMode, _ bool) (_ models.PurgeReport, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (r *RelDatastore) PruneRetention(_ []models.RetentionPolicy, _ bool) (_ models.PurgeReport, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetBatchFiatPriceInflux(_ []*models.FiatQuotation) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetSingleFiatPriceRedis(_ *models.FiatQuotation) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetFiatQuotation(_ *models.FiatQuotation) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetFiatQuotation(_ string, _ string, _ time.Time) (_ *models.FiatQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) ConvertUSDPrice(_ float64, _ string, _ time.Time) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetLatestSupply(_ string, _ *models.RelDB) (_ *dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSupplyCache(_ dia.Asset) (_ dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSupply(_ string, _ time.Time, _ time.Time, _ *models.RelDB) (_ []dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetSupply(_ *dia.Supply) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSupplyInflux(_ dia.Asset, _ time.Time, _ time.Time) (_ []dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSupplyInfluxRange(_ dia.Asset, _ time.Time, _ time.Time, _ time.Duration) (_ []dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSupplyAt(_ dia.Asset, _ time.Time) (_ dia.Supply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SaveSynthSupplyInfluxToTable(_ *dia.SynthAssetSupply, _ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SaveSynthSupplyInflux(_ *dia.SynthAssetSupply) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSynthSupplyInflux(_ string, _ string, _ string, _ int, _ time.Time, _ time.Time) (_ []dia.SynthAssetSupply, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSynthAssets(_ string, _ string) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetDiaTotalSupply(_ float64) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetDiaTotalSupply() (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetDiaCirculatingSupply(_ float64) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetDiaCirculatingSupply() (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSymbols(_ string) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetLastTradeTimeForExchange(_ dia.Asset, _ string) (_ *time.Time, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetLastTradeTimeForExchange(_ dia.Asset, _ string, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetFirstTradeDate(_ string) (_ time.Time, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradeInflux(_ dia.Asset, _ string, _ time.Time, _ time.Duration) (_ *dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SaveFilterInflux(_ string, _ dia.Asset, _ string, _ float64, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetFilterAllExchanges(_ string, _ string, _ string, _ time.Time, _ time.Time) (_ []models.AssetQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetLastTradesByExchangePair(_ string, _ string, _ int) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradeGaps(_ string, _ string, _ time.Time, _ time.Time, _ time.Duration) (_ []models.TradeGap, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetAllTrades(_ time.Time, _ int) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradesByExchangesFull(_ dia.Asset, _ []dia.Asset, _ []string, _ bool, _ time.Time, _ time.Time, _ int) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradesByExchangesAndBaseAssets(_ dia.Asset, _ []dia.Asset, _ []string, _ time.Time, _ time.Time, _ int) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradesByExchangesBatchedFull(_ dia.Asset, _ []dia.Asset, _ []string, _ bool, _ []time.Time, _ []time.Time, _ int) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradesByExchangesBatched(_ dia.Asset, _ []dia.Asset, _ []string, _ []time.Time, _ []time.Time, _ int) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetxcTradesByExchangesBatched(_ []dia.Asset, _ []string, _ []time.Time, _ []time.Time) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradesByExchangepairs(_ map[string][]dia.Pair, _ map[string][]string, _ time.Time, _ time.Time) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTradesByFeedSelection(_ []dia.FeedSelection, _ []time.Time, _ []time.Time) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetActiveExchangesAndPairs(_ string, _ string, _ int64, _ time.Time, _ time.Time) (_ map[string][]dia.Pair, _ map[string]int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetOldTradesFromInflux(_ string, _ string, _ bool, _ time.Time, _ time.Time) (_ []dia.Trade, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) CopyInfluxMeasurements(_ string, _ string, _ string, _ string, _ time.Time, _ time.Time) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) CreateRetentionPolicy(_ models.InfluxRetentionPolicy) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) AlterRetentionPolicy(_ models.InfluxRetentionPolicy) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) EnsureRetentionPolicy(_ models.InfluxRetentionPolicy) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetRetentionPolicies() (_ []models.InfluxRetentionPolicy, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetWriteRetentionPolicy(_ string, _ string) {
+}
+
+func (d *Datastore) InstallContinuousQueries(_ []models.DownsampleSeries) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetContinuousQueries() (_ map[string]string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) DropContinuousQuery(_ string) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) Downsample(_ models.DownsampleSeries, _ time.Time, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetDownsampledSeries(_ []models.DownsampleSeries) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetFilterPoints(_ string, _ string, _ string, _ string, _ time.Time, _ time.Time) (_ *models.Points, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetFilterPointsAsset(_ string, _ string, _ string, _ string, _ time.Time, _ time.Time) (_ *models.Points, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetFilterPointsRange(_ string, _ dia.Asset, _ string, _ time.Time, _ time.Time, _ time.Duration) (_ []dia.FilterPoint, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetLastPriceBefore(_ dia.Asset, _ string, _ string, _ time.Time) (_ models.Price, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetAvailablePairs(_ string, _ []dia.ExchangePair) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetAvailablePairs(_ string) (_ []dia.ExchangePair, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetCurrencyChange(_ *models.Change) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetCurrencyChange() (_ *models.Change, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) Get24HoursAssetVolume(_ dia.Asset) (_ *float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetVolumeInfluxRange(_ dia.Asset, _ time.Time, _ time.Time, _ time.Duration) (_ []models.VolumePoint, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) Get24HoursExchangeVolume(_ string) (_ *float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetNumTradesExchange24H(_ string) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetNumTrades(_ string, _ string, _ string, _ time.Time, _ time.Time) (_ int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetNumTradesSeries(_ dia.Asset, _ string, _ time.Time, _ time.Time, _ string) (_ []int64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetVolumesAllExchanges(_ dia.Asset, _ time.Time, _ time.Time) (_ dia.ExchangeVolumesList, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetExchangePairVolumes(_ dia.Asset, _ time.Time, _ time.Time, _ float64) (_ map[string][]dia.PairVolume, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetSortedAssetQuotations(_ []dia.Asset) (_ []models.AssetQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetAssetGroupQuotation(_ *models.AssetGroupQuotation) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetAssetGroupQuotation(_ string, _ time.Time) (_ *models.AssetGroupQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetAssetGroupQuotationLatest(_ string) (_ *models.AssetGroupQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetScraperLag(_ models.ScraperLag) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetScraperLags(_ string, _ string, _ time.Time, _ time.Time) (_ []models.ScraperLag, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTopAssetByMcap(_ string, _ *models.RelDB) (_ dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTopAssetByVolume(_ string, _ *models.RelDB) (_ dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetAssetsWithVOLInflux(_ time.Time) (_ []dia.Asset, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetOldestQuotation(_ dia.Asset) (_ models.AssetQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SavePoolInflux(_ dia.Pool) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetPoolInflux(_ string, _ time.Time, _ time.Time) (_ []dia.Pool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SavePoolLiquidityInflux(_ dia.Pool, _ []dia.AssetVolume, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetPoolLiquidity(_ dia.Pool, _ time.Time, _ time.Time) (_ []dia.Pool, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetPoolLiquiditiesUSD(_ *dia.Pool, _ map[string]float64) {
+}
+
+func (d *Datastore) SaveDefiRateInflux(_ dia.DefiRate) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetDefiRatesInflux(_ string, _ dia.Asset, _ time.Time, _ time.Time) (_ []dia.DefiRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) ComputePegDeviation(_ dia.Asset, _ float64, _ time.Duration) (_ models.PegDeviation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SavePegDeviationInflux(_ models.PegDeviation) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) UpdatePegDeviations() (_ int, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetPegDeviation(_ dia.Asset, _ time.Duration) (_ models.PegDeviation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetAssetsMarketCap(_ dia.Asset) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetMarketCap(_ dia.Asset, _ *models.RelDB) (_ models.MarketCap, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetTopAssetsByMarketCap(_ int, _ string, _ *models.RelDB) (_ []models.MarketCap, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetInterestRate(_ *models.InterestRate) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetInterestRate(_ string, _ string) (_ *models.InterestRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetInterestRateRange(_ string, _ string, _ string) (_ []*models.InterestRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetRatesMeta() (_ []models.InterestRateMeta, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetCompoundedIndex(_ string, _ time.Time, _ int, _ int) (_ *models.InterestRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetCompoundedIndexRange(_ string, _ time.Time, _ time.Time, _ int, _ int) (_ []*models.InterestRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetCompoundedAvg(_ string, _ time.Time, _ int, _ int, _ int) (_ *models.InterestRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetCompoundedAvgRange(_ string, _ time.Time, _ time.Time, _ int, _ int, _ int) (_ []*models.InterestRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetCompoundedAvgDIARange(_ string, _ time.Time, _ time.Time, _ int, _ int, _ int) (_ []*models.InterestRate, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SaveForeignQuotationInflux(_ models.ForeignQuotation) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetForeignQuotationInflux(_ string, _ string, _ time.Time) (_ models.ForeignQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetForeignPriceYesterday(_ string, _ string) (_ float64, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetForeignSymbolsInflux(_ string) (_ []string, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetForeignQuotationsInflux(_ string, _ string, _ time.Time, _ time.Time) (_ []models.ForeignQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) CompareForeignQuotations(_ dia.Asset, _ string, _ time.Time, _ time.Time) (_ []models.QuotationComparison, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetVWAPFirefly(_ string, _ float64, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetVWAPFirefly(_ string, _ time.Time, _ time.Time) (_ []float64, _ []time.Time, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SaveIndexEngineTimeInflux(_ map[string]string, _ map[string]interface{}, _ time.Time) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetBenchmarkedIndexValuesInflux(_ string, _ time.Time, _ time.Time) (_ models.BenchmarkedIndex, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) SetStockQuotation(_ models.StockQuotation) (err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetStockQuotation(_ string, _ string, _ time.Time, _ time.Time) (_ []models.StockQuotation, err error) {
+	err = ErrNotImplemented
+	return
+}
+
+func (d *Datastore) GetStockSymbols() (_ map[models.Stock]string, err error) {
+	err = ErrNotImplemented
+	return
+}
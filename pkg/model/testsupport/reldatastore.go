@@ -0,0 +1,430 @@
+package testsupport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	models "github.com/diadata-org/diadata/pkg/model"
+	"github.com/jackc/pgx/v4"
+)
+
+var _ models.RelDatastore = (*RelDatastore)(nil)
+
+// RelDatastore is an in-memory fake of models.RelDatastore. Missing records yield pgx.ErrNoRows
+// as in postgres.
+type RelDatastore struct {
+	lock sync.RWMutex
+	// assets and assetIDs are keyed by asset identifier.
+	assets      map[string]dia.Asset
+	assetIDs    map[string]string
+	exchanges   map[string]dia.Exchange
+	pairs       map[string]map[string]dia.ExchangePair
+	blockchains map[string]dia.BlockChain
+	volumes     map[string]models.AssetVolume24H
+	quotations  map[string][]models.AssetQuotation
+}
+
+// NewRelDatastore returns a RelDatastore seeded with the assets, exchanges, pairs and blockchains in @fixtures.
+func NewRelDatastore(fixtures Fixtures) *RelDatastore {
+	r := &RelDatastore{
+		assets:      make(map[string]dia.Asset),
+		assetIDs:    make(map[string]string),
+		exchanges:   make(map[string]dia.Exchange),
+		pairs:       make(map[string]map[string]dia.ExchangePair),
+		blockchains: make(map[string]dia.BlockChain),
+		volumes:     make(map[string]models.AssetVolume24H),
+		quotations:  make(map[string][]models.AssetQuotation),
+	}
+	for _, asset := range fixtures.Assets {
+		r.setAsset(asset)
+	}
+	for _, exchange := range fixtures.Exchanges {
+		r.exchanges[exchange.Name] = exchange
+	}
+	for _, pair := range fixtures.ExchangePairs {
+		r.setExchangePair(pair.Exchange, pair)
+	}
+	for _, blockchain := range fixtures.Blockchains {
+		r.blockchains[blockchain.Name] = blockchain
+	}
+	return r
+}
+
+// WithTx runs @fn on @r. Changes are not rolled back if @fn fails.
+func (r *RelDatastore) WithTx(ctx context.Context, fn func(tx models.RelStore) error) error {
+	return fn(r)
+}
+
+// ------------------------------------------------------------------------------
+// Assets
+// ------------------------------------------------------------------------------
+
+// setAsset stores @asset and assigns a deterministic ID to new assets.
+func (r *RelDatastore) setAsset(asset dia.Asset) {
+	identifier := asset.Identifier()
+	if _, ok := r.assetIDs[identifier]; !ok {
+		r.assetIDs[identifier] = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(r.assetIDs)+1)
+	}
+	r.assets[identifier] = asset
+}
+
+func (r *RelDatastore) SetAsset(asset dia.Asset) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.setAsset(asset)
+	return nil
+}
+
+func (r *RelDatastore) SetAssetCache(asset dia.Asset) error {
+	return r.SetAsset(asset)
+}
+
+func (r *RelDatastore) GetAsset(address, blockchain string, opts ...models.ReadOption) (dia.Asset, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	asset, ok := r.assets[blockchain+"-"+address]
+	if !ok {
+		return dia.Asset{}, pgx.ErrNoRows
+	}
+	return asset, nil
+}
+
+func (r *RelDatastore) GetAssetCache(blockchain string, address string) (dia.Asset, error) {
+	return r.GetAsset(address, blockchain)
+}
+
+func (r *RelDatastore) SetAssetCacheContext(ctx context.Context, asset dia.Asset) error {
+	return r.SetAssetCache(asset)
+}
+
+func (r *RelDatastore) GetAssetCacheContext(ctx context.Context, blockchain string, address string) (dia.Asset, error) {
+	return r.GetAssetCache(blockchain, address)
+}
+
+func (r *RelDatastore) SetAssetCacheBatch(assets []dia.Asset) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, asset := range assets {
+		r.setAsset(asset)
+	}
+	return nil
+}
+
+func (r *RelDatastore) GetAssetCacheBatch(assets []dia.Asset) ([]dia.Asset, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	result := []dia.Asset{}
+	for _, asset := range assets {
+		if cached, ok := r.assets[asset.Blockchain+"-"+asset.Address]; ok {
+			result = append(result, cached)
+		}
+	}
+	return result, nil
+}
+
+func (r *RelDatastore) GetAssetID(asset dia.Asset) (string, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	id, ok := r.assetIDs[asset.Identifier()]
+	if !ok {
+		return "", pgx.ErrNoRows
+	}
+	return id, nil
+}
+
+func (r *RelDatastore) GetAssetByID(ID string, opts ...models.ReadOption) (dia.Asset, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for identifier, id := range r.assetIDs {
+		if id == ID {
+			return r.assets[identifier], nil
+		}
+	}
+	return dia.Asset{}, pgx.ErrNoRows
+}
+
+// GetAllAssets returns all assets on @blockchain sorted by address.
+func (r *RelDatastore) GetAllAssets(blockchain string) (assets []dia.Asset, err error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, asset := range r.assets {
+		if asset.Blockchain == blockchain {
+			assets = append(assets, asset)
+		}
+	}
+	sortAssets(assets)
+	return
+}
+
+// GetAssetsBySymbolName returns all assets whose symbol or name equals @symbol resp. @name, ignoring case.
+func (r *RelDatastore) GetAssetsBySymbolName(symbol, name string) (assets []dia.Asset, err error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, asset := range r.assets {
+		if (symbol != "" && strings.EqualFold(asset.Symbol, symbol)) || (name != "" && strings.EqualFold(asset.Name, name)) {
+			assets = append(assets, asset)
+		}
+	}
+	sortAssets(assets)
+	return
+}
+
+func (r *RelDatastore) SetAssetVolume24H(asset dia.Asset, volume models.AssetVolume24H) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.assets[asset.Identifier()]; !ok {
+		return pgx.ErrNoRows
+	}
+	r.volumes[asset.Identifier()] = volume
+	return nil
+}
+
+func (r *RelDatastore) GetLastAssetVolume24H(asset dia.Asset) (models.AssetVolume24H, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	volume, ok := r.volumes[asset.Identifier()]
+	if !ok {
+		return models.AssetVolume24H{}, pgx.ErrNoRows
+	}
+	return volume, nil
+}
+
+// ------------------------------------------------------------------------------
+// Exchanges and pairs
+// ------------------------------------------------------------------------------
+
+func (r *RelDatastore) SetExchange(exchange dia.Exchange) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.exchanges[exchange.Name] = exchange
+	return nil
+}
+
+func (r *RelDatastore) GetExchange(name string) (dia.Exchange, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	exchange, ok := r.exchanges[name]
+	if !ok {
+		return dia.Exchange{}, pgx.ErrNoRows
+	}
+	return exchange, nil
+}
+
+// GetAllExchanges returns all exchanges sorted by name.
+func (r *RelDatastore) GetAllExchanges() (exchanges []dia.Exchange, err error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, exchange := range r.exchanges {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Slice(exchanges, func(i, j int) bool { return exchanges[i].Name < exchanges[j].Name })
+	return
+}
+
+func (r *RelDatastore) GetExchangeNames() (names []string, err error) {
+	exchanges, err := r.GetAllExchanges()
+	for _, exchange := range exchanges {
+		names = append(names, exchange.Name)
+	}
+	return
+}
+
+// setExchangePair stores @pair. Pairs of sandbox, testnet and demo markets are never verified.
+func (r *RelDatastore) setExchangePair(exchange string, pair dia.ExchangePair) {
+	if r.pairs[exchange] == nil {
+		r.pairs[exchange] = make(map[string]dia.ExchangePair)
+	}
+	pair.Exchange = exchange
+	if dia.IsExcludedMarket(exchange, pair.ForeignName) {
+		pair.Verified = false
+	}
+	r.pairs[exchange][pair.ForeignName] = pair
+}
+
+func (r *RelDatastore) SetExchangePair(exchange string, pair dia.ExchangePair, cache bool) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.setExchangePair(exchange, pair)
+	return nil
+}
+
+func (r *RelDatastore) SetExchangePairs(exchange string, pairs []dia.ExchangePair) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, pair := range pairs {
+		r.setExchangePair(exchange, pair)
+	}
+	return nil
+}
+
+func (r *RelDatastore) SetExchangePairCache(exchange string, pair dia.ExchangePair) error {
+	return r.SetExchangePair(exchange, pair, false)
+}
+
+func (r *RelDatastore) GetExchangePair(exchange string, foreignname string, caseSensitive bool) (dia.ExchangePair, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, pair := range r.sortedPairs(exchange) {
+		if pair.ForeignName == foreignname || (!caseSensitive && strings.EqualFold(pair.ForeignName, foreignname)) {
+			return pair, nil
+		}
+	}
+	return dia.ExchangePair{}, pgx.ErrNoRows
+}
+
+func (r *RelDatastore) GetExchangePairCache(exchange string, foreignName string) (dia.ExchangePair, error) {
+	return r.GetExchangePair(exchange, foreignName, true)
+}
+
+func (r *RelDatastore) SetExchangePairCacheContext(ctx context.Context, exchange string, pair dia.ExchangePair) error {
+	return r.SetExchangePairCache(exchange, pair)
+}
+
+func (r *RelDatastore) GetExchangePairCacheContext(ctx context.Context, exchange string, foreignName string) (dia.ExchangePair, error) {
+	return r.GetExchangePairCache(exchange, foreignName)
+}
+
+// GetExchangePairSymbols returns all pairs on @exchange sorted by foreign name.
+// Pairs of sandbox, testnet and demo markets are omitted.
+func (r *RelDatastore) GetExchangePairSymbols(exchange string) (pairs []dia.ExchangePair, err error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, pair := range r.sortedPairs(exchange) {
+		if !dia.IsExcludedMarket(exchange, pair.ForeignName) {
+			pairs = append(pairs, dia.ExchangePair{Symbol: pair.Symbol, ForeignName: pair.ForeignName, Exchange: exchange})
+		}
+	}
+	return
+}
+
+// GetPairsForExchange returns all pairs on @exchange sorted by foreign name. If @filterVerified is
+// true, only pairs with verification state @verified are returned.
+func (r *RelDatastore) GetPairsForExchange(exchange dia.Exchange, filterVerified bool, verified bool) (pairs []dia.ExchangePair, err error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, pair := range r.sortedPairs(exchange.Name) {
+		if !filterVerified || pair.Verified == verified {
+			pairs = append(pairs, pair)
+		}
+	}
+	return
+}
+
+func (r *RelDatastore) sortedPairs(exchange string) (pairs []dia.ExchangePair) {
+	for _, pair := range r.pairs[exchange] {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].ForeignName < pairs[j].ForeignName })
+	return
+}
+
+// ------------------------------------------------------------------------------
+// Blockchains
+// ------------------------------------------------------------------------------
+
+func (r *RelDatastore) SetBlockchain(blockchain dia.BlockChain) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.blockchains[blockchain.Name] = blockchain
+	return nil
+}
+
+func (r *RelDatastore) GetBlockchain(name string) (dia.BlockChain, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	blockchain, ok := r.blockchains[name]
+	if !ok {
+		return dia.BlockChain{}, pgx.ErrNoRows
+	}
+	return blockchain, nil
+}
+
+// GetAllBlockchains returns all blockchains sorted by name. If fullAsset=false, only the symbol of
+// the native token is returned.
+func (r *RelDatastore) GetAllBlockchains(fullAsset bool) (blockchains []dia.BlockChain, err error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, blockchain := range r.blockchains {
+		if !fullAsset {
+			blockchain.NativeToken = dia.Asset{Symbol: blockchain.NativeToken.Symbol}
+		}
+		blockchains = append(blockchains, blockchain)
+	}
+	sort.Slice(blockchains, func(i, j int) bool { return blockchains[i].Name < blockchains[j].Name })
+	return
+}
+
+func (r *RelDatastore) GetBlockchainsByType(chainType dia.ChainType, includeTestnets bool) (blockchains []dia.BlockChain, err error) {
+	all, err := r.GetAllBlockchains(true)
+	for _, blockchain := range all {
+		if blockchain.ChainType == chainType && (includeTestnets || !blockchain.IsTestnet) {
+			blockchains = append(blockchains, blockchain)
+		}
+	}
+	return
+}
+
+// ------------------------------------------------------------------------------
+// Historical quotations
+// ------------------------------------------------------------------------------
+
+func (r *RelDatastore) SetHistoricalQuotation(quotation models.AssetQuotation) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	identifier := quotation.Asset.Identifier()
+	r.quotations[identifier] = insertQuotation(r.quotations[identifier], quotation)
+	return nil
+}
+
+// GetHistoricalQuotations returns all historical quotations of @asset in [@starttime,@endtime] sorted by time.
+func (r *RelDatastore) GetHistoricalQuotations(asset dia.Asset, starttime time.Time, endtime time.Time) (quotations []models.AssetQuotation, err error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, quotation := range r.quotations[asset.Identifier()] {
+		if !quotation.Time.Before(starttime) && !quotation.Time.After(endtime) {
+			quotations = append(quotations, quotation)
+		}
+	}
+	return
+}
+
+func (r *RelDatastore) GetLastHistoricalQuotationTimestamp(asset dia.Asset) (time.Time, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	quotations := r.quotations[asset.Identifier()]
+	if len(quotations) == 0 {
+		return time.Time{}, pgx.ErrNoRows
+	}
+	return quotations[len(quotations)-1].Time, nil
+}
+
+// sortAssets sorts @assets by blockchain and address.
+func sortAssets(assets []dia.Asset) {
+	sort.Slice(assets, func(i, j int) bool {
+		if assets[i].Blockchain != assets[j].Blockchain {
+			return assets[i].Blockchain < assets[j].Blockchain
+		}
+		return assets[i].Address < assets[j].Address
+	})
+}
+
+// insertQuotation inserts @quotation into @quotations sorted by time. A quotation at the same
+// time and from the same source is replaced.
+func insertQuotation(quotations []models.AssetQuotation, quotation models.AssetQuotation) []models.AssetQuotation {
+	i := sort.Search(len(quotations), func(i int) bool { return !quotations[i].Time.Before(quotation.Time) })
+	for j := i; j < len(quotations) && quotations[j].Time.Equal(quotation.Time); j++ {
+		if quotations[j].Source == quotation.Source {
+			quotations[j] = quotation
+			return quotations
+		}
+	}
+	quotations = append(quotations, models.AssetQuotation{})
+	copy(quotations[i+1:], quotations[i:])
+	quotations[i] = quotation
+	return quotations
+}
@@ -0,0 +1,60 @@
+package testsupport
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/jackc/pgx/v4"
+)
+
+func TestFakes(t *testing.T) {
+	fixtures, err := LoadFixtures("testdata/fixtures.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relDB := NewRelDatastore(fixtures)
+	datastore := NewDatastore(fixtures)
+	eth := fixtures.Assets[0]
+
+	asset, err := relDB.GetAsset(eth.Address, eth.Blockchain)
+	if err != nil || asset != eth {
+		t.Errorf("expected %v, got %v (%v)", eth, asset, err)
+	}
+	if _, err = relDB.GetAsset("0x1", dia.ETHEREUM); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows, got %v", err)
+	}
+	pair, err := relDB.GetExchangePairCache(dia.BinanceExchange, "ETHUSDT")
+	if err != nil || !pair.Verified || pair.UnderlyingPair.QuoteToken.Symbol != "ETH" {
+		t.Errorf("unexpected pair %v (%v)", pair, err)
+	}
+	if _, err = relDB.GetNFTCategories(); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("expected ErrNotImplemented, got %v", err)
+	}
+
+	quotation, err := datastore.GetAssetQuotation(eth, time.Date(2023, 3, 14, 12, 1, 0, 0, time.UTC))
+	if err != nil || quotation.Price != 1800 {
+		t.Errorf("expected price 1800, got %v (%v)", quotation, err)
+	}
+	quotation, err = datastore.GetAssetQuotationLatest(eth)
+	if err != nil || quotation.Price != 1810 {
+		t.Errorf("expected latest price 1810, got %v (%v)", quotation, err)
+	}
+	trades, err := datastore.GetLastTrades(eth, "", time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC), 10, true)
+	if err != nil || len(trades) != 1 {
+		t.Errorf("expected one trade, got %v (%v)", trades, err)
+	}
+	keys := []string{trades[0].IdempotencyKey()}
+	claimed, _ := datastore.TradesClaimed(keys)
+	if claimed[0] {
+		t.Error("expected an unclaimed trade")
+	}
+	if err = datastore.ClaimTrades(keys, time.Hour); err != nil {
+		t.Error(err)
+	}
+	claimed, _ = datastore.TradesClaimed(keys)
+	if !claimed[0] {
+		t.Error("expected a claimed trade")
+	}
+}
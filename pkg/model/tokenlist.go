@@ -0,0 +1,377 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/jackc/pgx/v4"
+)
+
+// Limits of the token list schema, see https://uniswap.org/tokenlist.schema.json
+const (
+	tokenListMaxTokens      = 10000
+	tokenListMaxSymbol      = 20
+	tokenListMaxName        = 40
+	tokenListMaxTagID       = 10
+	tokenListMaxTagName     = 21
+	tokenListMaxDescription = 200
+)
+
+// tokenListMaxCached is the maximal number of token lists kept by a TokenListExporter.
+const tokenListMaxCached = 100
+
+var (
+	tokenListSymbolRegex = regexp.MustCompile(`^[a-zA-Z0-9+\-%/$.]+$`)
+	tokenListTagIDRegex  = regexp.MustCompile(`[^\w]`)
+	tokenListMaxAge      = getCacheTTL("TOKENLIST_REFRESH_SECONDS", "3600")
+)
+
+// TokenList is a token list in the format of the Uniswap token list standard.
+type TokenList struct {
+	Name      string                  `json:"name"`
+	Timestamp string                  `json:"timestamp"`
+	Version   TokenListVersion        `json:"version"`
+	Keywords  []string                `json:"keywords,omitempty"`
+	Tags      map[string]TokenListTag `json:"tags,omitempty"`
+	Tokens    []TokenListToken        `json:"tokens"`
+}
+
+// TokenListVersion is the semantic version of a token list. Removing tokens is a major change,
+// adding tokens a minor change and modifying the details of listed tokens a patch.
+type TokenListVersion struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+}
+
+// TokenListTag describes a tag referenced by the tokens of a token list.
+type TokenListTag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// TokenListToken is a single token entry of a token list.
+type TokenListToken struct {
+	ChainID  int      `json:"chainId"`
+	Address  string   `json:"address"`
+	Name     string   `json:"name"`
+	Symbol   string   `json:"symbol"`
+	Decimals uint8    `json:"decimals"`
+	LogoURI  string   `json:"logoURI,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+func (t TokenListToken) key() string {
+	return strconv.Itoa(t.ChainID) + "-" + strings.ToLower(t.Address)
+}
+
+func (t TokenListToken) equal(other TokenListToken) bool {
+	if t.Name != other.Name || t.Symbol != other.Symbol || t.Decimals != other.Decimals || t.LogoURI != other.LogoURI {
+		return false
+	}
+	if len(t.Tags) != len(other.Tags) {
+		return false
+	}
+	for i := range t.Tags {
+		if t.Tags[i] != other.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NextTokenListVersion returns the version of a token list that changes from @oldTokens to @newTokens.
+func NextTokenListVersion(previous TokenListVersion, oldTokens []TokenListToken, newTokens []TokenListToken) TokenListVersion {
+	oldMap := make(map[string]TokenListToken)
+	for _, token := range oldTokens {
+		oldMap[token.key()] = token
+	}
+	newMap := make(map[string]TokenListToken)
+	for _, token := range newTokens {
+		newMap[token.key()] = token
+	}
+
+	var removed, added, modified bool
+	for key, token := range oldMap {
+		newToken, ok := newMap[key]
+		if !ok {
+			removed = true
+			continue
+		}
+		if !token.equal(newToken) {
+			modified = true
+		}
+	}
+	for key := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			added = true
+		}
+	}
+
+	switch {
+	case removed:
+		return TokenListVersion{Major: previous.Major + 1}
+	case added:
+		return TokenListVersion{Major: previous.Major, Minor: previous.Minor + 1}
+	case modified:
+		return TokenListVersion{Major: previous.Major, Minor: previous.Minor, Patch: previous.Patch + 1}
+	default:
+		return previous
+	}
+}
+
+// GetTokenListTokens returns the token list entries of all active assets on @blockchain which have metadata.
+// If @assets is not empty, the entries of the curated set @assets are returned instead, regardless of @blockchain.
+// Assets on chains without numeric chain id cannot be represented in a token list and are omitted.
+func (rdb *RelDB) GetTokenListTokens(blockchain string, assets []dia.Asset) (tokens []TokenListToken, tags map[string]TokenListTag, err error) {
+	var (
+		rows pgx.Rows
+		args []interface{}
+	)
+	query := fmt.Sprintf(`
+	SELECT b.chain_id,a.address,a.name,a.symbol,a.decimals,am.logo,am.tags
+	FROM %s a
+	INNER JOIN %s b
+	ON a.blockchain=b.name
+	LEFT JOIN %s am
+	ON a.asset_id=am.asset_id
+	WHERE a.deprecated_at IS NULL
+	`, assetTable, blockchainTable, assetMetadataTable)
+	if len(assets) > 0 {
+		var addresses, blockchains []string
+		for _, asset := range assets {
+			addresses = append(addresses, asset.Address)
+			blockchains = append(blockchains, asset.Blockchain)
+		}
+		query += "AND (a.address,a.blockchain) IN (SELECT * FROM unnest($1::text[],$2::text[]))"
+		args = append(args, addresses, blockchains)
+	} else {
+		query += "AND a.blockchain=$1 AND am.asset_id IS NOT NULL"
+		args = append(args, blockchain)
+	}
+	query += fmt.Sprintf(" ORDER BY a.symbol,a.address LIMIT %d", tokenListMaxTokens)
+
+	rows, err = rdb.postgresClient.Query(context.Background(), query, args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	tags = make(map[string]TokenListTag)
+	for rows.Next() {
+		var (
+			chainID   sql.NullString
+			name      sql.NullString
+			token     TokenListToken
+			decimals  sql.NullInt64
+			logo      sql.NullString
+			assetTags []string
+		)
+		err = rows.Scan(&chainID, &token.Address, &name, &token.Symbol, &decimals, &logo, &assetTags)
+		if err != nil {
+			return
+		}
+		token.ChainID, err = strconv.Atoi(chainID.String)
+		if err != nil {
+			err = nil
+			continue
+		}
+		if !decimals.Valid || !validTokenListSymbol(token.Symbol) {
+			continue
+		}
+		token.Decimals = uint8(decimals.Int64)
+		token.Name = truncateRunes(name.String, tokenListMaxName)
+		if logo.Valid {
+			token.LogoURI = logo.String
+		}
+		for _, assetTag := range assetTags {
+			id := tokenListTagID(assetTag)
+			if id == "" || utils.Contains(&token.Tags, id) {
+				continue
+			}
+			if _, ok := tags[id]; !ok {
+				tags[id] = newTokenListTag(assetTag)
+			}
+			token.Tags = append(token.Tags, id)
+		}
+		sort.Strings(token.Tags)
+		tokens = append(tokens, token)
+	}
+	err = rows.Err()
+	return
+}
+
+func validTokenListSymbol(symbol string) bool {
+	return len(symbol) <= tokenListMaxSymbol && tokenListSymbolRegex.MatchString(symbol)
+}
+
+// tokenListTagID reduces a metadata tag to a valid tag identifier of the token list schema.
+func tokenListTagID(tag string) string {
+	id := tokenListTagIDRegex.ReplaceAllString(strings.ToLower(tag), "")
+	if len(id) > tokenListMaxTagID {
+		id = id[:tokenListMaxTagID]
+	}
+	return id
+}
+
+func newTokenListTag(tag string) TokenListTag {
+	return TokenListTag{Name: truncateRunes(tag, tokenListMaxTagName), Description: truncateRunes("Tokens tagged as "+tag, tokenListMaxDescription)}
+}
+
+// truncateRunes returns the first @n characters of @s. The length limits of the token list schema count characters.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// TokenListExporter generates token lists. Their versions are persisted, see exportTokenList.
+// A token list is regenerated once the asset universe changes or the list exceeds its maximal age.
+// At most tokenListMaxCached lists are kept, the least recently generated list is evicted first.
+type TokenListExporter struct {
+	rdb   *RelDB
+	lists map[string]tokenListState
+	mu    sync.Mutex
+}
+
+type tokenListState struct {
+	syncVersion int64
+	generated   time.Time
+	list        TokenList
+}
+
+// NewTokenListExporter returns a token list exporter reading from @rdb.
+func NewTokenListExporter(rdb *RelDB) *TokenListExporter {
+	return &TokenListExporter{
+		rdb:   rdb,
+		lists: make(map[string]tokenListState),
+	}
+}
+
+// Export returns the token list @name of all active assets with metadata on @blockchain
+// or, if @assets is not empty, of the curated set @assets.
+func (e *TokenListExporter) Export(name string, blockchain string, assets []dia.Asset) (TokenList, error) {
+	syncVersion, err := e.rdb.GetSyncVersion()
+	if err != nil {
+		return TokenList{}, err
+	}
+
+	key := tokenListCacheKey(name, blockchain, assets)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	state, ok := e.lists[key]
+	if ok && state.syncVersion == syncVersion && time.Since(state.generated) < tokenListMaxAge {
+		return state.list, nil
+	}
+
+	list, err := e.rdb.exportTokenList(key, name, blockchain, assets)
+	if err != nil {
+		return TokenList{}, err
+	}
+	if !ok && len(e.lists) >= tokenListMaxCached {
+		e.evictOldest()
+	}
+	e.lists[key] = tokenListState{syncVersion: syncVersion, generated: time.Now(), list: list}
+	return list, nil
+}
+
+// exportTokenList generates the token list @name identified by @key and versions it against the latest
+// persisted version of the list. The version is only persisted if the tokens changed, so all exporters
+// serve the same version and timestamp for the same tokens.
+func (rdb *RelDB) exportTokenList(key string, name string, blockchain string, assets []dia.Asset) (list TokenList, err error) {
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		var (
+			previous       TokenListVersion
+			previousTokens []TokenListToken
+			timestamp      time.Time
+			found          = true
+		)
+		query := fmt.Sprintf("SELECT major,minor,patch,tokens,time_stamp FROM %s WHERE list_key=$1 FOR UPDATE", tokenListVersionTable)
+		errQuery := txRDB.postgresClient.QueryRow(context.Background(), query, key).Scan(
+			&previous.Major,
+			&previous.Minor,
+			&previous.Patch,
+			&previousTokens,
+			&timestamp,
+		)
+		if errors.Is(errQuery, pgx.ErrNoRows) {
+			found = false
+		} else if errQuery != nil {
+			return errQuery
+		}
+
+		tokens, tags, errQuery := txRDB.GetTokenListTokens(blockchain, assets)
+		if errQuery != nil {
+			return errQuery
+		}
+		if tokens == nil {
+			tokens = []TokenListToken{}
+		}
+		version := TokenListVersion{Major: 1}
+		if found {
+			version = NextTokenListVersion(previous, previousTokens, tokens)
+		}
+		list = TokenList{
+			Name:     name,
+			Version:  version,
+			Keywords: []string{"diadata"},
+			Tags:     tags,
+			Tokens:   tokens,
+		}
+		if found && version == previous {
+			// Keep the timestamp of unchanged lists so that consumers can detect updates.
+			list.Timestamp = timestamp.UTC().Format(time.RFC3339)
+			return nil
+		}
+
+		timestamp = time.Now()
+		list.Timestamp = timestamp.UTC().Format(time.RFC3339)
+		query = fmt.Sprintf(`
+		INSERT INTO %s (list_key,major,minor,patch,tokens,time_stamp) VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (list_key) DO UPDATE SET major=EXCLUDED.major,minor=EXCLUDED.minor,patch=EXCLUDED.patch,tokens=EXCLUDED.tokens,time_stamp=EXCLUDED.time_stamp
+		`, tokenListVersionTable)
+		_, errQuery = txRDB.postgresClient.Exec(context.Background(), query, key, version.Major, version.Minor, version.Patch, tokens, timestamp)
+		return errQuery
+	})
+	return
+}
+
+// evictOldest removes the least recently generated token list.
+func (e *TokenListExporter) evictOldest() {
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+	for key, state := range e.lists {
+		if oldestKey == "" || state.generated.Before(oldest) {
+			oldestKey, oldest = key, state.generated
+		}
+	}
+	delete(e.lists, oldestKey)
+}
+
+// tokenListCacheKey identifies the token list @name of @blockchain or the curated set @assets,
+// irrespective of the order of @assets.
+func tokenListCacheKey(name string, blockchain string, assets []dia.Asset) string {
+	identifiers := make([]string, len(assets))
+	for i, asset := range assets {
+		identifiers[i] = asset.Identifier()
+	}
+	sort.Strings(identifiers)
+	hash := sha256.Sum256([]byte(strings.Join(identifiers, ",")))
+	return name + "_" + blockchain + "_" + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestNextTokenListVersion(t *testing.T) {
+	weth := TokenListToken{ChainID: 1, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18}
+	usdc := TokenListToken{ChainID: 1, Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Name: "USD Coin", Symbol: "USDC", Decimals: 6}
+	usdcTagged := usdc
+	usdcTagged.Tags = []string{"stablecoin"}
+	previous := TokenListVersion{Major: 1, Minor: 2, Patch: 3}
+
+	cases := []struct {
+		name      string
+		oldTokens []TokenListToken
+		newTokens []TokenListToken
+		expected  TokenListVersion
+	}{
+		{"unchanged", []TokenListToken{weth, usdc}, []TokenListToken{usdc, weth}, previous},
+		{"modified", []TokenListToken{weth, usdc}, []TokenListToken{weth, usdcTagged}, TokenListVersion{Major: 1, Minor: 2, Patch: 4}},
+		{"added", []TokenListToken{weth}, []TokenListToken{weth, usdcTagged}, TokenListVersion{Major: 1, Minor: 3}},
+		{"removed", []TokenListToken{weth, usdc}, []TokenListToken{usdcTagged}, TokenListVersion{Major: 2}},
+	}
+	for _, c := range cases {
+		version := NextTokenListVersion(previous, c.oldTokens, c.newTokens)
+		if version != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, version)
+		}
+	}
+}
+
+func TestTokenListCacheKey(t *testing.T) {
+	weth := dia.Asset{Blockchain: dia.ETHEREUM, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"}
+	usdc := dia.Asset{Blockchain: dia.ETHEREUM, Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"}
+
+	if tokenListCacheKey("curated", "", []dia.Asset{weth, usdc}) != tokenListCacheKey("curated", "", []dia.Asset{usdc, weth}) {
+		t.Error("cache key must not depend on the order of assets")
+	}
+	if tokenListCacheKey("curated", "", []dia.Asset{weth}) == tokenListCacheKey("curated", "", []dia.Asset{usdc}) {
+		t.Error("curated lists with the same name but different assets must have different cache keys")
+	}
+	if tokenListCacheKey("DIA", dia.ETHEREUM, nil) == tokenListCacheKey("DIA", dia.POLYGON, nil) {
+		t.Error("lists of different blockchains must have different cache keys")
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	cases := []struct {
+		s        string
+		n        int
+		expected string
+	}{
+		{"Wrapped Ether", 40, "Wrapped Ether"},
+		{"Wrapped Ether", 7, "Wrapped"},
+		{"Ünïcödé Token", 7, "Ünïcödé"},
+		{"日本円ステーブルコイン", 3, "日本円"},
+	}
+	for _, c := range cases {
+		if truncated := truncateRunes(c.s, c.n); truncated != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, truncated)
+		}
+	}
+}
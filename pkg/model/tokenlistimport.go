@@ -0,0 +1,162 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+	"github.com/diadata-org/diadata/pkg/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+)
+
+// tokenListSource is the source of assets staged by the import of the token list @name.
+func tokenListSource(name string) string {
+	return "tokenlist:" + name
+}
+
+// tokenListAsset is a token of a token list mapped to an asset, together with the metadata given by the list.
+type tokenListAsset struct {
+	asset dia.Asset
+	logo  string
+	tags  []string
+}
+
+// tokenListAssets maps the tokens of @list to assets. @chainNames maps chain ids to blockchain names.
+// Tokens on unknown chains and malformed tokens are skipped.
+func tokenListAssets(list TokenList, chainNames map[string]string) (assets []tokenListAsset) {
+	for _, token := range list.Tokens {
+		blockchain, ok := chainNames[strconv.Itoa(token.ChainID)]
+		if !ok {
+			log.Warnf("skip token %s of list %s: unknown chain id %d", token.Symbol, list.Name, token.ChainID)
+			continue
+		}
+		asset := dia.Asset{
+			Symbol:     token.Symbol,
+			Name:       token.Name,
+			Address:    token.Address,
+			Decimals:   token.Decimals,
+			Blockchain: blockchain,
+		}
+		if common.IsHexAddress(asset.Address) {
+			asset.Address = common.HexToAddress(asset.Address).Hex()
+		}
+		if errValid := ValidPendingAsset(asset); errValid != nil {
+			log.Warnf("skip token %s of list %s: %v", token.Symbol, list.Name, errValid)
+			continue
+		}
+		var tags []string
+		for _, tagID := range token.Tags {
+			if tag, ok := list.Tags[tagID]; ok && tag.Name != "" {
+				tags = append(tags, tag.Name)
+				continue
+			}
+			tags = append(tags, tagID)
+		}
+		assets = append(assets, tokenListAsset{asset: asset, logo: token.LogoURI, tags: tags})
+	}
+	return
+}
+
+// ImportTokenList parses a token list in the format of the Uniswap token list standard from @r and
+// imports its tokens in a single transaction. Tokens are mapped to blockchains by their chainId.
+// New tokens are submitted to the staging area, see SubmitPendingAsset. Tokens whose symbol, name or
+// decimals differ from the existing asset are staged as changes of the asset and only applied once
+// they are approved. Logos and tags of the tokens are stored as metadata of existing assets, keeping
+// all other metadata. Tokens on unknown chains and malformed tokens are skipped.
+// It returns the number of imported tokens.
+func (rdb *RelDB) ImportTokenList(r io.Reader) (imported int, err error) {
+	var list TokenList
+	err = json.NewDecoder(r).Decode(&list)
+	if err != nil {
+		return
+	}
+
+	blockchains, err := rdb.GetAllBlockchains(false)
+	if err != nil {
+		return
+	}
+	chainNames := make(map[string]string)
+	for _, blockchain := range blockchains {
+		if blockchain.ChainID != "" {
+			chainNames[blockchain.ChainID] = blockchain.Name
+		}
+	}
+
+	source := tokenListSource(list.Name)
+	rules := DefaultAutoApprovalRules()
+	err = rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		imported = 0
+		for _, t := range tokenListAssets(list, chainNames) {
+			errImport := txRDB.importTokenListAsset(t, source, rules)
+			if errImport != nil {
+				return errImport
+			}
+			imported++
+		}
+		return nil
+	})
+	return
+}
+
+// ImportTokenListFromURL downloads the token list at @url and imports it, see ImportTokenList.
+func (rdb *RelDB) ImportTokenListFromURL(url string) (int, error) {
+	data, statusCode, err := utils.GetRequest(url)
+	if err != nil {
+		return 0, err
+	}
+	if statusCode != http.StatusOK {
+		return 0, fmt.Errorf("get token list %s: status code %d", url, statusCode)
+	}
+	return rdb.ImportTokenList(bytes.NewReader(data))
+}
+
+// importTokenListAsset submits the asset of @t for review if it is new, stages changes of an existing asset
+// and sets the metadata given by the token list.
+func (rdb *RelDB) importTokenListAsset(t tokenListAsset, source string, rules []AutoApprovalRule) error {
+	existing, err := getAsset(rdb.postgresClient, t.asset.Address, t.asset.Blockchain)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		_, err = rdb.SubmitPendingAsset(t.asset, source, rules)
+	case err != nil:
+	case assetChanged(existing, t.asset):
+		err = stageAssetChange(rdb.postgresClient, t.asset, source)
+	}
+	if err != nil {
+		return err
+	}
+	return rdb.setTokenListMetadata(t.asset, t.logo, t.tags)
+}
+
+// assetChanged returns true if symbol, name or decimals of @asset differ from @existing.
+func assetChanged(existing dia.Asset, asset dia.Asset) bool {
+	return existing.Symbol != asset.Symbol || existing.Name != asset.Name || existing.Decimals != asset.Decimals
+}
+
+// setTokenListMetadata sets the @logo of @asset, if given, and adds @tags to its metadata tags.
+func (rdb *RelDB) setTokenListMetadata(asset dia.Asset, logo string, tags []string) error {
+	if logo == "" && len(tags) == 0 {
+		return nil
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (asset_id,logo,tags,last_update)
+	SELECT asset_id,NULLIF($1,''),$2,$3 FROM %s WHERE address=$4 AND blockchain=$5
+	ON CONFLICT (asset_id)
+	DO UPDATE SET
+		logo=COALESCE(EXCLUDED.logo,%s.logo),
+		tags=ARRAY(SELECT DISTINCT unnest(COALESCE(%s.tags,'{}') || EXCLUDED.tags)),
+		last_update=EXCLUDED.last_update
+	`, assetMetadataTable, assetTable, assetMetadataTable, assetMetadataTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, logo, tags, time.Now(), asset.Address, asset.Blockchain)
+	return err
+}
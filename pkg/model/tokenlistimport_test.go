@@ -0,0 +1,59 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestTokenListAssets(t *testing.T) {
+	list := TokenList{
+		Name: "curated",
+		Tags: map[string]TokenListTag{"stable": {Name: "stablecoin"}},
+		Tokens: []TokenListToken{
+			{ChainID: 1, Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Name: "USD Coin", Symbol: "USDC", Decimals: 6, LogoURI: "https://logo", Tags: []string{"stable", "bridged"}},
+			{ChainID: 999, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18},
+			{ChainID: 1, Address: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", Name: "Wrapped Ether", Symbol: "W ETH", Decimals: 18},
+		},
+	}
+	assets := tokenListAssets(list, map[string]string{"1": dia.ETHEREUM})
+
+	expected := []tokenListAsset{{
+		asset: dia.Asset{
+			Symbol:     "USDC",
+			Name:       "USD Coin",
+			Address:    "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+			Decimals:   6,
+			Blockchain: dia.ETHEREUM,
+		},
+		logo: "https://logo",
+		tags: []string{"stablecoin", "bridged"},
+	}}
+	if !reflect.DeepEqual(assets, expected) {
+		t.Errorf("expected %v, got %v", expected, assets)
+	}
+}
+
+func TestAssetChanged(t *testing.T) {
+	usdc := dia.Asset{Symbol: "USDC", Name: "USD Coin", Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Decimals: 6, Blockchain: dia.ETHEREUM}
+	renamed := usdc
+	renamed.Name = "USDC Coin"
+	redenominated := usdc
+	redenominated.Decimals = 18
+
+	cases := []struct {
+		name     string
+		asset    dia.Asset
+		expected bool
+	}{
+		{"unchanged", usdc, false},
+		{"name", renamed, true},
+		{"decimals", redenominated, true},
+	}
+	for _, c := range cases {
+		if changed := assetChanged(usdc, c.asset); changed != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, changed)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// TradeGap is a time-range (@Start, @End) without trades. @Start is the time of the last trade
+// before the gap, or the start of the scanned range, @End the time of the next trade or the end of the range.
+type TradeGap struct {
+	Start    time.Time     `json:"Start"`
+	End      time.Time     `json:"End"`
+	Duration time.Duration `json:"Duration"`
+}
+
+// GetTradeGaps returns all gaps longer than @maxGap between trades of the pair @foreignname on @exchange
+// in the time-range [starttime, endtime], such that missing trades can be backfilled.
+func (datastore *DB) GetTradeGaps(exchange string, foreignname string, starttime time.Time, endtime time.Time, maxGap time.Duration) ([]TradeGap, error) {
+	if maxGap <= 0 {
+		return nil, fmt.Errorf("invalid maximal gap %v", maxGap)
+	}
+	if !endtime.After(starttime) {
+		return nil, fmt.Errorf("invalid time-range [%v, %v]", starttime, endtime)
+	}
+	q := fmt.Sprintf("SELECT price FROM %s WHERE exchange=$exchange AND pair=$pair AND time>=$starttime AND time<=$endtime ORDER BY ASC", influxDbTradesTable)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"exchange":  exchange,
+		"pair":      foreignname,
+		"starttime": starttime.UnixNano(),
+		"endtime":   endtime.UnixNano(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	if len(res) > 0 && len(res[0].Series) > 0 {
+		for _, row := range res[0].Series[0].Values {
+			t, err := time.Parse(time.RFC3339, row[0].(string))
+			if err != nil {
+				return nil, err
+			}
+			times = append(times, t)
+		}
+	}
+	return findTradeGaps(times, starttime, endtime, maxGap), nil
+}
+
+// findTradeGaps returns the gaps longer than @maxGap between the ascending trade @times in [starttime, endtime].
+func findTradeGaps(times []time.Time, starttime time.Time, endtime time.Time, maxGap time.Duration) []TradeGap {
+	gaps := []TradeGap{}
+	previous := starttime
+	addGap := func(t time.Time) {
+		if t.Sub(previous) > maxGap {
+			gaps = append(gaps, TradeGap{Start: previous, End: t, Duration: t.Sub(previous)})
+		}
+		if t.After(previous) {
+			previous = t
+		}
+	}
+	for _, t := range times {
+		addGap(t)
+	}
+	addGap(endtime)
+	return gaps
+}
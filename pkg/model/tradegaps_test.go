@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindTradeGaps(t *testing.T) {
+	t0 := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	endtime := t0.Add(time.Hour)
+	times := []time.Time{
+		t0.Add(5 * time.Minute),
+		t0.Add(6 * time.Minute),
+		t0.Add(30 * time.Minute),
+		t0.Add(35 * time.Minute),
+	}
+
+	gaps := findTradeGaps(times, t0, endtime, 10*time.Minute)
+	expected := []TradeGap{
+		{Start: t0.Add(6 * time.Minute), End: t0.Add(30 * time.Minute), Duration: 24 * time.Minute},
+		{Start: t0.Add(35 * time.Minute), End: endtime, Duration: 25 * time.Minute},
+	}
+	if len(gaps) != len(expected) {
+		t.Fatalf("got %d gaps but expected %d", len(gaps), len(expected))
+	}
+	for i := range gaps {
+		if gaps[i] != expected[i] {
+			t.Errorf("gap %d is %v but should be %v", i, gaps[i], expected[i])
+		}
+	}
+
+	// Without trades, the whole range is a gap.
+	gaps = findTradeGaps(nil, t0, endtime, 10*time.Minute)
+	if len(gaps) != 1 || gaps[0].Duration != time.Hour {
+		t.Errorf("got gaps %v but expected the whole range", gaps)
+	}
+}
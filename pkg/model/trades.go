@@ -40,6 +40,11 @@ func (datastore *DB) SaveTradeInfluxToTable(t *dia.Trade, table string) error {
 		"estimatedUSDPrice": t.EstimatedUSDPrice,
 		"foreignTradeID":    t.ForeignTradeID,
 	}
+	if t.Estimation.Path != "" {
+		fields["estimationPath"] = t.Estimation.Path
+		fields["estimationBasePrice"] = t.Estimation.BasePrice
+		fields["estimationBasePriceTime"] = t.Estimation.BasePriceTime.UnixNano()
+	}
 
 	pt, err := clientInfluxdb.NewPoint(table, tags, fields, t.Time)
 	if err != nil {
@@ -51,21 +56,88 @@ func (datastore *DB) SaveTradeInfluxToTable(t *dia.Trade, table string) error {
 	return err
 }
 
+const (
+	keyTradeIdempotency = "dia_trade_idempotency_"
+
+	// lastTradesByPairLookback is the time-range in which the last trades of an exchangepair are searched.
+	lastTradesByPairLookback = 7 * 24 * time.Hour
+)
+
+// TradesClaimed returns for each of the idempotency @keys whether it was claimed before, i.e. whether
+// the corresponding trade is a redelivery of an already stored trade. All keys are looked up in one round trip.
+// If redis is unavailable, no key is reported as claimed.
+func (datastore *DB) TradesClaimed(keys []string) ([]bool, error) {
+	claimed := make([]bool, len(keys))
+	if len(keys) == 0 {
+		return claimed, nil
+	}
+	if !cacheAvailable(datastore.redisClient) {
+		fallthroughCacheRead()
+		return claimed, nil
+	}
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = keyTradeIdempotency + key
+	}
+	values, err := cacheMGet(datastore.cacheContext(), datastore.redisClient, redisKeys)
+	if checkCacheError(err) {
+		fallthroughCacheRead()
+		return claimed, nil
+	}
+	if err != nil {
+		return claimed, err
+	}
+	for i, value := range values {
+		claimed[i] = value != nil
+	}
+	return claimed, nil
+}
+
+// ClaimTrades records the idempotency @keys in redis for @ttl in one round trip. Trades must only be
+// claimed once they are stored, such that a trade which is lost before storage is not dropped on redelivery.
+func (datastore *DB) ClaimTrades(keys []string, ttl time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if !cacheAvailable(datastore.redisClient) {
+		skipCacheWrite()
+		return nil
+	}
+	ctx := datastore.cacheContext()
+	pipe := datastore.redisClient.Pipeline()
+	for _, key := range keys {
+		pipe.Set(ctx, keyTradeIdempotency+key, 1, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	if checkCacheError(err) {
+		skipCacheWrite()
+		return nil
+	}
+	return err
+}
+
 // GetTradeInflux returns the latest trade of @asset on @exchange before @timestamp in the time-range [endtime-window, endtime].
 func (datastore *DB) GetTradeInflux(asset dia.Asset, exchange string, endtime time.Time, window time.Duration) (*dia.Trade, error) {
 	starttime := endtime.Add(-window)
 	retval := dia.Trade{}
 	var q string
+	params := map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	}
 	if exchange != "" {
-		queryString := "SELECT estimatedUSDPrice,\"exchange\",foreignTradeID,\"pair\",price,\"symbol\",volume FROM %s WHERE quotetokenaddress='%s' AND quotetokenblockchain='%s' AND exchange='%s' AND time >= %d AND time < %d ORDER BY DESC LIMIT 1"
-		q = fmt.Sprintf(queryString, influxDbTradesTable, asset.Address, asset.Blockchain, exchange, starttime.UnixNano(), endtime.UnixNano())
+		queryString := "SELECT estimatedUSDPrice,\"exchange\",foreignTradeID,\"pair\",price,\"symbol\",volume FROM %s WHERE quotetokenaddress=$address AND quotetokenblockchain=$blockchain AND exchange=$exchange AND time >= $starttime AND time < $endtime ORDER BY DESC LIMIT 1"
+		q = fmt.Sprintf(queryString, influxDbTradesTable)
+		params["exchange"] = exchange
 	} else {
-		queryString := "SELECT estimatedUSDPrice,\"exchange\",foreignTradeID,\"pair\",price,\"symbol\",volume FROM %s WHERE quotetokenaddress='%s' AND quotetokenblockchain='%s' AND time >= %d AND time < %d ORDER BY DESC LIMIT 1"
-		q = fmt.Sprintf(queryString, influxDbTradesTable, asset.Address, asset.Blockchain, starttime.UnixNano(), endtime.UnixNano())
+		queryString := "SELECT estimatedUSDPrice,\"exchange\",foreignTradeID,\"pair\",price,\"symbol\",volume FROM %s WHERE quotetokenaddress=$address AND quotetokenblockchain=$blockchain AND time >= $starttime AND time < $endtime ORDER BY DESC LIMIT 1"
+		q = fmt.Sprintf(queryString, influxDbTradesTable)
 	}
 
 	/// TODO
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, params)
 	if err != nil {
 		return &retval, err
 	}
@@ -105,21 +177,23 @@ func (datastore *DB) GetTradeInflux(asset dia.Asset, exchange string, endtime ti
 func (datastore *DB) GetOldTradesFromInflux(table string, exchange string, verified bool, timeInit, timeFinal time.Time) ([]dia.Trade, error) {
 	allTrades := []dia.Trade{}
 	var queryString, query, addQueryString string
+	params := map[string]interface{}{"starttime": timeInit.UnixNano(), "endtime": timeFinal.UnixNano()}
 	if verified {
 		addQueryString = ",\"quotetokenaddress\",\"basetokenaddress\",\"quotetokenblockchain\",\"basetokenblockchain\",\"verified\""
 	}
 	if exchange == "" {
 		queryString = "SELECT estimatedUSDPrice,\"exchange\",foreignTradeID,\"pair\",price,\"symbol\",volume" +
 			addQueryString +
-			" FROM %s WHERE time>=%d and time<%d order by asc"
-		query = fmt.Sprintf(queryString, table, timeInit.UnixNano(), timeFinal.UnixNano())
+			" FROM %s WHERE time>=$starttime and time<$endtime order by asc"
+		query = fmt.Sprintf(queryString, table)
 	} else {
 		queryString = "SELECT estimatedUSDPrice,\"exchange\",foreignTradeID,\"pair\",price,\"symbol\",volume" +
 			addQueryString +
-			" FROM %s WHERE exchange='%s' and time>=%d and time<%d order by asc"
-		query = fmt.Sprintf(queryString, table, exchange, timeInit.UnixNano(), timeFinal.UnixNano())
+			" FROM %s WHERE exchange=$exchange and time>=$starttime and time<$endtime order by asc"
+		query = fmt.Sprintf(queryString, table)
+		params["exchange"] = exchange
 	}
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, params)
 	if err != nil {
 		log.Error("influx query: ", err)
 		return allTrades, err
@@ -279,6 +353,9 @@ func parseFullTrade(row []interface{}) *dia.Trade {
 				ForeignTradeID:    foreignTradeID,
 				VerifiedPair:      verified,
 			}
+			if len(row) > 16 {
+				trade.Estimation = parseTradeEstimation(row[14:17])
+			}
 
 			return &trade
 		}
@@ -287,6 +364,25 @@ func parseFullTrade(row []interface{}) *dia.Trade {
 	return nil
 }
 
+// parseTradeEstimation parses the estimation path, base price and base price time of a trade as
+// retreived from influx. Trades stored before estimations were recorded have an empty estimation.
+func parseTradeEstimation(row []interface{}) (estimation dia.TradeEstimation) {
+	path, ok := row[0].(string)
+	if !ok {
+		return
+	}
+	estimation.Path = path
+	if v, ok := row[1].(json.Number); ok {
+		estimation.BasePrice, _ = v.Float64()
+	}
+	if v, ok := row[2].(json.Number); ok {
+		if basePriceTime, err := v.Int64(); err == nil {
+			estimation.BasePriceTime = time.Unix(0, basePriceTime)
+		}
+	}
+	return
+}
+
 // parseTrade parses a trade as retreived from influx. If fullAsset=true blockchain and address of
 // the corresponding asset is returned as well.
 func parseTrade(row []interface{}, fullBasetoken bool) *dia.Trade {
@@ -376,6 +472,28 @@ func parseTrade(row []interface{}, fullBasetoken bool) *dia.Trade {
 	return nil
 }
 
+// exchangesCondition returns a condition matching trades on any of @exchanges, whose names are bound in @params.
+func exchangesCondition(exchanges []string, params map[string]interface{}) string {
+	var conditions []string
+	for _, exchange := range exchanges {
+		conditions = append(conditions, "exchange="+bindInfluxParam(params, exchange))
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")"
+}
+
+// assetsCondition returns a condition matching trades whose @token ("basetoken" or "quotetoken") is any of @assets.
+// Addresses and blockchains are bound in @params.
+func assetsCondition(token string, assets []dia.Asset, params map[string]interface{}) string {
+	var conditions []string
+	for _, asset := range assets {
+		conditions = append(conditions, fmt.Sprintf("(%saddress=%s AND %sblockchain=%s)",
+			token, bindInfluxParam(params, asset.Address),
+			token, bindInfluxParam(params, asset.Blockchain),
+		))
+	}
+	return "(" + strings.Join(conditions, " OR ") + ") "
+}
+
 func (datastore *DB) GetTradesByExchangesAndBaseAssets(asset dia.Asset, baseassets []dia.Asset, exchanges []string, startTime, endTime time.Time, maxTrades int) ([]dia.Trade, error) {
 	return datastore.GetTradesByExchangesFull(asset, baseassets, exchanges, false, startTime, endTime, maxTrades)
 }
@@ -390,38 +508,32 @@ func (datastore *DB) GetTradesByExchangesFull(
 	maxTrades int,
 ) ([]dia.Trade, error) {
 	var r []dia.Trade
+	params := map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  startTime.UnixNano(),
+		"endtime":    endTime.UnixNano(),
+	}
 	subQuery := ""
 	subQueryBase := ""
 	if len(exchanges) > 0 {
-		for _, exchange := range exchanges {
-			subQuery = subQuery + fmt.Sprintf("%s|", exchange)
-		}
-		subQuery = "AND exchange =~ /" + strings.TrimRight(subQuery, "|") + "/"
-
+		subQuery = "AND " + exchangesCondition(exchanges, params)
 		if len(baseassets) > 0 {
-			for i, baseasset := range baseassets {
-				if i == 0 {
-					subQueryBase = subQueryBase + fmt.Sprintf(` AND ((basetokenaddress='%s' AND basetokenblockchain='%s')`, baseasset.Address, baseasset.Blockchain)
-
-				} else {
-					subQueryBase = subQueryBase + fmt.Sprintf(` OR (basetokenaddress='%s' AND basetokenblockchain='%s')`, baseasset.Address, baseasset.Blockchain)
-				}
-			}
-			subQueryBase = subQueryBase + ") "
+			subQueryBase = "AND " + assetsCondition("basetoken", baseassets, params)
 		}
 	}
 	query := fmt.Sprintf(`
 	SELECT time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verified,basetokenblockchain,basetokenaddress 
 	FROM %s 
-	WHERE (quotetokenaddress='%s' and quotetokenblockchain='%s') %s %s 
+	WHERE (quotetokenaddress=$address and quotetokenblockchain=$blockchain) %s %s 
 	AND estimatedUSDPrice > 0 
-	AND time > %d AND time <= %d `,
-		influxDbTradesTable, asset.Address, asset.Blockchain, subQuery, subQueryBase, startTime.UnixNano(), endTime.UnixNano())
+	AND time > $starttime AND time <= $endtime `,
+		influxDbTradesTable, subQuery, subQueryBase)
 	if maxTrades > 0 {
 		query += fmt.Sprintf("ORDER BY DESC LIMIT %d ", maxTrades)
 	}
 	log.Info("query: ", query)
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, params)
 	if err != nil {
 		return r, err
 	}
@@ -469,39 +581,31 @@ func (datastore *DB) GetTradesByExchangesBatchedFull(
 		return []dia.Trade{}, errors.New("number of start times must equal number of end times.")
 	}
 	var query string
+	params := map[string]interface{}{
+		"address":    quoteasset.Address,
+		"blockchain": quoteasset.Blockchain,
+	}
+	subQuery := ""
+	subQueryBase := ""
+	if len(exchanges) > 0 {
+		subQuery = "and " + exchangesCondition(exchanges, params)
+	}
+	if len(baseassets) > 0 {
+		subQueryBase = "and " + assetsCondition("basetoken", baseassets, params)
+	}
 	for i := range startTimes {
-		subQuery := ""
-		subQueryBase := ""
-		if len(exchanges) > 0 {
-			for _, exchange := range exchanges {
-				subQuery = subQuery + fmt.Sprintf("%s|", exchange)
-			}
-			subQuery = "and exchange =~ /" + strings.TrimRight(subQuery, "|") + "/"
-		}
-
-		if len(baseassets) > 0 {
-			for i, baseasset := range baseassets {
-				if i == 0 {
-					subQueryBase = subQueryBase + fmt.Sprintf(` and ((basetokenaddress='%s' and basetokenblockchain='%s')`, baseasset.Address, baseasset.Blockchain)
-
-				} else {
-					subQueryBase = subQueryBase + fmt.Sprintf(` or (basetokenaddress='%s' and basetokenblockchain='%s')`, baseasset.Address, baseasset.Blockchain)
-				}
-
-			}
-			subQueryBase = subQueryBase + ") "
-
-		}
+		params[fmt.Sprintf("starttime%d", i)] = startTimes[i].UnixNano()
+		params[fmt.Sprintf("endtime%d", i)] = endTimes[i].UnixNano()
 		query = query + fmt.Sprintf(`
 		SELECT time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verified,basetokenblockchain,basetokenaddress 
 		FROM %s 
-		WHERE (quotetokenaddress='%s' AND quotetokenblockchain='%s') %s %s 
+		WHERE (quotetokenaddress=$address AND quotetokenblockchain=$blockchain) %s %s 
 		AND estimatedUSDPrice > 0 
-		AND time > %d AND time <= %d ; `,
-			influxDbTradesTable, quoteasset.Address, quoteasset.Blockchain, subQuery, subQueryBase, startTimes[i].UnixNano(), endTimes[i].UnixNano())
+		AND time > $starttime%d AND time <= $endtime%d ; `,
+			influxDbTradesTable, subQuery, subQueryBase, i, i)
 	}
 	log.Info("query: ", query)
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, params)
 	if err != nil {
 		return r, err
 	}
@@ -541,38 +645,25 @@ func (datastore *DB) GetxcTradesByExchangesBatched(
 		return []dia.Trade{}, errors.New("number of start times must equal number of end times.")
 	}
 	var query string
+	params := make(map[string]interface{})
+	subQueryExchanges := ""
+	subQueryAssets := ""
+	if len(exchanges) > 0 {
+		subQueryExchanges = "AND " + exchangesCondition(exchanges, params)
+	}
+	if len(quoteassets) > 0 {
+		subQueryAssets = "AND " + assetsCondition("quotetoken", quoteassets, params)
+	}
 	for i := range startTimes {
-		subQueryExchanges := ""
-		subQueryAssets := ""
-		if len(exchanges) > 0 {
-			for _, exchange := range exchanges {
-				subQueryExchanges = subQueryExchanges + fmt.Sprintf("%s|", exchange)
-			}
-			subQueryExchanges = "AND exchange =~ /" + strings.TrimRight(subQueryExchanges, "|") + "/"
-		}
-
-		if len(quoteassets) > 0 {
-			for i, quoteasset := range quoteassets {
-				if i == 0 {
-					subQueryAssets = subQueryAssets + fmt.Sprintf(` AND ((quotetokenaddress='%s' AND quotetokenblockchain='%s')`, quoteasset.Address, quoteasset.Blockchain)
-
-				} else {
-					subQueryAssets = subQueryAssets + fmt.Sprintf(` OR (quotetokenaddress='%s' AND quotetokenblockchain='%s')`, quoteasset.Address, quoteasset.Blockchain)
-				}
-
-			}
-			subQueryAssets = subQueryAssets + ") "
-
-		}
 		query = query + fmt.Sprintf(`
 		SELECT time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verified,basetokenblockchain,basetokenaddress
 		FROM %s 
 		WHERE estimatedUSDPrice > 0 
-		AND time > %d AND time <= %d 
+		AND time > %s AND time <= %s 
 		%s %s ;`,
-			influxDbTradesTable, startTimes[i].UnixNano(), endTimes[i].UnixNano(), subQueryExchanges, subQueryAssets)
+			influxDbTradesTable, bindInfluxParam(params, startTimes[i].UnixNano()), bindInfluxParam(params, endTimes[i].UnixNano()), subQueryExchanges, subQueryAssets)
 	}
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, params)
 	if err != nil {
 		return r, err
 	}
@@ -609,9 +700,10 @@ func (datastore *DB) GetTradesByFeedSelection(feedselection []dia.FeedSelection,
 		return []dia.Trade{}, errors.New("number of start times must equal number of end times.")
 	}
 
+	params := make(map[string]interface{})
 	for i := range starttimes {
 		query = fmt.Sprintf(`
-		SELECT time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verified,basetokenblockchain,basetokenaddress,quotetokenblockchain,quotetokenaddress,pooladdress  
+		SELECT time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verified,basetokenblockchain,basetokenaddress,quotetokenblockchain,quotetokenaddress,pooladdress,estimationPath,estimationBasePrice,estimationBasePriceTime
 		FROM %s 
 		WHERE ( `,
 			influxDbTradesTable,
@@ -641,12 +733,12 @@ func (datastore *DB) GetTradesByFeedSelection(feedselection []dia.FeedSelection,
 							pairsQuery += " OR "
 						}
 						pairsQuery += fmt.Sprintf(`
-							( quotetokenaddress='%s' AND quotetokenblockchain='%s' AND basetokenaddress='%s' and basetokenblockchain='%s')
+							( quotetokenaddress=%s AND quotetokenblockchain=%s AND basetokenaddress=%s and basetokenblockchain=%s)
 							`,
-							pair.QuoteToken.Address,
-							pair.QuoteToken.Blockchain,
-							pair.BaseToken.Address,
-							pair.BaseToken.Blockchain,
+							bindInfluxParam(params, pair.QuoteToken.Address),
+							bindInfluxParam(params, pair.QuoteToken.Blockchain),
+							bindInfluxParam(params, pair.BaseToken.Address),
+							bindInfluxParam(params, pair.BaseToken.Blockchain),
 						)
 					}
 				} else {
@@ -656,7 +748,7 @@ func (datastore *DB) GetTradesByFeedSelection(feedselection []dia.FeedSelection,
 						} else {
 							pairsQuery += " OR "
 						}
-						pairsQuery += fmt.Sprintf(" pooladdress='%s' ", pool.Address)
+						pairsQuery += fmt.Sprintf(" pooladdress=%s ", bindInfluxParam(params, pool.Address))
 					}
 				}
 				if len(exchangepairs.Pairs) > 0 || len(exchangepairs.Pools) > 0 {
@@ -664,7 +756,7 @@ func (datastore *DB) GetTradesByFeedSelection(feedselection []dia.FeedSelection,
 				}
 
 				if exchangepairs.Exchange.Name != "" {
-					exchangeQuery += fmt.Sprintf(`(exchange='%s' %s)`, exchangepairs.Exchange.Name, pairsQuery)
+					exchangeQuery += fmt.Sprintf(`(exchange=%s %s)`, bindInfluxParam(params, exchangepairs.Exchange.Name), pairsQuery)
 				} else {
 					// Take into account trades on all exchanges.
 					exchangeQuery += fmt.Sprintf(`exchange=~/./ %s`, pairsQuery)
@@ -676,10 +768,10 @@ func (datastore *DB) GetTradesByFeedSelection(feedselection []dia.FeedSelection,
 
 			// Main query for trades by asset.
 			query += fmt.Sprintf(`
-			( (quotetokenaddress='%s' AND quotetokenblockchain='%s') %s ) 
+			( (quotetokenaddress=%s AND quotetokenblockchain=%s) %s ) 
 			`,
-				item.Asset.Address,
-				item.Asset.Blockchain,
+				bindInfluxParam(params, item.Asset.Address),
+				bindInfluxParam(params, item.Asset.Blockchain),
 				exchangeQuery,
 			)
 		}
@@ -688,15 +780,15 @@ func (datastore *DB) GetTradesByFeedSelection(feedselection []dia.FeedSelection,
 		query += fmt.Sprintf(`
 		 )	
 		AND estimatedUSDPrice > 0
-		AND time > %d
-		AND time < %d ;`,
-			starttimes[i].UnixNano(),
-			endtimes[i].UnixNano(),
+		AND time > %s
+		AND time < %s ;`,
+			bindInfluxParam(params, starttimes[i].UnixNano()),
+			bindInfluxParam(params, endtimes[i].UnixNano()),
 		)
 	}
 
 	log.Info("query: ", query)
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, params)
 	if err != nil {
 		return r, err
 	}
@@ -726,8 +818,9 @@ func (datastore *DB) GetTradesByFeedSelection(feedselection []dia.FeedSelection,
 // 2. The exchange is a key of @exchangepoolMap AND the pool is in the corresponding slice @[]string.
 func (datastore *DB) GetTradesByExchangepairs(exchangepairMap map[string][]dia.Pair, exchangepoolMap map[string][]string, starttime time.Time, endtime time.Time) ([]dia.Trade, error) {
 	var (
-		query string
-		r     []dia.Trade
+		query  string
+		r      []dia.Trade
+		params = make(map[string]interface{})
 	)
 
 	query = fmt.Sprintf(`
@@ -753,19 +846,19 @@ func (datastore *DB) GetTradesByExchangepairs(exchangepairMap map[string][]dia.P
 					pairsQuery += " OR "
 				}
 				pairsQuery += fmt.Sprintf(`
-				( quotetokenaddress='%s' AND quotetokenblockchain='%s' AND basetokenaddress='%s' and basetokenblockchain='%s')
+				( quotetokenaddress=%s AND quotetokenblockchain=%s AND basetokenaddress=%s and basetokenblockchain=%s)
 				`,
-					pair.QuoteToken.Address,
-					pair.QuoteToken.Blockchain,
-					pair.BaseToken.Address,
-					pair.BaseToken.Blockchain,
+					bindInfluxParam(params, pair.QuoteToken.Address),
+					bindInfluxParam(params, pair.QuoteToken.Blockchain),
+					bindInfluxParam(params, pair.BaseToken.Address),
+					bindInfluxParam(params, pair.BaseToken.Blockchain),
 				)
 			}
 			pairsQuery += " ) "
 		}
 
 		// Main query for trades by exchange.
-		query += fmt.Sprintf(" ( exchange='%s' %s ) ", exchange, pairsQuery)
+		query += fmt.Sprintf(" ( exchange=%s %s ) ", bindInfluxParam(params, exchange), pairsQuery)
 		CEXCount++
 	}
 
@@ -784,13 +877,13 @@ func (datastore *DB) GetTradesByExchangepairs(exchangepairMap map[string][]dia.P
 				if i != 0 {
 					poolsQuery += " OR "
 				}
-				poolsQuery += fmt.Sprintf("( pooladdress='%s' )", pooladdress)
+				poolsQuery += fmt.Sprintf("( pooladdress=%s )", bindInfluxParam(params, pooladdress))
 			}
 			poolsQuery += " ) "
 		}
 
 		// Main query for trades by exchange.
-		query += fmt.Sprintf(" ( exchange='%s' %s ) ", exchange, poolsQuery)
+		query += fmt.Sprintf(" ( exchange=%s %s ) ", bindInfluxParam(params, exchange), poolsQuery)
 		DEXCount++
 	}
 
@@ -798,14 +891,14 @@ func (datastore *DB) GetTradesByExchangepairs(exchangepairMap map[string][]dia.P
 	query += fmt.Sprintf(`
 		 )	
 		AND estimatedUSDPrice > 0
-		AND time > %d
-		AND time < %d`,
-		starttime.UnixNano(),
-		endtime.UnixNano(),
+		AND time > %s
+		AND time < %s`,
+		bindInfluxParam(params, starttime.UnixNano()),
+		bindInfluxParam(params, endtime.UnixNano()),
 	)
 
 	log.Info("query: ", query)
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, params)
 	if err != nil {
 		return r, err
 	}
@@ -835,9 +928,9 @@ func (datastore *DB) GetTradesByExchangepairs(exchangepairMap map[string][]dia.P
 func (datastore *DB) GetAllTrades(t time.Time, maxTrades int) ([]dia.Trade, error) {
 	var r []dia.Trade
 	// TO DO: Substitute select * with precise statment select estimatedUSDPrice, source,...
-	q := fmt.Sprintf("SELECT time, estimatedUSDPrice, exchange, foreignTradeID, pair, price,symbol, volume,verified,basetokenblockchain,basetokenaddress  FROM %s WHERE time > %d LIMIT %d", influxDbTradesTable, t.Unix()*1000000000, maxTrades)
+	q := fmt.Sprintf("SELECT time, estimatedUSDPrice, exchange, foreignTradeID, pair, price,symbol, volume,verified,basetokenblockchain,basetokenaddress  FROM %s WHERE time > $time LIMIT %d", influxDbTradesTable, maxTrades)
 	log.Debug(q)
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{"time": t.Unix() * 1000000000})
 	if err != nil {
 		log.Errorln("GetAllTrades", err)
 		return r, err
@@ -865,46 +958,56 @@ func (datastore *DB) GetLastTrades(asset dia.Asset, exchange string, timestamp t
 		queryString string
 		q           string
 	)
+	params := map[string]interface{}{
+		"starttime": timestamp.AddDate(0, 0, -10).UnixNano(),
+		"endtime":   timestamp.UnixNano(),
+	}
 
 	if exchange == "" {
 		queryString = `
 		SELECT estimatedUSDPrice,"exchange",foreignTradeID,"pair",price,"symbol",volume,"verified","basetokenblockchain","basetokenaddress" 
 		FROM %s 
-		WHERE time<%d 
-		AND time>%d-10d 
-		AND quotetokenaddress='%s' 
-		AND quotetokenblockchain='%s' 
+		WHERE time<$endtime 
+		AND time>$starttime 
+		AND quotetokenaddress=$address 
+		AND quotetokenblockchain=$blockchain 
 		AND estimatedUSDPrice>0 
 		ORDER BY DESC LIMIT %d
 		`
-		q = fmt.Sprintf(queryString, influxDbTradesTable, timestamp.UnixNano(), timestamp.UnixNano(), asset.Address, asset.Blockchain, maxTrades)
+		q = fmt.Sprintf(queryString, influxDbTradesTable, maxTrades)
+		params["address"] = asset.Address
+		params["blockchain"] = asset.Blockchain
 	} else if (dia.Asset{}) == asset {
 		queryString = `
 		SELECT estimatedUSDPrice,"exchange",foreignTradeID,"pair",price,"symbol",volume,"verified","basetokenblockchain","basetokenaddress" 
 		FROM %s 
-		WHERE time<%d 
-		AND time>%d-10d 
-		AND exchange='%s' 
+		WHERE time<$endtime 
+		AND time>$starttime 
+		AND exchange=$exchange 
 		AND estimatedUSDPrice>0 
 		ORDER BY DESC LIMIT %d
 		`
-		q = fmt.Sprintf(queryString, influxDbTradesTable, timestamp.UnixNano(), timestamp.UnixNano(), exchange, maxTrades)
+		q = fmt.Sprintf(queryString, influxDbTradesTable, maxTrades)
+		params["exchange"] = exchange
 	} else {
 		queryString = `
 		SELECT estimatedUSDPrice,"exchange",foreignTradeID,"pair",price,"symbol",volume,"verified","basetokenblockchain","basetokenaddress" 
 		FROM %s 
-		WHERE time<%d
-		AND time>%d-10d 
-		AND exchange='%s' 
-		AND quotetokenaddress='%s' 
-		AND quotetokenblockchain='%s' 
+		WHERE time<$endtime
+		AND time>$starttime 
+		AND exchange=$exchange 
+		AND quotetokenaddress=$address 
+		AND quotetokenblockchain=$blockchain 
 		AND estimatedUSDPrice>0 
 		ORDER BY DESC LIMIT %d
 		`
-		q = fmt.Sprintf(queryString, influxDbTradesTable, timestamp.UnixNano(), timestamp.UnixNano(), exchange, asset.Address, asset.Blockchain, maxTrades)
+		q = fmt.Sprintf(queryString, influxDbTradesTable, maxTrades)
+		params["exchange"] = exchange
+		params["address"] = asset.Address
+		params["blockchain"] = asset.Blockchain
 	}
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, params)
 	if err != nil {
 		log.Errorln("GetLastTrades", err)
 		return r, err
@@ -926,6 +1029,43 @@ func (datastore *DB) GetLastTrades(asset dia.Asset, exchange string, timestamp t
 	return r, nil
 }
 
+// GetLastTradesByExchangePair returns the last @n trades of the pair @foreignname on @exchange in the past
+// lastTradesByPairLookback, most recent first. It returns an empty slice if the pair had no trades.
+func (datastore *DB) GetLastTradesByExchangePair(exchange string, foreignname string, n int) ([]dia.Trade, error) {
+	trades := []dia.Trade{}
+	if n <= 0 {
+		return trades, fmt.Errorf("invalid number of trades %d", n)
+	}
+	endtime := time.Now()
+	q := fmt.Sprintf(`
+	SELECT time,estimatedUSDPrice,exchange,foreignTradeID,pair,price,symbol,volume,verified,basetokenblockchain,basetokenaddress,quotetokenblockchain,quotetokenaddress,pooladdress,estimationPath,estimationBasePrice,estimationBasePriceTime
+	FROM %s
+	WHERE exchange=$exchange
+	AND pair=$pair
+	AND time>$starttime
+	AND time<=$endtime
+	ORDER BY DESC LIMIT %d`,
+		influxDbTradesTable, n)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"exchange":  exchange,
+		"pair":      foreignname,
+		"starttime": endtime.Add(-lastTradesByPairLookback).UnixNano(),
+		"endtime":   endtime.UnixNano(),
+	})
+	if err != nil {
+		return trades, err
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return trades, nil
+	}
+	for _, row := range res[0].Series[0].Values {
+		if t := parseFullTrade(row); t != nil {
+			trades = append(trades, *t)
+		}
+	}
+	return trades, nil
+}
+
 // GetNumTradesExchange24H returns the number of trades on @exchange in the last 24 hours.
 func (datastore *DB) GetNumTradesExchange24H(exchange string) (numTrades int64, err error) {
 	endtime := time.Now()
@@ -935,28 +1075,25 @@ func (datastore *DB) GetNumTradesExchange24H(exchange string) (numTrades int64,
 // GetNumTrades returns the number of trades on @exchange for asset with @address and @blockchain in the given time-range.
 // If @address and @blockchain are empty, it returns all trades on @exchange in the given-time range.
 func (datastore *DB) GetNumTrades(exchange string, address string, blockchain string, starttime time.Time, endtime time.Time) (numTrades int64, err error) {
-	var q string
-
-	if address != "" && blockchain != "" {
-		queryString := `
-	SELECT COUNT(*) 
-	FROM %s 
-	WHERE exchange='%s' 
-	AND quotetokenaddress='%s' AND quotetokenblockchain='%s' 
-	AND time > %d AND time<= %d
-	`
-		q = fmt.Sprintf(queryString, influxDbTradesTable, exchange, address, blockchain, starttime.UnixNano(), endtime.UnixNano())
-	} else {
-		queryString := `
+	params := map[string]interface{}{
+		"exchange":  exchange,
+		"starttime": starttime.UnixNano(),
+		"endtime":   endtime.UnixNano(),
+	}
+	queryString := `
 	SELECT COUNT(*) 
 	FROM %s 
-	WHERE exchange='%s' 
-	AND time > %d AND time<= %d
+	WHERE exchange=$exchange 
+	AND time > $starttime AND time<= $endtime
 	`
-		q = fmt.Sprintf(queryString, influxDbTradesTable, exchange, starttime.UnixNano(), endtime.UnixNano())
+	if address != "" && blockchain != "" {
+		queryString += "AND quotetokenaddress=$address AND quotetokenblockchain=$blockchain"
+		params["address"] = address
+		params["blockchain"] = blockchain
 	}
+	q := fmt.Sprintf(queryString, influxDbTradesTable)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, params)
 	if err != nil {
 		log.Errorln("GetNumTrades ", err)
 		return
@@ -985,33 +1122,24 @@ func (datastore *DB) GetNumTradesSeries(
 	endtime time.Time,
 	grouping string,
 ) (numTrades []int64, err error) {
-	var query string
-	selectQuery := "SELECT COUNT(price) FROM %s "
-	midQuery := "WHERE quotetokenaddress='%s' AND quotetokenblockchain='%s' AND time<=%d AND time>%d "
-	endQuery := "GROUP BY time(%s) ORDER BY ASC"
-	exchangeQuery := "AND exchange='%s' "
+	if !influxDurationPattern.MatchString(grouping) {
+		return nil, fmt.Errorf("invalid grouping %s", grouping)
+	}
+	params := map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	}
+	query := fmt.Sprintf("SELECT COUNT(price) FROM %s ", influxDbTradesTable) +
+		"WHERE quotetokenaddress=$address AND quotetokenblockchain=$blockchain AND time<=$endtime AND time>$starttime "
 	if exchange != "" {
-		query = fmt.Sprintf(selectQuery+midQuery+exchangeQuery+endQuery,
-			influxDbTradesTable,
-			exchange,
-			asset.Address,
-			asset.Blockchain,
-			endtime.UnixNano(),
-			starttime.UnixNano(),
-			grouping,
-		)
-	} else {
-		query = fmt.Sprintf(selectQuery+midQuery+endQuery,
-			influxDbTradesTable,
-			asset.Address,
-			asset.Blockchain,
-			endtime.UnixNano(),
-			starttime.UnixNano(),
-			grouping,
-		)
+		query += "AND exchange=$exchange "
+		params["exchange"] = exchange
 	}
+	query += fmt.Sprintf("GROUP BY time(%s) ORDER BY ASC", grouping)
 
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, params)
 	if err != nil {
 		return
 	}
@@ -1035,7 +1163,7 @@ func (datastore *DB) GetFirstTradeDate(table string) (time.Time, error) {
 	queryString := "SELECT \"exchange\",price FROM %s  where time<now() order by asc limit 1"
 	query = fmt.Sprintf(queryString, table)
 
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, nil)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -1057,7 +1185,7 @@ func getKeyLastTradeTimeForExchange(asset dia.Asset, exchange string) string {
 
 func (datastore *DB) GetLastTradeTimeForExchange(asset dia.Asset, exchange string) (*time.Time, error) {
 	key := getKeyLastTradeTimeForExchange(asset, exchange)
-	t, err := datastore.redisClient.Get(key).Result()
+	t, err := datastore.redisClient.Get(datastore.cacheContext(), key).Result()
 	if err != nil {
 		log.Errorln("Error: on GetLastTradeTimeForExchange", err, key)
 		return nil, err
@@ -1077,7 +1205,7 @@ func (datastore *DB) SetLastTradeTimeForExchange(asset dia.Asset, exchange strin
 	}
 	key := getKeyLastTradeTimeForExchange(asset, exchange)
 	log.Debug("setting ", key, t)
-	err := datastore.redisPipe.Set(key, t.Unix(), TimeOutRedis).Err()
+	err := datastore.redisPipe.Set(datastore.cacheContext(), key, t.Unix(), TimeOutRedis).Err()
 	if err != nil {
 		log.Printf("Error: %v on SetLastTradeTimeForExchange %v\n", err, asset.Symbol)
 	}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"sync"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	defaultTradeWriterBatchSize = 1000
+	defaultTradeDedupTTL        = 48 * time.Hour
+)
+
+// TradeWriter batches trade writes to influx and drops duplicates, such that a scraper which replays
+// trades after a restart does not count their volume twice. Duplicates are recognised by the trade's
+// idempotency key, which is claimed in redis for the dedup TTL once the trade is written.
+// If redis is unavailable, only duplicates within a batch are dropped. A TradeWriter is safe for concurrent use.
+type TradeWriter struct {
+	datastore *DB
+	batchSize int
+	dedupTTL  time.Duration
+
+	mu    sync.Mutex
+	batch []dia.Trade
+	// unclaimed are trades which were added to the influx batch, but whose write has not succeeded yet.
+	unclaimed  []dia.Trade
+	written    int64
+	duplicates int64
+}
+
+// TradeWriterOption configures a TradeWriter.
+type TradeWriterOption func(*TradeWriter)
+
+// WithTradeBatchSize sets the number of trades after which a batch is written. It defaults to 1000.
+func WithTradeBatchSize(size int) TradeWriterOption {
+	return func(w *TradeWriter) {
+		if size > 0 {
+			w.batchSize = size
+		}
+	}
+}
+
+// WithTradeDedupTTL sets the time for which written trades are remembered. It defaults to 48 hours
+// and must exceed the time for which a scraper can replay trades.
+func WithTradeDedupTTL(ttl time.Duration) TradeWriterOption {
+	return func(w *TradeWriter) {
+		if ttl > 0 {
+			w.dedupTTL = ttl
+		}
+	}
+}
+
+// NewTradeWriter returns a TradeWriter writing to @datastore.
+func NewTradeWriter(datastore *DB, opts ...TradeWriterOption) *TradeWriter {
+	w := &TradeWriter{
+		datastore: datastore,
+		batchSize: defaultTradeWriterBatchSize,
+		dedupTTL:  defaultTradeDedupTTL,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Add adds @t to the current batch and writes the batch once it is full.
+func (w *TradeWriter) Add(t dia.Trade) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.batch = append(w.batch, t)
+	if len(w.batch) < w.batchSize {
+		return nil
+	}
+	return w.flush()
+}
+
+// Flush writes the current batch.
+func (w *TradeWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+// Stats returns the number of trades written and dropped as duplicates so far.
+func (w *TradeWriter) Stats() (written int64, duplicates int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written, w.duplicates
+}
+
+// flush adds the new trades of the batch to the influx batch and writes it. Trades are only claimed after
+// a successful write. Otherwise they remain in the influx batch and are claimed after the next successful write.
+func (w *TradeWriter) flush() error {
+	if len(w.batch) == 0 && len(w.unclaimed) == 0 {
+		return nil
+	}
+	// Unclaimed trades are part of the influx batch already, so only new trades are added.
+	trades := dedupTradeBatch(append(w.unclaimed, w.batch...))[len(w.unclaimed):]
+	trades = w.dropClaimed(trades)
+	w.duplicates += int64(len(w.batch) - len(trades))
+	w.batch = w.batch[:0]
+
+	for i := range trades {
+		if err := w.datastore.SaveTradeInflux(&trades[i]); err != nil {
+			log.Error("save trade: ", err)
+		}
+	}
+	w.unclaimed = append(w.unclaimed, trades...)
+	if err := w.datastore.WriteBatchInflux(); err != nil {
+		return err
+	}
+
+	keys := make([]string, len(w.unclaimed))
+	for i := range w.unclaimed {
+		keys[i] = w.unclaimed[i].IdempotencyKey()
+	}
+	if err := w.datastore.ClaimTrades(keys, w.dedupTTL); err != nil {
+		log.Error("claim trades: ", err)
+	}
+	w.written += int64(len(w.unclaimed))
+	w.unclaimed = nil
+	return nil
+}
+
+// dedupTradeBatch returns the trades in @batch without duplicates, keeping the first of each.
+func dedupTradeBatch(batch []dia.Trade) []dia.Trade {
+	seen := make(map[string]struct{}, len(batch))
+	trades := make([]dia.Trade, 0, len(batch))
+	for _, t := range batch {
+		key := t.IdempotencyKey()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		trades = append(trades, t)
+	}
+	return trades
+}
+
+// dropClaimed returns the trades in @trades which were not claimed before.
+func (w *TradeWriter) dropClaimed(trades []dia.Trade) []dia.Trade {
+	keys := make([]string, len(trades))
+	for i := range trades {
+		keys[i] = trades[i].IdempotencyKey()
+	}
+	claimed, err := w.datastore.TradesClaimed(keys)
+	if err != nil {
+		log.Error("look up trade claims: ", err)
+	}
+	unclaimed := trades[:0]
+	for i, t := range trades {
+		if !claimed[i] {
+			unclaimed = append(unclaimed, t)
+		}
+	}
+	return unclaimed
+}
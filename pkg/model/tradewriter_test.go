@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestDedupTradeBatch(t *testing.T) {
+	t0 := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	batch := []dia.Trade{
+		{Source: dia.BinanceExchange, Pair: "BTCUSDT", ForeignTradeID: "1", Price: 20000, Volume: 1, Time: t0},
+		// Replay of the first trade with a different price estimation.
+		{Source: dia.BinanceExchange, Pair: "BTCUSDT", ForeignTradeID: "1", Price: 20000, Volume: 1, EstimatedUSDPrice: 20001, Time: t0},
+		{Source: dia.BinanceExchange, Pair: "ETHUSDT", ForeignTradeID: "1", Price: 1500, Volume: 1, Time: t0},
+		// Trades without foreign ID are identified by time, price and volume.
+		{Source: dia.KrakenExchange, Pair: "XBTUSD", Price: 20000, Volume: 0.5, Time: t0},
+		{Source: dia.KrakenExchange, Pair: "XBTUSD", Price: 20000, Volume: 0.5, Time: t0},
+		// Distinct fills at the same time are kept.
+		{Source: dia.KrakenExchange, Pair: "XBTUSD", Price: 20000, Volume: 0.2, Time: t0},
+		{Source: dia.KrakenExchange, Pair: "XBTUSD", Price: 20010, Volume: 0.5, Time: t0},
+	}
+
+	trades := dedupTradeBatch(batch)
+	if len(trades) != 5 {
+		t.Fatalf("got %d trades but expected 5", len(trades))
+	}
+	if trades[0].EstimatedUSDPrice != 0 {
+		t.Error("the first of duplicate trades must be kept")
+	}
+	if trades[3].Volume != 0.2 || trades[4].Price != 20010 {
+		t.Errorf("distinct fills at the same time must be kept, got %v", trades[3:])
+	}
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+// tvlMaxAge is the maximal age of a protocol's TVL value to be included in the aggregation per blockchain.
+// Older values belong to protocols which are no longer ingested.
+const tvlMaxAge = 24 * time.Hour
+
+// SetTVL stores the total value locked @tvl of a protocol. An existing value at the same time is overwritten.
+func (rdb *RelDB) SetTVL(tvl dia.ProtocolTVL) error {
+	if tvl.Protocol == "" || tvl.Blockchain == "" {
+		return errors.New("tvl without protocol or blockchain")
+	}
+	query := fmt.Sprintf(`
+	INSERT INTO %s (protocol,blockchain,tvl_usd,time_stamp) VALUES ($1,$2,$3,$4)
+	ON CONFLICT (protocol,blockchain,time_stamp) DO UPDATE SET tvl_usd=EXCLUDED.tvl_usd`,
+		tvlTable)
+	_, err := rdb.postgresClient.Exec(context.Background(), query, tvl.Protocol, tvl.Blockchain, tvl.TVL, tvl.Time)
+	return err
+}
+
+// GetTVL returns the latest total value locked of @protocol on @blockchain at or before @timestamp.
+func (rdb *RelDB) GetTVL(protocol string, blockchain string, timestamp time.Time) (tvl dia.ProtocolTVL, err error) {
+	query := fmt.Sprintf(`
+	SELECT tvl_usd,time_stamp FROM %s
+	WHERE protocol=$1 AND blockchain=$2 AND time_stamp<=$3
+	ORDER BY time_stamp DESC LIMIT 1`,
+		tvlTable)
+	err = rdb.postgresClient.QueryRow(context.Background(), query, protocol, blockchain, timestamp).Scan(&tvl.TVL, &tvl.Time)
+	if err != nil {
+		return
+	}
+	tvl.Protocol = protocol
+	tvl.Blockchain = blockchain
+	return
+}
+
+// GetTVLByChain returns the total value locked per blockchain at @timestamp, ordered by TVL descending.
+// It sums the latest value of each protocol at or before @timestamp, skipping values older than tvlMaxAge.
+func (rdb *RelDB) GetTVLByChain(timestamp time.Time) (tvls []dia.ChainTVL, err error) {
+	query := fmt.Sprintf(`
+	SELECT blockchain,SUM(tvl_usd),COUNT(*),MAX(time_stamp)
+	FROM (
+		SELECT DISTINCT ON (protocol,blockchain) protocol,blockchain,tvl_usd,time_stamp
+		FROM %s
+		WHERE time_stamp<=$1 AND time_stamp>$2
+		ORDER BY protocol,blockchain,time_stamp DESC
+	) latest
+	GROUP BY blockchain
+	ORDER BY SUM(tvl_usd) DESC`,
+		tvlTable)
+	rows, err := rdb.postgresClient.Query(context.Background(), query, timestamp, timestamp.Add(-tvlMaxAge))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tvl dia.ChainTVL
+		if err = rows.Scan(&tvl.Blockchain, &tvl.TVL, &tvl.NumProtocols, &tvl.Time); err != nil {
+			return
+		}
+		tvls = append(tvls, tvl)
+	}
+	err = rows.Err()
+	return
+}
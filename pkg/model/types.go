@@ -81,11 +81,17 @@ func (fq *FiatQuotation) UnmarshalBinary(data []byte) error {
 }
 
 // AssetQuotation is the most recent price point information on an asset.
+// A quotation with @CarriedForward=true re-emits the last price computed from trades
+// for an asset without recent trades. @Age is the time in seconds since that price was computed.
 type AssetQuotation struct {
-	Asset  dia.Asset `json:"Asset"`
-	Price  float64   `json:"Price"`
-	Source string    `json:"Source"`
-	Time   time.Time `json:"Time"`
+	Asset          dia.Asset `json:"Asset"`
+	Price          float64   `json:"Price"`
+	Source         string    `json:"Source"`
+	Time           time.Time `json:"Time"`
+	CarriedForward bool      `json:"CarriedForward,omitempty"`
+	Age            int64     `json:"Age,omitempty"`
+	// VolumeYesterdayUSD is the 24h trading volume of the asset when the quotation was written, at most volumeYesterdayRefresh old.
+	VolumeYesterdayUSD float64 `json:"VolumeYesterdayUSD,omitempty"`
 }
 
 // MarshalBinary for quotations
@@ -127,6 +133,73 @@ func (aq *AssetQuotationFull) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// AssetSnapshot bundles asset data which is read from a single consistent database snapshot.
+type AssetSnapshot struct {
+	Asset      dia.Asset          `json:"Asset"`
+	Volume     float64            `json:"Volume"`
+	VolumeUSD  float64            `json:"VolumeUSD"`
+	VolumeTime time.Time          `json:"VolumeTime"`
+	CEXSources []string           `json:"CEXSources"`
+	DEXSources []string           `json:"DEXSources"`
+	Pairs      []dia.ExchangePair `json:"Pairs"`
+}
+
+// AssetMatch is an asset returned by IdentifyAssetMatches together with the confidence
+// in [0,1] with which each of the requested fields matches.
+type AssetMatch struct {
+	Asset      dia.Asset          `json:"Asset"`
+	Confidence map[string]float64 `json:"Confidence"`
+}
+
+// IsExact returns true if all requested fields match exactly.
+func (am *AssetMatch) IsExact() bool {
+	for _, confidence := range am.Confidence {
+		if confidence < 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// AssetOrder determines the sorting of assets returned by GetAssetsByBlockchain.
+type AssetOrder string
+
+const (
+	AssetOrderSymbol  AssetOrder = "symbol"
+	AssetOrderName    AssetOrder = "name"
+	AssetOrderAddress AssetOrder = "address"
+	// AssetOrderVolume sorts by 24h volume in descending order.
+	AssetOrderVolume AssetOrder = "volume"
+)
+
+// ExchangeSymbolFilter selects symbols from exchangesymbol table.
+// Empty fields do not restrict the selection and a zero @Limit returns all symbols.
+type ExchangeSymbolFilter struct {
+	Exchanges []string
+	// Pattern is matched case insensitive. The wildcard * matches any sequence of characters,
+	// a pattern without wildcard matches as prefix.
+	Pattern      string
+	VerifiedOnly bool
+	Limit        int
+	Offset       int
+}
+
+// ExchangeSymbol is a symbol as listed on an exchange together with its verification status.
+type ExchangeSymbol struct {
+	Symbol   string `json:"Symbol"`
+	Exchange string `json:"Exchange"`
+	Verified bool   `json:"Verified"`
+}
+
+// ExchangeSymbolPage is a page of exchange symbols together with the total number of symbols
+// which satisfy the filter.
+type ExchangeSymbolPage struct {
+	Symbols []ExchangeSymbol `json:"Symbols"`
+	Total   int64            `json:"Total"`
+	Limit   int              `json:"Limit"`
+	Offset  int              `json:"Offset"`
+}
+
 type Price struct {
 	Symbol string
 	Name   string
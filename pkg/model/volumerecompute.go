@@ -0,0 +1,162 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+const (
+	defaultVolumeRecomputeLookback  = 48 * time.Hour
+	defaultVolumeRecomputeBatchSize = 100
+)
+
+// VolumeRecomputeOptions configures RecomputeAssetVolumes.
+// If @Assets is empty, the volumes of all assets with a volume filter value in the last @Lookback are recomputed.
+// @Progress is called after each batch with the number of processed and total assets.
+// For @DryRun true, volumes are computed and reported, but not written.
+type VolumeRecomputeOptions struct {
+	Assets    []dia.Asset
+	Lookback  time.Duration
+	BatchSize int
+	DryRun    bool
+	Progress  func(done int, total int)
+}
+
+// VolumeRecomputeReport summarizes a run of RecomputeAssetVolumes.
+// @Volumes holds the USD volume per asset identifier, @Failed the identifiers of assets whose volume
+// could not be computed from Influx or written to postgres and @Errors the corresponding errors.
+type VolumeRecomputeReport struct {
+	Assets  int                `json:"Assets"`
+	Updated int                `json:"Updated"`
+	Failed  []string           `json:"Failed"`
+	Errors  map[string]string  `json:"Errors"`
+	Volumes map[string]float64 `json:"Volumes"`
+	DryRun  bool               `json:"DryRun"`
+	Time    time.Time          `json:"Time"`
+}
+
+// RecomputeAssetVolumes recomputes the 24h volumes of the assets given by @opts from the Influx filters
+// and writes them to the assetvolume table and its daily history. The native volume is derived with the latest
+// price of each asset. Each batch is written in one transaction. If it fails, the assets of the batch are
+// written one by one, such that a failing asset does not prevent the others from being updated.
+// Failing assets are collected in the report and summarized in the returned error after all assets are processed.
+func RecomputeAssetVolumes(datastore Datastore, rdb *RelDB, opts VolumeRecomputeOptions) (report VolumeRecomputeReport, err error) {
+	if opts.Lookback <= 0 {
+		opts.Lookback = defaultVolumeRecomputeLookback
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultVolumeRecomputeBatchSize
+	}
+	if opts.Progress == nil {
+		opts.Progress = func(done int, total int) {
+			log.Infof("recomputed volumes of %d/%d assets", done, total)
+		}
+	}
+
+	assets := opts.Assets
+	if len(assets) == 0 {
+		assets, err = datastore.GetAssetsWithVOLInflux(time.Now().Add(-opts.Lookback))
+		if err != nil {
+			return
+		}
+	}
+
+	report = VolumeRecomputeReport{
+		Assets:  len(assets),
+		Failed:  []string{},
+		Errors:  make(map[string]string),
+		Volumes: make(map[string]float64),
+		DryRun:  opts.DryRun,
+		Time:    time.Now(),
+	}
+
+	done := 0
+	for _, batch := range assetBatches(assets, opts.BatchSize) {
+		var (
+			batchAssets  []dia.Asset
+			batchVolumes []AssetVolume24H
+		)
+		for _, asset := range batch {
+			volume, errVolume := datastore.Get24HoursAssetVolume(asset)
+			if errVolume != nil {
+				log.Errorf("get volume of asset %s: %v", asset.Identifier(), errVolume)
+				report.fail(asset, errVolume)
+				continue
+			}
+			assetVolume := AssetVolume24H{VolumeUSD: *volume, Time: report.Time}
+			quotation, errQuotation := datastore.GetAssetQuotationLatest(asset)
+			if errQuotation != nil {
+				log.Warnf("get price of asset %s, only USD volume is stored: %v", asset.Identifier(), errQuotation)
+			} else if quotation.Price > 0 {
+				assetVolume.Volume = *volume / quotation.Price
+				assetVolume.PriceTime = quotation.Time
+			}
+			batchAssets = append(batchAssets, asset)
+			batchVolumes = append(batchVolumes, assetVolume)
+			report.Volumes[asset.Identifier()] = *volume
+		}
+
+		if !opts.DryRun && len(batchAssets) > 0 {
+			errBatch := rdb.setAssetVolumes(batchAssets, batchVolumes, report.Time)
+			if errBatch == nil {
+				report.Updated += len(batchAssets)
+			} else {
+				log.Warnf("write volumes of batch, retry asset by asset: %v", errBatch)
+				for i, asset := range batchAssets {
+					errAsset := rdb.setAssetVolumes(batchAssets[i:i+1], batchVolumes[i:i+1], report.Time)
+					if errAsset != nil {
+						log.Errorf("write volume of asset %s: %v", asset.Identifier(), errAsset)
+						report.fail(asset, errAsset)
+						delete(report.Volumes, asset.Identifier())
+						continue
+					}
+					report.Updated++
+				}
+			}
+		}
+
+		done += len(batch)
+		opts.Progress(done, len(assets))
+	}
+	if len(report.Failed) > 0 {
+		err = fmt.Errorf("volumes of %d of %d assets failed: %s", len(report.Failed), report.Assets, strings.Join(report.Failed, ","))
+	}
+	return
+}
+
+// fail records that the volume of @asset could not be recomputed due to @err.
+func (report *VolumeRecomputeReport) fail(asset dia.Asset, err error) {
+	report.Failed = append(report.Failed, asset.Identifier())
+	report.Errors[asset.Identifier()] = err.Error()
+}
+
+// setAssetVolumes writes @volumes of @assets at @t in one transaction.
+func (rdb *RelDB) setAssetVolumes(assets []dia.Asset, volumes []AssetVolume24H, t time.Time) error {
+	return rdb.withTx(context.Background(), func(txRDB *RelDB) error {
+		for i, asset := range assets {
+			if err := txRDB.SetAssetVolume24H(asset, volumes[i]); err != nil {
+				return err
+			}
+			if err := txRDB.SetAssetVolumeAt(asset, volumes[i].VolumeUSD, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// assetBatches splits @assets into consecutive batches of at most @size assets.
+func assetBatches(assets []dia.Asset, size int) (batches [][]dia.Asset) {
+	for len(assets) > size {
+		batches = append(batches, assets[:size])
+		assets = assets[size:]
+	}
+	if len(assets) > 0 {
+		batches = append(batches, assets)
+	}
+	return
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/diadata-org/diadata/pkg/dia"
+)
+
+func TestAssetBatches(t *testing.T) {
+	assets := make([]dia.Asset, 5)
+	for i := range assets {
+		assets[i] = dia.Asset{Address: string(rune('a' + i)), Blockchain: dia.ETHEREUM}
+	}
+
+	cases := []struct {
+		size  int
+		sizes []int
+	}{
+		{size: 2, sizes: []int{2, 2, 1}},
+		{size: 5, sizes: []int{5}},
+		{size: 10, sizes: []int{5}},
+	}
+	for _, c := range cases {
+		batches := assetBatches(assets, c.size)
+		if len(batches) != len(c.sizes) {
+			t.Fatalf("size %d: got %d batches, want %d", c.size, len(batches), len(c.sizes))
+		}
+		next := 0
+		for i, batch := range batches {
+			if len(batch) != c.sizes[i] {
+				t.Errorf("size %d: batch %d has %d assets, want %d", c.size, i, len(batch), c.sizes[i])
+			}
+			for _, asset := range batch {
+				if asset.Address != assets[next].Address {
+					t.Errorf("size %d: got asset %s at position %d, want %s", c.size, asset.Address, next, assets[next].Address)
+				}
+				next++
+			}
+		}
+	}
+
+	if batches := assetBatches(nil, 3); len(batches) != 0 {
+		t.Errorf("got %d batches for no assets", len(batches))
+	}
+}
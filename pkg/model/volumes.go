@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/diadata-org/diadata/pkg/dia"
@@ -16,8 +17,47 @@ const (
 
 var (
 	volumeKey = "VOL" + strconv.Itoa(dia.BlockSizeSeconds)
+	// volumeYesterdayRefresh bounds the age of 24h volumes attached to cached quotations.
+	volumeYesterdayRefresh = 10 * time.Minute
 )
 
+// volumeYesterdayCache holds the 24h volume of assets, keyed by asset identifier, such that
+// quotation writers do not query influx for every quotation.
+type volumeYesterdayCache struct {
+	volumes map[string]volumeYesterdayEntry
+	lock    sync.Mutex
+}
+
+type volumeYesterdayEntry struct {
+	volume    float64
+	queryTime time.Time
+}
+
+// volumeYesterdayUSD returns the 24h volume of @asset, queried from influx at most once
+// per volumeYesterdayRefresh. It returns 0 if the volume is unknown.
+func (datastore *DB) volumeYesterdayUSD(asset dia.Asset) float64 {
+	if datastore.influxClient == nil || datastore.volumesYesterday == nil {
+		return 0
+	}
+	c := datastore.volumesYesterday
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.volumes[asset.Identifier()]
+	if ok && time.Since(entry.queryTime) < volumeYesterdayRefresh {
+		return entry.volume
+	}
+	volume, err := datastore.Get24HoursAssetVolume(asset)
+	if err != nil {
+		log.Warnf("get 24h volume of %s: %v", asset.Symbol, err)
+		// Keep serving the previous volume until the next refresh.
+		entry.queryTime = time.Now()
+		c.volumes[asset.Identifier()] = entry
+		return entry.volume
+	}
+	c.volumes[asset.Identifier()] = volumeYesterdayEntry{volume: *volume, queryTime: time.Now()}
+	return *volume
+}
+
 // GetVolumeInflux returns the volume of @asset on @exchange using the VOL120 filter in the given time-range.
 // Both, @asset and @exchange may be empty.
 // If @starttime,@endtime are empty, the last 24h are taken into account.
@@ -28,41 +68,27 @@ func (datastore *DB) GetVolumeInflux(asset dia.Asset, exchange string, starttime
 		starttime = endtime.AddDate(0, 0, -1)
 	}
 
-	var q string
-
-	if asset == (dia.Asset{}) {
-		queryString := `
-		SELECT SUM(value) 
-		FROM %s 
-		WHERE exchange='%s' 
-		AND filter='%s' 
-		AND time > %d AND time<= %d
-		`
-		q = fmt.Sprintf(queryString, influxDbFiltersTable, exchange, volumeKey, starttime.UnixNano(), endtime.UnixNano())
-	} else if exchange == "" {
-		queryString := `
-		SELECT SUM(value) 
-		FROM %s 
-		WHERE address='%s' AND blockchain='%s' 
-		AND exchange=''
-		AND filter='%s' 
-		AND time > %d AND time<= %d
-		`
-		q = fmt.Sprintf(queryString, influxDbFiltersTable, asset.Address, asset.Blockchain, volumeKey, starttime.UnixNano(), endtime.UnixNano())
-	} else {
-		queryString := `
+	params := map[string]interface{}{
+		"exchange":  exchange,
+		"filter":    volumeKey,
+		"starttime": starttime.UnixNano(),
+		"endtime":   endtime.UnixNano(),
+	}
+	q := fmt.Sprintf(`
 		SELECT SUM(value) 
 		FROM %s 
-		WHERE address='%s' AND blockchain='%s' 
-		AND exchange='%s' 
-		AND filter='%s' 
-		AND time > %d AND time<= %d
-		`
-		q = fmt.Sprintf(queryString, influxDbFiltersTable, asset.Address, asset.Blockchain, exchange, volumeKey, starttime.UnixNano(), endtime.UnixNano())
+		WHERE exchange=$exchange 
+		AND filter=$filter 
+		AND time > $starttime AND time<= $endtime
+		`, influxDbFiltersTable)
+	if asset != (dia.Asset{}) {
+		params["address"] = asset.Address
+		params["blockchain"] = asset.Blockchain
+		q += "AND address=$address AND blockchain=$blockchain"
 	}
 
 	var errorString string
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, params)
 	if err != nil {
 		log.Errorln("GetVolumeInflux ", err)
 		return nil, err
@@ -90,6 +116,63 @@ func (datastore *DB) GetVolumeInflux(asset dia.Asset, exchange string, starttime
 	}
 }
 
+// VolumePoint is the aggregated volume of the time bucket starting at @Time.
+type VolumePoint struct {
+	Time   time.Time `json:"Time"`
+	Volume float64   `json:"Volume"`
+}
+
+// GetVolumeInfluxRange returns the volume of @asset across exchanges in (@starttime,@endtime], aggregated from
+// the VOL120 filter into buckets of length @resolution. Buckets are aligned to the unix epoch and empty buckets
+// have zero volume. If a downsampled volume series fits @resolution, it is read instead of the raw filters.
+func (datastore *DB) GetVolumeInfluxRange(asset dia.Asset, starttime time.Time, endtime time.Time, resolution time.Duration) (points []VolumePoint, err error) {
+	if resolution < time.Second {
+		return nil, fmt.Errorf("resolution %v is below one second", resolution)
+	}
+	if !endtime.After(starttime) {
+		return nil, errors.New("endtime must be after starttime")
+	}
+
+	q := fmt.Sprintf(`
+	SELECT SUM(value)
+	FROM %s
+	WHERE address=$address AND blockchain=$blockchain
+	AND exchange=''
+	AND filter=$filter
+	AND time > $starttime AND time<= $endtime
+	GROUP BY time(%ds) fill(0)
+	`, datastore.downsampledSource(influxDbFiltersTable, volumeFilterWhere, resolution, starttime), int64(resolution.Seconds()))
+
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"filter":     volumeKey,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
+	if err != nil {
+		return
+	}
+	if len(res) == 0 || len(res[0].Series) == 0 {
+		return
+	}
+	for _, row := range res[0].Series[0].Values {
+		var point VolumePoint
+		point.Time, err = time.Parse(time.RFC3339, row[0].(string))
+		if err != nil {
+			return
+		}
+		if volume, ok := row[1].(json.Number); ok {
+			point.Volume, err = volume.Float64()
+			if err != nil {
+				return
+			}
+		}
+		points = append(points, point)
+	}
+	return
+}
+
 // Get24HoursAssetVolume returns the 24h trading volume of @asset across exchanges.
 func (datastore *DB) Get24HoursAssetVolume(asset dia.Asset) (*float64, error) {
 	endtime := time.Now()
@@ -107,21 +190,23 @@ func (datastore *DB) GetVolumesAllExchanges(asset dia.Asset, starttime time.Time
 	q := fmt.Sprintf(
 		`SELECT SUM(value) 
 		FROM %s 
-		WHERE filter='VOL120' 
-		AND address='%s' 
-		AND blockchain='%s'
+		WHERE filter=$filter 
+		AND address=$address 
+		AND blockchain=$blockchain
 		AND exchange!='' 
-		AND time>%d 
-		AND time<=%d 
+		AND time>$starttime 
+		AND time<=$endtime 
 		GROUP BY exchange`,
 		influxDbFiltersTable,
-		asset.Address,
-		asset.Blockchain,
-		starttime.UnixNano(),
-		endtime.UnixNano(),
 	)
 
-	res, err := queryInfluxDB(datastore.influxClient, q)
+	res, err := datastore.queryInflux(q, map[string]interface{}{
+		"filter":     volumeKey,
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
 	if err != nil {
 		log.Errorln("GetLastTrades", err)
 		return
@@ -164,21 +249,22 @@ func (datastore *DB) GetExchangePairVolumes(asset dia.Asset, starttime time.Time
 			SELECT ABS(estimatedUSDPrice*volume)
 			AS multiplication
 			FROM %s
-			WHERE quotetokenaddress='%s'
-			AND quotetokenblockchain='%s'
-			AND time>%d
-			AND time<=%d
+			WHERE quotetokenaddress=$address
+			AND quotetokenblockchain=$blockchain
+			AND time>$starttime
+			AND time<=$endtime
 			)
 		GROUP BY "exchange","basetokenaddress","basetokenblockchain","pooladdress"
 		`,
 		influxDbTradesTable,
-		asset.Address,
-		asset.Blockchain,
-		starttime.UnixNano(),
-		endtime.UnixNano(),
 	)
 
-	res, err := queryInfluxDB(datastore.influxClient, query)
+	res, err := datastore.queryInflux(query, map[string]interface{}{
+		"address":    asset.Address,
+		"blockchain": asset.Blockchain,
+		"starttime":  starttime.UnixNano(),
+		"endtime":    endtime.UnixNano(),
+	})
 	if err != nil {
 		return volumeMap, err
 	}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// OnReload calls @reload in a go routine whenever the process receives SIGHUP and,
+// if @interval is positive, every @interval.
+func OnReload(interval time.Duration, reload func()) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		tick = time.NewTicker(interval).C
+	}
+	go func() {
+		for {
+			select {
+			case <-hup:
+			case <-tick:
+			}
+			reload()
+		}
+	}()
+}